@@ -56,12 +56,14 @@ func main() {
 	// Payment service routes
 	apiRouter.PathPrefix("/payments").HandlerFunc(createProxyHandler("payment"))
 	apiRouter.PathPrefix("/donations").HandlerFunc(createProxyHandler("payment"))
+	apiRouter.PathPrefix("/ledger").HandlerFunc(createProxyHandler("payment"))
 
 	// Campaign service routes
 	apiRouter.PathPrefix("/campaigns").HandlerFunc(createProxyHandler("campaign"))
 
 	// Asset service routes
 	apiRouter.PathPrefix("/assets").HandlerFunc(createProxyHandler("asset"))
+	apiRouter.PathPrefix("/reports").HandlerFunc(createProxyHandler("asset"))
 
 	// Analytics service routes (Enterprise only)
 	apiRouter.PathPrefix("/analytics").HandlerFunc(createProxyHandler("analytics"))
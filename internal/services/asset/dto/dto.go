@@ -0,0 +1,341 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+)
+
+// CreateAssetRequest represents asset registration request
+type CreateAssetRequest struct {
+	CampaignID      int64            `json:"campaign_id"`
+	Type            domain.AssetType `json:"type"`
+	Name            string           `json:"name"`
+	Description     string           `json:"description"`
+	Location        string           `json:"location"`
+	Latitude        *float64         `json:"latitude,omitempty"`
+	Longitude       *float64         `json:"longitude,omitempty"`
+	Area            *float64         `json:"area,omitempty"`
+	AreaUnit        string           `json:"area_unit,omitempty"` // sqm, hectare
+	PurchaseValue   int64            `json:"purchase_value"`
+	AcquisitionDate string           `json:"acquisition_date"` // YYYY-MM-DD
+}
+
+// UpdateAssetRequest represents asset update request; omitted fields are kept
+type UpdateAssetRequest struct {
+	Type            *domain.AssetType `json:"type,omitempty"`
+	Name            *string           `json:"name,omitempty"`
+	Description     *string           `json:"description,omitempty"`
+	Location        *string           `json:"location,omitempty"`
+	Latitude        *float64          `json:"latitude,omitempty"`
+	Longitude       *float64          `json:"longitude,omitempty"`
+	Area            *float64          `json:"area,omitempty"`
+	AreaUnit        *string           `json:"area_unit,omitempty"`
+	PurchaseValue   *int64            `json:"purchase_value,omitempty"`
+	AcquisitionDate *string           `json:"acquisition_date,omitempty"`
+}
+
+// UpdateStatusRequest represents asset status change request
+type UpdateStatusRequest struct {
+	Status domain.AssetStatus `json:"status"`
+	Reason string             `json:"reason"`
+}
+
+// ListAssetsQuery represents asset listing filters
+type ListAssetsQuery struct {
+	CampaignID int64
+	Type       domain.AssetType
+	Status     domain.AssetStatus
+	Region     json.RawMessage // GeoJSON polygon the asset must lie inside
+	Page       int
+	PerPage    int
+}
+
+// DocumentRequest represents asset document attach or update request. The
+// file is uploaded to storage first; the request carries its metadata.
+type DocumentRequest struct {
+	DocumentType string `json:"document_type"` // certificate, deed, permit
+	DocumentName string `json:"document_name"`
+	FileURL      string `json:"file_url"`
+	FileSize     int64  `json:"file_size"`
+	MimeType     string `json:"mime_type"`
+	IssueDate    string `json:"issue_date,omitempty"`  // YYYY-MM-DD
+	ExpiryDate   string `json:"expiry_date,omitempty"` // YYYY-MM-DD
+	Notes        string `json:"notes,omitempty"`
+}
+
+// ValuationRequest represents asset valuation recording request
+type ValuationRequest struct {
+	ValuationValue int64  `json:"valuation_value"`
+	ValuationDate  string `json:"valuation_date"` // YYYY-MM-DD
+	ValuedBy       string `json:"valued_by"`
+	Method         string `json:"method"` // market_value, income_approach, cost_approach
+	Notes          string `json:"notes,omitempty"`
+	DocumentURL    string `json:"document_url,omitempty"`
+}
+
+// ValuationReviewRequest represents an admin's decision on a pending valuation
+type ValuationReviewRequest struct {
+	Decision domain.ValuationStatus `json:"decision"` // approved, rejected
+	Reason   string                 `json:"reason,omitempty"`
+}
+
+// ValuationTimeline represents an asset's value from purchase through each
+// approved valuation, oldest first
+type ValuationTimeline struct {
+	AssetID         int64                    `json:"asset_id"`
+	PurchaseValue   int64                    `json:"purchase_value"`
+	AcquisitionDate time.Time                `json:"acquisition_date"`
+	CurrentValue    int64                    `json:"current_value"`
+	LastValuationAt *time.Time               `json:"last_valuation_at,omitempty"`
+	Change          int64                    `json:"change"` // current value less purchase value
+	Valuations      []*domain.AssetValuation `json:"valuations"`
+}
+
+// BoundaryRequest represents saving the surveyed boundary of an asset
+type BoundaryRequest struct {
+	Boundary   json.RawMessage `json:"boundary"` // GeoJSON Polygon, or a Feature holding one
+	LandUse    string          `json:"land_use,omitempty"`
+	ZoningType string          `json:"zoning_type,omitempty"`
+	Elevation  *float64        `json:"elevation,omitempty"` // meters above sea level
+}
+
+// AreaCheck compares the declared area of an asset with the geodesic area of
+// its boundary
+type AreaCheck struct {
+	DeclaredSqm *float64 `json:"declared_sqm,omitempty"`
+	ComputedSqm float64  `json:"computed_sqm"`
+	Difference  *float64 `json:"difference,omitempty"` // relative to the computed area
+	Mismatch    bool     `json:"mismatch"`
+}
+
+// BoundaryResponse represents an asset boundary with its area check
+type BoundaryResponse struct {
+	*domain.GeospatialData
+	AreaCheck *AreaCheck `json:"area_check"`
+}
+
+// NearbyQuery represents a search for assets around a point
+type NearbyQuery struct {
+	Latitude  float64
+	Longitude float64
+	Radius    float64 // meters
+	Type      domain.AssetType
+	Status    domain.AssetStatus
+	Limit     int
+}
+
+// WithinRequest represents a search for assets inside a region
+type WithinRequest struct {
+	Region     json.RawMessage    `json:"region"` // GeoJSON Polygon, or a Feature holding one
+	CampaignID int64              `json:"campaign_id,omitempty"`
+	Type       domain.AssetType   `json:"type,omitempty"`
+	Status     domain.AssetStatus `json:"status,omitempty"`
+}
+
+// ExportQuery represents GIS export filters
+type ExportQuery struct {
+	CampaignID int64
+	Type       domain.AssetType
+	Status     domain.AssetStatus
+	Region     json.RawMessage // GeoJSON polygon the asset must lie inside
+}
+
+// ImportedFeature represents the outcome of importing one feature of an
+// uploaded boundary file
+type ImportedFeature struct {
+	Index   int    `json:"index"` // position of the feature in the file, from 0
+	AssetID int64  `json:"asset_id,omitempty"`
+	Status  string `json:"status"` // created, updated, failed
+	Error   string `json:"error,omitempty"`
+}
+
+// ImportResult represents the outcome of a boundary import
+type ImportResult struct {
+	Created  int                `json:"created"`
+	Updated  int                `json:"updated"`
+	Failed   int                `json:"failed"`
+	Features []*ImportedFeature `json:"features"`
+}
+
+// AssetResponse represents an asset with its documents and status history
+type AssetResponse struct {
+	*domain.Asset
+	Documents     []*domain.AssetDocument     `json:"documents"`
+	StatusHistory []*domain.AssetStatusChange `json:"status_history"`
+}
+
+// LeaseRequest represents a lease contract registration request
+type LeaseRequest struct {
+	LesseeName       string              `json:"lessee_name"`
+	LesseeIDNumber   string              `json:"lessee_id_number,omitempty"` // NIK or NPWP
+	LesseeEmail      string              `json:"lessee_email,omitempty"`
+	LesseePhone      string              `json:"lessee_phone,omitempty"`
+	LesseeAddress    string              `json:"lessee_address,omitempty"`
+	StartDate        string              `json:"start_date"` // YYYY-MM-DD
+	EndDate          string              `json:"end_date"`   // YYYY-MM-DD, last day of the lease
+	RentAmount       int64               `json:"rent_amount"`
+	BillingCycle     domain.BillingCycle `json:"billing_cycle"` // monthly, quarterly, semiannual, yearly
+	PaymentTermDays  int                 `json:"payment_term_days"`
+	EscalationBPS    int                 `json:"escalation_bps,omitempty"`    // e.g. 500 raises rent by 5%
+	EscalationMonths int                 `json:"escalation_months,omitempty"` // defaults to 12 with escalation_bps
+	DepositAmount    int64               `json:"deposit_amount,omitempty"`
+	Notes            string              `json:"notes,omitempty"`
+}
+
+// TerminateLeaseRequest represents ending a lease before its end date
+type TerminateLeaseRequest struct {
+	TerminatedAt  string               `json:"terminated_at"` // YYYY-MM-DD
+	Reason        string               `json:"reason"`
+	DepositStatus domain.DepositStatus `json:"deposit_status,omitempty"` // returned, forfeited; kept held if omitted
+}
+
+// LeasePaymentRequest represents rent received against an invoice
+type LeasePaymentRequest struct {
+	Amount    int64  `json:"amount"`
+	PaidAt    string `json:"paid_at"` // YYYY-MM-DD
+	Method    string `json:"method"`  // transfer, cash, qris
+	Reference string `json:"reference,omitempty"`
+	Notes     string `json:"notes,omitempty"`
+}
+
+// LeaseResponse represents a lease with its invoices and payments
+type LeaseResponse struct {
+	*domain.Lease
+	Outstanding int64                  `json:"outstanding"` // rent invoiced but not yet paid
+	Overdue     int64                  `json:"overdue"`     // part of outstanding past its due date
+	Invoices    []*domain.LeaseInvoice `json:"invoices"`
+	Payments    []*domain.LeasePayment `json:"payments"`
+}
+
+// ScheduleRequest represents a recurring maintenance schedule of an asset
+type ScheduleRequest struct {
+	Title          string `json:"title"`
+	Description    string `json:"description,omitempty"`
+	IntervalMonths int    `json:"interval_months"`
+	NextDueDate    string `json:"next_due_date"` // YYYY-MM-DD
+	EstimatedCost  int64  `json:"estimated_cost,omitempty"`
+	IsActive       *bool  `json:"is_active,omitempty"` // defaults to true
+}
+
+// WorkOrderRequest represents opening or editing a maintenance work order
+type WorkOrderRequest struct {
+	ScheduleID    *int64   `json:"schedule_id,omitempty"` // set only when opening
+	Title         string   `json:"title"`
+	Description   string   `json:"description,omitempty"`
+	Vendor        string   `json:"vendor,omitempty"`
+	VendorContact string   `json:"vendor_contact,omitempty"`
+	EstimatedCost int64    `json:"estimated_cost,omitempty"`
+	PhotoURLs     []string `json:"photo_urls,omitempty"`
+}
+
+// CompleteWorkOrderRequest represents signing off finished maintenance work
+type CompleteWorkOrderRequest struct {
+	ActualCost  int64    `json:"actual_cost"`
+	CompletedOn string   `json:"completed_on"` // YYYY-MM-DD
+	Notes       string   `json:"notes,omitempty"`
+	PhotoURLs   []string `json:"photo_urls,omitempty"` // added to the photos of the work order
+}
+
+// CancelWorkOrderRequest represents dropping a work order without posting cost
+type CancelWorkOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+// DepreciationRequest represents the depreciation policy of one asset
+type DepreciationRequest struct {
+	Method           domain.DepreciationMethod `json:"method"` // straight_line, declining_balance
+	UsefulLifeMonths int                       `json:"useful_life_months"`
+	SalvageValue     int64                     `json:"salvage_value"`
+	RateBPS          int                       `json:"rate_bps,omitempty"` // declining balance only; defaults to double the straight-line rate
+}
+
+// DepreciationDefaultRequest represents the depreciation policy of every
+// asset of a type without one of its own
+type DepreciationDefaultRequest struct {
+	Method           domain.DepreciationMethod `json:"method"` // straight_line, declining_balance
+	UsefulLifeMonths int                       `json:"useful_life_months"`
+	SalvageBPS       int                       `json:"salvage_bps"`        // salvage as a share of each asset's cost
+	RateBPS          int                       `json:"rate_bps,omitempty"` // declining balance only; defaults to double the straight-line rate
+}
+
+// DepreciationSchedule represents the depreciation of an asset over its
+// useful life: the months posted so far, then the months still to come
+type DepreciationSchedule struct {
+	AssetID     int64                       `json:"asset_id"`
+	AssetName   string                      `json:"asset_name"`
+	Policy      *domain.DepreciationPolicy  `json:"policy"`
+	Cost        int64                       `json:"cost"` // purchase value; revaluations do not change it
+	StartPeriod time.Time                   `json:"start_period"`
+	EndPeriod   time.Time                   `json:"end_period"`  // last month of the useful life
+	Accumulated int64                       `json:"accumulated"` // posted so far
+	BookValue   int64                       `json:"book_value"`  // cost less posted depreciation
+	Periods     []*domain.DepreciationEntry `json:"periods"`
+}
+
+// CertificationStepPlan represents who handles a certification stage and by
+// when
+type CertificationStepPlan struct {
+	ResponsibleUserID *int64 `json:"responsible_user_id,omitempty"`
+	ResponsibleName   string `json:"responsible_name,omitempty"`
+	DueDate           string `json:"due_date,omitempty"` // YYYY-MM-DD
+}
+
+// StartCertificationRequest represents starting the certification of a land
+// asset as wakaf land
+type StartCertificationRequest struct {
+	Notes string                                               `json:"notes,omitempty"`
+	Steps map[domain.CertificationStage]*CertificationStepPlan `json:"steps,omitempty"` // keyed by stage
+}
+
+// CertificationStepRequest represents progress on a certification step;
+// omitted fields are kept
+type CertificationStepRequest struct {
+	Status            domain.StepStatus `json:"status,omitempty"` // in_progress, blocked, completed
+	ResponsibleUserID *int64            `json:"responsible_user_id,omitempty"`
+	ResponsibleName   *string           `json:"responsible_name,omitempty"`
+	DueDate           *string           `json:"due_date,omitempty"` // YYYY-MM-DD, empty to clear
+	Notes             *string           `json:"notes,omitempty"`
+}
+
+// CancelCertificationRequest represents abandoning a certification workflow
+type CancelCertificationRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CertificationStepView represents a certification step with the documents
+// it needs
+type CertificationStepView struct {
+	*domain.CertificationStep
+	RequiredDocuments []string `json:"required_documents"`
+	MissingDocuments  []string `json:"missing_documents"`
+	Overdue           bool     `json:"overdue"`
+}
+
+// CertificationResponse represents a certification workflow with its steps
+type CertificationResponse struct {
+	*domain.CertificationWorkflow
+	CurrentStage domain.CertificationStage `json:"current_stage,omitempty"` // empty once completed
+	Steps        []*CertificationStepView  `json:"steps"`
+}
+
+// CertificationQuery represents filters of the certification dashboard
+type CertificationQuery struct {
+	Stage   domain.CertificationStage
+	Status  domain.StepStatus
+	Overdue bool
+}
+
+// ComplianceQuery represents filters of the document compliance dashboard
+type ComplianceQuery struct {
+	Status   string // expired, expiring or empty for both
+	Within   int    // days ahead a document counts as expiring
+	TenantID *int64 // admin only
+}
+
+// ExpiryAlertQuery represents filters of the document expiry alert history
+type ExpiryAlertQuery struct {
+	AssetID  int64
+	TenantID *int64 // admin only
+}
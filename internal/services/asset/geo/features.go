@@ -0,0 +1,188 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Format represents a GIS file format
+type Format string
+
+const (
+	FormatGeoJSON   Format = "geojson"
+	FormatKML       Format = "kml"
+	FormatShapefile Format = "shp" // zipped .shp, .shx, .dbf and .prj
+)
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatGeoJSON:
+		return "application/geo+json"
+	case FormatKML:
+		return "application/vnd.google-earth.kml+xml"
+	case FormatShapefile:
+		return "application/zip"
+	}
+	return "application/octet-stream"
+}
+
+// Extension returns the file extension of the format
+func (f Format) Extension() string {
+	if f == FormatShapefile {
+		return "zip"
+	}
+	return string(f)
+}
+
+// Property is a named feature attribute; values are string, int64, float64
+// or nil
+type Property struct {
+	Name  string
+	Value interface{}
+}
+
+// Feature is a located thing with its attributes. It is placed by its
+// polygon, or by its point when it has none.
+type Feature struct {
+	Polygon    Polygon
+	Point      *Position
+	Properties []Property
+
+	// Err is set on a read feature whose geometry cannot be used
+	Err error
+}
+
+// Property gets the value of a named property
+func (f *Feature) Property(name string) (interface{}, bool) {
+	for _, p := range f.Properties {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Write renders features in the given format. Features must share the same
+// property names in the same order.
+func Write(w io.Writer, format Format, name string, features []*Feature) error {
+	switch format {
+	case FormatKML:
+		return WriteKML(w, name, features)
+	case FormatShapefile:
+		return WriteShapefile(w, name, features)
+	default:
+		return WriteGeoJSON(w, name, features)
+	}
+}
+
+// Read parses the features of an uploaded GeoJSON or KML file. The file
+// fails as a whole only if it cannot be read; a feature without a usable
+// polygon is returned with Err set.
+func Read(format Format, data []byte) ([]*Feature, error) {
+	switch format {
+	case FormatGeoJSON:
+		return ReadGeoJSON(data)
+	case FormatKML:
+		return ReadKML(data)
+	}
+	return nil, fmt.Errorf("cannot read %s files", format)
+}
+
+// WriteGeoJSON renders features as a GeoJSON FeatureCollection (RFC 7946)
+func WriteGeoJSON(w io.Writer, name string, features []*Feature) error {
+	type geometry struct {
+		Type        string      `json:"type"`
+		Coordinates interface{} `json:"coordinates"`
+	}
+	type feature struct {
+		Type       string                 `json:"type"`
+		Geometry   *geometry              `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+
+	collection := struct {
+		Type     string     `json:"type"`
+		Name     string     `json:"name,omitempty"`
+		Features []*feature `json:"features"`
+	}{"FeatureCollection", name, make([]*feature, 0, len(features))}
+
+	for _, f := range features {
+		out := &feature{Type: "Feature", Properties: make(map[string]interface{}, len(f.Properties))}
+		switch {
+		case f.Polygon != nil:
+			out.Geometry = &geometry{"Polygon", f.Polygon.Oriented(false)}
+		case f.Point != nil:
+			out.Geometry = &geometry{"Point", *f.Point}
+		}
+		for _, p := range f.Properties {
+			out.Properties[p.Name] = p.Value
+		}
+		collection.Features = append(collection.Features, out)
+	}
+
+	return json.NewEncoder(w).Encode(collection)
+}
+
+// ReadGeoJSON parses a GeoJSON FeatureCollection, Feature or bare Polygon
+func ReadGeoJSON(data []byte) ([]*Feature, error) {
+	var obj struct {
+		geoJSON
+		Features []json.RawMessage `json:"features"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, errors.New("file is not valid GeoJSON")
+	}
+	if err := obj.checkCRS(); err != nil {
+		return nil, err
+	}
+
+	raw := obj.Features
+	if obj.Type != "FeatureCollection" {
+		raw = []json.RawMessage{data}
+	}
+
+	features := make([]*Feature, 0, len(raw))
+	for _, r := range raw {
+		f := &Feature{}
+		f.Polygon, f.Err = ParsePolygon(r)
+
+		var props struct {
+			Properties map[string]interface{} `json:"properties"`
+		}
+		json.Unmarshal(r, &props)
+		names := make([]string, 0, len(props.Properties))
+		for name := range props.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			f.Properties = append(f.Properties, Property{name, propertyValue(props.Properties[name])})
+		}
+
+		features = append(features, f)
+	}
+
+	return features, nil
+}
+
+// propertyValue narrows a decoded JSON property to the property value types
+func propertyValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case nil, string, float64:
+		return value
+	case bool:
+		if value {
+			return "true"
+		}
+		return "false"
+	}
+	// Nested objects and arrays are kept as their JSON text
+	var b bytes.Buffer
+	json.NewEncoder(&b).Encode(v)
+	return string(bytes.TrimSpace(b.Bytes()))
+}
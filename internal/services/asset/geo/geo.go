@@ -0,0 +1,405 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MaxVertices bounds the size of a polygon; the self-intersection check is
+// quadratic in the number of vertices
+const MaxVertices = 5000
+
+// AreaTolerance is the relative difference between a declared and a
+// computed area beyond which the two are reported as a mismatch
+const AreaTolerance = 0.05
+
+// earthRadius is the radius in meters of the sphere with the same surface
+// area as the WGS84 ellipsoid
+const earthRadius = 6371007.1809
+
+// Position is a WGS84 coordinate in GeoJSON order: longitude, latitude
+type Position [2]float64
+
+// Lng returns the longitude of the position
+func (p Position) Lng() float64 { return p[0] }
+
+// Lat returns the latitude of the position
+func (p Position) Lat() float64 { return p[1] }
+
+// Polygon is a GeoJSON polygon: an outer ring followed by its holes. Every
+// ring is closed, so its last position repeats the first.
+type Polygon [][]Position
+
+// geoJSON is the subset of a GeoJSON object needed to read a polygon, either
+// bare or wrapped in a Feature
+type geoJSON struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    json.RawMessage `json:"geometry"`
+	CRS         *struct {
+		Type       string `json:"type"`
+		Properties struct {
+			Name string `json:"name"`
+		} `json:"properties"`
+	} `json:"crs"`
+}
+
+// wgs84Names are the legacy crs names accepted for WGS84 coordinates
+var wgs84Names = map[string]bool{
+	"urn:ogc:def:crs:OGC:1.3:CRS84": true,
+	"urn:ogc:def:crs:OGC::CRS84":    true,
+	"urn:ogc:def:crs:EPSG::4326":    true,
+	"EPSG:4326":                     true,
+}
+
+// ParsePolygon parses and validates a GeoJSON Polygon geometry, or a Feature
+// whose geometry is a Polygon. A MultiPolygon with a single part, as GIS
+// tools often export, is accepted as that part.
+func ParsePolygon(data []byte) (Polygon, error) {
+	var obj geoJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, errors.New("must be a GeoJSON Polygon")
+	}
+
+	if obj.Type == "Feature" {
+		geometry, crs := obj.Geometry, obj.CRS
+		if len(geometry) == 0 || string(geometry) == "null" {
+			return nil, errors.New("feature has no geometry")
+		}
+		obj = geoJSON{}
+		if err := json.Unmarshal(geometry, &obj); err != nil {
+			return nil, errors.New("must be a GeoJSON Polygon")
+		}
+		if obj.CRS == nil {
+			obj.CRS = crs
+		}
+	}
+
+	if err := obj.checkCRS(); err != nil {
+		return nil, err
+	}
+
+	var rings [][][]float64
+	switch obj.Type {
+	case "Polygon":
+		if err := json.Unmarshal(obj.Coordinates, &rings); err != nil || len(rings) == 0 {
+			return nil, errors.New("polygon coordinates must be a list of rings")
+		}
+	case "MultiPolygon":
+		var parts [][][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &parts); err != nil || len(parts) == 0 {
+			return nil, errors.New("multipolygon coordinates must be a list of polygons")
+		}
+		if len(parts) > 1 {
+			return nil, errors.New("multi-part boundaries are not supported")
+		}
+		rings = parts[0]
+	default:
+		return nil, fmt.Errorf("must be a GeoJSON Polygon, got %q", obj.Type)
+	}
+
+	polygon := make(Polygon, len(rings))
+	for i, coords := range rings {
+		ring := make([]Position, 0, len(coords))
+		for _, c := range coords {
+			if len(c) < 2 || len(c) > 3 {
+				return nil, fmt.Errorf("ring %d has a position without longitude and latitude", i)
+			}
+			ring = appendPosition(ring, Position{c[0], c[1]})
+		}
+		polygon[i] = ring
+	}
+
+	if err := polygon.Validate(); err != nil {
+		return nil, err
+	}
+	return polygon, nil
+}
+
+// checkCRS checks a legacy crs member, if any, names WGS84
+func (obj *geoJSON) checkCRS() error {
+	if obj.CRS != nil && !wgs84Names[obj.CRS.Properties.Name] {
+		return errors.New("coordinates must be WGS84 (EPSG:4326)")
+	}
+	return nil
+}
+
+// appendPosition appends a position to a ring, skipping a repeat of the
+// previous position as it adds nothing to the shape
+func appendPosition(ring []Position, p Position) []Position {
+	if len(ring) > 0 && ring[len(ring)-1] == p {
+		return ring
+	}
+	return append(ring, p)
+}
+
+// Validate checks the polygon is a valid simple WGS84 polygon: coordinates
+// in range, closed rings, no ring crossing itself or another ring and every
+// hole inside the outer ring
+func (p Polygon) Validate() error {
+	if len(p) == 0 {
+		return errors.New("polygon has no rings")
+	}
+
+	vertices := 0
+	for i, ring := range p {
+		if len(ring) < 4 {
+			return fmt.Errorf("ring %d must have at least 4 positions", i)
+		}
+		if ring[0] != ring[len(ring)-1] {
+			return fmt.Errorf("ring %d is not closed; its last position must repeat the first", i)
+		}
+
+		minLng, maxLng := 180.0, -180.0
+		for _, pos := range ring {
+			if math.IsNaN(pos.Lng()) || pos.Lng() < -180 || pos.Lng() > 180 {
+				return fmt.Errorf("ring %d has a longitude outside -180 to 180", i)
+			}
+			if math.IsNaN(pos.Lat()) || pos.Lat() < -90 || pos.Lat() > 90 {
+				return fmt.Errorf("ring %d has a latitude outside -90 to 90", i)
+			}
+			minLng = math.Min(minLng, pos.Lng())
+			maxLng = math.Max(maxLng, pos.Lng())
+		}
+		if maxLng-minLng > 180 {
+			return fmt.Errorf("ring %d crosses the antimeridian", i)
+		}
+
+		vertices += len(ring) - 1
+		if vertices > MaxVertices {
+			return fmt.Errorf("polygon has more than %d vertices", MaxVertices)
+		}
+	}
+
+	for i, ring := range p {
+		if selfIntersects(ring) {
+			return fmt.Errorf("ring %d intersects itself", i)
+		}
+		if ringArea(ring) == 0 {
+			return fmt.Errorf("ring %d has no area", i)
+		}
+	}
+
+	for i := 1; i < len(p); i++ {
+		if !contains(p[0], p[i][0]) {
+			return fmt.Errorf("hole %d is outside the outer ring", i)
+		}
+		for j := 0; j < i; j++ {
+			if ringsCross(p[i], p[j]) {
+				return fmt.Errorf("ring %d crosses ring %d", i, j)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Area computes the geodesic area of the polygon in square meters, less its
+// holes
+func (p Polygon) Area() float64 {
+	if len(p) == 0 {
+		return 0
+	}
+
+	area := ringArea(p[0])
+	for _, hole := range p[1:] {
+		area -= ringArea(hole)
+	}
+	return math.Max(area, 0)
+}
+
+// GeoJSON encodes the polygon as a GeoJSON Polygon geometry
+func (p Polygon) GeoJSON() []byte {
+	data, _ := json.Marshal(struct {
+		Type        string  `json:"type"`
+		Coordinates Polygon `json:"coordinates"`
+	}{"Polygon", p})
+	return data
+}
+
+// Oriented returns the polygon with its outer ring wound counterclockwise
+// and its holes clockwise (the GeoJSON and KML convention), or the reverse
+// when clockwise is set (the Shapefile convention)
+func (p Polygon) Oriented(clockwise bool) Polygon {
+	oriented := make(Polygon, len(p))
+	for i, ring := range p {
+		// Holes wind against the outer ring
+		wantClockwise := clockwise == (i == 0)
+		if (signedArea(ring) < 0) == wantClockwise {
+			oriented[i] = ring
+			continue
+		}
+		reversed := make([]Position, len(ring))
+		for j, pos := range ring {
+			reversed[len(ring)-1-j] = pos
+		}
+		oriented[i] = reversed
+	}
+	return oriented
+}
+
+// Bounds returns the smallest box holding the polygon as minimum and
+// maximum positions
+func (p Polygon) Bounds() (min, max Position) {
+	min, max = Position{180, 90}, Position{-180, -90}
+	for _, ring := range p {
+		for _, pos := range ring {
+			min = Position{math.Min(min.Lng(), pos.Lng()), math.Min(min.Lat(), pos.Lat())}
+			max = Position{math.Max(max.Lng(), pos.Lng()), math.Max(max.Lat(), pos.Lat())}
+		}
+	}
+	return min, max
+}
+
+// BBox builds the polygon of a longitude/latitude bounding box
+func BBox(minLng, minLat, maxLng, maxLat float64) (Polygon, error) {
+	if minLng >= maxLng || minLat >= maxLat {
+		return nil, errors.New("bbox minimums must be below its maximums")
+	}
+
+	box := Polygon{{
+		{minLng, minLat}, {maxLng, minLat}, {maxLng, maxLat}, {minLng, maxLat}, {minLng, minLat},
+	}}
+	if err := box.Validate(); err != nil {
+		return nil, err
+	}
+	return box, nil
+}
+
+// SquareMeters converts an area in a declared unit (sqm, hectare) to square
+// meters
+func SquareMeters(area float64, unit string) (float64, bool) {
+	switch unit {
+	case "sqm":
+		return area, true
+	case "hectare":
+		return area * 10000, true
+	}
+	return 0, false
+}
+
+// ringArea computes the area of a closed ring on the sphere using the
+// Chamberlain–Duquette formula, regardless of winding order
+func ringArea(ring []Position) float64 {
+	n := len(ring) - 1
+	if n < 3 {
+		return 0
+	}
+
+	var total float64
+	for i := 0; i < n; i++ {
+		prev := ring[(i+n-1)%n]
+		next := ring[(i+1)%n]
+		total += (radians(next.Lng()) - radians(prev.Lng())) * math.Sin(radians(ring[i].Lat()))
+	}
+	return math.Abs(total * earthRadius * earthRadius / 2)
+}
+
+// signedArea computes the planar shoelace area of a closed ring in square
+// degrees; it is positive when the ring winds counterclockwise
+func signedArea(ring []Position) float64 {
+	var total float64
+	for i := 0; i < len(ring)-1; i++ {
+		total += ring[i].Lng()*ring[i+1].Lat() - ring[i+1].Lng()*ring[i].Lat()
+	}
+	return total / 2
+}
+
+// selfIntersects checks if any two edges of a closed ring touch, other than
+// neighbouring edges at their shared vertex
+func selfIntersects(ring []Position) bool {
+	n := len(ring) - 1
+	for i := 0; i < n; i++ {
+		a, b := ring[i], ring[i+1]
+
+		// A neighbouring edge may only share its vertex, not fold back over
+		// this edge
+		c := ring[(i+2)%n]
+		if orientation(a, b, c) == 0 && dot(a, b, c) > 0 {
+			return true
+		}
+
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue
+			}
+			if segmentsIntersect(a, b, ring[j], ring[j+1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ringsCross checks if any edge of one ring touches any edge of another
+func ringsCross(r1, r2 []Position) bool {
+	for i := 0; i < len(r1)-1; i++ {
+		for j := 0; j < len(r2)-1; j++ {
+			if segmentsIntersect(r1[i], r1[i+1], r2[j], r2[j+1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// contains checks if a position lies inside a closed ring (ray casting)
+func contains(ring []Position, p Position) bool {
+	inside := false
+	for i, j := 0, len(ring)-2; i < len(ring)-1; j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat() > p.Lat()) != (b.Lat() > p.Lat()) &&
+			p.Lng() < (b.Lng()-a.Lng())*(p.Lat()-a.Lat())/(b.Lat()-a.Lat())+a.Lng() {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// segmentsIntersect checks if segment p1-p2 touches segment p3-p4
+func segmentsIntersect(p1, p2, p3, p4 Position) bool {
+	d1 := orientation(p3, p4, p1)
+	d2 := orientation(p3, p4, p2)
+	d3 := orientation(p1, p2, p3)
+	d4 := orientation(p1, p2, p4)
+
+	if d1 != d2 && d3 != d4 && d1 != 0 && d2 != 0 && d3 != 0 && d4 != 0 {
+		return true
+	}
+
+	return (d1 == 0 && onSegment(p3, p4, p1)) ||
+		(d2 == 0 && onSegment(p3, p4, p2)) ||
+		(d3 == 0 && onSegment(p1, p2, p3)) ||
+		(d4 == 0 && onSegment(p1, p2, p4))
+}
+
+// orientation returns 1 if a, b, c turn counterclockwise, -1 if clockwise
+// and 0 if they are collinear
+func orientation(a, b, c Position) int {
+	v := (b.Lng()-a.Lng())*(c.Lat()-a.Lat()) - (b.Lat()-a.Lat())*(c.Lng()-a.Lng())
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// onSegment checks if collinear position p lies within the bounds of a-b
+func onSegment(a, b, p Position) bool {
+	return p.Lng() >= math.Min(a.Lng(), b.Lng()) && p.Lng() <= math.Max(a.Lng(), b.Lng()) &&
+		p.Lat() >= math.Min(a.Lat(), b.Lat()) && p.Lat() <= math.Max(a.Lat(), b.Lat())
+}
+
+// dot computes the dot product of b->a and b->c; it is positive when c
+// turns back towards a
+func dot(a, b, c Position) float64 {
+	return (a.Lng()-b.Lng())*(c.Lng()-b.Lng()) + (a.Lat()-b.Lat())*(c.Lat()-b.Lat())
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,193 @@
+package geo
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParsePolygon(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		rings   int
+		wantErr string
+	}{
+		{
+			name:  "polygon",
+			data:  `{"type":"Polygon","coordinates":[[[106.8,-6.2],[106.801,-6.2],[106.801,-6.199],[106.8,-6.199],[106.8,-6.2]]]}`,
+			rings: 1,
+		},
+		{
+			name:  "feature",
+			data:  `{"type":"Feature","properties":{},"geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}}`,
+			rings: 1,
+		},
+		{
+			name:  "single part multipolygon",
+			data:  `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,1],[0,0]]]]}`,
+			rings: 1,
+		},
+		{
+			name:  "polygon with a hole",
+			data:  `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,4],[0,0]],[[1,1],[1,2],[2,2],[2,1],[1,1]]]}`,
+			rings: 2,
+		},
+		{
+			name:  "repeated positions and altitude",
+			data:  `{"type":"Polygon","coordinates":[[[0,0,12],[1,0,12],[1,0,12],[1,1,13],[0,1,12],[0,0,12]]]}`,
+			rings: 1,
+		},
+		{
+			name:  "wgs84 crs",
+			data:  `{"type":"Polygon","crs":{"type":"name","properties":{"name":"EPSG:4326"}},"coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`,
+			rings: 1,
+		},
+		{
+			name:    "unclosed ring",
+			data:    `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1]]]}`,
+			wantErr: "not closed",
+		},
+		{
+			name:    "too few positions",
+			data:    `{"type":"Polygon","coordinates":[[[0,0],[1,0],[0,0]]]}`,
+			wantErr: "at least 4 positions",
+		},
+		{
+			name:    "bowtie",
+			data:    `{"type":"Polygon","coordinates":[[[0,0],[1,1],[1,0],[0,1],[0,0]]]}`,
+			wantErr: "intersects itself",
+		},
+		{
+			name:    "spike folding back on itself",
+			data:    `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[1,2],[1,1],[0,1],[0,0]]]}`,
+			wantErr: "intersects itself",
+		},
+		{
+			name:    "ring touching itself at a vertex",
+			data:    `{"type":"Polygon","coordinates":[[[0,0],[2,0],[1,1],[2,2],[0,2],[1,1],[0,0]]]}`,
+			wantErr: "intersects itself",
+		},
+		{
+			name:    "collinear ring",
+			data:    `{"type":"Polygon","coordinates":[[[0,0],[1,0],[2,0],[0,0]]]}`,
+			wantErr: "intersects itself",
+		},
+		{
+			name:    "hole outside the outer ring",
+			data:    `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]],[[2,2],[2,3],[3,3],[3,2],[2,2]]]}`,
+			wantErr: "outside the outer ring",
+		},
+		{
+			name:    "hole crossing the outer ring",
+			data:    `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,4],[0,0]],[[1,1],[1,5],[2,5],[2,1],[1,1]]]}`,
+			wantErr: "crosses ring 0",
+		},
+		{
+			name:    "latitude out of range",
+			data:    `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,91],[0,0]]]}`,
+			wantErr: "latitude",
+		},
+		{
+			name:    "antimeridian",
+			data:    `{"type":"Polygon","coordinates":[[[179,0],[-179,0],[-179,1],[179,1],[179,0]]]}`,
+			wantErr: "antimeridian",
+		},
+		{
+			name:    "multi-part multipolygon",
+			data:    `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]],[[[2,2],[3,2],[3,3],[2,2]]]]}`,
+			wantErr: "multi-part",
+		},
+		{
+			name:    "projected crs",
+			data:    `{"type":"Polygon","crs":{"type":"name","properties":{"name":"EPSG:32748"}},"coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`,
+			wantErr: "WGS84",
+		},
+		{
+			name:    "point",
+			data:    `{"type":"Point","coordinates":[0,0]}`,
+			wantErr: "must be a GeoJSON Polygon",
+		},
+		{
+			name:    "feature without geometry",
+			data:    `{"type":"Feature","geometry":null}`,
+			wantErr: "no geometry",
+		},
+		{
+			name:    "not json",
+			data:    `POLYGON((0 0, 1 0, 1 1, 0 0))`,
+			wantErr: "must be a GeoJSON Polygon",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polygon, err := ParsePolygon([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParsePolygon() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePolygon() error = %v", err)
+			}
+			if len(polygon) != tt.rings {
+				t.Errorf("ParsePolygon() has %d rings, want %d", len(polygon), tt.rings)
+			}
+		})
+	}
+}
+
+func TestPolygonArea(t *testing.T) {
+	// One thousandth of a degree at the equator, about 111.2 m a side
+	side := earthRadius * radians(0.001)
+
+	tests := []struct {
+		name    string
+		polygon Polygon
+		want    float64 // square meters
+	}{
+		{
+			name:    "square at the equator",
+			polygon: Polygon{{{0, 0}, {0.001, 0}, {0.001, 0.001}, {0, 0.001}, {0, 0}}},
+			want:    side * side,
+		},
+		{
+			name:    "clockwise winding",
+			polygon: Polygon{{{0, 0}, {0, 0.001}, {0.001, 0.001}, {0.001, 0}, {0, 0}}},
+			want:    side * side,
+		},
+		{
+			name:    "square at 60 degrees south is half as wide",
+			polygon: Polygon{{{0, -60}, {0.001, -60}, {0.001, -59.999}, {0, -59.999}, {0, -60}}},
+			want:    side * side * math.Cos(radians(60)),
+		},
+		{
+			name: "hole is subtracted",
+			polygon: Polygon{
+				{{0, 0}, {0.002, 0}, {0.002, 0.002}, {0, 0.002}, {0, 0}},
+				{{0.0005, 0.0005}, {0.0005, 0.0015}, {0.0015, 0.0015}, {0.0015, 0.0005}, {0.0005, 0.0005}},
+			},
+			want: 3 * side * side,
+		},
+		{
+			name:    "triangle",
+			polygon: Polygon{{{0, 0}, {0.001, 0}, {0, 0.001}, {0, 0}}},
+			want:    side * side / 2,
+		},
+		{
+			name: "empty",
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.polygon.Area()
+			if math.Abs(got-tt.want) > tt.want*0.001 {
+				t.Errorf("Area() = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
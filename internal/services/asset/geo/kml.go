@@ -0,0 +1,210 @@
+package geo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// kmlPolygon is a KML Polygon element
+type kmlPolygon struct {
+	Outer string   `xml:"outerBoundaryIs>LinearRing>coordinates"`
+	Inner []string `xml:"innerBoundaryIs>LinearRing>coordinates"`
+}
+
+// kmlPlacemark is the subset of a KML Placemark needed to read a boundary
+type kmlPlacemark struct {
+	Name         string `xml:"name"`
+	ExtendedData struct {
+		Data []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:"value"`
+		} `xml:"Data"`
+		SchemaData []struct {
+			SimpleData []struct {
+				Name  string `xml:"name,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"SimpleData"`
+		} `xml:"SchemaData"`
+	} `xml:"ExtendedData"`
+	Polygon       *kmlPolygon `xml:"Polygon"`
+	MultiGeometry *struct {
+		Polygons []kmlPolygon `xml:"Polygon"`
+	} `xml:"MultiGeometry"`
+}
+
+// WriteKML renders features as a KML document of placemarks; each
+// property is kept as ExtendedData
+func WriteKML(w io.Writer, name string, features []*Feature) error {
+	b := bufio.NewWriter(w)
+
+	b.WriteString(xml.Header)
+	b.WriteString(`<kml xmlns="http://www.opengis.net/kml/2.2">` + "\n<Document>\n")
+	fmt.Fprintf(b, "<name>%s</name>\n", escapeXML(name))
+
+	for _, f := range features {
+		b.WriteString("<Placemark>\n")
+		if value, ok := f.Property("name"); ok && value != nil {
+			fmt.Fprintf(b, "<name>%s</name>\n", escapeXML(propertyText(value)))
+		}
+
+		b.WriteString("<ExtendedData>\n")
+		for _, p := range f.Properties {
+			fmt.Fprintf(b, `<Data name="%s"><value>%s</value></Data>`+"\n", escapeXML(p.Name), escapeXML(propertyText(p.Value)))
+		}
+		b.WriteString("</ExtendedData>\n")
+
+		switch {
+		case f.Polygon != nil:
+			polygon := f.Polygon.Oriented(false)
+			b.WriteString("<Polygon>\n")
+			fmt.Fprintf(b, "<outerBoundaryIs><LinearRing><coordinates>%s</coordinates></LinearRing></outerBoundaryIs>\n", kmlCoordinates(polygon[0]))
+			for _, hole := range polygon[1:] {
+				fmt.Fprintf(b, "<innerBoundaryIs><LinearRing><coordinates>%s</coordinates></LinearRing></innerBoundaryIs>\n", kmlCoordinates(hole))
+			}
+			b.WriteString("</Polygon>\n")
+		case f.Point != nil:
+			fmt.Fprintf(b, "<Point><coordinates>%s</coordinates></Point>\n", kmlCoordinates([]Position{*f.Point}))
+		}
+
+		b.WriteString("</Placemark>\n")
+	}
+
+	b.WriteString("</Document>\n</kml>\n")
+	return b.Flush()
+}
+
+// ReadKML parses the placemarks of a KML document, however deeply they are
+// nested in folders. Properties come from the placemark name and its
+// ExtendedData.
+func ReadKML(data []byte) ([]*Feature, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	features := make([]*Feature, 0)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("file is not valid KML")
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Placemark" {
+			continue
+		}
+
+		var placemark kmlPlacemark
+		if err := decoder.DecodeElement(&placemark, &start); err != nil {
+			return nil, errors.New("file is not valid KML")
+		}
+		features = append(features, placemark.feature())
+	}
+
+	return features, nil
+}
+
+// feature converts a placemark to a feature
+func (p *kmlPlacemark) feature() *Feature {
+	f := &Feature{}
+	if p.Name != "" {
+		f.Properties = append(f.Properties, Property{"name", strings.TrimSpace(p.Name)})
+	}
+	for _, d := range p.ExtendedData.Data {
+		f.Properties = append(f.Properties, Property{d.Name, strings.TrimSpace(d.Value)})
+	}
+	for _, schema := range p.ExtendedData.SchemaData {
+		for _, d := range schema.SimpleData {
+			f.Properties = append(f.Properties, Property{d.Name, strings.TrimSpace(d.Value)})
+		}
+	}
+
+	polygon := p.Polygon
+	if polygon == nil && p.MultiGeometry != nil {
+		if len(p.MultiGeometry.Polygons) > 1 {
+			f.Err = errors.New("multi-part boundaries are not supported")
+			return f
+		}
+		if len(p.MultiGeometry.Polygons) == 1 {
+			polygon = &p.MultiGeometry.Polygons[0]
+		}
+	}
+	if polygon == nil {
+		f.Err = errors.New("placemark has no polygon")
+		return f
+	}
+
+	rings := append([]string{polygon.Outer}, polygon.Inner...)
+	f.Polygon = make(Polygon, len(rings))
+	for i, text := range rings {
+		ring, err := parseKMLCoordinates(text)
+		if err != nil {
+			f.Polygon, f.Err = nil, fmt.Errorf("ring %d %v", i, err)
+			return f
+		}
+		f.Polygon[i] = ring
+	}
+
+	if err := f.Polygon.Validate(); err != nil {
+		f.Polygon, f.Err = nil, err
+	}
+	return f
+}
+
+// parseKMLCoordinates parses a KML coordinates list of lon,lat[,alt]
+// tuples separated by whitespace
+func parseKMLCoordinates(text string) ([]Position, error) {
+	ring := make([]Position, 0)
+	for _, tuple := range strings.Fields(text) {
+		parts := strings.Split(tuple, ",")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, errors.New("has a coordinate without longitude and latitude")
+		}
+		lng, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			return nil, errors.New("has an invalid longitude")
+		}
+		lat, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, errors.New("has an invalid latitude")
+		}
+		ring = appendPosition(ring, Position{lng, lat})
+	}
+	return ring, nil
+}
+
+// kmlCoordinates formats positions as a KML coordinates list
+func kmlCoordinates(positions []Position) string {
+	tuples := make([]string, len(positions))
+	for i, p := range positions {
+		tuples[i] = strconv.FormatFloat(p.Lng(), 'f', -1, 64) + "," + strconv.FormatFloat(p.Lat(), 'f', -1, 64)
+	}
+	return strings.Join(tuples, " ")
+}
+
+// propertyText formats a property value as text
+func propertyText(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func escapeXML(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
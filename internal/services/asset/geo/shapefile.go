@@ -0,0 +1,287 @@
+package geo
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Shapefile shape types
+const (
+	shapePoint   int32 = 1
+	shapePolygon int32 = 5
+)
+
+// wgs84PRJ is the ESRI WKT of WGS84 written as the .prj of every layer
+const wgs84PRJ = `GEOGCS["GCS_WGS_1984",DATUM["D_WGS_1984",SPHEROID["WGS_1984",6378137.0,298.257223563]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]]`
+
+// dbfField is a dBASE column
+type dbfField struct {
+	name     string
+	kind     byte // C (character) or N (numeric)
+	length   int
+	decimals int
+}
+
+// WriteShapefile renders features as a zip of ESRI Shapefile layers. A
+// shapefile holds one geometry type, so polygons go to the name_boundaries
+// layer and points to the name_points layer.
+func WriteShapefile(w io.Writer, name string, features []*Feature) error {
+	var polygons, points []*Feature
+	for _, f := range features {
+		switch {
+		case f.Polygon != nil:
+			polygons = append(polygons, f)
+		case f.Point != nil:
+			points = append(points, f)
+		}
+	}
+
+	zw := zip.NewWriter(w)
+	layers := []struct {
+		suffix    string
+		shapeType int32
+		features  []*Feature
+	}{
+		{"_boundaries", shapePolygon, polygons},
+		{"_points", shapePoint, points},
+	}
+	for i, layer := range layers {
+		// An empty export still carries an (empty) boundaries layer
+		if len(layer.features) == 0 && (i > 0 || len(points) > 0) {
+			continue
+		}
+		if err := writeLayer(zw, name+layer.suffix, layer.shapeType, layer.features); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// writeLayer writes the .shp, .shx, .dbf, .prj and .cpg files of one layer
+func writeLayer(zw *zip.Writer, name string, shapeType int32, features []*Feature) error {
+	var shp, shx bytes.Buffer
+	min, max := Position{180, 90}, Position{-180, -90}
+	if len(features) == 0 {
+		min, max = Position{}, Position{}
+	}
+
+	var records [][]byte
+	for _, f := range features {
+		var record []byte
+		if shapeType == shapePoint {
+			record = pointRecord(*f.Point)
+			min = Position{math.Min(min.Lng(), f.Point.Lng()), math.Min(min.Lat(), f.Point.Lat())}
+			max = Position{math.Max(max.Lng(), f.Point.Lng()), math.Max(max.Lat(), f.Point.Lat())}
+		} else {
+			record = polygonRecord(f.Polygon)
+			lo, hi := f.Polygon.Bounds()
+			min = Position{math.Min(min.Lng(), lo.Lng()), math.Min(min.Lat(), lo.Lat())}
+			max = Position{math.Max(max.Lng(), hi.Lng()), math.Max(max.Lat(), hi.Lat())}
+		}
+		records = append(records, record)
+	}
+
+	shpLength := 100
+	for _, record := range records {
+		shpLength += 8 + len(record)
+	}
+	writeShapeHeader(&shp, shpLength, shapeType, min, max)
+	writeShapeHeader(&shx, 100+8*len(records), shapeType, min, max)
+
+	offset := 100
+	for i, record := range records {
+		binary.Write(&shp, binary.BigEndian, int32(i+1))
+		binary.Write(&shp, binary.BigEndian, int32(len(record)/2))
+		shp.Write(record)
+
+		binary.Write(&shx, binary.BigEndian, int32(offset/2))
+		binary.Write(&shx, binary.BigEndian, int32(len(record)/2))
+		offset += 8 + len(record)
+	}
+
+	files := []struct {
+		ext     string
+		content []byte
+	}{
+		{".shp", shp.Bytes()},
+		{".shx", shx.Bytes()},
+		{".dbf", dbfTable(features)},
+		{".prj", []byte(wgs84PRJ)},
+		{".cpg", []byte("UTF-8")},
+	}
+	for _, file := range files {
+		fw, err := zw.Create(name + file.ext)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(file.content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeShapeHeader writes the 100 byte header shared by .shp and .shx files;
+// length is the file length in bytes
+func writeShapeHeader(b *bytes.Buffer, length int, shapeType int32, min, max Position) {
+	binary.Write(b, binary.BigEndian, int32(9994))
+	b.Write(make([]byte, 20))
+	binary.Write(b, binary.BigEndian, int32(length/2))
+	binary.Write(b, binary.LittleEndian, int32(1000))
+	binary.Write(b, binary.LittleEndian, shapeType)
+	binary.Write(b, binary.LittleEndian, [4]float64{min.Lng(), min.Lat(), max.Lng(), max.Lat()})
+	b.Write(make([]byte, 32)) // Z and M ranges
+}
+
+// pointRecord encodes the content of a point record
+func pointRecord(p Position) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, shapePoint)
+	binary.Write(&b, binary.LittleEndian, [2]float64{p.Lng(), p.Lat()})
+	return b.Bytes()
+}
+
+// polygonRecord encodes the content of a polygon record. Shapefiles wind
+// outer rings clockwise and holes counterclockwise.
+func polygonRecord(polygon Polygon) []byte {
+	polygon = polygon.Oriented(true)
+	min, max := polygon.Bounds()
+
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, shapePolygon)
+	binary.Write(&b, binary.LittleEndian, [4]float64{min.Lng(), min.Lat(), max.Lng(), max.Lat()})
+
+	points := 0
+	for _, ring := range polygon {
+		points += len(ring)
+	}
+	binary.Write(&b, binary.LittleEndian, int32(len(polygon)))
+	binary.Write(&b, binary.LittleEndian, int32(points))
+
+	start := 0
+	for _, ring := range polygon {
+		binary.Write(&b, binary.LittleEndian, int32(start))
+		start += len(ring)
+	}
+	for _, ring := range polygon {
+		for _, p := range ring {
+			binary.Write(&b, binary.LittleEndian, [2]float64{p.Lng(), p.Lat()})
+		}
+	}
+	return b.Bytes()
+}
+
+// dbfTable encodes feature properties as a dBASE III table
+func dbfTable(features []*Feature) []byte {
+	fields := dbfFields(features)
+
+	recordLength := 1 // deletion flag
+	for _, field := range fields {
+		recordLength += field.length
+	}
+
+	var b bytes.Buffer
+	now := time.Now()
+	b.Write([]byte{0x03, byte(now.Year() - 1900), byte(now.Month()), byte(now.Day())})
+	binary.Write(&b, binary.LittleEndian, uint32(len(features)))
+	binary.Write(&b, binary.LittleEndian, uint16(32+32*len(fields)+1))
+	binary.Write(&b, binary.LittleEndian, uint16(recordLength))
+	b.Write(make([]byte, 20))
+
+	for _, field := range fields {
+		name := make([]byte, 11)
+		copy(name, field.name)
+		b.Write(name)
+		b.WriteByte(field.kind)
+		b.Write(make([]byte, 4))
+		b.WriteByte(byte(field.length))
+		b.WriteByte(byte(field.decimals))
+		b.Write(make([]byte, 14))
+	}
+	b.WriteByte(0x0D)
+
+	for _, f := range features {
+		b.WriteByte(' ')
+		for i, field := range fields {
+			var value interface{}
+			if i < len(f.Properties) {
+				value = f.Properties[i].Value
+			}
+			b.WriteString(dbfValue(field, value))
+		}
+	}
+	b.WriteByte(0x1A)
+
+	return b.Bytes()
+}
+
+// dbfFields derives the dBASE columns from the first feature's property
+// names and the values the features hold
+func dbfFields(features []*Feature) []dbfField {
+	if len(features) == 0 {
+		return []dbfField{{name: "id", kind: 'N', length: 19}}
+	}
+
+	fields := make([]dbfField, len(features[0].Properties))
+	for i, p := range features[0].Properties {
+		// dBASE column names are limited to 10 characters
+		name := p.Name
+		if len(name) > 10 {
+			name = name[:10]
+		}
+		fields[i] = dbfField{name: name, kind: 'C', length: 1}
+
+		for _, f := range features {
+			if i >= len(f.Properties) {
+				continue
+			}
+			switch value := f.Properties[i].Value.(type) {
+			case int64:
+				fields[i].kind, fields[i].length, fields[i].decimals = 'N', 19, 0
+			case float64:
+				fields[i].kind, fields[i].length, fields[i].decimals = 'N', 24, 6
+			case string:
+				if len(value) > fields[i].length {
+					fields[i].length = len(value)
+				}
+			}
+		}
+		if fields[i].kind == 'C' && fields[i].length > 254 {
+			fields[i].length = 254
+		}
+	}
+	return fields
+}
+
+// dbfValue formats a value to the fixed width of its column
+func dbfValue(field dbfField, value interface{}) string {
+	var text string
+	switch v := value.(type) {
+	case int64:
+		text = strconv.FormatInt(v, 10)
+	case float64:
+		text = strconv.FormatFloat(v, 'f', field.decimals, 64)
+	case string:
+		text = v
+	}
+
+	if len(text) > field.length {
+		text = text[:field.length]
+		// Do not leave half a UTF-8 character behind
+		for len(text) > 0 && !utf8.ValidString(text) {
+			text = text[:len(text)-1]
+		}
+	}
+
+	padding := string(bytes.Repeat([]byte{' '}, field.length-len(text)))
+	if field.kind == 'N' {
+		return padding + text
+	}
+	return text + padding
+}
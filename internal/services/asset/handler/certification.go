@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+	"github.com/gorilla/mux"
+)
+
+var certificationStages = []string{
+	string(domain.StageIkrar),
+	string(domain.StageAIWDeed),
+	string(domain.StageKUARegistration),
+	string(domain.StageBPNCertification),
+}
+
+var stepStatuses = []string{
+	string(domain.StepPending),
+	string(domain.StepInProgress),
+	string(domain.StepBlocked),
+	string(domain.StepCompleted),
+}
+
+// GetCertification handles retrieving the certification workflow of an asset
+func (h *Handler) GetCertification(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	workflow, err := h.service.GetCertification(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, workflow)
+}
+
+// StartCertification handles starting the certification of a land asset
+func (h *Handler) StartCertification(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.StartCertificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	for stage, plan := range req.Steps {
+		field := "steps." + string(stage)
+		v.In("steps", string(stage), certificationStages)
+		if plan != nil {
+			v.MaxLength(field+".responsible_name", plan.ResponsibleName, 255)
+		}
+	}
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	workflow, err := h.service.StartCertification(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, workflow)
+}
+
+// UpdateCertificationStep handles recording progress on a certification step
+func (h *Handler) UpdateCertificationStep(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	stage := mux.Vars(r)["stage"]
+
+	var req dto.CertificationStepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.In("stage", stage, certificationStages)
+	v.In("status", string(req.Status), stepStatuses[1:]) // steps never go back to pending
+	if req.ResponsibleName != nil {
+		v.MaxLength("responsible_name", *req.ResponsibleName, 255)
+	}
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	workflow, err := h.service.UpdateCertificationStep(r.Context(), middleware.ActorFromContext(r.Context()), id, domain.CertificationStage(stage), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, workflow)
+}
+
+// CancelCertification handles abandoning the certification of an asset
+func (h *Handler) CancelCertification(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.CancelCertificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("reason", req.Reason)
+	v.MaxLength("reason", req.Reason, 1000)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	workflow, err := h.service.CancelCertification(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, workflow)
+}
+
+// ListCertifications handles listing the land assets under certification
+// with the step each is stuck at
+func (h *Handler) ListCertifications(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := &dto.CertificationQuery{
+		Stage:   domain.CertificationStage(q.Get("stage")),
+		Status:  domain.StepStatus(q.Get("status")),
+		Overdue: q.Get("overdue") == "true",
+	}
+
+	v := validator.New()
+	v.In("stage", string(query.Stage), certificationStages)
+	v.In("status", string(query.Status), stepStatuses)
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	page, perPage := request.Pagination(r)
+	parcels, total, err := h.service.ListCertifications(r.Context(), middleware.ActorFromContext(r.Context()), query, page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, parcels, page, perPage, total)
+}
+
+// SummarizeCertifications handles counting the land assets at every
+// certification stage
+func (h *Handler) SummarizeCertifications(w http.ResponseWriter, r *http.Request) {
+	summaries, err := h.service.SummarizeCertifications(r.Context(), middleware.ActorFromContext(r.Context()))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, summaries)
+}
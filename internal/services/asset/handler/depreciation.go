@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+	"github.com/gorilla/mux"
+)
+
+var depreciationMethods = []string{
+	string(domain.DepreciationStraightLine),
+	string(domain.DepreciationDecliningBalance),
+}
+
+// maxUsefulLifeMonths bounds useful lives to 50 years
+const maxUsefulLifeMonths = 600
+
+// GetDepreciationSchedule handles retrieving the depreciation schedule of an
+// asset
+func (h *Handler) GetDepreciationSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	schedule, err := h.service.GetDepreciationSchedule(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, schedule)
+}
+
+// SetAssetDepreciation handles setting the depreciation policy of an asset
+func (h *Handler) SetAssetDepreciation(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.DepreciationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	validateDepreciation(v, req.Method, req.UsefulLifeMonths, req.RateBPS)
+	v.Min("salvage_value", req.SalvageValue, 0)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	schedule, err := h.service.SetAssetDepreciation(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, schedule)
+}
+
+// DeleteAssetDepreciation handles returning an asset to the depreciation
+// default of its type
+func (h *Handler) DeleteAssetDepreciation(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := h.service.DeleteAssetDepreciation(r.Context(), middleware.ActorFromContext(r.Context()), id); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// GetDepreciationDefaults handles listing the depreciation defaults of asset
+// types
+func (h *Handler) GetDepreciationDefaults(w http.ResponseWriter, r *http.Request) {
+	defaults, err := h.service.GetDepreciationDefaults(r.Context(), middleware.ActorFromContext(r.Context()))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, defaults)
+}
+
+// SaveDepreciationDefault handles setting the depreciation default of an
+// asset type
+func (h *Handler) SaveDepreciationDefault(w http.ResponseWriter, r *http.Request) {
+	assetType := mux.Vars(r)["type"]
+
+	var req dto.DepreciationDefaultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.In("type", assetType, assetTypes)
+	validateDepreciation(v, req.Method, req.UsefulLifeMonths, req.RateBPS)
+	v.Min("salvage_bps", int64(req.SalvageBPS), 0)
+	v.Max("salvage_bps", int64(req.SalvageBPS), domain.FullShareBPS-1)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	d, err := h.service.SaveDepreciationDefault(r.Context(), middleware.ActorFromContext(r.Context()), domain.AssetType(assetType), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, d)
+}
+
+// DeleteDepreciationDefault handles removing the depreciation default of an
+// asset type
+func (h *Handler) DeleteDepreciationDefault(w http.ResponseWriter, r *http.Request) {
+	assetType := mux.Vars(r)["type"]
+
+	v := validator.New()
+	v.In("type", assetType, assetTypes)
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	if err := h.service.DeleteDepreciationDefault(r.Context(), middleware.ActorFromContext(r.Context()), domain.AssetType(assetType)); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// validateDepreciation validates the fields shared by asset policies and
+// type defaults
+func validateDepreciation(v *validator.Validator, method domain.DepreciationMethod, lifeMonths, rateBPS int) {
+	v.Required("method", string(method))
+	v.In("method", string(method), depreciationMethods)
+	v.Min("useful_life_months", int64(lifeMonths), 1)
+	v.Max("useful_life_months", int64(lifeMonths), maxUsefulLifeMonths)
+	v.Min("rate_bps", int64(rateBPS), 0)
+	v.Max("rate_bps", int64(rateBPS), domain.FullShareBPS)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/services/asset/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+var expiryStatuses = []string{
+	repository.ExpiryStatusExpired,
+	repository.ExpiryStatusExpiring,
+}
+
+// maxComplianceDays bounds how far ahead the compliance dashboard looks
+const maxComplianceDays = 3650
+
+// GetExpiringDocuments handles listing the asset documents that have expired
+// or are expiring
+func (h *Handler) GetExpiringDocuments(w http.ResponseWriter, r *http.Request) {
+	query, err := complianceQuery(r.URL.Query())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	page, perPage := request.Pagination(r)
+	documents, total, err := h.service.GetExpiringDocuments(r.Context(), middleware.ActorFromContext(r.Context()), query, page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, documents, page, perPage, total)
+}
+
+// SummarizeCompliance handles counting the expired and expiring asset
+// documents of every tenant
+func (h *Handler) SummarizeCompliance(w http.ResponseWriter, r *http.Request) {
+	query, err := complianceQuery(r.URL.Query())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	totals, err := h.service.SummarizeCompliance(r.Context(), middleware.ActorFromContext(r.Context()), query)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, totals)
+}
+
+// GetExpiryAlerts handles listing the document expiry alerts sent
+func (h *Handler) GetExpiryAlerts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := &dto.ExpiryAlertQuery{}
+
+	if s := q.Get("asset_id"); s != "" {
+		assetID, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid asset_id", 400))
+			return
+		}
+		query.AssetID = assetID
+	}
+
+	tenantID, err := tenantParam(q)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	query.TenantID = tenantID
+
+	page, perPage := request.Pagination(r)
+	alerts, total, err := h.service.GetExpiryAlerts(r.Context(), middleware.ActorFromContext(r.Context()), query, page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, alerts, page, perPage, total)
+}
+
+// complianceQuery reads the filters of the compliance dashboard
+func complianceQuery(q url.Values) (*dto.ComplianceQuery, error) {
+	query := &dto.ComplianceQuery{Status: q.Get("status")}
+
+	v := validator.New()
+	v.In("status", query.Status, expiryStatuses)
+	if s := q.Get("within"); s != "" {
+		within, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errors.New(errors.ErrCodeBadRequest, "Invalid within", 400)
+		}
+		v.Min("within", int64(within), 1)
+		v.Max("within", int64(within), maxComplianceDays)
+		query.Within = within
+	}
+	if !v.IsValid() {
+		return nil, v.Error()
+	}
+
+	tenantID, err := tenantParam(q)
+	if err != nil {
+		return nil, err
+	}
+	query.TenantID = tenantID
+
+	return query, nil
+}
+
+// tenantParam reads the optional tenant_id filter
+func tenantParam(q url.Values) (*int64, error) {
+	s := q.Get("tenant_id")
+	if s == "" {
+		return nil, nil
+	}
+
+	tenantID, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid tenant_id", 400)
+	}
+	return &tenantID, nil
+}
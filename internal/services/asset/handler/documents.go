@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+// maxDocumentSize bounds the size of an attached document file (50 MB)
+const maxDocumentSize = 50 << 20
+
+// GetDocuments handles listing the documents of an asset
+func (h *Handler) GetDocuments(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	documents, err := h.service.GetDocuments(r.Context(), middleware.ActorFromContext(r.Context()), id, r.URL.Query().Get("type"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, documents)
+}
+
+// CreateDocument handles attaching a document to an asset
+func (h *Handler) CreateDocument(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeDocument(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	document, err := h.service.CreateDocument(r.Context(), middleware.ActorFromContext(r.Context()), id, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, document)
+}
+
+// UpdateDocument handles replacing a document's details
+func (h *Handler) UpdateDocument(w http.ResponseWriter, r *http.Request) {
+	id, documentID, err := documentPath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeDocument(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	document, err := h.service.UpdateDocument(r.Context(), middleware.ActorFromContext(r.Context()), id, documentID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, document)
+}
+
+// DeleteDocument handles removing a document from an asset
+func (h *Handler) DeleteDocument(w http.ResponseWriter, r *http.Request) {
+	id, documentID, err := documentPath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := h.service.DeleteDocument(r.Context(), middleware.ActorFromContext(r.Context()), id, documentID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// documentPath parses the asset and document IDs of a document route
+func documentPath(r *http.Request) (int64, int64, error) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	documentID, err := request.PathID(r, "documentID")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return id, documentID, nil
+}
+
+// decodeDocument decodes and validates an asset document request body
+func decodeDocument(r *http.Request) (*dto.DocumentRequest, error) {
+	var req dto.DocumentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400)
+	}
+
+	v := validator.New()
+	v.Required("document_type", req.DocumentType)
+	v.Matches("document_type", req.DocumentType, `^[a-z][a-z0-9_]{0,49}$`)
+	v.Required("document_name", req.DocumentName)
+	v.MaxLength("document_name", req.DocumentName, 255)
+	v.Required("file_url", req.FileURL)
+	if !validURL(req.FileURL) {
+		v.AddError("file_url", "must be an http or https URL")
+	}
+	v.Min("file_size", req.FileSize, 0)
+	v.Max("file_size", req.FileSize, maxDocumentSize)
+	v.MaxLength("mime_type", req.MimeType, 100)
+	v.MaxLength("notes", req.Notes, 2000)
+
+	if !v.IsValid() {
+		return nil, v.Error()
+	}
+
+	return &req, nil
+}
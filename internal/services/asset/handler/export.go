@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/services/asset/geo"
+	"github.com/akordium-id/waqfwise/internal/services/asset/service"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+// maxImportSize bounds an uploaded boundary file
+const maxImportSize = 10 << 20
+
+// Export handles downloading assets as a GeoJSON, KML or zipped Shapefile
+// feature collection
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := &dto.ExportQuery{
+		Type:   domain.AssetType(q.Get("type")),
+		Status: domain.AssetStatus(q.Get("status")),
+	}
+
+	v := validator.New()
+	v.In("type", string(query.Type), assetTypes)
+	v.In("status", string(query.Status), assetStatuses)
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	format, err := formatQuery(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if s := q.Get("campaign_id"); s != "" {
+		campaignID, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid campaign_id", 400))
+			return
+		}
+		query.CampaignID = campaignID
+	}
+
+	switch {
+	case q.Get("region") != "":
+		query.Region = []byte(q.Get("region"))
+	case q.Get("bbox") != "":
+		region, err := bboxQuery(q.Get("bbox"))
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		query.Region = region.GeoJSON()
+	}
+
+	file, err := h.service.ExportAssets(r.Context(), middleware.ActorFromContext(r.Context()), query, format)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	writeFile(w, file)
+}
+
+// ImportBoundaries handles creating or updating asset boundaries from an
+// uploaded GeoJSON or KML file (multipart field "file")
+func (h *Handler) ImportBoundaries(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize+1<<20)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "A GeoJSON or KML file is required in the file field", 400))
+		return
+	}
+	defer file.Close()
+
+	var format geo.Format
+	switch strings.ToLower(filepath.Ext(header.Filename)) {
+	case ".geojson", ".json":
+		format = geo.FormatGeoJSON
+	case ".kml":
+		format = geo.FormatKML
+	default:
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "file must be a .geojson, .json or .kml file", 400))
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImportSize+1))
+	if err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Failed to read uploaded file", 400))
+		return
+	}
+	if len(data) > maxImportSize {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "file must not exceed 10 MB", 400))
+		return
+	}
+
+	result, err := h.service.ImportBoundaries(r.Context(), middleware.ActorFromContext(r.Context()), format, data)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
+// formatQuery parses the export format, defaulting to GeoJSON
+func formatQuery(r *http.Request) (geo.Format, error) {
+	switch format := geo.Format(r.URL.Query().Get("format")); format {
+	case "", geo.FormatGeoJSON:
+		return geo.FormatGeoJSON, nil
+	case geo.FormatKML, geo.FormatShapefile:
+		return format, nil
+	}
+	return "", errors.New(errors.ErrCodeBadRequest, "format must be geojson, kml or shp", 400)
+}
+
+// bboxQuery parses a minLng,minLat,maxLng,maxLat bounding box
+func bboxQuery(s string) (geo.Polygon, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return nil, errors.New(errors.ErrCodeBadRequest, "bbox must be minLng,minLat,maxLng,maxLat", 400)
+	}
+
+	var values [4]float64
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, errors.New(errors.ErrCodeBadRequest, "bbox must be minLng,minLat,maxLng,maxLat", 400)
+		}
+		values[i] = value
+	}
+
+	box, err := geo.BBox(values[0], values[1], values[2], values[3])
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeValidation, "bbox: "+err.Error(), 400)
+	}
+	return box, nil
+}
+
+// writeFile sends a rendered export as a download
+func writeFile(w http.ResponseWriter, file *service.File) {
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Name))
+	w.Header().Set("Content-Length", strconv.Itoa(len(file.Content)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(file.Content)
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+const (
+	defaultNearbyRadius = 1000  // meters
+	maxNearbyRadius     = 50000 // meters
+	defaultNearbyLimit  = 50
+	maxNearbyLimit      = 200
+)
+
+// GetBoundary handles retrieval of an asset's boundary
+func (h *Handler) GetBoundary(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	boundary, err := h.service.GetBoundary(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, boundary)
+}
+
+// SaveBoundary handles saving an asset's surveyed boundary
+func (h *Handler) SaveBoundary(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.BoundaryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	if len(req.Boundary) == 0 || string(req.Boundary) == "null" {
+		v.AddError("boundary", "is required")
+	}
+	v.MaxLength("land_use", req.LandUse, 100)
+	v.MaxLength("zoning_type", req.ZoningType, 100)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	boundary, err := h.service.SaveBoundary(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, boundary)
+}
+
+// DeleteBoundary handles removal of an asset's boundary
+func (h *Handler) DeleteBoundary(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := h.service.DeleteBoundary(r.Context(), middleware.ActorFromContext(r.Context()), id); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Nearby handles finding assets around a point
+func (h *Handler) Nearby(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := &dto.NearbyQuery{
+		Type:   domain.AssetType(q.Get("type")),
+		Status: domain.AssetStatus(q.Get("status")),
+		Radius: defaultNearbyRadius,
+		Limit:  defaultNearbyLimit,
+	}
+
+	v := validator.New()
+	v.Required("lat", q.Get("lat"))
+	v.Required("lng", q.Get("lng"))
+	v.In("type", string(query.Type), assetTypes)
+	v.In("status", string(query.Status), assetStatuses)
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	var err error
+	if query.Latitude, err = strconv.ParseFloat(q.Get("lat"), 64); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid lat", 400))
+		return
+	}
+	if query.Longitude, err = strconv.ParseFloat(q.Get("lng"), 64); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid lng", 400))
+		return
+	}
+	if s := q.Get("radius"); s != "" {
+		if query.Radius, err = strconv.ParseFloat(s, 64); err != nil {
+			response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid radius", 400))
+			return
+		}
+	}
+	if s := q.Get("limit"); s != "" {
+		if query.Limit, err = strconv.Atoi(s); err != nil {
+			response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid limit", 400))
+			return
+		}
+	}
+
+	validateCoordinates(v, &query.Latitude, &query.Longitude)
+	if query.Radius <= 0 || query.Radius > maxNearbyRadius {
+		v.AddError("radius", "must be between 0 and 50000 meters")
+	}
+	if query.Limit < 1 || query.Limit > maxNearbyLimit {
+		v.AddError("limit", "must be between 1 and 200")
+	}
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	assets, err := h.service.Nearby(r.Context(), middleware.ActorFromContext(r.Context()), query)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, assets)
+}
+
+// Within handles listing the assets inside a region
+func (h *Handler) Within(w http.ResponseWriter, r *http.Request) {
+	var req dto.WithinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	if len(req.Region) == 0 || string(req.Region) == "null" {
+		v.AddError("region", "is required")
+	}
+	v.In("type", string(req.Type), assetTypes)
+	v.In("status", string(req.Status), assetStatuses)
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	query := &dto.ListAssetsQuery{
+		CampaignID: req.CampaignID,
+		Type:       req.Type,
+		Status:     req.Status,
+		Region:     req.Region,
+	}
+	query.Page, query.PerPage = request.Pagination(r)
+
+	assets, total, err := h.service.List(r.Context(), middleware.ActorFromContext(r.Context()), query)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, assets, query.Page, query.PerPage, total)
+}
+
+// GetOverlaps handles listing the assets whose boundaries overlap an asset's
+func (h *Handler) GetOverlaps(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	overlaps, err := h.service.GetOverlaps(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, overlaps)
+}
+
+// ListOverlaps handles the admin listing of overlapping boundaries
+func (h *Handler) ListOverlaps(w http.ResponseWriter, r *http.Request) {
+	page, perPage := request.Pagination(r)
+	overlaps, total, err := h.service.ListOverlaps(r.Context(), middleware.ActorFromContext(r.Context()), page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, overlaps, page, perPage, total)
+}
+
+// ListAreaMismatches handles the admin listing of assets whose declared
+// area differs from their boundary
+func (h *Handler) ListAreaMismatches(w http.ResponseWriter, r *http.Request) {
+	page, perPage := request.Pagination(r)
+	mismatches, total, err := h.service.ListAreaMismatches(r.Context(), middleware.ActorFromContext(r.Context()), page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, mismatches, page, perPage, total)
+}
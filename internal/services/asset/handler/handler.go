@@ -0,0 +1,336 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/services/asset/service"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+	"github.com/gorilla/mux"
+)
+
+var assetTypes = []string{
+	string(domain.AssetTypeLand),
+	string(domain.AssetTypeBuilding),
+	string(domain.AssetTypeVehicle),
+	string(domain.AssetTypeEquipment),
+	string(domain.AssetTypeOther),
+}
+
+var assetStatuses = []string{
+	string(domain.AssetStatusActive),
+	string(domain.AssetStatusInactive),
+	string(domain.AssetStatusUnderMaint),
+	string(domain.AssetStatusDisposed),
+}
+
+var areaUnits = []string{"sqm", "hectare"}
+
+// Handler handles HTTP requests for wakaf assets
+type Handler struct {
+	service service.Service
+	auth    *middleware.Auth
+}
+
+// New creates a new asset handler
+func New(service service.Service, auth *middleware.Auth) *Handler {
+	return &Handler{
+		service: service,
+		auth:    auth,
+	}
+}
+
+// Create handles asset registration
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateAssetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Min("campaign_id", req.CampaignID, 1)
+	v.Required("type", string(req.Type))
+	v.In("type", string(req.Type), assetTypes)
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, 255)
+	v.MaxLength("description", req.Description, 10000)
+	v.MaxLength("location", req.Location, 500)
+	validateCoordinates(v, req.Latitude, req.Longitude)
+	validateArea(v, req.Area, req.AreaUnit)
+	v.Min("purchase_value", req.PurchaseValue, 0)
+	v.Required("acquisition_date", req.AcquisitionDate)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	asset, err := h.service.Create(r.Context(), middleware.ActorFromContext(r.Context()), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, asset)
+}
+
+// Update handles asset update
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.UpdateAssetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	if req.Type != nil {
+		v.In("type", string(*req.Type), assetTypes)
+	}
+	if req.Name != nil {
+		v.Required("name", *req.Name)
+		v.MaxLength("name", *req.Name, 255)
+	}
+	if req.Description != nil {
+		v.MaxLength("description", *req.Description, 10000)
+	}
+	if req.Location != nil {
+		v.MaxLength("location", *req.Location, 500)
+	}
+	validateCoordinates(v, req.Latitude, req.Longitude)
+	if req.AreaUnit != nil {
+		v.In("area_unit", *req.AreaUnit, areaUnits)
+	}
+	if req.Area != nil && *req.Area <= 0 {
+		v.AddError("area", "must be greater than 0")
+	}
+	if req.PurchaseValue != nil {
+		v.Min("purchase_value", *req.PurchaseValue, 0)
+	}
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	asset, err := h.service.Update(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, asset)
+}
+
+// UpdateStatus handles asset status changes
+func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.UpdateStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("status", string(req.Status))
+	v.In("status", string(req.Status), assetStatuses)
+	v.MaxLength("reason", req.Reason, 2000)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	asset, err := h.service.UpdateStatus(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, asset)
+}
+
+// GetByID handles asset retrieval
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	asset, err := h.service.GetByID(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, asset)
+}
+
+// List handles asset listing
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := &dto.ListAssetsQuery{
+		Type:   domain.AssetType(q.Get("type")),
+		Status: domain.AssetStatus(q.Get("status")),
+	}
+	query.Page, query.PerPage = request.Pagination(r)
+
+	v := validator.New()
+	v.In("type", string(query.Type), assetTypes)
+	v.In("status", string(query.Status), assetStatuses)
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	if s := q.Get("campaign_id"); s != "" {
+		campaignID, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid campaign_id", 400))
+			return
+		}
+		query.CampaignID = campaignID
+	}
+
+	assets, total, err := h.service.List(r.Context(), middleware.ActorFromContext(r.Context()), query)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, assets, query.Page, query.PerPage, total)
+}
+
+// GetStatusHistory handles asset status history listing
+func (h *Handler) GetStatusHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	history, err := h.service.GetStatusHistory(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, history)
+}
+
+// RegisterRoutes registers HTTP routes under the assets prefix. Everything
+// but the map tiles needs a token.
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	readers := middleware.RequireRole(domain.RoleAdmin, domain.RoleNazir, domain.RoleAuditor)
+	managers := middleware.RequireRole(domain.RoleAdmin, domain.RoleNazir)
+	admins := middleware.RequireRole(domain.RoleAdmin)
+
+	// The asset map is public
+	r.HandleFunc("/tiles/{z:[0-9]+}/{x:[0-9]+}/{y:[0-9]+}.mvt", h.GetTile).Methods("GET")
+
+	routes := r.NewRoute().Subrouter()
+	routes.Use(h.auth.Authenticate)
+	routes.Handle("", readers(http.HandlerFunc(h.List))).Methods("GET")
+	routes.Handle("", managers(http.HandlerFunc(h.Create))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}", readers(http.HandlerFunc(h.GetByID))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}", managers(http.HandlerFunc(h.Update))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/status", managers(http.HandlerFunc(h.UpdateStatus))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/status-history", readers(http.HandlerFunc(h.GetStatusHistory))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/documents", readers(http.HandlerFunc(h.GetDocuments))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/documents", managers(http.HandlerFunc(h.CreateDocument))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/documents/{documentID:[0-9]+}", managers(http.HandlerFunc(h.UpdateDocument))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/documents/{documentID:[0-9]+}", managers(http.HandlerFunc(h.DeleteDocument))).Methods("DELETE")
+	routes.Handle("/{id:[0-9]+}/valuations", readers(http.HandlerFunc(h.GetValuations))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/valuations", managers(http.HandlerFunc(h.CreateValuation))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/valuations/timeline", readers(http.HandlerFunc(h.GetValuationTimeline))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/valuations/{valuationID:[0-9]+}/review", admins(http.HandlerFunc(h.ReviewValuation))).Methods("POST")
+	routes.Handle("/valuations/pending", admins(http.HandlerFunc(h.GetPendingValuations))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/boundary", readers(http.HandlerFunc(h.GetBoundary))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/boundary", managers(http.HandlerFunc(h.SaveBoundary))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/boundary", managers(http.HandlerFunc(h.DeleteBoundary))).Methods("DELETE")
+	routes.Handle("/{id:[0-9]+}/boundary/overlaps", readers(http.HandlerFunc(h.GetOverlaps))).Methods("GET")
+	routes.Handle("/nearby", readers(http.HandlerFunc(h.Nearby))).Methods("GET")
+	routes.Handle("/within", readers(http.HandlerFunc(h.Within))).Methods("POST")
+	routes.Handle("/boundaries/overlaps", admins(http.HandlerFunc(h.ListOverlaps))).Methods("GET")
+	routes.Handle("/boundaries/area-mismatches", admins(http.HandlerFunc(h.ListAreaMismatches))).Methods("GET")
+	routes.Handle("/boundaries/import", managers(http.HandlerFunc(h.ImportBoundaries))).Methods("POST")
+	routes.Handle("/export", readers(http.HandlerFunc(h.Export))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/leases", readers(http.HandlerFunc(h.GetLeases))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/leases", managers(http.HandlerFunc(h.CreateLease))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/leases/{leaseID:[0-9]+}", readers(http.HandlerFunc(h.GetLease))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/leases/{leaseID:[0-9]+}/terminate", managers(http.HandlerFunc(h.TerminateLease))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/leases/{leaseID:[0-9]+}/invoices/{invoiceID:[0-9]+}/payments", managers(http.HandlerFunc(h.RecordLeasePayment))).Methods("POST")
+	routes.Handle("/leases/overdue", readers(http.HandlerFunc(h.GetOverdueInvoices))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/maintenance-schedules", readers(http.HandlerFunc(h.GetSchedules))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/maintenance-schedules", managers(http.HandlerFunc(h.CreateSchedule))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/maintenance-schedules/{scheduleID:[0-9]+}", managers(http.HandlerFunc(h.UpdateSchedule))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/work-orders", readers(http.HandlerFunc(h.GetWorkOrders))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/work-orders", managers(http.HandlerFunc(h.CreateWorkOrder))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/work-orders/{orderID:[0-9]+}", readers(http.HandlerFunc(h.GetWorkOrder))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/work-orders/{orderID:[0-9]+}", managers(http.HandlerFunc(h.UpdateWorkOrder))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/work-orders/{orderID:[0-9]+}/complete", managers(http.HandlerFunc(h.CompleteWorkOrder))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/work-orders/{orderID:[0-9]+}/cancel", managers(http.HandlerFunc(h.CancelWorkOrder))).Methods("POST")
+	routes.Handle("/maintenance/due", readers(http.HandlerFunc(h.GetDueMaintenance))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/depreciation", readers(http.HandlerFunc(h.GetDepreciationSchedule))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/depreciation", managers(http.HandlerFunc(h.SetAssetDepreciation))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/depreciation", managers(http.HandlerFunc(h.DeleteAssetDepreciation))).Methods("DELETE")
+	routes.Handle("/depreciation/defaults", readers(http.HandlerFunc(h.GetDepreciationDefaults))).Methods("GET")
+	routes.Handle("/depreciation/defaults/{type}", admins(http.HandlerFunc(h.SaveDepreciationDefault))).Methods("PUT")
+	routes.Handle("/depreciation/defaults/{type}", admins(http.HandlerFunc(h.DeleteDepreciationDefault))).Methods("DELETE")
+	routes.Handle("/{id:[0-9]+}/certification", readers(http.HandlerFunc(h.GetCertification))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/certification", managers(http.HandlerFunc(h.StartCertification))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/certification/steps/{stage}", managers(http.HandlerFunc(h.UpdateCertificationStep))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/certification/cancel", managers(http.HandlerFunc(h.CancelCertification))).Methods("POST")
+	routes.Handle("/certifications", readers(http.HandlerFunc(h.ListCertifications))).Methods("GET")
+	routes.Handle("/certifications/summary", readers(http.HandlerFunc(h.SummarizeCertifications))).Methods("GET")
+	routes.Handle("/compliance/documents", readers(http.HandlerFunc(h.GetExpiringDocuments))).Methods("GET")
+	routes.Handle("/compliance/summary", readers(http.HandlerFunc(h.SummarizeCompliance))).Methods("GET")
+	routes.Handle("/compliance/alerts", readers(http.HandlerFunc(h.GetExpiryAlerts))).Methods("GET")
+}
+
+// validateCoordinates checks WGS84 latitude and longitude ranges
+func validateCoordinates(v *validator.Validator, latitude, longitude *float64) {
+	if latitude != nil && (*latitude < -90 || *latitude > 90) {
+		v.AddError("latitude", "must be between -90 and 90")
+	}
+	if longitude != nil && (*longitude < -180 || *longitude > 180) {
+		v.AddError("longitude", "must be between -180 and 180")
+	}
+}
+
+// validateArea checks a declared area is positive and has a known unit
+func validateArea(v *validator.Validator, area *float64, unit string) {
+	if area != nil && *area <= 0 {
+		v.AddError("area", "must be greater than 0")
+	}
+	v.In("area_unit", unit, areaUnits)
+}
+
+// validURL checks an optional URL is an absolute http or https URL
+func validURL(value string) bool {
+	if value == "" {
+		return true
+	}
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+var leaseStatuses = []string{
+	string(domain.LeaseStatusActive),
+	string(domain.LeaseStatusEnded),
+	string(domain.LeaseStatusTerminated),
+}
+
+var billingCycles = []string{
+	string(domain.BillingMonthly),
+	string(domain.BillingQuarterly),
+	string(domain.BillingSemiannual),
+	string(domain.BillingYearly),
+}
+
+var leasePaymentMethods = []string{
+	domain.LeasePaymentTransfer,
+	domain.LeasePaymentCash,
+	domain.LeasePaymentQRIS,
+}
+
+// GetLeases handles listing the leases of an asset
+func (h *Handler) GetLeases(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	status := domain.LeaseStatus(r.URL.Query().Get("status"))
+	v := validator.New()
+	v.In("status", string(status), leaseStatuses)
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	page, perPage := request.Pagination(r)
+	leases, total, err := h.service.GetLeases(r.Context(), middleware.ActorFromContext(r.Context()), id, status, page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, leases, page, perPage, total)
+}
+
+// GetLease handles retrieving a lease with its invoices and payments
+func (h *Handler) GetLease(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	leaseID, err := request.PathID(r, "leaseID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	lease, err := h.service.GetLease(r.Context(), middleware.ActorFromContext(r.Context()), id, leaseID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, lease)
+}
+
+// CreateLease handles registering a lease contract of an asset
+func (h *Handler) CreateLease(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.LeaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("lessee_name", req.LesseeName)
+	v.MaxLength("lessee_name", req.LesseeName, 255)
+	v.MaxLength("lessee_id_number", req.LesseeIDNumber, 50)
+	v.Email("lessee_email", req.LesseeEmail)
+	v.Phone("lessee_phone", req.LesseePhone)
+	v.MaxLength("lessee_address", req.LesseeAddress, 2000)
+	v.Required("start_date", req.StartDate)
+	v.Required("end_date", req.EndDate)
+	v.Min("rent_amount", req.RentAmount, 1)
+	v.Required("billing_cycle", string(req.BillingCycle))
+	v.In("billing_cycle", string(req.BillingCycle), billingCycles)
+	v.Min("payment_term_days", int64(req.PaymentTermDays), 0)
+	v.Max("payment_term_days", int64(req.PaymentTermDays), 90)
+	v.Min("escalation_bps", int64(req.EscalationBPS), 0)
+	v.Max("escalation_bps", int64(req.EscalationBPS), domain.FullShareBPS)
+	v.Min("escalation_months", int64(req.EscalationMonths), 0)
+	v.Max("escalation_months", int64(req.EscalationMonths), 120)
+	v.Min("deposit_amount", req.DepositAmount, 0)
+	v.MaxLength("notes", req.Notes, 2000)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	lease, err := h.service.CreateLease(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, lease)
+}
+
+// TerminateLease handles ending a lease early
+func (h *Handler) TerminateLease(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	leaseID, err := request.PathID(r, "leaseID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.TerminateLeaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("terminated_at", req.TerminatedAt)
+	v.Required("reason", req.Reason)
+	v.MaxLength("reason", req.Reason, 2000)
+	v.In("deposit_status", string(req.DepositStatus), []string{
+		string(domain.DepositStatusHeld),
+		string(domain.DepositStatusReturned),
+		string(domain.DepositStatusForfeited),
+	})
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	lease, err := h.service.TerminateLease(r.Context(), middleware.ActorFromContext(r.Context()), id, leaseID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, lease)
+}
+
+// RecordLeasePayment handles recording rent received against an invoice
+func (h *Handler) RecordLeasePayment(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	leaseID, err := request.PathID(r, "leaseID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	invoiceID, err := request.PathID(r, "invoiceID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.LeasePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Min("amount", req.Amount, 1)
+	v.Required("paid_at", req.PaidAt)
+	v.Required("method", req.Method)
+	v.In("method", req.Method, leasePaymentMethods)
+	v.MaxLength("reference", req.Reference, 255)
+	v.MaxLength("notes", req.Notes, 2000)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	payment, err := h.service.RecordLeasePayment(r.Context(), middleware.ActorFromContext(r.Context()), id, leaseID, invoiceID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, payment)
+}
+
+// GetOverdueInvoices handles listing rent invoices past their due date
+func (h *Handler) GetOverdueInvoices(w http.ResponseWriter, r *http.Request) {
+	page, perPage := request.Pagination(r)
+	invoices, total, err := h.service.GetOverdueInvoices(r.Context(), middleware.ActorFromContext(r.Context()), page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, invoices, page, perPage, total)
+}
@@ -0,0 +1,359 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+var workOrderStatuses = []string{
+	string(domain.WorkOrderStatusOpen),
+	string(domain.WorkOrderStatusCompleted),
+	string(domain.WorkOrderStatusCancelled),
+}
+
+// maxWorkOrderPhotos bounds the photos attached to one work order
+const maxWorkOrderPhotos = 20
+
+// GetSchedules handles listing the maintenance schedules of an asset
+func (h *Handler) GetSchedules(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	schedules, err := h.service.GetSchedules(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, schedules)
+}
+
+// CreateSchedule handles setting up recurring maintenance of an asset
+func (h *Handler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	validateSchedule(v, &req)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	schedule, err := h.service.CreateSchedule(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, schedule)
+}
+
+// UpdateSchedule handles changing or pausing a maintenance schedule
+func (h *Handler) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	scheduleID, err := request.PathID(r, "scheduleID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	validateSchedule(v, &req)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	schedule, err := h.service.UpdateSchedule(r.Context(), middleware.ActorFromContext(r.Context()), id, scheduleID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, schedule)
+}
+
+// GetDueMaintenance handles listing maintenance coming due without a work order
+func (h *Handler) GetDueMaintenance(w http.ResponseWriter, r *http.Request) {
+	page, perPage := request.Pagination(r)
+	due, total, err := h.service.GetDueMaintenance(r.Context(), middleware.ActorFromContext(r.Context()), page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, due, page, perPage, total)
+}
+
+// GetWorkOrders handles listing the work orders of an asset
+func (h *Handler) GetWorkOrders(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	status := domain.WorkOrderStatus(r.URL.Query().Get("status"))
+	v := validator.New()
+	v.In("status", string(status), workOrderStatuses)
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	page, perPage := request.Pagination(r)
+	orders, total, err := h.service.GetWorkOrders(r.Context(), middleware.ActorFromContext(r.Context()), id, status, page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, orders, page, perPage, total)
+}
+
+// GetWorkOrder handles retrieving a work order
+func (h *Handler) GetWorkOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	orderID, err := request.PathID(r, "orderID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	order, err := h.service.GetWorkOrder(r.Context(), middleware.ActorFromContext(r.Context()), id, orderID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, order)
+}
+
+// CreateWorkOrder handles opening a work order, which puts the asset under
+// maintenance
+func (h *Handler) CreateWorkOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.WorkOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	if req.ScheduleID == nil {
+		v.Required("title", req.Title)
+	}
+	validateWorkOrder(v, &req)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	order, err := h.service.CreateWorkOrder(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, order)
+}
+
+// UpdateWorkOrder handles editing an open work order
+func (h *Handler) UpdateWorkOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	orderID, err := request.PathID(r, "orderID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.WorkOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("title", req.Title)
+	if req.ScheduleID != nil {
+		v.AddError("schedule_id", "cannot be changed")
+	}
+	validateWorkOrder(v, &req)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	order, err := h.service.UpdateWorkOrder(r.Context(), middleware.ActorFromContext(r.Context()), id, orderID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, order)
+}
+
+// CompleteWorkOrder handles signing off finished maintenance work
+func (h *Handler) CompleteWorkOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	orderID, err := request.PathID(r, "orderID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.CompleteWorkOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Min("actual_cost", req.ActualCost, 0)
+	v.Required("completed_on", req.CompletedOn)
+	v.MaxLength("notes", req.Notes, 2000)
+	validatePhotos(v, req.PhotoURLs)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	order, err := h.service.CompleteWorkOrder(r.Context(), middleware.ActorFromContext(r.Context()), id, orderID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, order)
+}
+
+// CancelWorkOrder handles dropping an open work order
+func (h *Handler) CancelWorkOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	orderID, err := request.PathID(r, "orderID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.CancelWorkOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("reason", req.Reason)
+	v.MaxLength("reason", req.Reason, 2000)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	order, err := h.service.CancelWorkOrder(r.Context(), middleware.ActorFromContext(r.Context()), id, orderID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, order)
+}
+
+// validateSchedule validates a maintenance schedule request
+func validateSchedule(v *validator.Validator, req *dto.ScheduleRequest) {
+	v.Required("title", req.Title)
+	v.MaxLength("title", req.Title, 255)
+	v.MaxLength("description", req.Description, 2000)
+	v.Min("interval_months", int64(req.IntervalMonths), 1)
+	v.Max("interval_months", int64(req.IntervalMonths), 120)
+	v.Required("next_due_date", req.NextDueDate)
+	v.Min("estimated_cost", req.EstimatedCost, 0)
+}
+
+// validateWorkOrder validates the editable details of a work order
+func validateWorkOrder(v *validator.Validator, req *dto.WorkOrderRequest) {
+	v.MaxLength("title", req.Title, 255)
+	v.MaxLength("description", req.Description, 2000)
+	v.MaxLength("vendor", req.Vendor, 255)
+	v.MaxLength("vendor_contact", req.VendorContact, 255)
+	v.Min("estimated_cost", req.EstimatedCost, 0)
+	validatePhotos(v, req.PhotoURLs)
+}
+
+// validatePhotos checks that work order photos are http or https URLs
+func validatePhotos(v *validator.Validator, urls []string) {
+	if len(urls) > maxWorkOrderPhotos {
+		v.AddError("photo_urls", fmt.Sprintf("must have at most %d photos", maxWorkOrderPhotos))
+		return
+	}
+	for _, url := range urls {
+		if !validURL(url) {
+			v.AddError("photo_urls", "must be http or https URLs")
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/gorilla/mux"
+)
+
+// GetTile handles a public Mapbox Vector Tile of the asset map. Tiles carry
+// the asset map version as their ETag, so clients revalidate cheaply and
+// get a new tile as soon as an asset changes.
+func (h *Handler) GetTile(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	z, errZ := strconv.Atoi(vars["z"])
+	x, errX := strconv.Atoi(vars["x"])
+	y, errY := strconv.Atoi(vars["y"])
+	if errZ != nil || errX != nil || errY != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid tile coordinates", 400))
+		return
+	}
+
+	etag, err := h.service.TileETag(r.Context())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=60, must-revalidate")
+	if r.Header.Get("If-None-Match") == etag {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	tile, err := h.service.GetTile(r.Context(), z, x, y)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.mapbox-vector-tile")
+	w.Header().Set("ETag", tile.ETag)
+	w.Header().Set("Content-Length", strconv.Itoa(len(tile.Content)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(tile.Content)
+}
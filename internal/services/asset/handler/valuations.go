@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+var valuationMethods = []string{
+	domain.ValuationMethodMarketValue,
+	domain.ValuationMethodIncomeApproach,
+	domain.ValuationMethodCostApproach,
+}
+
+var valuationStatuses = []string{
+	string(domain.ValuationStatusPending),
+	string(domain.ValuationStatusApproved),
+	string(domain.ValuationStatusRejected),
+}
+
+// GetValuations handles listing the valuation history of an asset
+func (h *Handler) GetValuations(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	status := domain.ValuationStatus(r.URL.Query().Get("status"))
+	v := validator.New()
+	v.In("status", string(status), valuationStatuses)
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	page, perPage := request.Pagination(r)
+	valuations, total, err := h.service.GetValuations(r.Context(), middleware.ActorFromContext(r.Context()), id, status, page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, valuations, page, perPage, total)
+}
+
+// GetValuationTimeline handles the approved valuation timeline of an asset
+func (h *Handler) GetValuationTimeline(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	timeline, err := h.service.GetValuationTimeline(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, timeline)
+}
+
+// GetPendingValuations handles the admin queue of valuations awaiting approval
+func (h *Handler) GetPendingValuations(w http.ResponseWriter, r *http.Request) {
+	page, perPage := request.Pagination(r)
+	valuations, total, err := h.service.GetPendingValuations(r.Context(), middleware.ActorFromContext(r.Context()), page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, valuations, page, perPage, total)
+}
+
+// CreateValuation handles recording an asset valuation
+func (h *Handler) CreateValuation(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.ValuationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Min("valuation_value", req.ValuationValue, 0)
+	v.Required("valuation_date", req.ValuationDate)
+	v.Required("valued_by", req.ValuedBy)
+	v.MaxLength("valued_by", req.ValuedBy, 255)
+	v.Required("method", req.Method)
+	v.In("method", req.Method, valuationMethods)
+	v.MaxLength("notes", req.Notes, 2000)
+	if !validURL(req.DocumentURL) {
+		v.AddError("document_url", "must be an http or https URL")
+	}
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	valuation, err := h.service.CreateValuation(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, valuation)
+}
+
+// ReviewValuation handles an admin's approval or rejection of a valuation
+func (h *Handler) ReviewValuation(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	valuationID, err := request.PathID(r, "valuationID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.ValuationReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("decision", string(req.Decision))
+	v.In("decision", string(req.Decision), []string{
+		string(domain.ValuationStatusApproved),
+		string(domain.ValuationStatusRejected),
+	})
+	v.MaxLength("reason", req.Reason, 2000)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	valuation, err := h.service.ReviewValuation(r.Context(), middleware.ActorFromContext(r.Context()), id, valuationID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, valuation)
+}
@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/lib/pq"
+)
+
+// CertificationFilter filters the certification dashboard; empty fields
+// match everything
+type CertificationFilter struct {
+	Stage   domain.CertificationStage
+	Status  domain.StepStatus
+	Overdue bool      // only steps past their due date as of Day
+	Day     time.Time // today
+	NazirID int64
+}
+
+// StuckParcel is a land asset under certification with the step it is at
+type StuckParcel struct {
+	WorkflowID       int64                     `json:"workflow_id"`
+	AssetID          int64                     `json:"asset_id"`
+	AssetName        string                    `json:"asset_name"`
+	Location         string                    `json:"location,omitempty"`
+	Step             *domain.CertificationStep `json:"step"`
+	DaysAtStep       int                       `json:"days_at_step"`
+	DaysOverdue      int                       `json:"days_overdue,omitempty"`
+	MissingDocuments []string                  `json:"missing_documents"`
+
+	DocumentTypes []string `json:"-"` // document types the asset has
+}
+
+// StageSummary counts the parcels whose certification is at a stage
+type StageSummary struct {
+	Stage   domain.CertificationStage `json:"stage"`
+	Parcels int                       `json:"parcels"`
+	Blocked int                       `json:"blocked"`
+	Overdue int                       `json:"overdue"`
+}
+
+const workflowColumns = `
+	id, asset_id, status, COALESCE(notes, ''), started_by, completed_at, COALESCE(cancel_reason, ''),
+	created_at, updated_at
+`
+
+const stepColumns = `
+	id, workflow_id, asset_id, stage, sequence, status, responsible_user_id, COALESCE(responsible_name, ''),
+	due_date, started_at, completed_at, COALESCE(notes, ''), updated_by, updated_at
+`
+
+// currentStepJoin joins the first step not completed of workflow w as cs
+const currentStepJoin = `
+	JOIN LATERAL (
+		SELECT ` + stepColumns + ` FROM certification_steps
+		WHERE workflow_id = w.id AND status <> 'completed'
+		ORDER BY sequence LIMIT 1
+	) cs ON TRUE
+`
+
+// CreateCertification starts a certification workflow of an asset with its
+// steps. An asset has at most one workflow that is not cancelled.
+func (r *repository) CreateCertification(ctx context.Context, workflow *domain.CertificationWorkflow) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO certification_workflows (asset_id, status, notes, started_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		RETURNING id
+	`, workflow.AssetID, workflow.Status, workflow.Notes, workflow.StartedBy, now).Scan(&workflow.ID)
+	if isUniqueViolation(err) {
+		return errors.New(errors.ErrCodeConflict, "Asset already has a certification workflow", 409)
+	}
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create certification workflow", 500)
+	}
+
+	query := `
+		INSERT INTO certification_steps (
+			workflow_id, asset_id, stage, sequence, status, responsible_user_id, responsible_name, due_date,
+			started_at, notes, updated_by, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11, $12)
+		RETURNING id
+	`
+
+	for _, step := range workflow.Steps {
+		step.WorkflowID = workflow.ID
+		step.AssetID = workflow.AssetID
+		if err := tx.QueryRowContext(ctx, query,
+			step.WorkflowID, step.AssetID, step.Stage, step.Sequence, step.Status, step.ResponsibleUserID,
+			step.ResponsibleName, step.DueDate, step.StartedAt, step.Notes, step.UpdatedBy, now,
+		).Scan(&step.ID); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create certification step", 500)
+		}
+		step.UpdatedAt = now
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit certification workflow", 500)
+	}
+
+	workflow.CreatedAt = now
+	workflow.UpdatedAt = now
+	return nil
+}
+
+// SaveCertification saves the status of a workflow and the given steps of
+// it. A workflow changed since it was loaded is refused with a conflict.
+func (r *repository) SaveCertification(ctx context.Context, workflow *domain.CertificationWorkflow, steps ...*domain.CertificationStep) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE certification_workflows
+		SET status = $3, completed_at = $4, cancel_reason = NULLIF($5, ''), updated_at = $6
+		WHERE id = $1 AND updated_at = $2
+	`, workflow.ID, workflow.UpdatedAt, workflow.Status, workflow.CompletedAt, workflow.CancelReason, now)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update certification workflow", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Certification workflow was changed by another request", 409)
+	}
+
+	query := `
+		UPDATE certification_steps
+		SET status = $2, responsible_user_id = $3, responsible_name = NULLIF($4, ''), due_date = $5,
+		    started_at = $6, completed_at = $7, notes = $8, updated_by = $9, updated_at = $10
+		WHERE id = $1
+	`
+
+	for _, step := range steps {
+		if _, err := tx.ExecContext(ctx, query,
+			step.ID, step.Status, step.ResponsibleUserID, step.ResponsibleName, step.DueDate, step.StartedAt,
+			step.CompletedAt, step.Notes, step.UpdatedBy, now,
+		); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update certification step", 500)
+		}
+		step.UpdatedAt = now
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit certification workflow", 500)
+	}
+
+	workflow.UpdatedAt = now
+	return nil
+}
+
+// FindCertification finds the latest certification workflow of an asset
+// with its steps in order
+func (r *repository) FindCertification(ctx context.Context, assetID int64) (*domain.CertificationWorkflow, error) {
+	query := `SELECT ` + workflowColumns + ` FROM certification_workflows WHERE asset_id = $1 ORDER BY id DESC LIMIT 1`
+
+	workflow := &domain.CertificationWorkflow{}
+	var completedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, assetID).Scan(
+		&workflow.ID, &workflow.AssetID, &workflow.Status, &workflow.Notes, &workflow.StartedBy, &completedAt,
+		&workflow.CancelReason, &workflow.CreatedAt, &workflow.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Certification workflow not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find certification workflow", 500)
+	}
+
+	if completedAt.Valid {
+		workflow.CompletedAt = &completedAt.Time
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+stepColumns+` FROM certification_steps WHERE workflow_id = $1 ORDER BY sequence`, workflow.ID,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get certification steps", 500)
+	}
+	defer rows.Close()
+
+	workflow.Steps = make([]*domain.CertificationStep, 0, len(domain.CertificationStages))
+	for rows.Next() {
+		step, err := scanStep(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan certification step", 500)
+		}
+		workflow.Steps = append(workflow.Steps, step)
+	}
+
+	return workflow, rows.Err()
+}
+
+// ListCertifications lists the land assets under certification at the step
+// each is at, those due soonest first
+func (r *repository) ListCertifications(ctx context.Context, filter *CertificationFilter, limit, offset int) ([]*StuckParcel, int64, error) {
+	from := `
+		FROM certification_workflows w
+		JOIN assets a ON a.id = w.asset_id
+		JOIN campaigns c ON c.id = a.campaign_id
+	` + currentStepJoin + `
+		WHERE w.status = $1 AND ($2 = 0 OR c.nazir_id = $2) AND ($3 = '' OR cs.stage = $3)
+		  AND ($4 = '' OR cs.status = $4) AND (NOT $5 OR cs.due_date < $6::date)
+	`
+	args := []interface{}{domain.CertificationInProgress, filter.NazirID, filter.Stage, filter.Status, filter.Overdue, filter.Day}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+from, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count certifications", 500)
+	}
+
+	query := `
+		SELECT cs.*, w.id, a.id, a.name, COALESCE(a.location, ''),
+		       ARRAY(SELECT DISTINCT d.document_type FROM asset_documents d WHERE d.asset_id = a.id)
+	` + from + `
+		ORDER BY cs.due_date NULLS LAST, w.id
+		LIMIT $7 OFFSET $8
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to list certifications", 500)
+	}
+	defer rows.Close()
+
+	parcels := make([]*StuckParcel, 0)
+	for rows.Next() {
+		p := &StuckParcel{}
+		step, err := scanStep(extraScanner{rows, []interface{}{
+			&p.WorkflowID, &p.AssetID, &p.AssetName, &p.Location, pq.Array(&p.DocumentTypes),
+		}})
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan certification", 500)
+		}
+		p.Step = step
+		parcels = append(parcels, p)
+	}
+
+	return parcels, total, rows.Err()
+}
+
+// SummarizeCertifications counts the land assets under certification at
+// each stage; stages without any are left out
+func (r *repository) SummarizeCertifications(ctx context.Context, day time.Time, nazirID int64) ([]*StageSummary, error) {
+	query := `
+		SELECT cs.stage, COUNT(*), COUNT(*) FILTER (WHERE cs.status = $3),
+		       COUNT(*) FILTER (WHERE cs.due_date < $4::date)
+		FROM certification_workflows w
+		JOIN assets a ON a.id = w.asset_id
+		JOIN campaigns c ON c.id = a.campaign_id
+	` + currentStepJoin + `
+		WHERE w.status = $1 AND ($2 = 0 OR c.nazir_id = $2)
+		GROUP BY cs.stage
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.CertificationInProgress, nazirID, domain.StepBlocked, day)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to summarize certifications", 500)
+	}
+	defer rows.Close()
+
+	summaries := make([]*StageSummary, 0)
+	for rows.Next() {
+		s := &StageSummary{}
+		if err := rows.Scan(&s.Stage, &s.Parcels, &s.Blocked, &s.Overdue); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan certification summary", 500)
+		}
+		summaries = append(summaries, s)
+	}
+
+	return summaries, rows.Err()
+}
+
+// scanStep scans a row selected with stepColumns
+func scanStep(row rowScanner) (*domain.CertificationStep, error) {
+	step := &domain.CertificationStep{}
+	var responsibleUserID sql.NullInt64
+	var dueDate, startedAt, completedAt sql.NullTime
+
+	if err := row.Scan(
+		&step.ID, &step.WorkflowID, &step.AssetID, &step.Stage, &step.Sequence, &step.Status, &responsibleUserID,
+		&step.ResponsibleName, &dueDate, &startedAt, &completedAt, &step.Notes, &step.UpdatedBy, &step.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if responsibleUserID.Valid {
+		step.ResponsibleUserID = &responsibleUserID.Int64
+	}
+	if dueDate.Valid {
+		step.DueDate = &dueDate.Time
+	}
+	if startedAt.Valid {
+		step.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		step.CompletedAt = &completedAt.Time
+	}
+
+	return step, nil
+}
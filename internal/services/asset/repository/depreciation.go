@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/ledger/journal"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// DepreciationFunc builds the ledger entries of depreciation posted for an
+// asset, dated the given day
+type DepreciationFunc func(entries []*domain.DepreciationEntry, postedOn time.Time) []*domain.Ledger
+
+// DepreciationDue is an asset with depreciation not yet posted up to the
+// month being run
+type DepreciationDue struct {
+	AssetID         int64
+	AssetName       string
+	Cost            int64 // purchase value, see depreciationSchedule
+	AcquisitionDate time.Time
+	Policy          *domain.DepreciationPolicy
+	LastPeriod      *time.Time // latest month posted
+	Accumulated     int64
+}
+
+const depreciationDefaultColumns = `
+	asset_type, method, useful_life_months, salvage_bps, rate_bps, updated_by, updated_at
+`
+
+const depreciationEntryColumns = `
+	id, asset_id, period, amount, accumulated, book_value, created_at
+`
+
+// policyJoins joins the depreciation policies of assets a
+const policyJoins = `
+	LEFT JOIN asset_depreciation ad ON ad.asset_id = a.id
+	LEFT JOIN depreciation_defaults dd ON dd.asset_type = a.type
+`
+
+// policyColumns selects the policy in effect through policyJoins; scan it
+// with scanPolicy
+const policyColumns = `
+	ad.asset_id IS NOT NULL, COALESCE(ad.method, dd.method), COALESCE(ad.useful_life_months, dd.useful_life_months),
+	COALESCE(ad.salvage_value, 0), COALESCE(dd.salvage_bps, 0), COALESCE(ad.rate_bps, dd.rate_bps)
+`
+
+// GetDepreciationDefaults gets the depreciation defaults of every asset type
+// that has one
+func (r *repository) GetDepreciationDefaults(ctx context.Context) ([]*domain.DepreciationDefault, error) {
+	query := `SELECT ` + depreciationDefaultColumns + ` FROM depreciation_defaults ORDER BY asset_type`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get depreciation defaults", 500)
+	}
+	defer rows.Close()
+
+	defaults := make([]*domain.DepreciationDefault, 0)
+	for rows.Next() {
+		d := &domain.DepreciationDefault{}
+		if err := rows.Scan(
+			&d.AssetType, &d.Method, &d.UsefulLifeMonths, &d.SalvageBPS, &d.RateBPS, &d.UpdatedBy, &d.UpdatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan depreciation default", 500)
+		}
+		defaults = append(defaults, d)
+	}
+
+	return defaults, rows.Err()
+}
+
+// SaveDepreciationDefault creates or replaces the depreciation default of an
+// asset type
+func (r *repository) SaveDepreciationDefault(ctx context.Context, d *domain.DepreciationDefault) error {
+	query := `
+		INSERT INTO depreciation_defaults (asset_type, method, useful_life_months, salvage_bps, rate_bps, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (asset_type) DO UPDATE
+		SET method = EXCLUDED.method, useful_life_months = EXCLUDED.useful_life_months,
+		    salvage_bps = EXCLUDED.salvage_bps, rate_bps = EXCLUDED.rate_bps,
+		    updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+	`
+
+	now := time.Now()
+	if _, err := r.db.ExecContext(ctx, query,
+		d.AssetType, d.Method, d.UsefulLifeMonths, d.SalvageBPS, d.RateBPS, d.UpdatedBy, now,
+	); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to save depreciation default", 500)
+	}
+
+	d.UpdatedAt = now
+	return nil
+}
+
+// DeleteDepreciationDefault removes the depreciation default of an asset type
+func (r *repository) DeleteDepreciationDefault(ctx context.Context, assetType domain.AssetType) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM depreciation_defaults WHERE asset_type = $1`, assetType)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete depreciation default", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeNotFound, "Depreciation default not found", 404)
+	}
+
+	return nil
+}
+
+// SaveAssetDepreciation creates or replaces the depreciation policy of an asset
+func (r *repository) SaveAssetDepreciation(ctx context.Context, d *domain.AssetDepreciation) error {
+	query := `
+		INSERT INTO asset_depreciation (asset_id, method, useful_life_months, salvage_value, rate_bps, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (asset_id) DO UPDATE
+		SET method = EXCLUDED.method, useful_life_months = EXCLUDED.useful_life_months,
+		    salvage_value = EXCLUDED.salvage_value, rate_bps = EXCLUDED.rate_bps,
+		    updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+	`
+
+	now := time.Now()
+	if _, err := r.db.ExecContext(ctx, query,
+		d.AssetID, d.Method, d.UsefulLifeMonths, d.SalvageValue, d.RateBPS, d.UpdatedBy, now,
+	); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to save asset depreciation", 500)
+	}
+
+	d.UpdatedAt = now
+	return nil
+}
+
+// DeleteAssetDepreciation removes the depreciation policy of an asset, so the
+// default of its type applies again
+func (r *repository) DeleteAssetDepreciation(ctx context.Context, assetID int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM asset_depreciation WHERE asset_id = $1`, assetID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete asset depreciation", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeNotFound, "Asset has no depreciation policy of its own", 404)
+	}
+
+	return nil
+}
+
+// FindDepreciationPolicy finds the depreciation policy in effect for an
+// asset: its own, else the default of its type
+func (r *repository) FindDepreciationPolicy(ctx context.Context, asset *domain.Asset) (*domain.DepreciationPolicy, error) {
+	query := `
+		SELECT ` + policyColumns + `
+		FROM assets a ` + policyJoins + `
+		WHERE a.id = $1 AND (ad.asset_id IS NOT NULL OR dd.asset_type IS NOT NULL)
+	`
+
+	policy, err := scanPolicy(r.db.QueryRowContext(ctx, query, asset.ID), asset.PurchaseValue)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "No depreciation policy is set for this asset or its type", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find depreciation policy", 500)
+	}
+
+	return policy, nil
+}
+
+// GetDepreciationEntries gets the depreciation posted for an asset, oldest
+// month first
+func (r *repository) GetDepreciationEntries(ctx context.Context, assetID int64) ([]*domain.DepreciationEntry, error) {
+	query := `SELECT ` + depreciationEntryColumns + ` FROM asset_depreciation_entries WHERE asset_id = $1 ORDER BY period`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get depreciation entries", 500)
+	}
+	defer rows.Close()
+
+	entries := make([]*domain.DepreciationEntry, 0)
+	for rows.Next() {
+		e := &domain.DepreciationEntry{}
+		if err := rows.Scan(&e.ID, &e.AssetID, &e.Period, &e.Amount, &e.Accumulated, &e.BookValue, &e.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan depreciation entry", 500)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetDepreciationDue gets the depreciable assets with a policy whose latest
+// posted month is before the given one. Disposed assets are no longer
+// depreciated.
+func (r *repository) GetDepreciationDue(ctx context.Context, through time.Time) ([]*DepreciationDue, error) {
+	query := `
+		SELECT a.id, a.name, a.purchase_value, a.acquisition_date, e.period, COALESCE(e.accumulated, 0),
+		       ` + policyColumns + `
+		FROM assets a ` + policyJoins + `
+		LEFT JOIN LATERAL (
+			SELECT period, accumulated FROM asset_depreciation_entries
+			WHERE asset_id = a.id ORDER BY period DESC LIMIT 1
+		) e ON TRUE
+		WHERE (ad.asset_id IS NOT NULL OR dd.asset_type IS NOT NULL)
+		  AND a.type <> $1 AND a.status <> $2 AND a.purchase_value > 0
+		  AND a.acquisition_date < $3::date + INTERVAL '1 month'
+		  AND (e.period IS NULL OR e.period < $3::date)
+		ORDER BY a.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.AssetTypeLand, domain.AssetStatusDisposed, through)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get assets due for depreciation", 500)
+	}
+	defer rows.Close()
+
+	due := make([]*DepreciationDue, 0)
+	for rows.Next() {
+		d := &DepreciationDue{}
+		var lastPeriod sql.NullTime
+		var own bool
+		var method domain.DepreciationMethod
+		var lifeMonths, salvageBPS, rateBPS int
+		var salvage int64
+
+		if err := rows.Scan(
+			&d.AssetID, &d.AssetName, &d.Cost, &d.AcquisitionDate, &lastPeriod, &d.Accumulated,
+			&own, &method, &lifeMonths, &salvage, &salvageBPS, &rateBPS,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan asset due for depreciation", 500)
+		}
+
+		d.Policy = policyOf(own, method, lifeMonths, salvage, salvageBPS, rateBPS, d.Cost)
+		if lastPeriod.Valid {
+			d.LastPeriod = &lastPeriod.Time
+		}
+		due = append(due, d)
+	}
+
+	return due, rows.Err()
+}
+
+// PostDepreciation records months of depreciation of an asset and posts
+// them to the ledger in one transaction. The posting is dated the last day
+// of the latest month, or today when that month is in a closed accounting
+// period, so an asset that fell behind catches up in the open period.
+// Months already posted by a concurrent run fail the whole batch with a
+// conflict.
+func (r *repository) PostDepreciation(ctx context.Context, assetID int64, entries []*domain.DepreciationEntry, compose DepreciationFunc) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO asset_depreciation_entries (asset_id, period, amount, accumulated, book_value, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	now := time.Now()
+	for _, entry := range entries {
+		err := tx.QueryRowContext(ctx, query,
+			assetID, entry.Period, entry.Amount, entry.Accumulated, entry.BookValue, now,
+		).Scan(&entry.ID)
+		if isUniqueViolation(err) {
+			return errors.New(errors.ErrCodeConflict, "Depreciation of this month was already posted", 409)
+		}
+		if err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to record depreciation", 500)
+		}
+		entry.CreatedAt = now
+	}
+
+	postedOn := entries[len(entries)-1].Period.AddDate(0, 1, -1)
+	closed, err := journal.IsClosed(ctx, tx, postedOn)
+	if err != nil {
+		return err
+	}
+	if closed {
+		postedOn = now
+	}
+
+	ledger := compose(entries, postedOn)
+	for _, entry := range ledger {
+		entry.ReferenceID = assetID
+	}
+	if err := journal.Append(ctx, tx, ledger...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit depreciation", 500)
+	}
+
+	return nil
+}
+
+// scanPolicy scans a row selected with policyColumns for an asset of the
+// given cost
+func scanPolicy(row rowScanner, cost int64) (*domain.DepreciationPolicy, error) {
+	var own bool
+	var method domain.DepreciationMethod
+	var lifeMonths, salvageBPS, rateBPS int
+	var salvage int64
+
+	if err := row.Scan(&own, &method, &lifeMonths, &salvage, &salvageBPS, &rateBPS); err != nil {
+		return nil, err
+	}
+
+	return policyOf(own, method, lifeMonths, salvage, salvageBPS, rateBPS, cost), nil
+}
+
+// policyOf builds the policy of an asset from its own policy or its type
+// default
+func policyOf(own bool, method domain.DepreciationMethod, lifeMonths int, salvage int64, salvageBPS, rateBPS int, cost int64) *domain.DepreciationPolicy {
+	if own {
+		d := &domain.AssetDepreciation{Method: method, UsefulLifeMonths: lifeMonths, SalvageValue: salvage, RateBPS: rateBPS}
+		return d.Policy()
+	}
+
+	d := &domain.DepreciationDefault{Method: method, UsefulLifeMonths: lifeMonths, SalvageBPS: salvageBPS, RateBPS: rateBPS}
+	return d.PolicyFor(cost)
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/notify"
+	"github.com/lib/pq"
+)
+
+// Expiry statuses of ExpiryFilter
+const (
+	ExpiryStatusExpired  = "expired"
+	ExpiryStatusExpiring = "expiring"
+)
+
+// ExpiryFilter filters asset documents by expiry. Only the latest expiry of
+// a document type is considered, so a renewed permit replaces the old one.
+type ExpiryFilter struct {
+	Day         time.Time // today
+	Within      int       // days ahead of Day a document counts as expiring
+	Status      string    // ExpiryStatusExpired, ExpiryStatusExpiring or empty for both
+	NazirID     int64
+	TenantID    *int64
+	SkipAlerted bool // leave out expired documents already alerted as expired
+}
+
+// ExpiringDocument is an asset document that is expiring or has expired
+type ExpiringDocument struct {
+	*domain.AssetDocument
+	AssetName string `json:"asset_name"`
+	TenantID  *int64 `json:"tenant_id,omitempty"`
+	NazirID   int64  `json:"nazir_id"`
+	DaysLeft  int    `json:"days_left"` // negative once expired
+
+	NazirEmail string `json:"-"`
+	NazirPhone string `json:"-"`
+}
+
+// ComplianceTotal counts the expired and expiring documents of a tenant;
+// TenantID is nil for community assets
+type ComplianceTotal struct {
+	TenantID *int64 `json:"tenant_id,omitempty"`
+	Expired  int    `json:"expired"`
+	Expiring int    `json:"expiring"`
+}
+
+// ExpiryAlertFilter filters the alert history; empty fields match everything
+type ExpiryAlertFilter struct {
+	AssetID  int64
+	NazirID  int64
+	TenantID *int64
+}
+
+// ExpiryAlert is an alert of the history with the asset and document it
+// was about
+type ExpiryAlert struct {
+	*domain.DocumentExpiryAlert
+	AssetName    string `json:"asset_name"`
+	DocumentName string `json:"document_name,omitempty"` // empty once the document is deleted
+}
+
+// expiryFrom selects the latest expiry of every document type of the live
+// assets; the arguments are those of expiryArgs
+const expiryFrom = `
+	FROM asset_documents d
+	JOIN assets a ON a.id = d.asset_id
+	JOIN campaigns c ON c.id = a.campaign_id
+	WHERE d.expiry_date IS NOT NULL AND d.expiry_date <= $1::date + $2::int AND a.status <> $3
+	  AND NOT EXISTS (
+		SELECT 1 FROM asset_documents n
+		WHERE n.asset_id = d.asset_id AND n.document_type = d.document_type AND n.expiry_date > d.expiry_date
+	  )
+	  AND ($4 = '' OR ($4 = 'expired') = (d.expiry_date < $1::date))
+	  AND ($5 = 0 OR c.nazir_id = $5)
+	  AND ($6::bigint IS NULL OR a.tenant_id = $6)
+	  AND (NOT $7 OR d.expiry_date >= $1::date OR NOT EXISTS (
+		SELECT 1 FROM document_expiry_alerts x
+		WHERE x.document_id = d.id AND x.expiry_date = d.expiry_date AND x.window_days = 0
+	  ))
+`
+
+func expiryArgs(f *ExpiryFilter) []interface{} {
+	var tenantID sql.NullInt64
+	if f.TenantID != nil {
+		tenantID = sql.NullInt64{Int64: *f.TenantID, Valid: true}
+	}
+	return []interface{}{f.Day, f.Within, domain.AssetStatusDisposed, f.Status, f.NazirID, tenantID, f.SkipAlerted}
+}
+
+// GetExpiringDocuments gets the documents expiring within the filter's
+// window or already expired, soonest expiry first
+func (r *repository) GetExpiringDocuments(ctx context.Context, filter *ExpiryFilter, limit, offset int) ([]*ExpiringDocument, int64, error) {
+	args := expiryArgs(filter)
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+expiryFrom, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count expiring documents", 500)
+	}
+
+	query := `
+		SELECT d.id, d.asset_id, d.document_type, d.document_name, d.file_url, d.file_size,
+		       COALESCE(d.mime_type, ''), d.issue_date, d.expiry_date, COALESCE(d.notes, ''), d.uploaded_by,
+		       d.created_at, d.updated_at,
+		       a.name, a.tenant_id, c.nazir_id, d.expiry_date - $1::date,
+		       COALESCE((SELECT u.email FROM users u WHERE u.id = c.nazir_id), ''),
+		       COALESCE((SELECT u.phone FROM users u WHERE u.id = c.nazir_id), '')
+	` + expiryFrom + `
+		ORDER BY d.expiry_date, d.id
+		LIMIT $8 OFFSET $9
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get expiring documents", 500)
+	}
+	defer rows.Close()
+
+	documents := make([]*ExpiringDocument, 0)
+	for rows.Next() {
+		e := &ExpiringDocument{}
+		var tenantID sql.NullInt64
+		document, err := scanDocument(extraScanner{rows, []interface{}{
+			&e.AssetName, &tenantID, &e.NazirID, &e.DaysLeft, &e.NazirEmail, &e.NazirPhone,
+		}})
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan expiring document", 500)
+		}
+		if tenantID.Valid {
+			e.TenantID = &tenantID.Int64
+		}
+		e.AssetDocument = document
+		documents = append(documents, e)
+	}
+
+	return documents, total, rows.Err()
+}
+
+// SummarizeCompliance counts the expired and expiring documents of every
+// tenant
+func (r *repository) SummarizeCompliance(ctx context.Context, filter *ExpiryFilter) ([]*ComplianceTotal, error) {
+	query := `
+		SELECT a.tenant_id,
+		       COUNT(*) FILTER (WHERE d.expiry_date < $1::date),
+		       COUNT(*) FILTER (WHERE d.expiry_date >= $1::date)
+	` + expiryFrom + `
+		GROUP BY a.tenant_id
+		ORDER BY a.tenant_id NULLS FIRST
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, expiryArgs(filter)...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to summarize document compliance", 500)
+	}
+	defer rows.Close()
+
+	totals := make([]*ComplianceTotal, 0)
+	for rows.Next() {
+		t := &ComplianceTotal{}
+		var tenantID sql.NullInt64
+		if err := rows.Scan(&tenantID, &t.Expired, &t.Expiring); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan compliance total", 500)
+		}
+		if tenantID.Valid {
+			t.TenantID = &tenantID.Int64
+		}
+		totals = append(totals, t)
+	}
+
+	return totals, rows.Err()
+}
+
+// RecordExpiryAlert records an alert in the history and queues its
+// notifications in one transaction. An alert of the same document, expiry
+// date and window is only recorded once; false is returned for a repeat.
+func (r *repository) RecordExpiryAlert(ctx context.Context, alert *domain.DocumentExpiryAlert, messages ...*notify.Message) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO document_expiry_alerts (
+			document_id, asset_id, document_type, expiry_date, window_days, days_left, nazir_id, recipients,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (document_id, expiry_date, window_days) DO NOTHING
+		RETURNING id
+	`, alert.DocumentID, alert.AssetID, alert.DocumentType, alert.ExpiryDate, alert.WindowDays, alert.DaysLeft,
+		alert.NazirID, pq.Array(alert.Recipients), now,
+	).Scan(&alert.ID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to record document expiry alert", 500)
+	}
+
+	if err := notify.Enqueue(ctx, tx, messages...); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit document expiry alert", 500)
+	}
+
+	alert.CreatedAt = now
+	return true, nil
+}
+
+// GetExpiryAlerts gets the alert history, newest first
+func (r *repository) GetExpiryAlerts(ctx context.Context, filter *ExpiryAlertFilter, limit, offset int) ([]*ExpiryAlert, int64, error) {
+	var tenantID sql.NullInt64
+	if filter.TenantID != nil {
+		tenantID = sql.NullInt64{Int64: *filter.TenantID, Valid: true}
+	}
+
+	from := `
+		FROM document_expiry_alerts x
+		JOIN assets a ON a.id = x.asset_id
+		JOIN campaigns c ON c.id = a.campaign_id
+		LEFT JOIN asset_documents d ON d.id = x.document_id
+		WHERE ($1 = 0 OR x.asset_id = $1)
+		  AND ($2 = 0 OR c.nazir_id = $2)
+		  AND ($3::bigint IS NULL OR a.tenant_id = $3)
+	`
+	args := []interface{}{filter.AssetID, filter.NazirID, tenantID}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+from, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count document expiry alerts", 500)
+	}
+
+	query := `
+		SELECT x.id, x.document_id, x.asset_id, x.document_type, x.expiry_date, x.window_days, x.days_left,
+		       x.nazir_id, x.recipients, x.created_at, a.name, COALESCE(d.document_name, '')
+	` + from + `
+		ORDER BY x.created_at DESC, x.id DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get document expiry alerts", 500)
+	}
+	defer rows.Close()
+
+	alerts := make([]*ExpiryAlert, 0)
+	for rows.Next() {
+		a := &ExpiryAlert{DocumentExpiryAlert: &domain.DocumentExpiryAlert{}}
+		var nazirID sql.NullInt64
+		if err := rows.Scan(
+			&a.ID, &a.DocumentID, &a.AssetID, &a.DocumentType, &a.ExpiryDate, &a.WindowDays, &a.DaysLeft,
+			&nazirID, pq.Array(&a.Recipients), &a.CreatedAt, &a.AssetName, &a.DocumentName,
+		); err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan document expiry alert", 500)
+		}
+		if nazirID.Valid {
+			a.NazirID = &nazirID.Int64
+		}
+		alerts = append(alerts, a)
+	}
+
+	return alerts, total, rows.Err()
+}
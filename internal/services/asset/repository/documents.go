@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+const documentColumns = `
+	id, asset_id, document_type, document_name, file_url, file_size, COALESCE(mime_type, ''), issue_date,
+	expiry_date, COALESCE(notes, ''), uploaded_by, created_at, updated_at
+`
+
+// CreateDocument attaches a document to an asset
+func (r *repository) CreateDocument(ctx context.Context, document *domain.AssetDocument) error {
+	query := `
+		INSERT INTO asset_documents (
+			asset_id, document_type, document_name, file_url, file_size, mime_type, issue_date, expiry_date,
+			notes, uploaded_by, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(
+		ctx, query,
+		document.AssetID,
+		document.DocumentType,
+		document.DocumentName,
+		document.FileURL,
+		document.FileSize,
+		document.MimeType,
+		document.IssueDate,
+		document.ExpiryDate,
+		document.Notes,
+		document.UploadedBy,
+		now,
+	).Scan(&document.ID)
+
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create asset document", 500)
+	}
+
+	document.CreatedAt = now
+	document.UpdatedAt = now
+	return nil
+}
+
+// UpdateDocument updates a document's details and file
+func (r *repository) UpdateDocument(ctx context.Context, document *domain.AssetDocument) error {
+	query := `
+		UPDATE asset_documents
+		SET document_type = $3, document_name = $4, file_url = $5, file_size = $6, mime_type = $7,
+		    issue_date = $8, expiry_date = $9, notes = $10, updated_at = $11
+		WHERE id = $1 AND asset_id = $2
+	`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(
+		ctx, query,
+		document.ID,
+		document.AssetID,
+		document.DocumentType,
+		document.DocumentName,
+		document.FileURL,
+		document.FileSize,
+		document.MimeType,
+		document.IssueDate,
+		document.ExpiryDate,
+		document.Notes,
+		now,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update asset document", 500)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update asset document", 500)
+	}
+	if rows == 0 {
+		return errors.New(errors.ErrCodeNotFound, "Document not found", 404)
+	}
+
+	document.UpdatedAt = now
+	return nil
+}
+
+// DeleteDocument removes a document from an asset
+func (r *repository) DeleteDocument(ctx context.Context, assetID, documentID int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM asset_documents WHERE id = $1 AND asset_id = $2`, documentID, assetID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete asset document", 500)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete asset document", 500)
+	}
+	if rows == 0 {
+		return errors.New(errors.ErrCodeNotFound, "Document not found", 404)
+	}
+
+	return nil
+}
+
+// FindDocument finds a document of an asset
+func (r *repository) FindDocument(ctx context.Context, assetID, documentID int64) (*domain.AssetDocument, error) {
+	query := `SELECT ` + documentColumns + ` FROM asset_documents WHERE id = $1 AND asset_id = $2`
+
+	document, err := scanDocument(r.db.QueryRowContext(ctx, query, documentID, assetID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Document not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find asset document", 500)
+	}
+
+	return document, nil
+}
+
+// GetDocuments gets the documents of an asset, optionally of one type
+func (r *repository) GetDocuments(ctx context.Context, assetID int64, documentType string) ([]*domain.AssetDocument, error) {
+	query := `
+		SELECT ` + documentColumns + `
+		FROM asset_documents
+		WHERE asset_id = $1 AND ($2 = '' OR document_type = $2)
+		ORDER BY document_type, created_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID, documentType)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get asset documents", 500)
+	}
+	defer rows.Close()
+
+	documents := make([]*domain.AssetDocument, 0)
+	for rows.Next() {
+		document, err := scanDocument(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan asset document", 500)
+		}
+		documents = append(documents, document)
+	}
+
+	return documents, rows.Err()
+}
+
+// scanDocument scans a row selected with documentColumns
+func scanDocument(row rowScanner) (*domain.AssetDocument, error) {
+	document := &domain.AssetDocument{}
+	var issueDate, expiryDate sql.NullTime
+
+	if err := row.Scan(
+		&document.ID, &document.AssetID, &document.DocumentType, &document.DocumentName, &document.FileURL,
+		&document.FileSize, &document.MimeType, &issueDate, &expiryDate, &document.Notes, &document.UploadedBy,
+		&document.CreatedAt, &document.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if issueDate.Valid {
+		document.IssueDate = &issueDate.Time
+	}
+	if expiryDate.Valid {
+		document.ExpiryDate = &expiryDate.Time
+	}
+
+	return document, nil
+}
@@ -0,0 +1,392 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// overlapToleranceSqm is the shared area below which two boundaries are
+// taken to merely touch; surveys of neighbouring parcels rarely line up
+// exactly
+const overlapToleranceSqm = 1.0
+
+// boundaryLockKey serializes boundary saves so two overlapping parcels
+// cannot be registered concurrently (pg advisory lock)
+const boundaryLockKey int64 = 7_042_004_002
+
+// locatedAssets selects assets with the shape they occupy: their surveyed
+// boundary if they have one, otherwise their point
+const locatedAssets = `(
+	SELECT a.*, COALESCE(g.geometry, ST_SetSRID(ST_MakePoint(a.longitude, a.latitude), 4326)) AS shape,
+	       g.boundary, g.area_sqm AS boundary_area_sqm, g.land_use, g.zoning_type
+	FROM assets a
+	LEFT JOIN asset_geospatial g ON g.asset_id = a.id
+) assets`
+
+// NearbyAsset is an asset with its distance from a search point
+type NearbyAsset struct {
+	*domain.Asset
+	DistanceMeters float64 `json:"distance_meters"`
+}
+
+// AssetFeature is an asset with its boundary, for GIS export
+type AssetFeature struct {
+	*domain.Asset
+	Boundary   json.RawMessage // GeoJSON polygon; nil if the asset has no boundary
+	AreaSqm    *float64
+	LandUse    string
+	ZoningType string
+}
+
+// BoundaryOverlap is land claimed by the boundaries of two assets
+type BoundaryOverlap struct {
+	AssetID        int64   `json:"asset_id"`
+	AssetName      string  `json:"asset_name"`
+	OtherAssetID   int64   `json:"other_asset_id"`
+	OtherAssetName string  `json:"other_asset_name"`
+	OverlapSqm     float64 `json:"overlap_sqm"`
+}
+
+// AreaMismatch is an asset whose declared area differs from the area of its
+// boundary
+type AreaMismatch struct {
+	AssetID     int64   `json:"asset_id"`
+	Name        string  `json:"name"`
+	CampaignID  int64   `json:"campaign_id"`
+	DeclaredSqm float64 `json:"declared_sqm"`
+	ComputedSqm float64 `json:"computed_sqm"`
+	Difference  float64 `json:"difference"` // relative to the computed area
+}
+
+const boundaryColumns = `
+	asset_id, ST_AsText(geometry), boundary, COALESCE(land_use, ''), COALESCE(zoning_type, ''), elevation,
+	area_sqm, updated_by, created_at, updated_at
+`
+
+// SaveBoundary creates or replaces the boundary of an asset and reports
+// whether it was created. It fails with a conflict if the boundary overlaps
+// land already registered to another asset.
+func (r *repository) SaveBoundary(ctx context.Context, data *domain.GeospatialData) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, boundaryLockKey); err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to lock asset boundaries", 500)
+	}
+
+	overlapQuery := `
+		SELECT g.asset_id
+		FROM asset_geospatial g
+		JOIN assets a ON a.id = g.asset_id
+		WHERE g.asset_id <> $1 AND a.status <> $2
+		  AND ST_Intersects(g.geometry, ST_SetSRID(ST_GeomFromGeoJSON($3), 4326))
+		  AND ST_Area(ST_Intersection(g.geometry, ST_SetSRID(ST_GeomFromGeoJSON($3), 4326))::geography) > $4
+		ORDER BY g.asset_id
+	`
+	rows, err := tx.QueryContext(ctx, overlapQuery, data.AssetID, domain.AssetStatusDisposed, string(data.Boundary), overlapToleranceSqm)
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to check boundary overlaps", 500)
+	}
+	var overlapping []string
+	for rows.Next() {
+		var assetID int64
+		if err := rows.Scan(&assetID); err != nil {
+			rows.Close()
+			return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan boundary overlap", 500)
+		}
+		overlapping = append(overlapping, strconv.FormatInt(assetID, 10))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to check boundary overlaps", 500)
+	}
+	if len(overlapping) > 0 {
+		return false, errors.New(errors.ErrCodeConflict, "Boundary overlaps land already registered to asset ID "+strings.Join(overlapping, ", "), 409)
+	}
+
+	query := `
+		INSERT INTO asset_geospatial (
+			asset_id, geometry, boundary, land_use, zoning_type, elevation, area_sqm, updated_by,
+			created_at, updated_at
+		)
+		VALUES ($1, ST_SetSRID(ST_GeomFromGeoJSON($2), 4326), $2::jsonb, NULLIF($3, ''), NULLIF($4, ''), $5, $6, $7, $8, $8)
+		ON CONFLICT (asset_id) DO UPDATE
+		SET geometry = EXCLUDED.geometry, boundary = EXCLUDED.boundary, land_use = EXCLUDED.land_use,
+		    zoning_type = EXCLUDED.zoning_type, elevation = EXCLUDED.elevation, area_sqm = EXCLUDED.area_sqm,
+		    updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+		RETURNING ST_AsText(geometry), created_at, xmax = 0
+	`
+
+	now := time.Now()
+	var created bool
+	if err := tx.QueryRowContext(ctx, query,
+		data.AssetID, string(data.Boundary), data.LandUse, data.ZoningType, data.Elevation, data.AreaSqm,
+		data.UpdatedBy, now,
+	).Scan(&data.Geometry, &data.CreatedAt, &created); err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to save asset boundary", 500)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit asset boundary", 500)
+	}
+
+	data.UpdatedAt = now
+	return created, nil
+}
+
+// FindBoundary finds the boundary of an asset
+func (r *repository) FindBoundary(ctx context.Context, assetID int64) (*domain.GeospatialData, error) {
+	query := `SELECT ` + boundaryColumns + ` FROM asset_geospatial WHERE asset_id = $1`
+
+	data := &domain.GeospatialData{}
+	var boundary []byte
+	var elevation sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, query, assetID).Scan(
+		&data.AssetID, &data.Geometry, &boundary, &data.LandUse, &data.ZoningType, &elevation,
+		&data.AreaSqm, &data.UpdatedBy, &data.CreatedAt, &data.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Asset boundary not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find asset boundary", 500)
+	}
+
+	data.Boundary = boundary
+	if elevation.Valid {
+		data.Elevation = &elevation.Float64
+	}
+	return data, nil
+}
+
+// DeleteBoundary removes the boundary of an asset
+func (r *repository) DeleteBoundary(ctx context.Context, assetID int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM asset_geospatial WHERE asset_id = $1`, assetID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete asset boundary", 500)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete asset boundary", 500)
+	}
+	if rows == 0 {
+		return errors.New(errors.ErrCodeNotFound, "Asset boundary not found", 404)
+	}
+
+	return nil
+}
+
+// Nearby lists assets matching filter within radius meters of a point,
+// nearest first. Assets with a boundary are measured from its nearest edge.
+func (r *repository) Nearby(ctx context.Context, filter *ListFilter, latitude, longitude, radius float64, limit int) ([]*NearbyAsset, error) {
+	where, args := filter.where()
+	if where == "" {
+		where = " WHERE "
+	} else {
+		where += " AND "
+	}
+
+	n := len(args)
+	origin := fmt.Sprintf("ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography", n+1, n+2)
+	query := fmt.Sprintf(`
+		SELECT %s, ST_Distance(shape::geography, %s) AS distance
+		FROM %s%sshape IS NOT NULL AND ST_DWithin(shape::geography, %s, $%d)
+		ORDER BY distance, id
+		LIMIT $%d
+	`, assetColumns, origin, locatedAssets, where, origin, n+3, n+4)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, longitude, latitude, radius, limit)...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find nearby assets", 500)
+	}
+	defer rows.Close()
+
+	assets := make([]*NearbyAsset, 0)
+	for rows.Next() {
+		nearby := &NearbyAsset{}
+		asset, err := scanAsset(extraScanner{rows, []interface{}{&nearby.DistanceMeters}})
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan nearby asset", 500)
+		}
+		nearby.Asset = asset
+		assets = append(assets, nearby)
+	}
+
+	return assets, rows.Err()
+}
+
+// ListFeatures lists located assets matching filter for export, oldest
+// first, up to limit
+func (r *repository) ListFeatures(ctx context.Context, filter *ListFilter, limit int) ([]*AssetFeature, error) {
+	where, args := filter.where()
+	if where == "" {
+		where = " WHERE "
+	} else {
+		where += " AND "
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, boundary, boundary_area_sqm, COALESCE(land_use, ''), COALESCE(zoning_type, '')
+		FROM %s%sshape IS NOT NULL
+		ORDER BY id
+		LIMIT $%d
+	`, assetColumns, locatedAssets, where, len(args)+1)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to list asset features", 500)
+	}
+	defer rows.Close()
+
+	features := make([]*AssetFeature, 0)
+	for rows.Next() {
+		feature := &AssetFeature{}
+		var boundary []byte
+		var areaSqm sql.NullFloat64
+		asset, err := scanAsset(extraScanner{rows, []interface{}{&boundary, &areaSqm, &feature.LandUse, &feature.ZoningType}})
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan asset feature", 500)
+		}
+		feature.Asset = asset
+		feature.Boundary = boundary
+		if areaSqm.Valid {
+			feature.AreaSqm = &areaSqm.Float64
+		}
+		features = append(features, feature)
+	}
+
+	return features, rows.Err()
+}
+
+// FindOverlaps finds the assets whose boundaries overlap the boundary of an
+// asset, largest overlap first
+func (r *repository) FindOverlaps(ctx context.Context, assetID int64) ([]*BoundaryOverlap, error) {
+	query := `
+		SELECT a.id, a.name, b.id, b.name, ST_Area(ST_Intersection(ga.geometry, gb.geometry)::geography) AS overlap
+		FROM asset_geospatial ga
+		JOIN assets a ON a.id = ga.asset_id
+		JOIN asset_geospatial gb ON gb.asset_id <> ga.asset_id AND ST_Intersects(ga.geometry, gb.geometry)
+		JOIN assets b ON b.id = gb.asset_id AND b.status <> $2
+		WHERE ga.asset_id = $1
+		  AND ST_Area(ST_Intersection(ga.geometry, gb.geometry)::geography) > $3
+		ORDER BY overlap DESC, b.id
+	`
+
+	return r.queryOverlaps(ctx, query, assetID, domain.AssetStatusDisposed, overlapToleranceSqm)
+}
+
+// ListOverlaps lists every pair of live assets whose boundaries overlap,
+// largest overlap first
+func (r *repository) ListOverlaps(ctx context.Context, limit, offset int) ([]*BoundaryOverlap, int64, error) {
+	pairs := `
+		FROM asset_geospatial ga
+		JOIN assets a ON a.id = ga.asset_id AND a.status <> $1
+		JOIN asset_geospatial gb ON gb.asset_id > ga.asset_id AND ST_Intersects(ga.geometry, gb.geometry)
+		JOIN assets b ON b.id = gb.asset_id AND b.status <> $1
+		WHERE ST_Area(ST_Intersection(ga.geometry, gb.geometry)::geography) > $2
+	`
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+pairs, domain.AssetStatusDisposed, overlapToleranceSqm).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count boundary overlaps", 500)
+	}
+
+	query := `
+		SELECT a.id, a.name, b.id, b.name, ST_Area(ST_Intersection(ga.geometry, gb.geometry)::geography) AS overlap
+	` + pairs + `
+		ORDER BY overlap DESC, a.id, b.id
+		LIMIT $3 OFFSET $4
+	`
+
+	overlaps, err := r.queryOverlaps(ctx, query, domain.AssetStatusDisposed, overlapToleranceSqm, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return overlaps, total, nil
+}
+
+// ListAreaMismatches lists live assets whose declared area differs from the
+// area of their boundary by more than tolerance, largest difference first
+func (r *repository) ListAreaMismatches(ctx context.Context, tolerance float64, limit, offset int) ([]*AreaMismatch, int64, error) {
+	mismatched := `
+		FROM (
+			SELECT a.id, a.name, a.campaign_id, g.area_sqm,
+			       CASE a.area_unit WHEN 'hectare' THEN a.area * 10000 ELSE a.area END AS declared_sqm
+			FROM assets a
+			JOIN asset_geospatial g ON g.asset_id = a.id
+			WHERE a.status <> $1 AND a.area IS NOT NULL
+		) declared
+		WHERE ABS(declared_sqm - area_sqm) / area_sqm > $2
+	`
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+mismatched, domain.AssetStatusDisposed, tolerance).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count area mismatches", 500)
+	}
+
+	query := `
+		SELECT id, name, campaign_id, declared_sqm, area_sqm, (declared_sqm - area_sqm) / area_sqm AS difference
+	` + mismatched + `
+		ORDER BY ABS(declared_sqm - area_sqm) / area_sqm DESC, id
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.AssetStatusDisposed, tolerance, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to list area mismatches", 500)
+	}
+	defer rows.Close()
+
+	mismatches := make([]*AreaMismatch, 0)
+	for rows.Next() {
+		m := &AreaMismatch{}
+		if err := rows.Scan(&m.AssetID, &m.Name, &m.CampaignID, &m.DeclaredSqm, &m.ComputedSqm, &m.Difference); err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan area mismatch", 500)
+		}
+		mismatches = append(mismatches, m)
+	}
+
+	return mismatches, total, rows.Err()
+}
+
+// queryOverlaps runs a query selecting boundary overlaps
+func (r *repository) queryOverlaps(ctx context.Context, query string, args ...interface{}) ([]*BoundaryOverlap, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find boundary overlaps", 500)
+	}
+	defer rows.Close()
+
+	overlaps := make([]*BoundaryOverlap, 0)
+	for rows.Next() {
+		o := &BoundaryOverlap{}
+		if err := rows.Scan(&o.AssetID, &o.AssetName, &o.OtherAssetID, &o.OtherAssetName, &o.OverlapSqm); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan boundary overlap", 500)
+		}
+		overlaps = append(overlaps, o)
+	}
+
+	return overlaps, rows.Err()
+}
+
+// extraScanner scans a row of known columns followed by extra columns
+type extraScanner struct {
+	rows  *sql.Rows
+	extra []interface{}
+}
+
+func (s extraScanner) Scan(dest ...interface{}) error {
+	return s.rows.Scan(append(dest, s.extra...)...)
+}
@@ -0,0 +1,527 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/ledger/journal"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/notify"
+)
+
+// RentIncomeFunc builds the ledger entries of a rent payment given the
+// asset's undistributed income before it
+type RentIncomeFunc func(payment *domain.LeasePayment, balance int64) []*domain.Ledger
+
+// InvoiceDue is an active lease with the number of periods already invoiced
+type InvoiceDue struct {
+	Lease    *domain.Lease
+	Invoiced int
+}
+
+// OverdueInvoice is a rent invoice still owed after its due date, with the
+// contacts needed to chase it
+type OverdueInvoice struct {
+	*domain.LeaseInvoice
+	AssetName   string `json:"asset_name"`
+	LesseeName  string `json:"lessee_name"`
+	DaysOverdue int    `json:"days_overdue"`
+
+	LesseeEmail string `json:"-"`
+	LesseePhone string `json:"-"`
+	NazirEmail  string `json:"-"`
+}
+
+const leaseColumns = `
+	id, asset_id, lessee_name, COALESCE(lessee_id_number, ''), COALESCE(lessee_email, ''),
+	COALESCE(lessee_phone, ''), COALESCE(lessee_address, ''), start_date, end_date, rent_amount, billing_cycle,
+	payment_term_days, escalation_bps, escalation_months, deposit_amount, deposit_status, status, terminated_at,
+	COALESCE(termination_reason, ''), COALESCE(notes, ''), created_by, created_at, updated_at
+`
+
+const invoiceColumns = `
+	id, lease_id, asset_id, sequence, number, period_start, period_end, amount, amount_paid, due_date, status,
+	paid_at, created_at, updated_at
+`
+
+const leasePaymentColumns = `
+	id, invoice_id, lease_id, asset_id, income_id, amount, paid_at, method, COALESCE(reference, ''),
+	COALESCE(notes, ''), recorded_by, created_at
+`
+
+// CreateLease creates a lease contract of an asset
+func (r *repository) CreateLease(ctx context.Context, lease *domain.Lease) error {
+	query := `
+		INSERT INTO leases (
+			asset_id, lessee_name, lessee_id_number, lessee_email, lessee_phone, lessee_address, start_date,
+			end_date, rent_amount, billing_cycle, payment_term_days, escalation_bps, escalation_months,
+			deposit_amount, deposit_status, status, notes, created_by, created_at, updated_at
+		)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, $10, $11, $12,
+		        $13, $14, $15, $16, $17, $18, $19, $19)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(
+		ctx, query,
+		lease.AssetID,
+		lease.LesseeName,
+		lease.LesseeIDNo,
+		lease.LesseeEmail,
+		lease.LesseePhone,
+		lease.LesseeAddress,
+		lease.StartDate,
+		lease.EndDate,
+		lease.RentAmount,
+		lease.BillingCycle,
+		lease.PaymentTermDays,
+		lease.EscalationBPS,
+		lease.EscalationMonths,
+		lease.DepositAmount,
+		lease.DepositStatus,
+		lease.Status,
+		lease.Notes,
+		lease.CreatedBy,
+		now,
+	).Scan(&lease.ID)
+
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create lease", 500)
+	}
+
+	lease.CreatedAt = now
+	lease.UpdatedAt = now
+	return nil
+}
+
+// TerminateLease ends an active lease early. Invoices of periods starting
+// after the termination date that have not been paid at all are voided.
+func (r *repository) TerminateLease(ctx context.Context, lease *domain.Lease) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE leases
+		SET status = $1, terminated_at = $2, termination_reason = $3, deposit_status = $4, updated_at = $5
+		WHERE id = $6 AND status = $7
+	`
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, query,
+		domain.LeaseStatusTerminated, lease.TerminatedAt, lease.TerminationReason, lease.DepositStatus, now,
+		lease.ID, domain.LeaseStatusActive,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to terminate lease", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Lease is no longer active", 409)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE lease_invoices SET status = $1, updated_at = $2 WHERE lease_id = $3 AND period_start > $4 AND amount_paid = 0`,
+		domain.InvoiceStatusVoid, now, lease.ID, lease.TerminatedAt,
+	); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to void lease invoices", 500)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit lease termination", 500)
+	}
+
+	lease.Status = domain.LeaseStatusTerminated
+	lease.UpdatedAt = now
+	return nil
+}
+
+// FindLease finds a lease of an asset
+func (r *repository) FindLease(ctx context.Context, assetID, leaseID int64) (*domain.Lease, error) {
+	query := `SELECT ` + leaseColumns + ` FROM leases WHERE id = $1 AND asset_id = $2`
+
+	lease, err := scanLease(r.db.QueryRowContext(ctx, query, leaseID, assetID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Lease not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find lease", 500)
+	}
+
+	return lease, nil
+}
+
+// GetLeases gets the leases of an asset, optionally in one status, latest first
+func (r *repository) GetLeases(ctx context.Context, assetID int64, status domain.LeaseStatus, limit, offset int) ([]*domain.Lease, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM leases WHERE asset_id = $1 AND ($2 = '' OR status = $2)`
+	if err := r.db.QueryRowContext(ctx, countQuery, assetID, status).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count leases", 500)
+	}
+
+	query := `
+		SELECT ` + leaseColumns + `
+		FROM leases
+		WHERE asset_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY start_date DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID, status, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get leases", 500)
+	}
+	defer rows.Close()
+
+	leases := make([]*domain.Lease, 0)
+	for rows.Next() {
+		lease, err := scanLease(rows)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan lease", 500)
+		}
+		leases = append(leases, lease)
+	}
+
+	return leases, total, rows.Err()
+}
+
+// GetLeasesToInvoice gets active leases whose next billing period has
+// started by day
+func (r *repository) GetLeasesToInvoice(ctx context.Context, day time.Time) ([]*InvoiceDue, error) {
+	query := `
+		SELECT ` + leaseColumns + `,
+		       COALESCE((SELECT MAX(i.sequence) FROM lease_invoices i WHERE i.lease_id = leases.id), 0)
+		FROM leases
+		WHERE status = $1
+		  AND COALESCE((SELECT MAX(i.period_end) + 1 FROM lease_invoices i WHERE i.lease_id = leases.id), start_date)
+		      <= LEAST($2::date, end_date)
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.LeaseStatusActive, day)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get leases to invoice", 500)
+	}
+	defer rows.Close()
+
+	due := make([]*InvoiceDue, 0)
+	for rows.Next() {
+		d := &InvoiceDue{}
+		lease, err := scanLease(extraScanner{rows, []interface{}{&d.Invoiced}})
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan lease", 500)
+		}
+		d.Lease = lease
+		due = append(due, d)
+	}
+
+	return due, rows.Err()
+}
+
+// EndExpiredLeases ends active leases whose end date has passed
+func (r *repository) EndExpiredLeases(ctx context.Context, day time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE leases SET status = $1, updated_at = $2 WHERE status = $3 AND end_date < $4`,
+		domain.LeaseStatusEnded, time.Now(), domain.LeaseStatusActive, day,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to end expired leases", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	return int(rows), nil
+}
+
+// CreateInvoices issues rent invoices. An invoice whose period was already
+// issued is skipped, so overlapping runs never bill a period twice; it
+// returns how many were issued.
+func (r *repository) CreateInvoices(ctx context.Context, invoices ...*domain.LeaseInvoice) (int, error) {
+	query := `
+		INSERT INTO lease_invoices (
+			lease_id, asset_id, sequence, number, period_start, period_end, amount, due_date, status,
+			created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+		ON CONFLICT (lease_id, sequence) DO NOTHING
+		RETURNING id
+	`
+
+	created := 0
+	now := time.Now()
+	for _, invoice := range invoices {
+		err := r.db.QueryRowContext(ctx, query,
+			invoice.LeaseID, invoice.AssetID, invoice.Sequence, invoice.Number, invoice.PeriodStart,
+			invoice.PeriodEnd, invoice.Amount, invoice.DueDate, invoice.Status, now,
+		).Scan(&invoice.ID)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return created, errors.Wrap(err, errors.ErrCodeInternal, "Failed to create lease invoice", 500)
+		}
+
+		invoice.CreatedAt = now
+		invoice.UpdatedAt = now
+		created++
+	}
+
+	return created, nil
+}
+
+// GetInvoices gets the invoices of a lease in billing order
+func (r *repository) GetInvoices(ctx context.Context, leaseID int64) ([]*domain.LeaseInvoice, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM lease_invoices WHERE lease_id = $1 ORDER BY sequence`
+
+	rows, err := r.db.QueryContext(ctx, query, leaseID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get lease invoices", 500)
+	}
+	defer rows.Close()
+
+	invoices := make([]*domain.LeaseInvoice, 0)
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan lease invoice", 500)
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	return invoices, rows.Err()
+}
+
+// RecordLeasePayment records rent received against an invoice. The payment
+// is recorded as rent income of the asset, awaiting distribution, and posted
+// to the ledger in the same transaction.
+func (r *repository) RecordLeasePayment(ctx context.Context, payment *domain.LeasePayment, compose RentIncomeFunc) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	invoice, err := scanInvoice(tx.QueryRowContext(ctx,
+		`SELECT `+invoiceColumns+` FROM lease_invoices WHERE id = $1 AND lease_id = $2 FOR UPDATE`,
+		payment.InvoiceID, payment.LeaseID,
+	))
+	if err == sql.ErrNoRows {
+		return errors.New(errors.ErrCodeNotFound, "Invoice not found", 404)
+	}
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to lock lease invoice", 500)
+	}
+
+	switch {
+	case invoice.Status == domain.InvoiceStatusVoid:
+		return errors.New(errors.ErrCodeConflict, "Invoice was voided", 409)
+	case invoice.Status == domain.InvoiceStatusPaid:
+		return errors.New(errors.ErrCodeConflict, "Invoice is already paid", 409)
+	case payment.Amount > invoice.Outstanding():
+		return errors.New(errors.ErrCodeValidation, "amount must not exceed the outstanding rent of the invoice", 400)
+	}
+
+	var balance int64
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(amount), 0) FROM asset_incomes WHERE asset_id = $1 AND distribution_run_id IS NULL`,
+		invoice.AssetID,
+	).Scan(&balance); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to get undistributed balance", 500)
+	}
+
+	now := time.Now()
+	payment.AssetID = invoice.AssetID
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO asset_incomes (asset_id, source, amount, received_at, description, recorded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, invoice.AssetID, domain.IncomeSourceRent, payment.Amount, payment.PaidAt, "Rent invoice "+invoice.Number,
+		payment.RecordedBy, now,
+	).Scan(&payment.IncomeID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create asset income", 500)
+	}
+
+	query := `
+		INSERT INTO lease_payments (
+			invoice_id, lease_id, asset_id, income_id, amount, paid_at, method, reference, notes, recorded_by,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10, $11)
+		RETURNING id
+	`
+	if err := tx.QueryRowContext(ctx, query,
+		payment.InvoiceID, payment.LeaseID, payment.AssetID, payment.IncomeID, payment.Amount, payment.PaidAt,
+		payment.Method, payment.Reference, payment.Notes, payment.RecordedBy, now,
+	).Scan(&payment.ID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create lease payment", 500)
+	}
+
+	invoice.AmountPaid += payment.Amount
+	invoice.Status = domain.InvoiceStatusPartial
+	if invoice.AmountPaid == invoice.Amount {
+		invoice.Status = domain.InvoiceStatusPaid
+		invoice.PaidAt = &payment.PaidAt
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE lease_invoices SET amount_paid = $1, status = $2, paid_at = $3, updated_at = $4 WHERE id = $5`,
+		invoice.AmountPaid, invoice.Status, invoice.PaidAt, now, invoice.ID,
+	); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update lease invoice", 500)
+	}
+
+	entries := compose(payment, balance)
+	for _, entry := range entries {
+		entry.ReferenceID = payment.IncomeID
+	}
+	if err := journal.Append(ctx, tx, entries...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit lease payment", 500)
+	}
+
+	payment.CreatedAt = now
+	return nil
+}
+
+// GetLeasePayments gets the payments of a lease, latest first
+func (r *repository) GetLeasePayments(ctx context.Context, leaseID int64) ([]*domain.LeasePayment, error) {
+	query := `SELECT ` + leasePaymentColumns + ` FROM lease_payments WHERE lease_id = $1 ORDER BY paid_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, leaseID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get lease payments", 500)
+	}
+	defer rows.Close()
+
+	payments := make([]*domain.LeasePayment, 0)
+	for rows.Next() {
+		payment := &domain.LeasePayment{}
+		if err := rows.Scan(
+			&payment.ID, &payment.InvoiceID, &payment.LeaseID, &payment.AssetID, &payment.IncomeID,
+			&payment.Amount, &payment.PaidAt, &payment.Method, &payment.Reference, &payment.Notes,
+			&payment.RecordedBy, &payment.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan lease payment", 500)
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+// GetOverdueInvoices gets invoices still owed after their due date as of
+// day, longest overdue first. A non-zero nazirID limits them to the assets
+// of that nazir's campaigns.
+func (r *repository) GetOverdueInvoices(ctx context.Context, day time.Time, nazirID int64, limit, offset int) ([]*OverdueInvoice, int64, error) {
+	from := `
+		FROM lease_invoices i
+		JOIN leases l ON l.id = i.lease_id
+		JOIN assets a ON a.id = i.asset_id
+		JOIN campaigns c ON c.id = a.campaign_id
+		WHERE i.status IN ($1, $2) AND i.due_date < $3 AND ($4 = 0 OR c.nazir_id = $4)
+	`
+	args := []interface{}{domain.InvoiceStatusUnpaid, domain.InvoiceStatusPartial, day, nazirID}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+from, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count overdue invoices", 500)
+	}
+
+	query := `
+		SELECT i.id, i.lease_id, i.asset_id, i.sequence, i.number, i.period_start, i.period_end, i.amount,
+		       i.amount_paid, i.due_date, i.status, i.paid_at, i.created_at, i.updated_at,
+		       a.name, l.lessee_name, $3::date - i.due_date, COALESCE(l.lessee_email, ''),
+		       COALESCE(l.lessee_phone, ''), COALESCE((SELECT u.email FROM users u WHERE u.id = c.nazir_id), '')
+	` + from + `
+		ORDER BY i.due_date, i.id
+		LIMIT $5 OFFSET $6
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get overdue invoices", 500)
+	}
+	defer rows.Close()
+
+	overdue := make([]*OverdueInvoice, 0)
+	for rows.Next() {
+		o := &OverdueInvoice{}
+		invoice, err := scanInvoice(extraScanner{rows, []interface{}{
+			&o.AssetName, &o.LesseeName, &o.DaysOverdue, &o.LesseeEmail, &o.LesseePhone, &o.NazirEmail,
+		}})
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan overdue invoice", 500)
+		}
+		o.LeaseInvoice = invoice
+		overdue = append(overdue, o)
+	}
+
+	return overdue, total, rows.Err()
+}
+
+// QueueNotifications queues messages in the notification outbox
+func (r *repository) QueueNotifications(ctx context.Context, messages ...*notify.Message) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	if err := notify.Enqueue(ctx, tx, messages...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to queue notifications", 500)
+	}
+	return nil
+}
+
+// scanLease scans a row selected with leaseColumns
+func scanLease(row rowScanner) (*domain.Lease, error) {
+	lease := &domain.Lease{}
+	var terminatedAt sql.NullTime
+
+	if err := row.Scan(
+		&lease.ID, &lease.AssetID, &lease.LesseeName, &lease.LesseeIDNo, &lease.LesseeEmail, &lease.LesseePhone,
+		&lease.LesseeAddress, &lease.StartDate, &lease.EndDate, &lease.RentAmount, &lease.BillingCycle,
+		&lease.PaymentTermDays, &lease.EscalationBPS, &lease.EscalationMonths, &lease.DepositAmount,
+		&lease.DepositStatus, &lease.Status, &terminatedAt, &lease.TerminationReason, &lease.Notes,
+		&lease.CreatedBy, &lease.CreatedAt, &lease.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if terminatedAt.Valid {
+		lease.TerminatedAt = &terminatedAt.Time
+	}
+
+	return lease, nil
+}
+
+// scanInvoice scans a row selected with invoiceColumns
+func scanInvoice(row rowScanner) (*domain.LeaseInvoice, error) {
+	invoice := &domain.LeaseInvoice{}
+	var paidAt sql.NullTime
+
+	if err := row.Scan(
+		&invoice.ID, &invoice.LeaseID, &invoice.AssetID, &invoice.Sequence, &invoice.Number,
+		&invoice.PeriodStart, &invoice.PeriodEnd, &invoice.Amount, &invoice.AmountPaid, &invoice.DueDate,
+		&invoice.Status, &paidAt, &invoice.CreatedAt, &invoice.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if paidAt.Valid {
+		invoice.PaidAt = &paidAt.Time
+	}
+
+	return invoice, nil
+}
@@ -0,0 +1,532 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/ledger/journal"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/lib/pq"
+)
+
+// MaintenanceExpenseFunc builds the ledger entries of a completed work order
+type MaintenanceExpenseFunc func(order *domain.WorkOrder) []*domain.Ledger
+
+// DueSchedule is a maintenance schedule coming due without an open work
+// order, with the contacts of the asset's nazir
+type DueSchedule struct {
+	*domain.MaintenanceSchedule
+	AssetName string `json:"asset_name"`
+	DaysLeft  int    `json:"days_left"` // negative once overdue
+
+	NazirEmail string `json:"-"`
+	NazirPhone string `json:"-"`
+}
+
+const scheduleColumns = `
+	id, asset_id, title, COALESCE(description, ''), interval_months, next_due_date, last_done_date,
+	estimated_cost, is_active, created_by, created_at, updated_at
+`
+
+const workOrderColumns = `
+	id, asset_id, schedule_id, title, COALESCE(description, ''), COALESCE(vendor, ''),
+	COALESCE(vendor_contact, ''), estimated_cost, actual_cost, photo_urls, status, restore_status, opened_by,
+	closed_by, completed_on, closed_at, COALESCE(close_notes, ''), created_at, updated_at
+`
+
+// CreateSchedule creates a maintenance schedule of an asset
+func (r *repository) CreateSchedule(ctx context.Context, schedule *domain.MaintenanceSchedule) error {
+	query := `
+		INSERT INTO maintenance_schedules (
+			asset_id, title, description, interval_months, next_due_date, estimated_cost, is_active,
+			created_by, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(
+		ctx, query,
+		schedule.AssetID,
+		schedule.Title,
+		schedule.Description,
+		schedule.IntervalMonths,
+		schedule.NextDueDate,
+		schedule.EstimatedCost,
+		schedule.IsActive,
+		schedule.CreatedBy,
+		now,
+	).Scan(&schedule.ID)
+
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create maintenance schedule", 500)
+	}
+
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+	return nil
+}
+
+// UpdateSchedule updates a maintenance schedule
+func (r *repository) UpdateSchedule(ctx context.Context, schedule *domain.MaintenanceSchedule) error {
+	query := `
+		UPDATE maintenance_schedules
+		SET title = $3, description = $4, interval_months = $5, next_due_date = $6, estimated_cost = $7,
+		    is_active = $8, updated_at = $9
+		WHERE id = $1 AND asset_id = $2
+	`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(
+		ctx, query,
+		schedule.ID,
+		schedule.AssetID,
+		schedule.Title,
+		schedule.Description,
+		schedule.IntervalMonths,
+		schedule.NextDueDate,
+		schedule.EstimatedCost,
+		schedule.IsActive,
+		now,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update maintenance schedule", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeNotFound, "Maintenance schedule not found", 404)
+	}
+
+	schedule.UpdatedAt = now
+	return nil
+}
+
+// FindSchedule finds a maintenance schedule of an asset
+func (r *repository) FindSchedule(ctx context.Context, assetID, scheduleID int64) (*domain.MaintenanceSchedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM maintenance_schedules WHERE id = $1 AND asset_id = $2`
+
+	schedule, err := scanSchedule(r.db.QueryRowContext(ctx, query, scheduleID, assetID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Maintenance schedule not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find maintenance schedule", 500)
+	}
+
+	return schedule, nil
+}
+
+// GetSchedules gets the maintenance schedules of an asset, soonest due first
+func (r *repository) GetSchedules(ctx context.Context, assetID int64) ([]*domain.MaintenanceSchedule, error) {
+	query := `
+		SELECT ` + scheduleColumns + `
+		FROM maintenance_schedules
+		WHERE asset_id = $1
+		ORDER BY is_active DESC, next_due_date, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get maintenance schedules", 500)
+	}
+	defer rows.Close()
+
+	schedules := make([]*domain.MaintenanceSchedule, 0)
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan maintenance schedule", 500)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+// GetDueSchedules gets active schedules of live assets due within the given
+// number of days of day, or overdue, that have no open work order. A
+// non-zero nazirID limits them to the assets of that nazir's campaigns.
+func (r *repository) GetDueSchedules(ctx context.Context, day time.Time, within int, nazirID int64, limit, offset int) ([]*DueSchedule, int64, error) {
+	from := `
+		FROM maintenance_schedules s
+		JOIN assets a ON a.id = s.asset_id
+		JOIN campaigns c ON c.id = a.campaign_id
+		WHERE s.is_active AND s.next_due_date <= $1::date + $2::int AND a.status <> $3
+		  AND ($4 = 0 OR c.nazir_id = $4)
+		  AND NOT EXISTS (SELECT 1 FROM work_orders w WHERE w.schedule_id = s.id AND w.status = $5)
+	`
+	args := []interface{}{day, within, domain.AssetStatusDisposed, nazirID, domain.WorkOrderStatusOpen}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+from, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count due maintenance", 500)
+	}
+
+	query := `
+		SELECT s.id, s.asset_id, s.title, COALESCE(s.description, ''), s.interval_months, s.next_due_date,
+		       s.last_done_date, s.estimated_cost, s.is_active, s.created_by, s.created_at, s.updated_at,
+		       a.name, s.next_due_date - $1::date,
+		       COALESCE((SELECT u.email FROM users u WHERE u.id = c.nazir_id), ''),
+		       COALESCE((SELECT u.phone FROM users u WHERE u.id = c.nazir_id), '')
+	` + from + `
+		ORDER BY s.next_due_date, s.id
+		LIMIT $6 OFFSET $7
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get due maintenance", 500)
+	}
+	defer rows.Close()
+
+	due := make([]*DueSchedule, 0)
+	for rows.Next() {
+		d := &DueSchedule{}
+		schedule, err := scanSchedule(extraScanner{rows, []interface{}{&d.AssetName, &d.DaysLeft, &d.NazirEmail, &d.NazirPhone}})
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan maintenance schedule", 500)
+		}
+		d.MaintenanceSchedule = schedule
+		due = append(due, d)
+	}
+
+	return due, total, rows.Err()
+}
+
+// CreateWorkOrder opens a work order and moves its asset to under
+// maintenance. The status the asset returns to once its last open work order
+// closes is kept on the work order.
+func (r *repository) CreateWorkOrder(ctx context.Context, order *domain.WorkOrder) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	status, err := lockAssetStatus(ctx, tx, order.AssetID)
+	if err != nil {
+		return err
+	}
+	if status == domain.AssetStatusDisposed {
+		return errors.New(errors.ErrCodeConflict, "Disposed assets cannot be maintained", 409)
+	}
+
+	// An asset already under maintenance returns to where its other open
+	// work orders will return it, or to active if it was set by hand
+	order.RestoreStatus = status
+	if status == domain.AssetStatusUnderMaint {
+		err := tx.QueryRowContext(ctx,
+			`SELECT restore_status FROM work_orders WHERE asset_id = $1 AND status = $2 ORDER BY id LIMIT 1`,
+			order.AssetID, domain.WorkOrderStatusOpen,
+		).Scan(&order.RestoreStatus)
+		if err == sql.ErrNoRows {
+			order.RestoreStatus = domain.AssetStatusActive
+		} else if err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to get open work orders", 500)
+		}
+	}
+
+	query := `
+		INSERT INTO work_orders (
+			asset_id, schedule_id, title, description, vendor, vendor_contact, estimated_cost, photo_urls,
+			status, restore_status, opened_by, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, $10, $11, $12, $12)
+		RETURNING id
+	`
+
+	now := time.Now()
+	if err := tx.QueryRowContext(
+		ctx, query,
+		order.AssetID,
+		order.ScheduleID,
+		order.Title,
+		order.Description,
+		order.Vendor,
+		order.VendorContact,
+		order.EstimatedCost,
+		pq.Array(order.PhotoURLs),
+		order.Status,
+		order.RestoreStatus,
+		order.OpenedBy,
+		now,
+	).Scan(&order.ID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create work order", 500)
+	}
+
+	if status != domain.AssetStatusUnderMaint {
+		if err := changeStatus(ctx, tx, &domain.AssetStatusChange{
+			AssetID:    order.AssetID,
+			FromStatus: status,
+			ToStatus:   domain.AssetStatusUnderMaint,
+			Reason:     fmt.Sprintf("Work order #%d opened: %s", order.ID, order.Title),
+			ChangedBy:  order.OpenedBy,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit work order", 500)
+	}
+
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	return nil
+}
+
+// UpdateWorkOrder updates the details of an open work order
+func (r *repository) UpdateWorkOrder(ctx context.Context, order *domain.WorkOrder) error {
+	query := `
+		UPDATE work_orders
+		SET title = $3, description = $4, vendor = NULLIF($5, ''), vendor_contact = NULLIF($6, ''),
+		    estimated_cost = $7, photo_urls = $8, updated_at = $9
+		WHERE id = $1 AND asset_id = $2 AND status = $10
+	`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(
+		ctx, query,
+		order.ID,
+		order.AssetID,
+		order.Title,
+		order.Description,
+		order.Vendor,
+		order.VendorContact,
+		order.EstimatedCost,
+		pq.Array(order.PhotoURLs),
+		now,
+		domain.WorkOrderStatusOpen,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update work order", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Work order is no longer open", 409)
+	}
+
+	order.UpdatedAt = now
+	return nil
+}
+
+// CloseWorkOrder completes or cancels an open work order. A completed one
+// advances its schedule and posts its cost to the ledger. Once no work order
+// of the asset is open, the asset returns to the status it had before.
+func (r *repository) CloseWorkOrder(ctx context.Context, order *domain.WorkOrder, compose MaintenanceExpenseFunc) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	// Lock the asset first, as opening a work order does
+	status, err := lockAssetStatus(ctx, tx, order.AssetID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE work_orders
+		SET status = $2, actual_cost = $3, photo_urls = $4, closed_by = $5, completed_on = $6, closed_at = $7,
+		    close_notes = $8, updated_at = $7
+		WHERE id = $1 AND status = $9
+	`
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, query,
+		order.ID, order.Status, order.ActualCost, pq.Array(order.PhotoURLs), order.ClosedBy, order.CompletedOn,
+		now, order.CloseNotes, domain.WorkOrderStatusOpen,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to close work order", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Work order is no longer open", 409)
+	}
+
+	if order.Status == domain.WorkOrderStatusCompleted && order.ScheduleID != nil {
+		schedule, err := scanSchedule(tx.QueryRowContext(ctx,
+			`SELECT `+scheduleColumns+` FROM maintenance_schedules WHERE id = $1 FOR UPDATE`, *order.ScheduleID,
+		))
+		if err != nil && err != sql.ErrNoRows {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to lock maintenance schedule", 500)
+		}
+		if err == nil {
+			schedule.Advance(*order.CompletedOn)
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE maintenance_schedules SET last_done_date = $1, next_due_date = $2, updated_at = $3 WHERE id = $4`,
+				schedule.LastDoneDate, schedule.NextDueDate, now, schedule.ID,
+			); err != nil {
+				return errors.Wrap(err, errors.ErrCodeInternal, "Failed to advance maintenance schedule", 500)
+			}
+		}
+	}
+
+	var open int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM work_orders WHERE asset_id = $1 AND status = $2`,
+		order.AssetID, domain.WorkOrderStatusOpen,
+	).Scan(&open); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to count open work orders", 500)
+	}
+
+	// Leave the asset alone if someone already moved it out of maintenance
+	if open == 0 && status == domain.AssetStatusUnderMaint && order.RestoreStatus != domain.AssetStatusUnderMaint {
+		if err := changeStatus(ctx, tx, &domain.AssetStatusChange{
+			AssetID:    order.AssetID,
+			FromStatus: status,
+			ToStatus:   order.RestoreStatus,
+			Reason:     fmt.Sprintf("Work order #%d %s: %s", order.ID, order.Status, order.Title),
+			ChangedBy:  *order.ClosedBy,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if order.Status == domain.WorkOrderStatusCompleted {
+		entries := compose(order)
+		for _, entry := range entries {
+			entry.ReferenceID = order.ID
+		}
+		if err := journal.Append(ctx, tx, entries...); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit work order", 500)
+	}
+
+	order.ClosedAt = &now
+	order.UpdatedAt = now
+	return nil
+}
+
+// FindWorkOrder finds a work order of an asset
+func (r *repository) FindWorkOrder(ctx context.Context, assetID, orderID int64) (*domain.WorkOrder, error) {
+	query := `SELECT ` + workOrderColumns + ` FROM work_orders WHERE id = $1 AND asset_id = $2`
+
+	order, err := scanWorkOrder(r.db.QueryRowContext(ctx, query, orderID, assetID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Work order not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find work order", 500)
+	}
+
+	return order, nil
+}
+
+// GetWorkOrders gets the work orders of an asset, optionally in one status,
+// latest first
+func (r *repository) GetWorkOrders(ctx context.Context, assetID int64, status domain.WorkOrderStatus, limit, offset int) ([]*domain.WorkOrder, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM work_orders WHERE asset_id = $1 AND ($2 = '' OR status = $2)`
+	if err := r.db.QueryRowContext(ctx, countQuery, assetID, status).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count work orders", 500)
+	}
+
+	query := `
+		SELECT ` + workOrderColumns + `
+		FROM work_orders
+		WHERE asset_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID, status, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get work orders", 500)
+	}
+	defer rows.Close()
+
+	orders := make([]*domain.WorkOrder, 0)
+	for rows.Next() {
+		order, err := scanWorkOrder(rows)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan work order", 500)
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, total, rows.Err()
+}
+
+// lockAssetStatus locks an asset for the rest of tx and gets its status
+func lockAssetStatus(ctx context.Context, tx *sql.Tx, assetID int64) (domain.AssetStatus, error) {
+	var status domain.AssetStatus
+	err := tx.QueryRowContext(ctx, `SELECT status FROM assets WHERE id = $1 FOR UPDATE`, assetID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", errors.New(errors.ErrCodeNotFound, "Asset not found", 404)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, errors.ErrCodeInternal, "Failed to lock asset", 500)
+	}
+	return status, nil
+}
+
+// scanSchedule scans a row selected with scheduleColumns
+func scanSchedule(row rowScanner) (*domain.MaintenanceSchedule, error) {
+	schedule := &domain.MaintenanceSchedule{}
+	var lastDoneDate sql.NullTime
+
+	if err := row.Scan(
+		&schedule.ID, &schedule.AssetID, &schedule.Title, &schedule.Description, &schedule.IntervalMonths,
+		&schedule.NextDueDate, &lastDoneDate, &schedule.EstimatedCost, &schedule.IsActive, &schedule.CreatedBy,
+		&schedule.CreatedAt, &schedule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if lastDoneDate.Valid {
+		schedule.LastDoneDate = &lastDoneDate.Time
+	}
+
+	return schedule, nil
+}
+
+// scanWorkOrder scans a row selected with workOrderColumns
+func scanWorkOrder(row rowScanner) (*domain.WorkOrder, error) {
+	order := &domain.WorkOrder{}
+	var scheduleID, actualCost, closedBy sql.NullInt64
+	var completedOn, closedAt sql.NullTime
+
+	if err := row.Scan(
+		&order.ID, &order.AssetID, &scheduleID, &order.Title, &order.Description, &order.Vendor,
+		&order.VendorContact, &order.EstimatedCost, &actualCost, pq.Array(&order.PhotoURLs), &order.Status,
+		&order.RestoreStatus, &order.OpenedBy, &closedBy, &completedOn, &closedAt, &order.CloseNotes,
+		&order.CreatedAt, &order.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if scheduleID.Valid {
+		order.ScheduleID = &scheduleID.Int64
+	}
+	if actualCost.Valid {
+		order.ActualCost = &actualCost.Int64
+	}
+	if closedBy.Valid {
+		order.ClosedBy = &closedBy.Int64
+	}
+	if completedOn.Valid {
+		order.CompletedOn = &completedOn.Time
+	}
+	if closedAt.Valid {
+		order.ClosedAt = &closedAt.Time
+	}
+	if order.PhotoURLs == nil {
+		order.PhotoURLs = []string{}
+	}
+
+	return order, nil
+}
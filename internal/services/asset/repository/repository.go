@@ -0,0 +1,436 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/notify"
+	"github.com/lib/pq"
+)
+
+// CampaignRef is the subset of a campaign needed to own assets
+type CampaignRef struct {
+	ID       int64
+	NazirID  int64
+	TenantID *int64
+}
+
+// ListFilter filters asset listings; empty fields match everything
+type ListFilter struct {
+	CampaignID int64
+	Type       domain.AssetType
+	Status     domain.AssetStatus
+	NazirID    int64
+	TenantID   *int64
+	Region     string // GeoJSON polygon the asset must lie inside
+}
+
+// Repository defines asset repository interface
+type Repository interface {
+	FindCampaign(ctx context.Context, campaignID int64) (*CampaignRef, error)
+	Create(ctx context.Context, asset *domain.Asset) error
+	Update(ctx context.Context, asset *domain.Asset) error
+	UpdateStatus(ctx context.Context, asset *domain.Asset, change *domain.AssetStatusChange) error
+	FindByID(ctx context.Context, id int64) (*domain.Asset, error)
+	List(ctx context.Context, filter *ListFilter, limit, offset int) ([]*domain.Asset, int64, error)
+	GetStatusChanges(ctx context.Context, assetID int64) ([]*domain.AssetStatusChange, error)
+	CreateDocument(ctx context.Context, document *domain.AssetDocument) error
+	UpdateDocument(ctx context.Context, document *domain.AssetDocument) error
+	DeleteDocument(ctx context.Context, assetID, documentID int64) error
+	FindDocument(ctx context.Context, assetID, documentID int64) (*domain.AssetDocument, error)
+	GetDocuments(ctx context.Context, assetID int64, documentType string) ([]*domain.AssetDocument, error)
+	CreateValuation(ctx context.Context, valuation *domain.AssetValuation) error
+	ApproveValuation(ctx context.Context, valuation *domain.AssetValuation, compose RevaluationFunc) error
+	RejectValuation(ctx context.Context, valuation *domain.AssetValuation) error
+	FindValuation(ctx context.Context, assetID, valuationID int64) (*domain.AssetValuation, error)
+	GetValuations(ctx context.Context, assetID int64, status domain.ValuationStatus, limit, offset int) ([]*domain.AssetValuation, int64, error)
+	GetValuationTimeline(ctx context.Context, assetID int64) ([]*domain.AssetValuation, error)
+	GetPendingValuations(ctx context.Context, limit, offset int) ([]*domain.AssetValuation, int64, error)
+	SaveBoundary(ctx context.Context, data *domain.GeospatialData) (bool, error)
+	FindBoundary(ctx context.Context, assetID int64) (*domain.GeospatialData, error)
+	DeleteBoundary(ctx context.Context, assetID int64) error
+	Nearby(ctx context.Context, filter *ListFilter, latitude, longitude, radius float64, limit int) ([]*NearbyAsset, error)
+	ListFeatures(ctx context.Context, filter *ListFilter, limit int) ([]*AssetFeature, error)
+	FindOverlaps(ctx context.Context, assetID int64) ([]*BoundaryOverlap, error)
+	ListOverlaps(ctx context.Context, limit, offset int) ([]*BoundaryOverlap, int64, error)
+	ListAreaMismatches(ctx context.Context, tolerance float64, limit, offset int) ([]*AreaMismatch, int64, error)
+	MapVersion(ctx context.Context) (int64, error)
+	Tile(ctx context.Context, z, x, y int, tolerance float64, asPoints bool) ([]byte, error)
+	CreateLease(ctx context.Context, lease *domain.Lease) error
+	TerminateLease(ctx context.Context, lease *domain.Lease) error
+	FindLease(ctx context.Context, assetID, leaseID int64) (*domain.Lease, error)
+	GetLeases(ctx context.Context, assetID int64, status domain.LeaseStatus, limit, offset int) ([]*domain.Lease, int64, error)
+	GetLeasesToInvoice(ctx context.Context, day time.Time) ([]*InvoiceDue, error)
+	EndExpiredLeases(ctx context.Context, day time.Time) (int, error)
+	CreateInvoices(ctx context.Context, invoices ...*domain.LeaseInvoice) (int, error)
+	GetInvoices(ctx context.Context, leaseID int64) ([]*domain.LeaseInvoice, error)
+	RecordLeasePayment(ctx context.Context, payment *domain.LeasePayment, compose RentIncomeFunc) error
+	GetLeasePayments(ctx context.Context, leaseID int64) ([]*domain.LeasePayment, error)
+	GetOverdueInvoices(ctx context.Context, day time.Time, nazirID int64, limit, offset int) ([]*OverdueInvoice, int64, error)
+	QueueNotifications(ctx context.Context, messages ...*notify.Message) error
+	CreateSchedule(ctx context.Context, schedule *domain.MaintenanceSchedule) error
+	UpdateSchedule(ctx context.Context, schedule *domain.MaintenanceSchedule) error
+	FindSchedule(ctx context.Context, assetID, scheduleID int64) (*domain.MaintenanceSchedule, error)
+	GetSchedules(ctx context.Context, assetID int64) ([]*domain.MaintenanceSchedule, error)
+	GetDueSchedules(ctx context.Context, day time.Time, within int, nazirID int64, limit, offset int) ([]*DueSchedule, int64, error)
+	CreateWorkOrder(ctx context.Context, order *domain.WorkOrder) error
+	UpdateWorkOrder(ctx context.Context, order *domain.WorkOrder) error
+	CloseWorkOrder(ctx context.Context, order *domain.WorkOrder, compose MaintenanceExpenseFunc) error
+	FindWorkOrder(ctx context.Context, assetID, orderID int64) (*domain.WorkOrder, error)
+	GetWorkOrders(ctx context.Context, assetID int64, status domain.WorkOrderStatus, limit, offset int) ([]*domain.WorkOrder, int64, error)
+	GetDepreciationDefaults(ctx context.Context) ([]*domain.DepreciationDefault, error)
+	SaveDepreciationDefault(ctx context.Context, d *domain.DepreciationDefault) error
+	DeleteDepreciationDefault(ctx context.Context, assetType domain.AssetType) error
+	SaveAssetDepreciation(ctx context.Context, d *domain.AssetDepreciation) error
+	DeleteAssetDepreciation(ctx context.Context, assetID int64) error
+	FindDepreciationPolicy(ctx context.Context, asset *domain.Asset) (*domain.DepreciationPolicy, error)
+	GetDepreciationEntries(ctx context.Context, assetID int64) ([]*domain.DepreciationEntry, error)
+	GetDepreciationDue(ctx context.Context, through time.Time) ([]*DepreciationDue, error)
+	PostDepreciation(ctx context.Context, assetID int64, entries []*domain.DepreciationEntry, compose DepreciationFunc) error
+	CreateCertification(ctx context.Context, workflow *domain.CertificationWorkflow) error
+	SaveCertification(ctx context.Context, workflow *domain.CertificationWorkflow, steps ...*domain.CertificationStep) error
+	FindCertification(ctx context.Context, assetID int64) (*domain.CertificationWorkflow, error)
+	ListCertifications(ctx context.Context, filter *CertificationFilter, limit, offset int) ([]*StuckParcel, int64, error)
+	SummarizeCertifications(ctx context.Context, day time.Time, nazirID int64) ([]*StageSummary, error)
+	GetExpiringDocuments(ctx context.Context, filter *ExpiryFilter, limit, offset int) ([]*ExpiringDocument, int64, error)
+	SummarizeCompliance(ctx context.Context, filter *ExpiryFilter) ([]*ComplianceTotal, error)
+	RecordExpiryAlert(ctx context.Context, alert *domain.DocumentExpiryAlert, messages ...*notify.Message) (bool, error)
+	GetExpiryAlerts(ctx context.Context, filter *ExpiryAlertFilter, limit, offset int) ([]*ExpiryAlert, int64, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// New creates a new asset repository
+func New(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+const assetColumns = `
+	id, campaign_id, type, status, name, COALESCE(description, ''), COALESCE(location, ''), latitude,
+	longitude, area, COALESCE(area_unit, ''), purchase_value, current_value, last_valuation_at,
+	acquisition_date, tenant_id, created_at, updated_at
+`
+
+// FindCampaign finds the campaign an asset belongs to with its nazir
+func (r *repository) FindCampaign(ctx context.Context, campaignID int64) (*CampaignRef, error) {
+	query := `SELECT id, nazir_id, tenant_id FROM campaigns WHERE id = $1`
+
+	campaign := &CampaignRef{}
+	var tenantID sql.NullInt64
+	err := r.db.QueryRowContext(ctx, query, campaignID).Scan(&campaign.ID, &campaign.NazirID, &tenantID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find campaign", 500)
+	}
+
+	if tenantID.Valid {
+		campaign.TenantID = &tenantID.Int64
+	}
+	return campaign, nil
+}
+
+// Create creates a new asset
+func (r *repository) Create(ctx context.Context, asset *domain.Asset) error {
+	query := `
+		INSERT INTO assets (
+			campaign_id, type, status, name, description, location, latitude, longitude, area, area_unit,
+			purchase_value, current_value, acquisition_date, tenant_id, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, $12, $13, $14, $15, $15)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(
+		ctx, query,
+		asset.CampaignID,
+		asset.Type,
+		asset.Status,
+		asset.Name,
+		asset.Description,
+		asset.Location,
+		asset.Latitude,
+		asset.Longitude,
+		asset.Area,
+		asset.AreaUnit,
+		asset.PurchaseValue,
+		asset.CurrentValue,
+		asset.AcquisitionDate,
+		asset.TenantID,
+		now,
+	).Scan(&asset.ID)
+
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create asset", 500)
+	}
+
+	asset.CreatedAt = now
+	asset.UpdatedAt = now
+	return nil
+}
+
+// Update updates asset details. Status and value have their own flows and
+// are left untouched.
+func (r *repository) Update(ctx context.Context, asset *domain.Asset) error {
+	query := `
+		UPDATE assets
+		SET type = $2, name = $3, description = $4, location = $5, latitude = $6, longitude = $7,
+		    area = $8, area_unit = NULLIF($9, ''), purchase_value = $10, acquisition_date = $11, updated_at = $12
+		WHERE id = $1
+	`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(
+		ctx, query,
+		asset.ID,
+		asset.Type,
+		asset.Name,
+		asset.Description,
+		asset.Location,
+		asset.Latitude,
+		asset.Longitude,
+		asset.Area,
+		asset.AreaUnit,
+		asset.PurchaseValue,
+		asset.AcquisitionDate,
+		now,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update asset", 500)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update asset", 500)
+	}
+	if rows == 0 {
+		return errors.New(errors.ErrCodeNotFound, "Asset not found", 404)
+	}
+
+	asset.UpdatedAt = now
+	return nil
+}
+
+// UpdateStatus moves an asset to the change's status and records the change.
+// It fails with a conflict if the status changed concurrently.
+func (r *repository) UpdateStatus(ctx context.Context, asset *domain.Asset, change *domain.AssetStatusChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	change.AssetID = asset.ID
+	if err := changeStatus(ctx, tx, change); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit asset status", 500)
+	}
+
+	asset.Status = change.ToStatus
+	asset.UpdatedAt = change.CreatedAt
+	return nil
+}
+
+// changeStatus moves an asset from the change's status to its new one inside
+// tx and records the change
+func changeStatus(ctx context.Context, tx *sql.Tx, change *domain.AssetStatusChange) error {
+	now := time.Now()
+	result, err := tx.ExecContext(ctx,
+		`UPDATE assets SET status = $2, updated_at = $3 WHERE id = $1 AND status = $4`,
+		change.AssetID, change.ToStatus, now, change.FromStatus,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update asset status", 500)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update asset status", 500)
+	}
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Asset status was changed by another request", 409)
+	}
+
+	query := `
+		INSERT INTO asset_status_changes (asset_id, from_status, to_status, reason, changed_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	if err := tx.QueryRowContext(ctx, query,
+		change.AssetID, change.FromStatus, change.ToStatus, change.Reason, change.ChangedBy, now,
+	).Scan(&change.ID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to record asset status change", 500)
+	}
+
+	change.CreatedAt = now
+	return nil
+}
+
+// FindByID finds asset by ID
+func (r *repository) FindByID(ctx context.Context, id int64) (*domain.Asset, error) {
+	query := `SELECT ` + assetColumns + ` FROM assets WHERE id = $1`
+
+	asset, err := scanAsset(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Asset not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find asset", 500)
+	}
+
+	return asset, nil
+}
+
+// List lists assets matching filter, newest first
+func (r *repository) List(ctx context.Context, filter *ListFilter, limit, offset int) ([]*domain.Asset, int64, error) {
+	where, args := filter.where()
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM ` + locatedAssets + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count assets", 500)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s%s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		assetColumns, locatedAssets, where, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to list assets", 500)
+	}
+	defer rows.Close()
+
+	assets := make([]*domain.Asset, 0)
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan asset", 500)
+		}
+		assets = append(assets, asset)
+	}
+
+	return assets, total, rows.Err()
+}
+
+// GetStatusChanges gets the status history of an asset, latest first
+func (r *repository) GetStatusChanges(ctx context.Context, assetID int64) ([]*domain.AssetStatusChange, error) {
+	query := `
+		SELECT id, asset_id, from_status, to_status, COALESCE(reason, ''), changed_by, created_at
+		FROM asset_status_changes
+		WHERE asset_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get asset status history", 500)
+	}
+	defer rows.Close()
+
+	changes := make([]*domain.AssetStatusChange, 0)
+	for rows.Next() {
+		change := &domain.AssetStatusChange{}
+		if err := rows.Scan(
+			&change.ID, &change.AssetID, &change.FromStatus, &change.ToStatus, &change.Reason,
+			&change.ChangedBy, &change.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan asset status change", 500)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// where builds the WHERE clause and its arguments
+func (f *ListFilter) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.CampaignID != 0 {
+		add("campaign_id = $%d", f.CampaignID)
+	}
+	if f.Type != "" {
+		add("type = $%d", f.Type)
+	}
+	if f.Status != "" {
+		add("status = $%d", f.Status)
+	}
+	if f.NazirID != 0 {
+		add("campaign_id IN (SELECT id FROM campaigns WHERE nazir_id = $%d)", f.NazirID)
+	}
+	if f.TenantID != nil {
+		add("tenant_id = $%d", *f.TenantID)
+	}
+	if f.Region != "" {
+		add("ST_CoveredBy(shape, ST_SetSRID(ST_GeomFromGeoJSON($%d), 4326))", f.Region)
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAsset scans a row selected with assetColumns
+func scanAsset(row rowScanner) (*domain.Asset, error) {
+	asset := &domain.Asset{}
+	var latitude, longitude, area sql.NullFloat64
+	var lastValuationAt sql.NullTime
+	var tenantID sql.NullInt64
+
+	if err := row.Scan(
+		&asset.ID, &asset.CampaignID, &asset.Type, &asset.Status, &asset.Name, &asset.Description,
+		&asset.Location, &latitude, &longitude, &area, &asset.AreaUnit, &asset.PurchaseValue,
+		&asset.CurrentValue, &lastValuationAt, &asset.AcquisitionDate, &tenantID, &asset.CreatedAt,
+		&asset.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if latitude.Valid {
+		asset.Latitude = &latitude.Float64
+	}
+	if longitude.Valid {
+		asset.Longitude = &longitude.Float64
+	}
+	if area.Valid {
+		asset.Area = &area.Float64
+	}
+	if lastValuationAt.Valid {
+		asset.LastValuationAt = &lastValuationAt.Time
+	}
+	if tenantID.Valid {
+		asset.TenantID = &tenantID.Int64
+	}
+
+	return asset, nil
+}
+
+// isUniqueViolation checks for a PostgreSQL unique_violation error
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// tileExtent is the coordinate space of a vector tile
+const tileExtent = 4096
+
+// tileLayer is the name of the asset layer in vector tiles
+const tileLayer = "assets"
+
+// MapVersion gets the version of the asset map, which changes whenever an
+// asset is added, moved, renamed or changes type or status
+func (r *repository) MapVersion(ctx context.Context) (int64, error) {
+	var version int64
+	err := r.db.QueryRowContext(ctx, `SELECT version FROM asset_map_version WHERE id = 1`).Scan(&version)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get asset map version", 500)
+	}
+	return version, nil
+}
+
+// Tile builds a Mapbox Vector Tile of the live assets in tile z/x/y with
+// their id, name, type and status. Boundaries are simplified to tolerance
+// meters, or drawn as a point inside them when asPoints is set.
+func (r *repository) Tile(ctx context.Context, z, x, y int, tolerance float64, asPoints bool) ([]byte, error) {
+	query := fmt.Sprintf(`
+		WITH tile AS (SELECT ST_TileEnvelope($1, $2, $3) AS envelope)
+		SELECT ST_AsMVT(features, '%s', %d, 'geom', 'id')
+		FROM (
+			SELECT id, name, type, status,
+			       ST_AsMVTGeom(
+			           CASE WHEN $4 THEN ST_Transform(ST_PointOnSurface(shape), 3857)
+			                ELSE ST_SimplifyPreserveTopology(ST_Transform(shape, 3857), $5)
+			           END,
+			           tile.envelope, %d, 64, true
+			       ) AS geom
+			FROM %s, tile
+			WHERE status <> $6 AND shape IS NOT NULL
+			  AND ST_Intersects(shape, ST_Transform(tile.envelope, 4326))
+		) features
+		WHERE geom IS NOT NULL
+	`, tileLayer, tileExtent, tileExtent, locatedAssets)
+
+	var tile []byte
+	if err := r.db.QueryRowContext(ctx, query, z, x, y, asPoints, tolerance, domain.AssetStatusDisposed).Scan(&tile); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to build asset tile", 500)
+	}
+	return tile, nil
+}
@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/ledger/journal"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// RevaluationFunc builds the ledger entries of an approved valuation given
+// the asset's carrying value before it, dated the given day
+type RevaluationFunc func(valuation *domain.AssetValuation, previous int64, postedOn time.Time) []*domain.Ledger
+
+const valuationColumns = `
+	id, asset_id, valuation_value, valuation_date, COALESCE(valued_by, ''), COALESCE(method, ''),
+	COALESCE(notes, ''), COALESCE(document_url, ''), created_at, status, previous_value, adjustment,
+	recorded_by, decided_by, decided_at, COALESCE(rejection_reason, '')
+`
+
+// CreateValuation records a valuation of an asset awaiting approval
+func (r *repository) CreateValuation(ctx context.Context, valuation *domain.AssetValuation) error {
+	query := `
+		INSERT INTO asset_valuations (
+			asset_id, valuation_value, valuation_date, valued_by, method, notes, document_url, status,
+			recorded_by, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(
+		ctx, query,
+		valuation.AssetID,
+		valuation.ValuationValue,
+		valuation.ValuationDate,
+		valuation.ValuedBy,
+		valuation.Method,
+		valuation.Notes,
+		valuation.DocumentURL,
+		valuation.Status,
+		valuation.RecordedBy,
+		now,
+	).Scan(&valuation.ID)
+
+	if isUniqueViolation(err) {
+		return errors.New(errors.ErrCodeConflict, "Another valuation of this asset is awaiting approval", 409)
+	}
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create asset valuation", 500)
+	}
+
+	valuation.CreatedAt = now
+	return nil
+}
+
+// ApproveValuation applies a pending valuation to its asset: the asset takes
+// the appraised value and the difference is posted to the ledger on the
+// valuation date, or today when that date is in a closed period
+func (r *repository) ApproveValuation(ctx context.Context, valuation *domain.AssetValuation, compose RevaluationFunc) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	var previous int64
+	var status domain.AssetStatus
+	var lastValuationAt sql.NullTime
+	err = tx.QueryRowContext(ctx,
+		`SELECT current_value, status, last_valuation_at FROM assets WHERE id = $1 FOR UPDATE`,
+		valuation.AssetID,
+	).Scan(&previous, &status, &lastValuationAt)
+	if err == sql.ErrNoRows {
+		return errors.New(errors.ErrCodeNotFound, "Asset not found", 404)
+	}
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to lock asset", 500)
+	}
+
+	if status == domain.AssetStatusDisposed {
+		return errors.New(errors.ErrCodeConflict, "Disposed assets cannot be revalued", 409)
+	}
+	if lastValuationAt.Valid && valuation.ValuationDate.Before(lastValuationAt.Time) {
+		return errors.New(errors.ErrCodeConflict, "A later valuation of this asset has already been approved", 409)
+	}
+
+	now := time.Now()
+	adjustment := valuation.ValuationValue - previous
+	query := `
+		UPDATE asset_valuations
+		SET status = $1, previous_value = $2, adjustment = $3, decided_by = $4, decided_at = $5
+		WHERE id = $6 AND status = $7
+	`
+	result, err := tx.ExecContext(ctx, query,
+		domain.ValuationStatusApproved, previous, adjustment, valuation.DecidedBy, now, valuation.ID,
+		domain.ValuationStatusPending,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to approve asset valuation", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Valuation is no longer awaiting approval", 409)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE assets SET current_value = $1, last_valuation_at = $2, updated_at = $3 WHERE id = $4`,
+		valuation.ValuationValue, valuation.ValuationDate, now, valuation.AssetID,
+	); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update asset value", 500)
+	}
+
+	postedOn := valuation.ValuationDate
+	closed, err := journal.IsClosed(ctx, tx, postedOn)
+	if err != nil {
+		return err
+	}
+	if closed {
+		postedOn = now
+	}
+
+	entries := compose(valuation, previous, postedOn)
+	for _, entry := range entries {
+		entry.ReferenceID = valuation.ID
+		entry.BalanceBefore = previous
+		entry.BalanceAfter = valuation.ValuationValue
+	}
+	if err := journal.Append(ctx, tx, entries...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit asset valuation approval", 500)
+	}
+
+	valuation.Status = domain.ValuationStatusApproved
+	valuation.PreviousValue = &previous
+	valuation.Adjustment = &adjustment
+	valuation.DecidedAt = &now
+	return nil
+}
+
+// RejectValuation rejects a pending valuation, leaving the asset as it was
+func (r *repository) RejectValuation(ctx context.Context, valuation *domain.AssetValuation) error {
+	query := `
+		UPDATE asset_valuations
+		SET status = $1, decided_by = $2, decided_at = $3, rejection_reason = $4
+		WHERE id = $5 AND status = $6
+	`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query,
+		domain.ValuationStatusRejected, valuation.DecidedBy, now, valuation.RejectionReason, valuation.ID,
+		domain.ValuationStatusPending,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to reject asset valuation", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Valuation is no longer awaiting approval", 409)
+	}
+
+	valuation.Status = domain.ValuationStatusRejected
+	valuation.DecidedAt = &now
+	return nil
+}
+
+// FindValuation finds a valuation of an asset
+func (r *repository) FindValuation(ctx context.Context, assetID, valuationID int64) (*domain.AssetValuation, error) {
+	query := `SELECT ` + valuationColumns + ` FROM asset_valuations WHERE id = $1 AND asset_id = $2`
+
+	valuation, err := scanValuation(r.db.QueryRowContext(ctx, query, valuationID, assetID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Valuation not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find asset valuation", 500)
+	}
+
+	return valuation, nil
+}
+
+// GetValuations gets the valuations of an asset, optionally in one status,
+// latest first
+func (r *repository) GetValuations(ctx context.Context, assetID int64, status domain.ValuationStatus, limit, offset int) ([]*domain.AssetValuation, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM asset_valuations WHERE asset_id = $1 AND ($2 = '' OR status = $2)`
+	if err := r.db.QueryRowContext(ctx, countQuery, assetID, status).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count asset valuations", 500)
+	}
+
+	query := `
+		SELECT ` + valuationColumns + `
+		FROM asset_valuations
+		WHERE asset_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY valuation_date DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID, status, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get asset valuations", 500)
+	}
+	defer rows.Close()
+
+	valuations, err := scanValuations(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return valuations, total, nil
+}
+
+// GetValuationTimeline gets the approved valuations of an asset, oldest first
+func (r *repository) GetValuationTimeline(ctx context.Context, assetID int64) ([]*domain.AssetValuation, error) {
+	query := `
+		SELECT ` + valuationColumns + `
+		FROM asset_valuations
+		WHERE asset_id = $1 AND status = $2
+		ORDER BY valuation_date, decided_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID, domain.ValuationStatusApproved)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get asset valuation timeline", 500)
+	}
+	defer rows.Close()
+
+	return scanValuations(rows)
+}
+
+// GetPendingValuations gets valuations awaiting approval, oldest first
+func (r *repository) GetPendingValuations(ctx context.Context, limit, offset int) ([]*domain.AssetValuation, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM asset_valuations WHERE status = $1`
+	if err := r.db.QueryRowContext(ctx, countQuery, domain.ValuationStatusPending).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count pending valuations", 500)
+	}
+
+	query := `
+		SELECT ` + valuationColumns + `
+		FROM asset_valuations
+		WHERE status = $1
+		ORDER BY created_at, id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.ValuationStatusPending, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get pending valuations", 500)
+	}
+	defer rows.Close()
+
+	valuations, err := scanValuations(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return valuations, total, nil
+}
+
+// scanValuations scans valuation rows
+func scanValuations(rows *sql.Rows) ([]*domain.AssetValuation, error) {
+	valuations := make([]*domain.AssetValuation, 0)
+	for rows.Next() {
+		valuation, err := scanValuation(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan asset valuation", 500)
+		}
+		valuations = append(valuations, valuation)
+	}
+
+	return valuations, rows.Err()
+}
+
+// scanValuation scans a row selected with valuationColumns
+func scanValuation(row rowScanner) (*domain.AssetValuation, error) {
+	valuation := &domain.AssetValuation{}
+	var previousValue, adjustment, decidedBy sql.NullInt64
+	var decidedAt sql.NullTime
+
+	if err := row.Scan(
+		&valuation.ID, &valuation.AssetID, &valuation.ValuationValue, &valuation.ValuationDate,
+		&valuation.ValuedBy, &valuation.Method, &valuation.Notes, &valuation.DocumentURL, &valuation.CreatedAt,
+		&valuation.Status, &previousValue, &adjustment, &valuation.RecordedBy, &decidedBy, &decidedAt,
+		&valuation.RejectionReason,
+	); err != nil {
+		return nil, err
+	}
+
+	if previousValue.Valid {
+		valuation.PreviousValue = &previousValue.Int64
+	}
+	if adjustment.Valid {
+		valuation.Adjustment = &adjustment.Int64
+	}
+	if decidedBy.Valid {
+		valuation.DecidedBy = &decidedBy.Int64
+	}
+	if decidedAt.Valid {
+		valuation.DecidedAt = &decidedAt.Time
+	}
+
+	return valuation, nil
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/services/asset/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// GetCertification gets the latest certification workflow of an asset
+func (s *service) GetCertification(ctx context.Context, actor *domain.Actor, assetID int64) (*dto.CertificationResponse, error) {
+	if _, err := s.viewable(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	workflow, err := s.repo.FindCertification(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.certificationResponse(ctx, workflow)
+}
+
+// StartCertification starts the certification of a land asset as wakaf land.
+// The ikrar is under way from the start; the later steps wait their turn.
+func (s *service) StartCertification(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.StartCertificationRequest) (*dto.CertificationResponse, error) {
+	asset, err := s.authorize(ctx, actor, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Type != domain.AssetTypeLand {
+		return nil, errors.New(errors.ErrCodeValidation, "Only land assets go through wakaf certification", 400)
+	}
+	if asset.Status == domain.AssetStatusDisposed {
+		return nil, errors.New(errors.ErrCodeConflict, "Disposed assets cannot be certified", 409)
+	}
+
+	now := time.Now()
+	workflow := &domain.CertificationWorkflow{
+		AssetID:   assetID,
+		Status:    domain.CertificationInProgress,
+		Notes:     req.Notes,
+		StartedBy: actor.UserID,
+		Steps:     make([]*domain.CertificationStep, 0, len(domain.CertificationStages)),
+	}
+
+	for i, stage := range domain.CertificationStages {
+		step := &domain.CertificationStep{
+			Stage:     stage,
+			Sequence:  i + 1,
+			Status:    domain.StepPending,
+			UpdatedBy: actor.UserID,
+		}
+		if i == 0 {
+			step.Status = domain.StepInProgress
+			step.StartedAt = &now
+		}
+
+		if plan := req.Steps[stage]; plan != nil {
+			step.ResponsibleUserID = plan.ResponsibleUserID
+			step.ResponsibleName = strings.TrimSpace(plan.ResponsibleName)
+			if step.DueDate, err = parseDate("steps."+string(stage)+".due_date", plan.DueDate); err != nil {
+				return nil, err
+			}
+		}
+
+		workflow.Steps = append(workflow.Steps, step)
+	}
+
+	if err := s.repo.CreateCertification(ctx, workflow); err != nil {
+		return nil, err
+	}
+
+	return s.certificationResponse(ctx, workflow)
+}
+
+// UpdateCertificationStep records progress on a step: who handles it, its
+// due date and its status. Steps are completed in order, each only once
+// the documents it requires are uploaded to the asset. Completing the last
+// step completes the workflow.
+func (s *service) UpdateCertificationStep(ctx context.Context, actor *domain.Actor, assetID int64, stage domain.CertificationStage, req *dto.CertificationStepRequest) (*dto.CertificationResponse, error) {
+	if _, err := s.authorize(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	workflow, err := s.repo.FindCertification(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !workflow.IsActive() {
+		return nil, errors.New(errors.ErrCodeConflict, "Certification workflow is no longer in progress", 409)
+	}
+
+	step := workflow.Step(stage)
+	if step == nil {
+		return nil, errors.New(errors.ErrCodeNotFound, "Certification step not found", 404)
+	}
+	if step.Status == domain.StepCompleted {
+		return nil, errors.New(errors.ErrCodeConflict, "Certification step is already completed", 409)
+	}
+
+	if req.ResponsibleUserID != nil {
+		step.ResponsibleUserID = req.ResponsibleUserID
+	}
+	if req.ResponsibleName != nil {
+		step.ResponsibleName = strings.TrimSpace(*req.ResponsibleName)
+	}
+	if req.DueDate != nil {
+		if step.DueDate, err = parseDate("due_date", *req.DueDate); err != nil {
+			return nil, err
+		}
+	}
+	if req.Notes != nil {
+		step.Notes = *req.Notes
+	}
+	step.UpdatedBy = actor.UserID
+
+	changed := []*domain.CertificationStep{step}
+	now := time.Now()
+
+	if req.Status != "" && req.Status != step.Status {
+		if workflow.CurrentStep() != step {
+			return nil, errors.New(errors.ErrCodeConflict, "Previous certification steps must be completed first", 409)
+		}
+
+		switch req.Status {
+		case domain.StepCompleted:
+			documents, err := s.repo.GetDocuments(ctx, assetID, "")
+			if err != nil {
+				return nil, err
+			}
+			if missing := stage.MissingDocuments(documentTypes(documents)); len(missing) > 0 {
+				return nil, errors.New(errors.ErrCodeConflict, "Upload the documents this step requires first: "+strings.Join(missing, ", "), 409)
+			}
+
+			step.CompletedAt = &now
+			if next := nextStep(workflow, step); next != nil {
+				next.Status = domain.StepInProgress
+				next.StartedAt = &now
+				next.UpdatedBy = actor.UserID
+				changed = append(changed, next)
+			} else {
+				workflow.Status = domain.CertificationCompleted
+				workflow.CompletedAt = &now
+			}
+		case domain.StepInProgress, domain.StepBlocked:
+			if step.StartedAt == nil {
+				step.StartedAt = &now
+			}
+		}
+		step.Status = req.Status
+	}
+
+	if err := s.repo.SaveCertification(ctx, workflow, changed...); err != nil {
+		return nil, err
+	}
+
+	return s.certificationResponse(ctx, workflow)
+}
+
+// CancelCertification abandons a certification workflow in progress, e.g.
+// when the wakif withdraws before the ikrar. A new one may be started later.
+func (s *service) CancelCertification(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.CancelCertificationRequest) (*dto.CertificationResponse, error) {
+	if _, err := s.authorize(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	workflow, err := s.repo.FindCertification(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !workflow.IsActive() {
+		return nil, errors.New(errors.ErrCodeConflict, "Certification workflow is no longer in progress", 409)
+	}
+
+	workflow.Status = domain.CertificationCancelled
+	workflow.CancelReason = strings.TrimSpace(req.Reason)
+	if workflow.CancelReason == "" {
+		return nil, errors.New(errors.ErrCodeValidation, "A reason is required to cancel a certification", 400)
+	}
+
+	if err := s.repo.SaveCertification(ctx, workflow); err != nil {
+		return nil, err
+	}
+
+	return s.certificationResponse(ctx, workflow)
+}
+
+// ListCertifications lists the land assets under certification with the step
+// each is stuck at; nazirs only see their own assets
+func (s *service) ListCertifications(ctx context.Context, actor *domain.Actor, query *dto.CertificationQuery, page, perPage int) ([]*repository.StuckParcel, int64, error) {
+	day := today()
+	filter := &repository.CertificationFilter{
+		Stage:   query.Stage,
+		Status:  query.Status,
+		Overdue: query.Overdue,
+		Day:     day,
+	}
+	if actor.Role == domain.RoleNazir {
+		filter.NazirID = actor.UserID
+	}
+
+	parcels, total, err := s.repo.ListCertifications(ctx, filter, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, p := range parcels {
+		p.MissingDocuments = p.Step.Stage.MissingDocuments(p.DocumentTypes)
+		since := p.Step.UpdatedAt
+		if p.Step.StartedAt != nil {
+			since = *p.Step.StartedAt
+		}
+		p.DaysAtStep = daysSince(since, day)
+		if p.Step.IsOverdue(day) {
+			p.DaysOverdue = daysSince(*p.Step.DueDate, day)
+		}
+	}
+
+	return parcels, total, nil
+}
+
+// SummarizeCertifications counts the land assets under certification at
+// every stage, in the order of the stages
+func (s *service) SummarizeCertifications(ctx context.Context, actor *domain.Actor) ([]*repository.StageSummary, error) {
+	var nazirID int64
+	if actor.Role == domain.RoleNazir {
+		nazirID = actor.UserID
+	}
+
+	counted, err := s.repo.SummarizeCertifications(ctx, today(), nazirID)
+	if err != nil {
+		return nil, err
+	}
+
+	byStage := make(map[domain.CertificationStage]*repository.StageSummary, len(counted))
+	for _, summary := range counted {
+		byStage[summary.Stage] = summary
+	}
+
+	summaries := make([]*repository.StageSummary, 0, len(domain.CertificationStages))
+	for _, stage := range domain.CertificationStages {
+		summary := byStage[stage]
+		if summary == nil {
+			summary = &repository.StageSummary{Stage: stage}
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// certificationResponse adds the required and missing documents of each
+// step of a workflow
+func (s *service) certificationResponse(ctx context.Context, workflow *domain.CertificationWorkflow) (*dto.CertificationResponse, error) {
+	documents, err := s.repo.GetDocuments(ctx, workflow.AssetID, "")
+	if err != nil {
+		return nil, err
+	}
+	have := documentTypes(documents)
+
+	resp := &dto.CertificationResponse{
+		CertificationWorkflow: workflow,
+		Steps:                 make([]*dto.CertificationStepView, 0, len(workflow.Steps)),
+	}
+	if current := workflow.CurrentStep(); current != nil && workflow.IsActive() {
+		resp.CurrentStage = current.Stage
+	}
+
+	day := today()
+	for _, step := range workflow.Steps {
+		view := &dto.CertificationStepView{
+			CertificationStep: step,
+			RequiredDocuments: step.Stage.RequiredDocuments(),
+			MissingDocuments:  []string{},
+			Overdue:           workflow.IsActive() && step.IsOverdue(day),
+		}
+		if step.Status != domain.StepCompleted {
+			view.MissingDocuments = step.Stage.MissingDocuments(have)
+		}
+		resp.Steps = append(resp.Steps, view)
+	}
+
+	return resp, nil
+}
+
+// nextStep returns the step after the given one, or nil after the last
+func nextStep(workflow *domain.CertificationWorkflow, step *domain.CertificationStep) *domain.CertificationStep {
+	for _, s := range workflow.Steps {
+		if s.Sequence == step.Sequence+1 {
+			return s
+		}
+	}
+	return nil
+}
+
+// documentTypes returns the types of the given documents
+func documentTypes(documents []*domain.AssetDocument) []string {
+	types := make([]string, 0, len(documents))
+	for _, document := range documents {
+		types = append(types, document.DocumentType)
+	}
+	return types
+}
+
+// daysSince counts the whole days from a time to day
+func daysSince(from, day time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	if day.Before(from) {
+		return 0
+	}
+	return int(day.Sub(from).Hours() / 24)
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// Ledger accounts of depreciation: the expense of the month against the
+// contra-asset account that lowers the book value of fixed assets
+const (
+	accountDepreciationExpense     = "depreciation_expense"
+	accountAccumulatedDepreciation = "accumulated_depreciation"
+)
+
+// GetDepreciationDefaults gets the depreciation defaults of the asset types
+func (s *service) GetDepreciationDefaults(ctx context.Context, actor *domain.Actor) ([]*domain.DepreciationDefault, error) {
+	return s.repo.GetDepreciationDefaults(ctx)
+}
+
+// SaveDepreciationDefault sets the depreciation policy of every asset of a
+// type that has none of its own. Months already posted are kept; the new
+// policy applies from the next run.
+func (s *service) SaveDepreciationDefault(ctx context.Context, actor *domain.Actor, assetType domain.AssetType, req *dto.DepreciationDefaultRequest) (*domain.DepreciationDefault, error) {
+	if !actor.IsAdmin() {
+		return nil, errors.ErrForbidden
+	}
+
+	if !assetType.IsDepreciable() {
+		return nil, errors.New(errors.ErrCodeValidation, "Land is not depreciated", 400)
+	}
+
+	d := &domain.DepreciationDefault{
+		AssetType:        assetType,
+		Method:           req.Method,
+		UsefulLifeMonths: req.UsefulLifeMonths,
+		SalvageBPS:       req.SalvageBPS,
+		RateBPS:          req.RateBPS,
+		UpdatedBy:        actor.UserID,
+	}
+	if d.Method == domain.DepreciationStraightLine {
+		d.RateBPS = 0
+	}
+
+	if err := s.repo.SaveDepreciationDefault(ctx, d); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DeleteDepreciationDefault stops depreciating assets of a type that have no
+// policy of their own
+func (s *service) DeleteDepreciationDefault(ctx context.Context, actor *domain.Actor, assetType domain.AssetType) error {
+	if !actor.IsAdmin() {
+		return errors.ErrForbidden
+	}
+
+	return s.repo.DeleteDepreciationDefault(ctx, assetType)
+}
+
+// GetDepreciationSchedule gets the depreciation schedule of an asset
+func (s *service) GetDepreciationSchedule(ctx context.Context, actor *domain.Actor, assetID int64) (*dto.DepreciationSchedule, error) {
+	asset, err := s.viewable(ctx, actor, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.depreciationSchedule(ctx, asset)
+}
+
+// SetAssetDepreciation sets the depreciation policy of one asset, overriding
+// the default of its type from the next run on
+func (s *service) SetAssetDepreciation(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.DepreciationRequest) (*dto.DepreciationSchedule, error) {
+	asset, err := s.authorize(ctx, actor, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !asset.Type.IsDepreciable() {
+		return nil, errors.New(errors.ErrCodeValidation, "Land is not depreciated", 400)
+	}
+	if asset.Status == domain.AssetStatusDisposed {
+		return nil, errors.New(errors.ErrCodeConflict, "Disposed assets cannot be changed", 409)
+	}
+	if req.SalvageValue >= asset.PurchaseValue {
+		return nil, errors.New(errors.ErrCodeValidation, "salvage_value must be less than the purchase value", 400)
+	}
+
+	d := &domain.AssetDepreciation{
+		AssetID:          assetID,
+		Method:           req.Method,
+		UsefulLifeMonths: req.UsefulLifeMonths,
+		SalvageValue:     req.SalvageValue,
+		RateBPS:          req.RateBPS,
+		UpdatedBy:        actor.UserID,
+	}
+	if d.Method == domain.DepreciationStraightLine {
+		d.RateBPS = 0
+	}
+
+	if err := s.repo.SaveAssetDepreciation(ctx, d); err != nil {
+		return nil, err
+	}
+
+	return s.depreciationSchedule(ctx, asset)
+}
+
+// DeleteAssetDepreciation removes the policy of an asset, so the default of
+// its type applies again
+func (s *service) DeleteAssetDepreciation(ctx context.Context, actor *domain.Actor, assetID int64) error {
+	if _, err := s.authorize(ctx, actor, assetID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteAssetDepreciation(ctx, assetID)
+}
+
+// depreciationSchedule lists the months posted for an asset followed by the
+// months its current policy will still charge.
+//
+// Depreciation follows the cost model: it is charged on the purchase value
+// and approved valuations do not change it. A valuation posts its surplus or
+// deficit against the asset's previous value to wakaf equity on its own;
+// depreciating the revalued amount instead would also mean restating the
+// accumulated depreciation at every approval.
+func (s *service) depreciationSchedule(ctx context.Context, asset *domain.Asset) (*dto.DepreciationSchedule, error) {
+	policy, err := s.repo.FindDepreciationPolicy(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	posted, err := s.repo.GetDepreciationEntries(ctx, asset.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := domain.DepreciationStart(asset.AcquisitionDate)
+	schedule := &dto.DepreciationSchedule{
+		AssetID:     asset.ID,
+		AssetName:   asset.Name,
+		Policy:      policy,
+		Cost:        asset.PurchaseValue,
+		StartPeriod: start,
+		EndPeriod:   start.AddDate(0, policy.UsefulLifeMonths-1, 0),
+		Periods:     posted,
+	}
+
+	from := start
+	if len(posted) > 0 {
+		last := posted[len(posted)-1]
+		schedule.Accumulated = last.Accumulated
+		from = last.Period.AddDate(0, 1, 0)
+	}
+	schedule.BookValue = asset.PurchaseValue - schedule.Accumulated
+
+	if asset.Status != domain.AssetStatusDisposed {
+		for _, entry := range policy.Project(asset.ID, asset.PurchaseValue, schedule.Accumulated, start, from) {
+			entry.Projected = true
+			schedule.Periods = append(schedule.Periods, entry)
+		}
+	}
+
+	return schedule, nil
+}
+
+// depreciationEntries builds the ledger entries of months of depreciation of
+// an asset: debit the expense, credit accumulated depreciation. The months
+// covered stay in the description when the posting is dated after them.
+func depreciationEntries(assetName string, entries []*domain.DepreciationEntry, postedOn time.Time) []*domain.Ledger {
+	var amount int64
+	for _, entry := range entries {
+		amount += entry.Amount
+	}
+
+	first, last := entries[0].Period, entries[len(entries)-1].Period
+	description := fmt.Sprintf("Depreciation of asset %s for %s", assetName, first.Format("2006-01"))
+	if !last.Equal(first) {
+		description += " to " + last.Format("2006-01")
+	}
+	if postedOn.After(last.AddDate(0, 1, 0)) {
+		description += ", posted late as its period is closed"
+	}
+
+	return []*domain.Ledger{
+		{
+			AccountType:   "debit",
+			AccountName:   accountDepreciationExpense,
+			Amount:        amount,
+			Description:   description,
+			ReferenceType: domain.LedgerRefAssetDepreciation,
+			EntryDate:     postedOn,
+		},
+		{
+			AccountType:   "credit",
+			AccountName:   accountAccumulatedDepreciation,
+			Amount:        amount,
+			Description:   description,
+			ReferenceType: domain.LedgerRefAssetDepreciation,
+			EntryDate:     postedOn,
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+)
+
+// DepreciationScheduler posts the depreciation of every month that has
+// ended. Assets behind, e.g. given a policy late, catch up in one posting,
+// in the open period when their months are already closed.
+// Months are only posted once, so ticks may overlap or be missed.
+type DepreciationScheduler struct {
+	repo repository.Repository
+}
+
+// NewDepreciationScheduler creates a new depreciation scheduler
+func NewDepreciationScheduler(repo repository.Repository) *DepreciationScheduler {
+	return &DepreciationScheduler{repo: repo}
+}
+
+// DepreciationSchedulerResult counts what one scheduler tick posted. Failed
+// counts assets that could not be posted, e.g. as today falls in a closed
+// accounting period too; they are retried on the next tick.
+type DepreciationSchedulerResult struct {
+	Through time.Time `json:"through"` // latest month posted
+	Assets  int       `json:"assets"`
+	Months  int       `json:"months"`
+	Amount  int64     `json:"amount"`
+	Failed  int       `json:"failed"`
+}
+
+// Tick posts the depreciation of every asset up to the month before the
+// current one
+func (s *DepreciationScheduler) Tick(ctx context.Context) (*DepreciationSchedulerResult, error) {
+	through := domain.MonthStart(today()).AddDate(0, -1, 0)
+	result := &DepreciationSchedulerResult{Through: through}
+
+	due, err := s.repo.GetDepreciationDue(ctx, through)
+	if err != nil {
+		return result, err
+	}
+
+	for _, d := range due {
+		start := domain.DepreciationStart(d.AcquisitionDate)
+		from := start
+		if d.LastPeriod != nil {
+			from = d.LastPeriod.AddDate(0, 1, 0)
+		}
+
+		entries := d.Policy.Project(d.AssetID, d.Cost, d.Accumulated, start, from)
+		for i, entry := range entries {
+			if entry.Period.After(through) {
+				entries = entries[:i]
+				break
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		name := d.AssetName
+		compose := func(entries []*domain.DepreciationEntry, postedOn time.Time) []*domain.Ledger {
+			return depreciationEntries(name, entries, postedOn)
+		}
+		if err := s.repo.PostDepreciation(ctx, d.AssetID, entries, compose); err != nil {
+			log.Printf("depreciation scheduler: asset %d: %v", d.AssetID, err)
+			result.Failed++
+			continue
+		}
+
+		result.Assets++
+		result.Months += len(entries)
+		for _, entry := range entries {
+			result.Amount += entry.Amount
+		}
+	}
+
+	return result, nil
+}
+
+// Run ticks on an interval until ctx is cancelled
+func (s *DepreciationScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if result, err := s.Tick(ctx); err != nil {
+			log.Printf("depreciation scheduler: tick failed: %v", err)
+		} else if result.Assets > 0 {
+			log.Printf("depreciation scheduler: posted %d month(s) of %d asset(s) through %s",
+				result.Months, result.Assets, result.Through.Format("2006-01"))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+)
+
+func TestDepreciationEntries(t *testing.T) {
+	month := func(m time.Month) *domain.DepreciationEntry {
+		return &domain.DepreciationEntry{Period: time.Date(2026, m, 1, 0, 0, 0, 0, time.UTC), Amount: 1000}
+	}
+
+	tests := []struct {
+		name     string
+		entries  []*domain.DepreciationEntry
+		postedOn time.Time
+		want     string
+	}{
+		{
+			name:     "one month",
+			entries:  []*domain.DepreciationEntry{month(time.March)},
+			postedOn: time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+			want:     "Depreciation of asset Gedung Serbaguna for 2026-03",
+		},
+		{
+			name:     "catch-up",
+			entries:  []*domain.DepreciationEntry{month(time.January), month(time.February), month(time.March)},
+			postedOn: time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+			want:     "Depreciation of asset Gedung Serbaguna for 2026-01 to 2026-03",
+		},
+		{
+			name:     "catch-up into the open period",
+			entries:  []*domain.DepreciationEntry{month(time.January), month(time.February)},
+			postedOn: time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC),
+			want:     "Depreciation of asset Gedung Serbaguna for 2026-01 to 2026-02, posted late as its period is closed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := depreciationEntries("Gedung Serbaguna", tt.entries, tt.postedOn)
+			if len(ledger) != 2 {
+				t.Fatalf("got %d ledger entries, want 2", len(ledger))
+			}
+
+			want := int64(1000 * len(tt.entries))
+			debit, credit := ledger[0], ledger[1]
+			if debit.AccountType != "debit" || debit.AccountName != accountDepreciationExpense ||
+				credit.AccountType != "credit" || credit.AccountName != accountAccumulatedDepreciation {
+				t.Errorf("accounts = %s %s, %s %s", debit.AccountType, debit.AccountName, credit.AccountType, credit.AccountName)
+			}
+
+			for _, entry := range ledger {
+				if entry.Amount != want {
+					t.Errorf("%s amount = %d, want %d", entry.AccountName, entry.Amount, want)
+				}
+				if !entry.EntryDate.Equal(tt.postedOn) {
+					t.Errorf("%s dated %s, want %s", entry.AccountName, entry.EntryDate.Format("2006-01-02"), tt.postedOn.Format("2006-01-02"))
+				}
+				if entry.Description != tt.want {
+					t.Errorf("description = %q, want %q", entry.Description, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/services/asset/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// complianceDays is how many days ahead a document counts as expiring on the
+// compliance dashboard by default, the largest default alert window
+const complianceDays = 90
+
+// GetExpiringDocuments lists the asset documents that have expired or expire
+// within the query's window, soonest first; nazirs only see their own
+func (s *service) GetExpiringDocuments(ctx context.Context, actor *domain.Actor, query *dto.ComplianceQuery, page, perPage int) ([]*repository.ExpiringDocument, int64, error) {
+	filter, err := complianceFilter(actor, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.repo.GetExpiringDocuments(ctx, filter, perPage, (page-1)*perPage)
+}
+
+// SummarizeCompliance counts the expired and expiring asset documents of
+// every tenant
+func (s *service) SummarizeCompliance(ctx context.Context, actor *domain.Actor, query *dto.ComplianceQuery) ([]*repository.ComplianceTotal, error) {
+	filter, err := complianceFilter(actor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.SummarizeCompliance(ctx, filter)
+}
+
+// GetExpiryAlerts lists the document expiry alerts sent, newest first
+func (s *service) GetExpiryAlerts(ctx context.Context, actor *domain.Actor, query *dto.ExpiryAlertQuery, page, perPage int) ([]*repository.ExpiryAlert, int64, error) {
+	if query.TenantID != nil && !actor.IsAdmin() {
+		return nil, 0, errors.ErrForbidden
+	}
+	if query.AssetID != 0 {
+		if _, err := s.viewable(ctx, actor, query.AssetID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	filter := &repository.ExpiryAlertFilter{AssetID: query.AssetID, TenantID: query.TenantID}
+	if actor.Role == domain.RoleNazir {
+		filter.NazirID = actor.UserID
+	}
+
+	return s.repo.GetExpiryAlerts(ctx, filter, perPage, (page-1)*perPage)
+}
+
+// complianceFilter scopes a compliance query to the actor
+func complianceFilter(actor *domain.Actor, query *dto.ComplianceQuery) (*repository.ExpiryFilter, error) {
+	if query.TenantID != nil && !actor.IsAdmin() {
+		return nil, errors.ErrForbidden
+	}
+
+	filter := &repository.ExpiryFilter{
+		Day:      today(),
+		Within:   query.Within,
+		Status:   query.Status,
+		TenantID: query.TenantID,
+	}
+	if filter.Within == 0 {
+		filter.Within = complianceDays
+	}
+	if actor.Role == domain.RoleNazir {
+		filter.NazirID = actor.UserID
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/notify"
+)
+
+// DefaultExpiryWindows are the days before expiry on which nazirs are
+// alerted of an expiring document
+var DefaultExpiryWindows = []int{90, 30, 7}
+
+// ParseExpiryWindows parses comma-separated alert windows in days, e.g.
+// "90,30,7"; empty gives DefaultExpiryWindows
+func ParseExpiryWindows(s string) ([]int, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultExpiryWindows, nil
+	}
+
+	windows := make([]int, 0)
+	for _, part := range strings.Split(s, ",") {
+		days, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || days <= 0 {
+			return nil, fmt.Errorf("invalid document expiry window %q: must be a positive number of days", part)
+		}
+		windows = append(windows, days)
+	}
+	return windows, nil
+}
+
+// DocumentExpiryScheduler alerts nazirs of asset documents, e.g. building
+// permits, as they enter each expiry window and once they have expired.
+// Every alert is kept in the history and sent once, so ticks may overlap
+// or be missed; a document found late only gets the alert of the window it
+// is in.
+type DocumentExpiryScheduler struct {
+	repo    repository.Repository
+	windows []int // smallest first
+}
+
+// NewDocumentExpiryScheduler creates a new document expiry scheduler alerting
+// within the given windows in days, or DefaultExpiryWindows if none
+func NewDocumentExpiryScheduler(repo repository.Repository, windows []int) *DocumentExpiryScheduler {
+	if len(windows) == 0 {
+		windows = DefaultExpiryWindows
+	}
+
+	sorted := make([]int, 0, len(windows))
+	seen := make(map[int]bool, len(windows))
+	for _, w := range windows {
+		if !seen[w] {
+			seen[w] = true
+			sorted = append(sorted, w)
+		}
+	}
+	sort.Ints(sorted)
+
+	return &DocumentExpiryScheduler{repo: repo, windows: sorted}
+}
+
+// DocumentExpirySchedulerResult counts the alerts one tick sent; Expired
+// counts those about documents that have expired
+type DocumentExpirySchedulerResult struct {
+	Alerts  int `json:"alerts"`
+	Expired int `json:"expired"`
+}
+
+// Tick alerts of every document in an expiry window as of the current day
+func (s *DocumentExpiryScheduler) Tick(ctx context.Context) (*DocumentExpirySchedulerResult, error) {
+	day := today()
+	result := &DocumentExpirySchedulerResult{}
+	filter := &repository.ExpiryFilter{
+		Day:         day,
+		Within:      s.windows[len(s.windows)-1],
+		SkipAlerted: true,
+	}
+
+	for offset := 0; ; {
+		documents, _, err := s.repo.GetExpiringDocuments(ctx, filter, overdueBatch, offset)
+		if err != nil {
+			return result, err
+		}
+
+		// Expired documents alerted here leave the filter, so the next
+		// batch starts that much earlier
+		offset += len(documents)
+		for _, d := range documents {
+			window := s.window(d.DaysLeft)
+			messages := expiryAlerts(d, window)
+			if len(messages) == 0 {
+				continue
+			}
+
+			alert := &domain.DocumentExpiryAlert{
+				DocumentID:   d.ID,
+				AssetID:      d.AssetID,
+				DocumentType: d.DocumentType,
+				ExpiryDate:   *d.ExpiryDate,
+				WindowDays:   window,
+				DaysLeft:     d.DaysLeft,
+				NazirID:      &d.NazirID,
+			}
+			for _, m := range messages {
+				alert.Recipients = append(alert.Recipients, m.To)
+			}
+
+			sent, err := s.repo.RecordExpiryAlert(ctx, alert, messages...)
+			if err != nil {
+				return result, err
+			}
+			if !sent {
+				continue
+			}
+
+			result.Alerts++
+			if window == 0 {
+				result.Expired++
+				offset--
+			}
+		}
+
+		if len(documents) < overdueBatch {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// Run ticks on an interval until ctx is cancelled
+func (s *DocumentExpiryScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if result, err := s.Tick(ctx); err != nil {
+			log.Printf("document expiry scheduler: tick failed: %v", err)
+		} else if result.Alerts > 0 {
+			log.Printf("document expiry scheduler: sent %d alert(s), %d of expired documents", result.Alerts, result.Expired)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// window returns the smallest window a document with daysLeft is in, or 0
+// once it has expired
+func (s *DocumentExpiryScheduler) window(daysLeft int) int {
+	if daysLeft < 0 {
+		return 0
+	}
+	for _, w := range s.windows {
+		if daysLeft <= w {
+			return w
+		}
+	}
+	return s.windows[len(s.windows)-1]
+}
+
+// expiryAlerts builds the alerts of an expiring document to its nazir. The
+// dedupe key holds the expiry date and window, so a renewed document is
+// alerted again.
+func expiryAlerts(d *repository.ExpiringDocument, window int) []*notify.Message {
+	expiryDate := d.ExpiryDate.Format("2006-01-02")
+	dedupe := fmt.Sprintf("document_expiry:%d:%s:%d", d.ID, expiryDate, window)
+
+	subject := fmt.Sprintf("Dokumen %s (%s) berakhir %s", d.DocumentName, d.AssetName, expiryDate)
+	if d.DaysLeft < 0 {
+		subject = fmt.Sprintf("Dokumen %s (%s) telah kedaluwarsa", d.DocumentName, d.AssetName)
+	}
+
+	var b strings.Builder
+	b.WriteString("Assalamu'alaikum,\n\n")
+	switch {
+	case d.DaysLeft < 0:
+		fmt.Fprintf(&b, "Dokumen %s (%s) untuk aset wakaf %s telah kedaluwarsa pada %s.\n",
+			d.DocumentName, d.DocumentType, d.AssetName, expiryDate)
+	case d.DaysLeft == 0:
+		fmt.Fprintf(&b, "Dokumen %s (%s) untuk aset wakaf %s berakhir hari ini, %s.\n",
+			d.DocumentName, d.DocumentType, d.AssetName, expiryDate)
+	default:
+		fmt.Fprintf(&b, "Dokumen %s (%s) untuk aset wakaf %s akan berakhir pada %s (%d hari lagi).\n",
+			d.DocumentName, d.DocumentType, d.AssetName, expiryDate, d.DaysLeft)
+	}
+	b.WriteString("\nMohon segera mengurus perpanjangan dan unggah dokumen yang baru agar aset wakaf tetap sesuai ketentuan.\n")
+
+	messages := make([]*notify.Message, 0, 2)
+	if d.NazirEmail != "" {
+		messages = append(messages, &notify.Message{
+			Channel:       notify.ChannelEmail,
+			To:            d.NazirEmail,
+			Subject:       subject,
+			Body:          b.String(),
+			DedupeKey:     dedupe + ":email",
+			ReferenceType: "asset_document",
+			ReferenceID:   d.ID,
+		})
+	}
+	if d.NazirPhone != "" {
+		messages = append(messages, &notify.Message{
+			Channel:       notify.ChannelWhatsApp,
+			To:            d.NazirPhone,
+			Body:          b.String(),
+			DedupeKey:     dedupe + ":whatsapp",
+			ReferenceType: "asset_document",
+			ReferenceID:   d.ID,
+		})
+	}
+
+	return messages
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExpiryWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{name: "empty", input: "", want: DefaultExpiryWindows},
+		{name: "blank", input: "  ", want: DefaultExpiryWindows},
+		{name: "one window", input: "14", want: []int{14}},
+		{name: "several windows", input: "90,30,7", want: []int{90, 30, 7}},
+		{name: "spaces around windows", input: " 60 , 14,1 ", want: []int{60, 14, 1}},
+		{name: "unsorted and repeated are kept for the scheduler", input: "7,30,7", want: []int{7, 30, 7}},
+		{name: "zero", input: "30,0", wantErr: true},
+		{name: "negative", input: "-7", wantErr: true},
+		{name: "not a number", input: "90,thirty", wantErr: true},
+		{name: "empty entry", input: "90,,7", wantErr: true},
+		{name: "trailing comma", input: "90,30,", wantErr: true},
+		{name: "unit suffix", input: "90d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExpiryWindows(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseExpiryWindows(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseExpiryWindows(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocumentExpirySchedulerWindow(t *testing.T) {
+	scheduler := NewDocumentExpiryScheduler(nil, []int{90, 7, 30, 7})
+
+	if want := []int{7, 30, 90}; !reflect.DeepEqual(scheduler.windows, want) {
+		t.Fatalf("windows = %v, want %v", scheduler.windows, want)
+	}
+
+	tests := []struct {
+		daysLeft int
+		want     int
+	}{
+		{-1, 0}, // expired
+		{0, 7},
+		{7, 7},
+		{8, 30},
+		{30, 30},
+		{31, 90},
+		{90, 90},
+	}
+
+	for _, tt := range tests {
+		if got := scheduler.window(tt.daysLeft); got != tt.want {
+			t.Errorf("window(%d) = %d, want %d", tt.daysLeft, got, tt.want)
+		}
+	}
+
+	if defaults := NewDocumentExpiryScheduler(nil, nil); !reflect.DeepEqual(defaults.windows, []int{7, 30, 90}) {
+		t.Errorf("default windows = %v, want [7 30 90]", defaults.windows)
+	}
+}
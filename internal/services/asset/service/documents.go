@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// GetDocuments gets the documents of an asset, optionally of one type
+func (s *service) GetDocuments(ctx context.Context, actor *domain.Actor, assetID int64, documentType string) ([]*domain.AssetDocument, error) {
+	if _, err := s.viewable(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetDocuments(ctx, assetID, documentType)
+}
+
+// CreateDocument attaches an uploaded document to an asset
+func (s *service) CreateDocument(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.DocumentRequest) (*domain.AssetDocument, error) {
+	if _, err := s.authorize(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	document := &domain.AssetDocument{
+		AssetID:    assetID,
+		UploadedBy: actor.UserID,
+	}
+	if err := applyDocument(document, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateDocument(ctx, document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// UpdateDocument replaces a document's details, for example with a renewed permit
+func (s *service) UpdateDocument(ctx context.Context, actor *domain.Actor, assetID, documentID int64, req *dto.DocumentRequest) (*domain.AssetDocument, error) {
+	if _, err := s.authorize(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	document, err := s.repo.FindDocument(ctx, assetID, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyDocument(document, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateDocument(ctx, document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// DeleteDocument removes a document from an asset
+func (s *service) DeleteDocument(ctx context.Context, actor *domain.Actor, assetID, documentID int64) error {
+	if _, err := s.authorize(ctx, actor, assetID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteDocument(ctx, assetID, documentID)
+}
+
+// applyDocument copies request fields onto a document
+func applyDocument(document *domain.AssetDocument, req *dto.DocumentRequest) error {
+	issueDate, err := parseDate("issue_date", req.IssueDate)
+	if err != nil {
+		return err
+	}
+
+	expiryDate, err := parseDate("expiry_date", req.ExpiryDate)
+	if err != nil {
+		return err
+	}
+
+	if issueDate != nil && expiryDate != nil && expiryDate.Before(*issueDate) {
+		return errors.New(errors.ErrCodeValidation, "expiry_date must not be before issue_date", 400)
+	}
+
+	document.DocumentType = req.DocumentType
+	document.DocumentName = strings.TrimSpace(req.DocumentName)
+	document.FileURL = req.FileURL
+	document.FileSize = req.FileSize
+	document.MimeType = req.MimeType
+	document.IssueDate = issueDate
+	document.ExpiryDate = expiryDate
+	document.Notes = req.Notes
+	return nil
+}
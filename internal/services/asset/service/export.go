@@ -0,0 +1,234 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/services/asset/geo"
+	"github.com/akordium-id/waqfwise/internal/services/asset/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// Export and import limits
+const (
+	maxExportFeatures = 10000
+	maxImportFeatures = 1000
+)
+
+// File is a rendered download
+type File struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// ExportAssets renders the located assets matching query as a GIS file.
+// Assets are placed by their boundary, or by their point when they have
+// none; assets with neither are left out.
+func (s *service) ExportAssets(ctx context.Context, actor *domain.Actor, query *dto.ExportQuery, format geo.Format) (*File, error) {
+	filter := &repository.ListFilter{
+		CampaignID: query.CampaignID,
+		Type:       query.Type,
+		Status:     query.Status,
+	}
+	if actor.Role == domain.RoleNazir {
+		filter.NazirID = actor.UserID
+	}
+	if len(query.Region) > 0 {
+		region, err := parsePolygon("region", query.Region)
+		if err != nil {
+			return nil, err
+		}
+		filter.Region = string(region.GeoJSON())
+	}
+
+	assets, err := s.repo.ListFeatures(ctx, filter, maxExportFeatures+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(assets) > maxExportFeatures {
+		return nil, errors.New(errors.ErrCodeBadRequest, fmt.Sprintf("Export is limited to %d assets; narrow the filters", maxExportFeatures), 400)
+	}
+
+	features := make([]*geo.Feature, 0, len(assets))
+	for _, asset := range assets {
+		feature, err := assetFeature(asset)
+		if err != nil {
+			return nil, err
+		}
+		features = append(features, feature)
+	}
+
+	name := "wakaf-assets-" + time.Now().Format("20060102")
+	var buf bytes.Buffer
+	if err := geo.Write(&buf, format, name, features); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to render asset export", 500)
+	}
+
+	return &File{
+		Name:        name + "." + format.Extension(),
+		ContentType: format.ContentType(),
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+// ImportBoundaries creates or updates asset boundaries from the features of
+// an uploaded GeoJSON or KML file. Each feature names its asset with an
+// asset_id (or id) property and may carry land_use and zoning_type. Features
+// are saved one by one; a failed feature does not stop the others.
+func (s *service) ImportBoundaries(ctx context.Context, actor *domain.Actor, format geo.Format, data []byte) (*dto.ImportResult, error) {
+	features, err := geo.Read(format, data)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeValidation, err.Error(), 400)
+	}
+	if len(features) == 0 {
+		return nil, errors.New(errors.ErrCodeValidation, "File has no features", 400)
+	}
+	if len(features) > maxImportFeatures {
+		return nil, errors.New(errors.ErrCodeValidation, fmt.Sprintf("Import is limited to %d features per file", maxImportFeatures), 400)
+	}
+
+	result := &dto.ImportResult{Features: make([]*dto.ImportedFeature, 0, len(features))}
+	for i, feature := range features {
+		imported := &dto.ImportedFeature{Index: i}
+		result.Features = append(result.Features, imported)
+
+		if err := s.importBoundary(ctx, actor, feature, imported); err != nil {
+			imported.Status = "failed"
+			imported.Error = err.Error()
+			if appErr, ok := err.(*errors.AppError); ok {
+				imported.Error = appErr.Message
+			}
+			result.Failed++
+			continue
+		}
+
+		if imported.Status == "created" {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+
+	return result, nil
+}
+
+// importBoundary saves one imported feature as its asset's boundary, keeping
+// the land use, zoning and elevation the feature does not give
+func (s *service) importBoundary(ctx context.Context, actor *domain.Actor, feature *geo.Feature, imported *dto.ImportedFeature) error {
+	assetID, ok := featureAssetID(feature)
+	if !ok {
+		return errors.New(errors.ErrCodeValidation, "Feature has no asset_id property", 400)
+	}
+	imported.AssetID = assetID
+
+	if feature.Err != nil {
+		return errors.New(errors.ErrCodeValidation, "boundary: "+feature.Err.Error(), 400)
+	}
+
+	data := &domain.GeospatialData{}
+	if existing, err := s.repo.FindBoundary(ctx, assetID); err == nil {
+		data.LandUse, data.ZoningType, data.Elevation = existing.LandUse, existing.ZoningType, existing.Elevation
+	}
+	if value, ok := feature.Property("land_use"); ok {
+		data.LandUse = propertyString(value)
+	}
+	if value, ok := feature.Property("zoning_type"); ok {
+		data.ZoningType = propertyString(value)
+	}
+	if len(data.LandUse) > 100 || len(data.ZoningType) > 100 {
+		return errors.New(errors.ErrCodeValidation, "land_use and zoning_type must not exceed 100 characters", 400)
+	}
+
+	_, created, err := s.saveBoundary(ctx, actor, assetID, feature.Polygon, data)
+	if err != nil {
+		return err
+	}
+
+	imported.Status = "updated"
+	if created {
+		imported.Status = "created"
+	}
+	return nil
+}
+
+// assetFeature converts an exported asset to a GIS feature
+func assetFeature(asset *repository.AssetFeature) (*geo.Feature, error) {
+	feature := &geo.Feature{
+		Properties: []geo.Property{
+			{Name: "id", Value: asset.ID},
+			{Name: "campaign_id", Value: asset.CampaignID},
+			{Name: "name", Value: asset.Name},
+			{Name: "type", Value: string(asset.Type)},
+			{Name: "status", Value: string(asset.Status)},
+			{Name: "location", Value: asset.Location},
+			{Name: "area", Value: optionalFloat(asset.Area)},
+			{Name: "area_unit", Value: asset.AreaUnit},
+			{Name: "area_sqm", Value: optionalFloat(asset.AreaSqm)},
+			{Name: "land_use", Value: asset.LandUse},
+			{Name: "zoning_type", Value: asset.ZoningType},
+			{Name: "current_value", Value: asset.CurrentValue},
+			{Name: "acquired_on", Value: asset.AcquisitionDate.Format("2006-01-02")},
+		},
+	}
+
+	if asset.Boundary != nil {
+		polygon, err := geo.ParsePolygon(asset.Boundary)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to read boundary of asset ID "+strconv.FormatInt(asset.ID, 10), 500)
+		}
+		feature.Polygon = polygon
+	} else if asset.HasGeolocation() {
+		feature.Point = &geo.Position{*asset.Longitude, *asset.Latitude}
+	}
+
+	return feature, nil
+}
+
+// featureAssetID reads the asset ID of an imported feature from its
+// asset_id property, falling back to id
+func featureAssetID(feature *geo.Feature) (int64, bool) {
+	for _, name := range []string{"asset_id", "id"} {
+		value, ok := feature.Property(name)
+		if !ok {
+			continue
+		}
+
+		switch v := value.(type) {
+		case float64:
+			if v > 0 && v == float64(int64(v)) {
+				return int64(v), true
+			}
+		case string:
+			if id, err := strconv.ParseInt(v, 10, 64); err == nil && id > 0 {
+				return id, true
+			}
+		}
+		return 0, false
+	}
+	return 0, false
+}
+
+// propertyString reads an imported property as text
+func propertyString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+// optionalFloat turns a missing number into a nil property value
+func optionalFloat(value *float64) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/services/asset/geo"
+	"github.com/akordium-id/waqfwise/internal/services/asset/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// GetBoundary gets the surveyed boundary of an asset and how its area
+// compares with the declared area
+func (s *service) GetBoundary(ctx context.Context, actor *domain.Actor, assetID int64) (*dto.BoundaryResponse, error) {
+	asset, err := s.viewable(ctx, actor, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.FindBoundary(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.BoundaryResponse{
+		GeospatialData: data,
+		AreaCheck:      areaCheck(asset, data.AreaSqm),
+	}, nil
+}
+
+// SaveBoundary validates, measures and saves the boundary of a land or
+// building asset. Land already registered to another asset is refused.
+func (s *service) SaveBoundary(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.BoundaryRequest) (*dto.BoundaryResponse, error) {
+	polygon, err := parsePolygon("boundary", req.Boundary)
+	if err != nil {
+		return nil, err
+	}
+
+	boundary, _, err := s.saveBoundary(ctx, actor, assetID, polygon, &domain.GeospatialData{
+		LandUse:    req.LandUse,
+		ZoningType: req.ZoningType,
+		Elevation:  req.Elevation,
+	})
+	return boundary, err
+}
+
+// saveBoundary measures and saves a validated polygon as the boundary of an
+// asset the actor manages, reporting whether the boundary is new
+func (s *service) saveBoundary(ctx context.Context, actor *domain.Actor, assetID int64, polygon geo.Polygon, data *domain.GeospatialData) (*dto.BoundaryResponse, bool, error) {
+	asset, err := s.authorize(ctx, actor, assetID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if asset.Status == domain.AssetStatusDisposed {
+		return nil, false, errors.New(errors.ErrCodeConflict, "Disposed assets cannot be changed", 409)
+	}
+	if asset.Type != domain.AssetTypeLand && asset.Type != domain.AssetTypeBuilding {
+		return nil, false, errors.New(errors.ErrCodeValidation, "Only land and building assets have boundaries", 400)
+	}
+
+	data.AssetID = assetID
+	data.Boundary = polygon.GeoJSON()
+	data.AreaSqm = polygon.Area()
+	data.UpdatedBy = actor.UserID
+
+	created, err := s.repo.SaveBoundary(ctx, data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &dto.BoundaryResponse{
+		GeospatialData: data,
+		AreaCheck:      areaCheck(asset, data.AreaSqm),
+	}, created, nil
+}
+
+// DeleteBoundary removes the boundary of an asset
+func (s *service) DeleteBoundary(ctx context.Context, actor *domain.Actor, assetID int64) error {
+	if _, err := s.authorize(ctx, actor, assetID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteBoundary(ctx, assetID)
+}
+
+// Nearby finds assets around a point, nearest first; nazirs only see the
+// assets of their own campaigns
+func (s *service) Nearby(ctx context.Context, actor *domain.Actor, query *dto.NearbyQuery) ([]*repository.NearbyAsset, error) {
+	filter := &repository.ListFilter{
+		Type:   query.Type,
+		Status: query.Status,
+	}
+	if actor.Role == domain.RoleNazir {
+		filter.NazirID = actor.UserID
+	}
+
+	return s.repo.Nearby(ctx, filter, query.Latitude, query.Longitude, query.Radius, query.Limit)
+}
+
+// GetOverlaps gets the other assets whose boundaries overlap an asset's
+// boundary
+func (s *service) GetOverlaps(ctx context.Context, actor *domain.Actor, assetID int64) ([]*repository.BoundaryOverlap, error) {
+	if _, err := s.viewable(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindOverlaps(ctx, assetID)
+}
+
+// ListOverlaps lists every pair of assets whose boundaries overlap. Pairs
+// saved before overlap checks existed, or re-activated after disposal,
+// show up here.
+func (s *service) ListOverlaps(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*repository.BoundaryOverlap, int64, error) {
+	if !actor.IsAdmin() {
+		return nil, 0, errors.ErrForbidden
+	}
+
+	return s.repo.ListOverlaps(ctx, perPage, (page-1)*perPage)
+}
+
+// ListAreaMismatches lists assets whose declared area differs from the area
+// of their boundary beyond the tolerance
+func (s *service) ListAreaMismatches(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*repository.AreaMismatch, int64, error) {
+	if !actor.IsAdmin() {
+		return nil, 0, errors.ErrForbidden
+	}
+
+	return s.repo.ListAreaMismatches(ctx, geo.AreaTolerance, perPage, (page-1)*perPage)
+}
+
+// areaCheck compares an asset's declared area with the computed area of its
+// boundary
+func areaCheck(asset *domain.Asset, computedSqm float64) *dto.AreaCheck {
+	check := &dto.AreaCheck{ComputedSqm: computedSqm}
+	if asset.Area == nil || computedSqm <= 0 {
+		return check
+	}
+
+	declared, ok := geo.SquareMeters(*asset.Area, asset.AreaUnit)
+	if !ok {
+		return check
+	}
+
+	difference := (declared - computedSqm) / computedSqm
+	check.DeclaredSqm = &declared
+	check.Difference = &difference
+	check.Mismatch = math.Abs(difference) > geo.AreaTolerance
+	return check
+}
+
+// parsePolygon parses a GeoJSON polygon field
+func parsePolygon(field string, data []byte) (geo.Polygon, error) {
+	polygon, err := geo.ParsePolygon(data)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeValidation, field+": "+err.Error(), 400)
+	}
+	return polygon, nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/notify"
+)
+
+// overdueWindows are the days past the due date on which lessees are
+// reminded of unpaid rent, smallest first
+var overdueWindows = []int{1, 7, 30}
+
+// overdueBatch bounds the overdue invoices loaded per query
+const overdueBatch = 500
+
+// LeaseScheduler issues rent invoices as billing periods start, ends leases
+// past their end date and reminds lessees of overdue rent. Every step is
+// idempotent, so ticks may overlap or be missed.
+type LeaseScheduler struct {
+	repo repository.Repository
+}
+
+// NewLeaseScheduler creates a new lease scheduler
+func NewLeaseScheduler(repo repository.Repository) *LeaseScheduler {
+	return &LeaseScheduler{repo: repo}
+}
+
+// LeaseSchedulerResult counts what one scheduler tick changed. Reminders
+// counts overdue invoices in a reminder window; reminders already sent are
+// not resent.
+type LeaseSchedulerResult struct {
+	Invoiced  int `json:"invoiced"`
+	Ended     int `json:"ended"`
+	Reminders int `json:"reminders"`
+}
+
+// Tick runs every scheduler step for the current day
+func (s *LeaseScheduler) Tick(ctx context.Context) (*LeaseSchedulerResult, error) {
+	day := today()
+	result := &LeaseSchedulerResult{}
+
+	// Invoice before ending leases, so the last period is never skipped
+	due, err := s.repo.GetLeasesToInvoice(ctx, day)
+	if err != nil {
+		return result, err
+	}
+	for _, d := range due {
+		n, err := issueInvoices(ctx, s.repo, d.Lease, d.Invoiced, day)
+		result.Invoiced += n
+		if err != nil {
+			return result, err
+		}
+	}
+
+	ended, err := s.repo.EndExpiredLeases(ctx, day)
+	if err != nil {
+		return result, err
+	}
+	result.Ended = ended
+
+	for offset := 0; ; offset += overdueBatch {
+		overdue, _, err := s.repo.GetOverdueInvoices(ctx, day, 0, overdueBatch, offset)
+		if err != nil {
+			return result, err
+		}
+
+		for _, o := range overdue {
+			messages := overdueReminders(o)
+			if len(messages) == 0 {
+				continue
+			}
+			if err := s.repo.QueueNotifications(ctx, messages...); err != nil {
+				return result, err
+			}
+			result.Reminders++
+		}
+
+		if len(overdue) < overdueBatch {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// Run ticks on an interval until ctx is cancelled
+func (s *LeaseScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if result, err := s.Tick(ctx); err != nil {
+			log.Printf("lease scheduler: tick failed: %v", err)
+		} else if result.Invoiced > 0 || result.Ended > 0 {
+			log.Printf("lease scheduler: issued %d invoice(s), ended %d lease(s)", result.Invoiced, result.Ended)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// overdueReminders builds the reminders of an overdue invoice to the lessee,
+// copied to the nazir by email. The dedupe key holds the window, so each
+// window is sent once per invoice.
+func overdueReminders(o *repository.OverdueInvoice) []*notify.Message {
+	window := 0
+	for _, w := range overdueWindows {
+		if o.DaysOverdue >= w {
+			window = w
+		}
+	}
+	if window == 0 {
+		return nil
+	}
+
+	invoice := o.LeaseInvoice
+	dedupe := fmt.Sprintf("lease_overdue:%d:%d", invoice.ID, window)
+	subject := "Pengingat pembayaran sewa: " + invoice.Number
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Assalamu'alaikum %s,\n\n", o.LesseeName)
+	fmt.Fprintf(&b, "Tagihan sewa %s atas %s untuk periode %s s.d. %s telah jatuh tempo pada %s (%d hari lalu).\n\n",
+		invoice.Number, o.AssetName, invoice.PeriodStart.Format("2006-01-02"), invoice.PeriodEnd.Format("2006-01-02"),
+		invoice.DueDate.Format("2006-01-02"), o.DaysOverdue)
+	fmt.Fprintf(&b, "Sisa tagihan: Rp%d dari Rp%d.\n\n", invoice.Outstanding(), invoice.Amount)
+	b.WriteString("Hasil sewa aset wakaf disalurkan kepada mauquf 'alaih. Mohon segera melakukan pembayaran dan abaikan pesan ini bila sudah membayar.\n")
+
+	messages := make([]*notify.Message, 0, 3)
+	if o.LesseeEmail != "" {
+		messages = append(messages, &notify.Message{
+			Channel:       notify.ChannelEmail,
+			To:            o.LesseeEmail,
+			Subject:       subject,
+			Body:          b.String(),
+			DedupeKey:     dedupe + ":email",
+			ReferenceType: "lease_invoice",
+			ReferenceID:   invoice.ID,
+		})
+	}
+	if o.LesseePhone != "" {
+		messages = append(messages, &notify.Message{
+			Channel:       notify.ChannelWhatsApp,
+			To:            o.LesseePhone,
+			Body:          b.String(),
+			DedupeKey:     dedupe + ":whatsapp",
+			ReferenceType: "lease_invoice",
+			ReferenceID:   invoice.ID,
+		})
+	}
+	if o.NazirEmail != "" {
+		messages = append(messages, &notify.Message{
+			Channel:       notify.ChannelEmail,
+			To:            o.NazirEmail,
+			Subject:       fmt.Sprintf("Sewa %s menunggak %d hari: %s", o.AssetName, o.DaysOverdue, invoice.Number),
+			Body:          b.String(),
+			DedupeKey:     dedupe + ":nazir",
+			ReferenceType: "lease_invoice",
+			ReferenceID:   invoice.ID,
+		})
+	}
+
+	return messages
+}
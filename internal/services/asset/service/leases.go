@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/services/asset/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// Ledger accounts used by rent income, the same as other productive wakaf
+// income so rent is distributed with it
+const (
+	accountCash        = "cash_account"
+	accountWakafIncome = "wakaf_income"
+)
+
+// defaultEscalationMonths is the escalation interval of a lease that gives
+// an escalation rate without one
+const defaultEscalationMonths = 12
+
+// GetLeases gets the leases of an asset, optionally in one status, latest first
+func (s *service) GetLeases(ctx context.Context, actor *domain.Actor, assetID int64, status domain.LeaseStatus, page, perPage int) ([]*domain.Lease, int64, error) {
+	if _, err := s.viewable(ctx, actor, assetID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.repo.GetLeases(ctx, assetID, status, perPage, (page-1)*perPage)
+}
+
+// GetLease gets a lease with its invoices and payments
+func (s *service) GetLease(ctx context.Context, actor *domain.Actor, assetID, leaseID int64) (*dto.LeaseResponse, error) {
+	if _, err := s.viewable(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	lease, err := s.repo.FindLease(ctx, assetID, leaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.leaseResponse(ctx, lease)
+}
+
+// CreateLease registers a lease contract of an asset. Invoices of billing
+// periods that have already started are issued right away, so a contract
+// entered late starts with its arrears.
+func (s *service) CreateLease(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.LeaseRequest) (*dto.LeaseResponse, error) {
+	asset, err := s.authorize(ctx, actor, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Status == domain.AssetStatusDisposed {
+		return nil, errors.New(errors.ErrCodeConflict, "Disposed assets cannot be leased", 409)
+	}
+
+	startDate, err := requireDate("start_date", req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	endDate, err := requireDate("end_date", req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if endDate.Before(startDate) {
+		return nil, errors.New(errors.ErrCodeValidation, "end_date must not be before start_date", 400)
+	}
+
+	lease := &domain.Lease{
+		AssetID:          assetID,
+		LesseeName:       strings.TrimSpace(req.LesseeName),
+		LesseeIDNo:       strings.TrimSpace(req.LesseeIDNumber),
+		LesseeEmail:      strings.TrimSpace(req.LesseeEmail),
+		LesseePhone:      strings.TrimSpace(req.LesseePhone),
+		LesseeAddress:    req.LesseeAddress,
+		StartDate:        startDate,
+		EndDate:          endDate,
+		RentAmount:       req.RentAmount,
+		BillingCycle:     req.BillingCycle,
+		PaymentTermDays:  req.PaymentTermDays,
+		EscalationBPS:    req.EscalationBPS,
+		EscalationMonths: req.EscalationMonths,
+		DepositAmount:    req.DepositAmount,
+		DepositStatus:    domain.DepositStatusHeld,
+		Status:           domain.LeaseStatusActive,
+		Notes:            req.Notes,
+		CreatedBy:        actor.UserID,
+	}
+	if lease.EscalationBPS > 0 && lease.EscalationMonths == 0 {
+		lease.EscalationMonths = defaultEscalationMonths
+	}
+
+	if err := s.repo.CreateLease(ctx, lease); err != nil {
+		return nil, err
+	}
+
+	if _, err := issueInvoices(ctx, s.repo, lease, 0, today()); err != nil {
+		return nil, err
+	}
+
+	return s.leaseResponse(ctx, lease)
+}
+
+// TerminateLease ends an active lease before its end date. Unpaid invoices of
+// periods after the termination date are voided; earlier arrears stay owed.
+func (s *service) TerminateLease(ctx context.Context, actor *domain.Actor, assetID, leaseID int64, req *dto.TerminateLeaseRequest) (*domain.Lease, error) {
+	if _, err := s.authorize(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	lease, err := s.repo.FindLease(ctx, assetID, leaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lease.IsActive() {
+		return nil, errors.New(errors.ErrCodeConflict, "Only an active lease can be terminated", 409)
+	}
+
+	terminatedAt, err := requireDate("terminated_at", req.TerminatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if terminatedAt.Before(lease.StartDate) || terminatedAt.After(lease.EndDate) {
+		return nil, errors.New(errors.ErrCodeValidation, "terminated_at must be within the lease period", 400)
+	}
+
+	lease.TerminatedAt = &terminatedAt
+	lease.TerminationReason = strings.TrimSpace(req.Reason)
+	if lease.TerminationReason == "" {
+		return nil, errors.New(errors.ErrCodeValidation, "A reason is required to terminate a lease", 400)
+	}
+	if req.DepositStatus != "" {
+		lease.DepositStatus = req.DepositStatus
+	}
+
+	if err := s.repo.TerminateLease(ctx, lease); err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+// RecordLeasePayment records rent received against an invoice of a lease.
+// The rent becomes income of the asset awaiting distribution.
+func (s *service) RecordLeasePayment(ctx context.Context, actor *domain.Actor, assetID, leaseID, invoiceID int64, req *dto.LeasePaymentRequest) (*domain.LeasePayment, error) {
+	asset, err := s.authorize(ctx, actor, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Status == domain.AssetStatusDisposed {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Cannot record income for a disposed asset", 400)
+	}
+
+	lease, err := s.repo.FindLease(ctx, assetID, leaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	paidAt, err := requireDate("paid_at", req.PaidAt)
+	if err != nil {
+		return nil, err
+	}
+	if paidAt.After(time.Now()) {
+		return nil, errors.New(errors.ErrCodeValidation, "paid_at must not be in the future", 400)
+	}
+
+	payment := &domain.LeasePayment{
+		InvoiceID:  invoiceID,
+		LeaseID:    lease.ID,
+		Amount:     req.Amount,
+		PaidAt:     paidAt,
+		Method:     req.Method,
+		Reference:  strings.TrimSpace(req.Reference),
+		Notes:      req.Notes,
+		RecordedBy: actor.UserID,
+	}
+
+	compose := func(payment *domain.LeasePayment, balance int64) []*domain.Ledger {
+		return rentIncomeEntries(asset, lease, payment, balance)
+	}
+	if err := s.repo.RecordLeasePayment(ctx, payment, compose); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// GetOverdueInvoices gets rent invoices past their due date; nazirs only see
+// those of their own assets
+func (s *service) GetOverdueInvoices(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*repository.OverdueInvoice, int64, error) {
+	var nazirID int64
+	if actor.Role == domain.RoleNazir {
+		nazirID = actor.UserID
+	}
+
+	return s.repo.GetOverdueInvoices(ctx, today(), nazirID, perPage, (page-1)*perPage)
+}
+
+// leaseResponse loads the invoices and payments of a lease and totals what
+// is still owed
+func (s *service) leaseResponse(ctx context.Context, lease *domain.Lease) (*dto.LeaseResponse, error) {
+	invoices, err := s.repo.GetInvoices(ctx, lease.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.repo.GetLeasePayments(ctx, lease.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.LeaseResponse{
+		Lease:    lease,
+		Invoices: invoices,
+		Payments: payments,
+	}
+	day := today()
+	for _, invoice := range invoices {
+		resp.Outstanding += invoice.Outstanding()
+		if invoice.IsOverdue(day) {
+			resp.Overdue += invoice.Outstanding()
+		}
+	}
+
+	return resp, nil
+}
+
+// issueInvoices issues the invoices of every billing period of a lease that
+// has started by day, after the first invoiced ones
+func issueInvoices(ctx context.Context, repo repository.Repository, lease *domain.Lease, invoiced int, day time.Time) (int, error) {
+	var invoices []*domain.LeaseInvoice
+	for n := invoiced; ; n++ {
+		start := lease.PeriodStart(n)
+		if start.After(day) || start.After(lease.EndDate) {
+			break
+		}
+		invoices = append(invoices, lease.Invoice(n))
+	}
+
+	if len(invoices) == 0 {
+		return 0, nil
+	}
+	return repo.CreateInvoices(ctx, invoices...)
+}
+
+// rentIncomeEntries builds the ledger entries of a rent payment: debit cash,
+// credit undistributed wakaf income of the asset
+func rentIncomeEntries(asset *domain.Asset, lease *domain.Lease, payment *domain.LeasePayment, balance int64) []*domain.Ledger {
+	return []*domain.Ledger{
+		{
+			AccountType:   "debit",
+			AccountName:   accountCash,
+			Amount:        payment.Amount,
+			BalanceBefore: balance,
+			BalanceAfter:  balance + payment.Amount,
+			Description:   fmt.Sprintf("%s income from asset %s (lease ID %d, %s)", domain.IncomeSourceRent, asset.Name, lease.ID, lease.LesseeName),
+			ReferenceType: domain.LedgerRefAssetIncome,
+			EntryDate:     payment.PaidAt,
+		},
+		{
+			AccountType:   "credit",
+			AccountName:   accountWakafIncome,
+			Amount:        payment.Amount,
+			BalanceBefore: balance,
+			BalanceAfter:  balance + payment.Amount,
+			Description:   fmt.Sprintf("Hasil wakaf of asset ID %d awaiting distribution", asset.ID),
+			ReferenceType: domain.LedgerRefAssetIncome,
+			EntryDate:     payment.PaidAt,
+		},
+	}
+}
+
+// today returns the current date at midnight UTC
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/services/asset/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// accountMaintenanceExpense is the ledger account of asset upkeep, paid from
+// cash
+const accountMaintenanceExpense = "asset_maintenance_expense"
+
+// maintenanceDueDays is how far ahead schedules count as due
+const maintenanceDueDays = 14
+
+// GetSchedules gets the maintenance schedules of an asset
+func (s *service) GetSchedules(ctx context.Context, actor *domain.Actor, assetID int64) ([]*domain.MaintenanceSchedule, error) {
+	if _, err := s.viewable(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSchedules(ctx, assetID)
+}
+
+// CreateSchedule sets up recurring maintenance of an asset
+func (s *service) CreateSchedule(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.ScheduleRequest) (*domain.MaintenanceSchedule, error) {
+	asset, err := s.authorize(ctx, actor, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Status == domain.AssetStatusDisposed {
+		return nil, errors.New(errors.ErrCodeConflict, "Disposed assets cannot be maintained", 409)
+	}
+
+	schedule := &domain.MaintenanceSchedule{
+		AssetID:   assetID,
+		IsActive:  true,
+		CreatedBy: actor.UserID,
+	}
+	if err := applySchedule(schedule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// UpdateSchedule changes a maintenance schedule, including pausing it
+func (s *service) UpdateSchedule(ctx context.Context, actor *domain.Actor, assetID, scheduleID int64, req *dto.ScheduleRequest) (*domain.MaintenanceSchedule, error) {
+	if _, err := s.authorize(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.repo.FindSchedule(ctx, assetID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applySchedule(schedule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// GetDueMaintenance gets schedules due within two weeks or overdue that have
+// no open work order; nazirs only see those of their own assets
+func (s *service) GetDueMaintenance(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*repository.DueSchedule, int64, error) {
+	var nazirID int64
+	if actor.Role == domain.RoleNazir {
+		nazirID = actor.UserID
+	}
+
+	return s.repo.GetDueSchedules(ctx, today(), maintenanceDueDays, nazirID, perPage, (page-1)*perPage)
+}
+
+// GetWorkOrders gets the work orders of an asset, optionally in one status
+func (s *service) GetWorkOrders(ctx context.Context, actor *domain.Actor, assetID int64, status domain.WorkOrderStatus, page, perPage int) ([]*domain.WorkOrder, int64, error) {
+	if _, err := s.viewable(ctx, actor, assetID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.repo.GetWorkOrders(ctx, assetID, status, perPage, (page-1)*perPage)
+}
+
+// GetWorkOrder gets a work order of an asset
+func (s *service) GetWorkOrder(ctx context.Context, actor *domain.Actor, assetID, orderID int64) (*domain.WorkOrder, error) {
+	if _, err := s.viewable(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindWorkOrder(ctx, assetID, orderID)
+}
+
+// CreateWorkOrder opens a work order, optionally for a maintenance schedule.
+// The asset is under maintenance until its last open work order closes.
+func (s *service) CreateWorkOrder(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.WorkOrderRequest) (*domain.WorkOrder, error) {
+	if _, err := s.authorize(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	order := &domain.WorkOrder{
+		AssetID:  assetID,
+		Status:   domain.WorkOrderStatusOpen,
+		OpenedBy: actor.UserID,
+	}
+
+	if req.ScheduleID != nil {
+		schedule, err := s.repo.FindSchedule(ctx, assetID, *req.ScheduleID)
+		if err != nil {
+			return nil, err
+		}
+		order.ScheduleID = &schedule.ID
+		if req.Title == "" {
+			req.Title = schedule.Title
+		}
+		if req.EstimatedCost == 0 {
+			req.EstimatedCost = schedule.EstimatedCost
+		}
+	}
+
+	applyWorkOrder(order, req)
+	if order.Title == "" {
+		return nil, errors.New(errors.ErrCodeValidation, "title is required", 400)
+	}
+
+	if err := s.repo.CreateWorkOrder(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// UpdateWorkOrder changes the details of an open work order
+func (s *service) UpdateWorkOrder(ctx context.Context, actor *domain.Actor, assetID, orderID int64, req *dto.WorkOrderRequest) (*domain.WorkOrder, error) {
+	if _, err := s.authorize(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	order, err := s.repo.FindWorkOrder(ctx, assetID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.IsOpen() {
+		return nil, errors.New(errors.ErrCodeConflict, "Work order is no longer open", 409)
+	}
+
+	applyWorkOrder(order, req)
+	if order.Title == "" {
+		return nil, errors.New(errors.ErrCodeValidation, "title is required", 400)
+	}
+
+	if err := s.repo.UpdateWorkOrder(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// CompleteWorkOrder signs off a finished work order. Its actual cost is
+// posted to the ledger as an asset expense and its schedule, if any, moves
+// to the next due date.
+func (s *service) CompleteWorkOrder(ctx context.Context, actor *domain.Actor, assetID, orderID int64, req *dto.CompleteWorkOrderRequest) (*domain.WorkOrder, error) {
+	asset, err := s.authorize(ctx, actor, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.repo.FindWorkOrder(ctx, assetID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.IsOpen() {
+		return nil, errors.New(errors.ErrCodeConflict, "Work order is no longer open", 409)
+	}
+
+	completedOn, err := requireDate("completed_on", req.CompletedOn)
+	if err != nil {
+		return nil, err
+	}
+	if completedOn.After(time.Now()) {
+		return nil, errors.New(errors.ErrCodeValidation, "completed_on must not be in the future", 400)
+	}
+	if completedOn.Before(order.CreatedAt.UTC().Truncate(24 * time.Hour)) {
+		return nil, errors.New(errors.ErrCodeValidation, "completed_on must not be before the work order was opened", 400)
+	}
+
+	order.Status = domain.WorkOrderStatusCompleted
+	order.ActualCost = &req.ActualCost
+	order.CompletedOn = &completedOn
+	order.ClosedBy = &actor.UserID
+	order.CloseNotes = req.Notes
+	order.PhotoURLs = append(order.PhotoURLs, cleanURLs(req.PhotoURLs)...)
+
+	compose := func(order *domain.WorkOrder) []*domain.Ledger {
+		return maintenanceExpenseEntries(asset, order)
+	}
+	if err := s.repo.CloseWorkOrder(ctx, order, compose); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// CancelWorkOrder drops an open work order without posting any cost
+func (s *service) CancelWorkOrder(ctx context.Context, actor *domain.Actor, assetID, orderID int64, req *dto.CancelWorkOrderRequest) (*domain.WorkOrder, error) {
+	if _, err := s.authorize(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	order, err := s.repo.FindWorkOrder(ctx, assetID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.IsOpen() {
+		return nil, errors.New(errors.ErrCodeConflict, "Work order is no longer open", 409)
+	}
+
+	order.Status = domain.WorkOrderStatusCancelled
+	order.ClosedBy = &actor.UserID
+	order.CloseNotes = strings.TrimSpace(req.Reason)
+	if order.CloseNotes == "" {
+		return nil, errors.New(errors.ErrCodeValidation, "A reason is required to cancel a work order", 400)
+	}
+
+	if err := s.repo.CloseWorkOrder(ctx, order, nil); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// applySchedule copies a schedule request onto a schedule
+func applySchedule(schedule *domain.MaintenanceSchedule, req *dto.ScheduleRequest) error {
+	nextDueDate, err := requireDate("next_due_date", req.NextDueDate)
+	if err != nil {
+		return err
+	}
+
+	schedule.Title = strings.TrimSpace(req.Title)
+	schedule.Description = req.Description
+	schedule.IntervalMonths = req.IntervalMonths
+	schedule.NextDueDate = nextDueDate
+	schedule.EstimatedCost = req.EstimatedCost
+	if req.IsActive != nil {
+		schedule.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// applyWorkOrder copies the editable details of a work order request
+func applyWorkOrder(order *domain.WorkOrder, req *dto.WorkOrderRequest) {
+	order.Title = strings.TrimSpace(req.Title)
+	order.Description = req.Description
+	order.Vendor = strings.TrimSpace(req.Vendor)
+	order.VendorContact = strings.TrimSpace(req.VendorContact)
+	order.EstimatedCost = req.EstimatedCost
+	if req.PhotoURLs != nil {
+		order.PhotoURLs = cleanURLs(req.PhotoURLs)
+	}
+	if order.PhotoURLs == nil {
+		order.PhotoURLs = []string{}
+	}
+}
+
+// cleanURLs trims URLs and drops blank ones
+func cleanURLs(urls []string) []string {
+	cleaned := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if url = strings.TrimSpace(url); url != "" {
+			cleaned = append(cleaned, url)
+		}
+	}
+	return cleaned
+}
+
+// maintenanceExpenseEntries builds the ledger entries of a completed work
+// order: debit the maintenance expense, credit cash. Work done at no cost
+// posts nothing.
+func maintenanceExpenseEntries(asset *domain.Asset, order *domain.WorkOrder) []*domain.Ledger {
+	if order.ActualCost == nil || *order.ActualCost == 0 {
+		return nil
+	}
+
+	description := fmt.Sprintf("Maintenance of asset %s (work order ID %d, %s)", asset.Name, order.ID, order.Title)
+	if order.Vendor != "" {
+		description += " by " + order.Vendor
+	}
+
+	return []*domain.Ledger{
+		{
+			AccountType:   "debit",
+			AccountName:   accountMaintenanceExpense,
+			Amount:        *order.ActualCost,
+			Description:   description,
+			ReferenceType: domain.LedgerRefAssetMaintenance,
+			EntryDate:     *order.CompletedOn,
+		},
+		{
+			AccountType:   "credit",
+			AccountName:   accountCash,
+			Amount:        *order.ActualCost,
+			Description:   description,
+			ReferenceType: domain.LedgerRefAssetMaintenance,
+			EntryDate:     *order.CompletedOn,
+		},
+	}
+}
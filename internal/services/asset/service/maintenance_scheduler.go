@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/notify"
+)
+
+// MaintenanceScheduler reminds nazirs of maintenance coming due within two
+// weeks and again once it is overdue, until a work order is opened for it
+type MaintenanceScheduler struct {
+	repo repository.Repository
+}
+
+// NewMaintenanceScheduler creates a new maintenance scheduler
+func NewMaintenanceScheduler(repo repository.Repository) *MaintenanceScheduler {
+	return &MaintenanceScheduler{repo: repo}
+}
+
+// MaintenanceSchedulerResult counts the schedules one tick reminded of;
+// reminders already sent are not resent
+type MaintenanceSchedulerResult struct {
+	Reminders int `json:"reminders"`
+}
+
+// Tick reminds of every schedule due as of the current day
+func (s *MaintenanceScheduler) Tick(ctx context.Context) (*MaintenanceSchedulerResult, error) {
+	day := today()
+	result := &MaintenanceSchedulerResult{}
+
+	for offset := 0; ; offset += overdueBatch {
+		due, _, err := s.repo.GetDueSchedules(ctx, day, maintenanceDueDays, 0, overdueBatch, offset)
+		if err != nil {
+			return result, err
+		}
+
+		for _, d := range due {
+			messages := maintenanceReminders(d)
+			if len(messages) == 0 {
+				continue
+			}
+			if err := s.repo.QueueNotifications(ctx, messages...); err != nil {
+				return result, err
+			}
+			result.Reminders++
+		}
+
+		if len(due) < overdueBatch {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// Run ticks on an interval until ctx is cancelled
+func (s *MaintenanceScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Tick(ctx); err != nil {
+			log.Printf("maintenance scheduler: tick failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// maintenanceReminders builds the reminders of a due schedule to its nazir.
+// The dedupe key holds the due date and whether it is overdue, so each
+// occurrence is announced once ahead and once when late.
+func maintenanceReminders(d *repository.DueSchedule) []*notify.Message {
+	stage := "upcoming"
+	if d.DaysLeft < 0 {
+		stage = "overdue"
+	}
+	dueDate := d.NextDueDate.Format("2006-01-02")
+	dedupe := fmt.Sprintf("maintenance_due:%d:%s:%s", d.ID, stage, dueDate)
+
+	subject := fmt.Sprintf("Pemeliharaan %s jatuh tempo %s: %s", d.AssetName, dueDate, d.Title)
+	if d.DaysLeft < 0 {
+		subject = fmt.Sprintf("Pemeliharaan %s terlambat %d hari: %s", d.AssetName, -d.DaysLeft, d.Title)
+	}
+
+	var b strings.Builder
+	b.WriteString("Assalamu'alaikum,\n\n")
+	fmt.Fprintf(&b, "Jadwal pemeliharaan \"%s\" untuk aset wakaf %s jatuh tempo pada %s.\n", d.Title, d.AssetName, dueDate)
+	if d.EstimatedCost > 0 {
+		fmt.Fprintf(&b, "Perkiraan biaya: Rp%d.\n", d.EstimatedCost)
+	}
+	b.WriteString("\nMohon buat perintah kerja (work order) agar aset tetap terpelihara.\n")
+
+	messages := make([]*notify.Message, 0, 2)
+	if d.NazirEmail != "" {
+		messages = append(messages, &notify.Message{
+			Channel:       notify.ChannelEmail,
+			To:            d.NazirEmail,
+			Subject:       subject,
+			Body:          b.String(),
+			DedupeKey:     dedupe + ":email",
+			ReferenceType: "maintenance_schedule",
+			ReferenceID:   d.ID,
+		})
+	}
+	if d.NazirPhone != "" {
+		messages = append(messages, &notify.Message{
+			Channel:       notify.ChannelWhatsApp,
+			To:            d.NazirPhone,
+			Body:          b.String(),
+			DedupeKey:     dedupe + ":whatsapp",
+			ReferenceType: "maintenance_schedule",
+			ReferenceID:   d.ID,
+		})
+	}
+
+	return messages
+}
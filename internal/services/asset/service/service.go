@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/services/asset/geo"
+	"github.com/akordium-id/waqfwise/internal/services/asset/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// Service defines asset service interface
+type Service interface {
+	Create(ctx context.Context, actor *domain.Actor, req *dto.CreateAssetRequest) (*domain.Asset, error)
+	Update(ctx context.Context, actor *domain.Actor, id int64, req *dto.UpdateAssetRequest) (*domain.Asset, error)
+	UpdateStatus(ctx context.Context, actor *domain.Actor, id int64, req *dto.UpdateStatusRequest) (*domain.Asset, error)
+	GetByID(ctx context.Context, actor *domain.Actor, id int64) (*dto.AssetResponse, error)
+	List(ctx context.Context, actor *domain.Actor, query *dto.ListAssetsQuery) ([]*domain.Asset, int64, error)
+	GetStatusHistory(ctx context.Context, actor *domain.Actor, id int64) ([]*domain.AssetStatusChange, error)
+	GetDocuments(ctx context.Context, actor *domain.Actor, assetID int64, documentType string) ([]*domain.AssetDocument, error)
+	CreateDocument(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.DocumentRequest) (*domain.AssetDocument, error)
+	UpdateDocument(ctx context.Context, actor *domain.Actor, assetID, documentID int64, req *dto.DocumentRequest) (*domain.AssetDocument, error)
+	DeleteDocument(ctx context.Context, actor *domain.Actor, assetID, documentID int64) error
+	GetValuations(ctx context.Context, actor *domain.Actor, assetID int64, status domain.ValuationStatus, page, perPage int) ([]*domain.AssetValuation, int64, error)
+	GetValuationTimeline(ctx context.Context, actor *domain.Actor, assetID int64) (*dto.ValuationTimeline, error)
+	GetPendingValuations(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*domain.AssetValuation, int64, error)
+	CreateValuation(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.ValuationRequest) (*domain.AssetValuation, error)
+	ReviewValuation(ctx context.Context, actor *domain.Actor, assetID, valuationID int64, req *dto.ValuationReviewRequest) (*domain.AssetValuation, error)
+	GetBoundary(ctx context.Context, actor *domain.Actor, assetID int64) (*dto.BoundaryResponse, error)
+	SaveBoundary(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.BoundaryRequest) (*dto.BoundaryResponse, error)
+	DeleteBoundary(ctx context.Context, actor *domain.Actor, assetID int64) error
+	Nearby(ctx context.Context, actor *domain.Actor, query *dto.NearbyQuery) ([]*repository.NearbyAsset, error)
+	GetOverlaps(ctx context.Context, actor *domain.Actor, assetID int64) ([]*repository.BoundaryOverlap, error)
+	ListOverlaps(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*repository.BoundaryOverlap, int64, error)
+	ListAreaMismatches(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*repository.AreaMismatch, int64, error)
+	ExportAssets(ctx context.Context, actor *domain.Actor, query *dto.ExportQuery, format geo.Format) (*File, error)
+	ImportBoundaries(ctx context.Context, actor *domain.Actor, format geo.Format, data []byte) (*dto.ImportResult, error)
+	TileETag(ctx context.Context) (string, error)
+	GetTile(ctx context.Context, z, x, y int) (*Tile, error)
+	GetLeases(ctx context.Context, actor *domain.Actor, assetID int64, status domain.LeaseStatus, page, perPage int) ([]*domain.Lease, int64, error)
+	GetLease(ctx context.Context, actor *domain.Actor, assetID, leaseID int64) (*dto.LeaseResponse, error)
+	CreateLease(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.LeaseRequest) (*dto.LeaseResponse, error)
+	TerminateLease(ctx context.Context, actor *domain.Actor, assetID, leaseID int64, req *dto.TerminateLeaseRequest) (*domain.Lease, error)
+	RecordLeasePayment(ctx context.Context, actor *domain.Actor, assetID, leaseID, invoiceID int64, req *dto.LeasePaymentRequest) (*domain.LeasePayment, error)
+	GetOverdueInvoices(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*repository.OverdueInvoice, int64, error)
+	GetSchedules(ctx context.Context, actor *domain.Actor, assetID int64) ([]*domain.MaintenanceSchedule, error)
+	CreateSchedule(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.ScheduleRequest) (*domain.MaintenanceSchedule, error)
+	UpdateSchedule(ctx context.Context, actor *domain.Actor, assetID, scheduleID int64, req *dto.ScheduleRequest) (*domain.MaintenanceSchedule, error)
+	GetDueMaintenance(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*repository.DueSchedule, int64, error)
+	GetWorkOrders(ctx context.Context, actor *domain.Actor, assetID int64, status domain.WorkOrderStatus, page, perPage int) ([]*domain.WorkOrder, int64, error)
+	GetWorkOrder(ctx context.Context, actor *domain.Actor, assetID, orderID int64) (*domain.WorkOrder, error)
+	CreateWorkOrder(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.WorkOrderRequest) (*domain.WorkOrder, error)
+	UpdateWorkOrder(ctx context.Context, actor *domain.Actor, assetID, orderID int64, req *dto.WorkOrderRequest) (*domain.WorkOrder, error)
+	CompleteWorkOrder(ctx context.Context, actor *domain.Actor, assetID, orderID int64, req *dto.CompleteWorkOrderRequest) (*domain.WorkOrder, error)
+	CancelWorkOrder(ctx context.Context, actor *domain.Actor, assetID, orderID int64, req *dto.CancelWorkOrderRequest) (*domain.WorkOrder, error)
+	GetDepreciationDefaults(ctx context.Context, actor *domain.Actor) ([]*domain.DepreciationDefault, error)
+	SaveDepreciationDefault(ctx context.Context, actor *domain.Actor, assetType domain.AssetType, req *dto.DepreciationDefaultRequest) (*domain.DepreciationDefault, error)
+	DeleteDepreciationDefault(ctx context.Context, actor *domain.Actor, assetType domain.AssetType) error
+	GetDepreciationSchedule(ctx context.Context, actor *domain.Actor, assetID int64) (*dto.DepreciationSchedule, error)
+	SetAssetDepreciation(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.DepreciationRequest) (*dto.DepreciationSchedule, error)
+	DeleteAssetDepreciation(ctx context.Context, actor *domain.Actor, assetID int64) error
+	GetCertification(ctx context.Context, actor *domain.Actor, assetID int64) (*dto.CertificationResponse, error)
+	StartCertification(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.StartCertificationRequest) (*dto.CertificationResponse, error)
+	UpdateCertificationStep(ctx context.Context, actor *domain.Actor, assetID int64, stage domain.CertificationStage, req *dto.CertificationStepRequest) (*dto.CertificationResponse, error)
+	CancelCertification(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.CancelCertificationRequest) (*dto.CertificationResponse, error)
+	ListCertifications(ctx context.Context, actor *domain.Actor, query *dto.CertificationQuery, page, perPage int) ([]*repository.StuckParcel, int64, error)
+	SummarizeCertifications(ctx context.Context, actor *domain.Actor) ([]*repository.StageSummary, error)
+	GetExpiringDocuments(ctx context.Context, actor *domain.Actor, query *dto.ComplianceQuery, page, perPage int) ([]*repository.ExpiringDocument, int64, error)
+	SummarizeCompliance(ctx context.Context, actor *domain.Actor, query *dto.ComplianceQuery) ([]*repository.ComplianceTotal, error)
+	GetExpiryAlerts(ctx context.Context, actor *domain.Actor, query *dto.ExpiryAlertQuery, page, perPage int) ([]*repository.ExpiryAlert, int64, error)
+}
+
+type service struct {
+	repo  repository.Repository
+	tiles *tileCache
+}
+
+// New creates a new asset service
+func New(repo repository.Repository) Service {
+	return &service{repo: repo, tiles: newTileCache()}
+}
+
+// Create registers an asset under a campaign managed by the actor
+func (s *service) Create(ctx context.Context, actor *domain.Actor, req *dto.CreateAssetRequest) (*domain.Asset, error) {
+	campaign, err := s.repo.FindCampaign(ctx, req.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManage(actor, campaign) {
+		return nil, errors.New(errors.ErrCodeForbidden, "Only the nazir of this campaign can register its assets", 403)
+	}
+
+	acquisitionDate, err := requireDate("acquisition_date", req.AcquisitionDate)
+	if err != nil {
+		return nil, err
+	}
+
+	asset := &domain.Asset{
+		CampaignID:      campaign.ID,
+		Type:            req.Type,
+		Status:          domain.AssetStatusActive,
+		Name:            strings.TrimSpace(req.Name),
+		Description:     req.Description,
+		Location:        req.Location,
+		Latitude:        req.Latitude,
+		Longitude:       req.Longitude,
+		Area:            req.Area,
+		AreaUnit:        req.AreaUnit,
+		PurchaseValue:   req.PurchaseValue,
+		CurrentValue:    req.PurchaseValue,
+		AcquisitionDate: acquisitionDate,
+		TenantID:        campaign.TenantID,
+	}
+
+	if err := validateAsset(asset); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, asset); err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// Update updates asset details. Disposed assets are kept as they were.
+func (s *service) Update(ctx context.Context, actor *domain.Actor, id int64, req *dto.UpdateAssetRequest) (*domain.Asset, error) {
+	asset, err := s.authorize(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Status == domain.AssetStatusDisposed {
+		return nil, errors.New(errors.ErrCodeConflict, "Disposed assets cannot be changed", 409)
+	}
+
+	if req.Type != nil {
+		asset.Type = *req.Type
+	}
+	if req.Name != nil {
+		asset.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		asset.Description = *req.Description
+	}
+	if req.Location != nil {
+		asset.Location = *req.Location
+	}
+	if req.Latitude != nil {
+		asset.Latitude = req.Latitude
+	}
+	if req.Longitude != nil {
+		asset.Longitude = req.Longitude
+	}
+	if req.Area != nil {
+		asset.Area = req.Area
+	}
+	if req.AreaUnit != nil {
+		asset.AreaUnit = *req.AreaUnit
+	}
+	if req.PurchaseValue != nil {
+		asset.PurchaseValue = *req.PurchaseValue
+	}
+	if req.AcquisitionDate != nil {
+		acquisitionDate, err := requireDate("acquisition_date", *req.AcquisitionDate)
+		if err != nil {
+			return nil, err
+		}
+		asset.AcquisitionDate = acquisitionDate
+	}
+
+	if err := validateAsset(asset); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, asset); err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// UpdateStatus moves an asset to another status and records why
+func (s *service) UpdateStatus(ctx context.Context, actor *domain.Actor, id int64, req *dto.UpdateStatusRequest) (*domain.Asset, error) {
+	asset, err := s.authorize(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !asset.CanTransitionTo(req.Status) {
+		return nil, errors.New(errors.ErrCodeConflict, "Asset cannot move from "+string(asset.Status)+" to "+string(req.Status), 409)
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if req.Status == domain.AssetStatusDisposed && reason == "" {
+		return nil, errors.New(errors.ErrCodeValidation, "A reason is required to dispose of an asset", 400)
+	}
+
+	change := &domain.AssetStatusChange{
+		FromStatus: asset.Status,
+		ToStatus:   req.Status,
+		Reason:     reason,
+		ChangedBy:  actor.UserID,
+	}
+
+	if err := s.repo.UpdateStatus(ctx, asset, change); err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// GetByID gets an asset with its documents and status history
+func (s *service) GetByID(ctx context.Context, actor *domain.Actor, id int64) (*dto.AssetResponse, error) {
+	asset, err := s.viewable(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := s.repo.GetDocuments(ctx, id, "")
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.GetStatusChanges(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AssetResponse{
+		Asset:         asset,
+		Documents:     documents,
+		StatusHistory: history,
+	}, nil
+}
+
+// List lists assets, optionally only those inside a region; nazirs only see
+// the assets of their own campaigns
+func (s *service) List(ctx context.Context, actor *domain.Actor, query *dto.ListAssetsQuery) ([]*domain.Asset, int64, error) {
+	filter := &repository.ListFilter{
+		CampaignID: query.CampaignID,
+		Type:       query.Type,
+		Status:     query.Status,
+	}
+	if actor.Role == domain.RoleNazir {
+		filter.NazirID = actor.UserID
+	}
+	if len(query.Region) > 0 {
+		region, err := parsePolygon("region", query.Region)
+		if err != nil {
+			return nil, 0, err
+		}
+		filter.Region = string(region.GeoJSON())
+	}
+
+	return s.repo.List(ctx, filter, query.PerPage, (query.Page-1)*query.PerPage)
+}
+
+// GetStatusHistory gets the status changes of an asset, latest first
+func (s *service) GetStatusHistory(ctx context.Context, actor *domain.Actor, id int64) ([]*domain.AssetStatusChange, error) {
+	if _, err := s.viewable(ctx, actor, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetStatusChanges(ctx, id)
+}
+
+// authorize loads an asset and checks the actor may manage it
+func (s *service) authorize(ctx context.Context, actor *domain.Actor, id int64) (*domain.Asset, error) {
+	asset, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign, err := s.repo.FindCampaign(ctx, asset.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManage(actor, campaign) {
+		return nil, errors.New(errors.ErrCodeForbidden, "Only the nazir of this asset can manage it", 403)
+	}
+
+	return asset, nil
+}
+
+// viewable loads an asset the actor may see. Nazirs only see the assets of
+// their own campaigns; other nazirs' assets are reported as not found.
+func (s *service) viewable(ctx context.Context, actor *domain.Actor, id int64) (*domain.Asset, error) {
+	asset, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if actor.Role != domain.RoleNazir {
+		return asset, nil
+	}
+
+	campaign, err := s.repo.FindCampaign(ctx, asset.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.NazirID != actor.UserID {
+		return nil, errors.New(errors.ErrCodeNotFound, "Asset not found", 404)
+	}
+
+	return asset, nil
+}
+
+// canManage checks if the actor is an admin or the nazir of the asset's campaign
+func canManage(actor *domain.Actor, campaign *repository.CampaignRef) bool {
+	if actor == nil {
+		return false
+	}
+	return actor.IsAdmin() || (actor.Role == domain.RoleNazir && campaign.NazirID == actor.UserID)
+}
+
+// validateAsset checks the asset's location and area are consistent
+func validateAsset(asset *domain.Asset) error {
+	if (asset.Latitude == nil) != (asset.Longitude == nil) {
+		return errors.New(errors.ErrCodeValidation, "latitude and longitude must be given together", 400)
+	}
+	if asset.Area != nil && asset.AreaUnit == "" {
+		return errors.New(errors.ErrCodeValidation, "area_unit is required with area", 400)
+	}
+	if asset.AcquisitionDate.After(time.Now()) {
+		return errors.New(errors.ErrCodeValidation, "acquisition_date must not be in the future", 400)
+	}
+	return nil
+}
+
+// parseDate parses an optional YYYY-MM-DD date field
+func parseDate(field, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeValidation, field+" must be a date (YYYY-MM-DD)", 400)
+	}
+	return &date, nil
+}
+
+// requireDate parses a required YYYY-MM-DD date field
+func requireDate(field, value string) (time.Time, error) {
+	date, err := parseDate(field, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if date == nil {
+		return time.Time{}, errors.New(errors.ErrCodeValidation, field+" is required", 400)
+	}
+	return *date, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+const (
+	maxTileZoom = 22
+	// Below this zoom a parcel is only a few pixels wide, so boundaries are
+	// drawn as points
+	minBoundaryZoom = 12
+	// Tiles kept in memory; the cache starts over when it fills up
+	maxCachedTiles = 5000
+	// Web Mercator circumference in meters
+	mercatorWidth = 40075016.686
+)
+
+// Tile is a Mapbox Vector Tile of the asset map
+type Tile struct {
+	ETag    string
+	Content []byte
+}
+
+// tileCache keeps the tiles of one asset map version. A new version drops
+// every tile built before it.
+type tileCache struct {
+	mu      sync.Mutex
+	version int64
+	tiles   map[string][]byte
+}
+
+func newTileCache() *tileCache {
+	return &tileCache{tiles: make(map[string][]byte)}
+}
+
+func (c *tileCache) get(version int64, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if version != c.version {
+		return nil, false
+	}
+	tile, ok := c.tiles[key]
+	return tile, ok
+}
+
+func (c *tileCache) put(version int64, key string, tile []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if version < c.version {
+		return
+	}
+	if version > c.version || len(c.tiles) >= maxCachedTiles {
+		c.version = version
+		c.tiles = make(map[string][]byte)
+	}
+	c.tiles[key] = tile
+}
+
+// TileETag gets the entity tag every tile of the current asset map carries
+func (s *service) TileETag(ctx context.Context) (string, error) {
+	version, err := s.repo.MapVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	return tileETag(version), nil
+}
+
+// GetTile gets vector tile z/x/y of the public asset map, building it only
+// if the asset map changed since it was last built
+func (s *service) GetTile(ctx context.Context, z, x, y int) (*Tile, error) {
+	if z < 0 || z > maxTileZoom || x < 0 || y < 0 || x >= 1<<z || y >= 1<<z {
+		return nil, errors.New(errors.ErrCodeNotFound, "Tile not found", 404)
+	}
+
+	version, err := s.repo.MapVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%d/%d/%d", z, x, y)
+	if content, ok := s.tiles.get(version, key); ok {
+		return &Tile{ETag: tileETag(version), Content: content}, nil
+	}
+
+	// Simplify to about one pixel of a 512 pixel tile
+	tolerance := mercatorWidth / float64(int64(1)<<z) / 512
+	content, err := s.repo.Tile(ctx, z, x, y, tolerance, z < minBoundaryZoom)
+	if err != nil {
+		return nil, err
+	}
+
+	s.tiles.put(version, key, content)
+	return &Tile{ETag: tileETag(version), Content: content}, nil
+}
+
+func tileETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
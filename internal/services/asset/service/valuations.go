@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/asset/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// Ledger accounts used by asset revaluation
+const (
+	accountWakafAssets = "wakaf_assets"
+	accountWakafEquity = "wakaf_equity"
+)
+
+// GetValuations gets the valuations of an asset, optionally in one status,
+// latest first
+func (s *service) GetValuations(ctx context.Context, actor *domain.Actor, assetID int64, status domain.ValuationStatus, page, perPage int) ([]*domain.AssetValuation, int64, error) {
+	if _, err := s.viewable(ctx, actor, assetID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.repo.GetValuations(ctx, assetID, status, perPage, (page-1)*perPage)
+}
+
+// GetValuationTimeline gets how an asset's value moved from its purchase
+// value through each approved valuation
+func (s *service) GetValuationTimeline(ctx context.Context, actor *domain.Actor, assetID int64) (*dto.ValuationTimeline, error) {
+	asset, err := s.viewable(ctx, actor, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	valuations, err := s.repo.GetValuationTimeline(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ValuationTimeline{
+		AssetID:         asset.ID,
+		PurchaseValue:   asset.PurchaseValue,
+		AcquisitionDate: asset.AcquisitionDate,
+		CurrentValue:    asset.CurrentValue,
+		LastValuationAt: asset.LastValuationAt,
+		Change:          asset.CurrentValue - asset.PurchaseValue,
+		Valuations:      valuations,
+	}, nil
+}
+
+// GetPendingValuations gets the valuations awaiting an admin's approval
+func (s *service) GetPendingValuations(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*domain.AssetValuation, int64, error) {
+	if !actor.IsAdmin() {
+		return nil, 0, errors.ErrForbidden
+	}
+
+	return s.repo.GetPendingValuations(ctx, perPage, (page-1)*perPage)
+}
+
+// CreateValuation records an appraisal of an asset. It changes the asset's
+// value only once an admin approves it.
+func (s *service) CreateValuation(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.ValuationRequest) (*domain.AssetValuation, error) {
+	asset, err := s.authorize(ctx, actor, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Status == domain.AssetStatusDisposed {
+		return nil, errors.New(errors.ErrCodeConflict, "Disposed assets cannot be valued", 409)
+	}
+
+	valuationDate, err := requireDate("valuation_date", req.ValuationDate)
+	if err != nil {
+		return nil, err
+	}
+	if valuationDate.After(time.Now()) {
+		return nil, errors.New(errors.ErrCodeValidation, "valuation_date must not be in the future", 400)
+	}
+	if asset.LastValuationAt != nil && valuationDate.Before(*asset.LastValuationAt) {
+		return nil, errors.New(errors.ErrCodeValidation, "valuation_date must not be before the asset's last approved valuation", 400)
+	}
+
+	valuation := &domain.AssetValuation{
+		AssetID:        assetID,
+		ValuationValue: req.ValuationValue,
+		ValuationDate:  valuationDate,
+		ValuedBy:       strings.TrimSpace(req.ValuedBy),
+		Method:         req.Method,
+		Notes:          req.Notes,
+		DocumentURL:    req.DocumentURL,
+		Status:         domain.ValuationStatusPending,
+		RecordedBy:     actor.UserID,
+	}
+
+	if err := s.repo.CreateValuation(ctx, valuation); err != nil {
+		return nil, err
+	}
+
+	return valuation, nil
+}
+
+// ReviewValuation approves or rejects a pending valuation. An approved one
+// becomes the asset's current value and its surplus or deficit is posted to
+// the ledger.
+func (s *service) ReviewValuation(ctx context.Context, actor *domain.Actor, assetID, valuationID int64, req *dto.ValuationReviewRequest) (*domain.AssetValuation, error) {
+	if !actor.IsAdmin() {
+		return nil, errors.ErrForbidden
+	}
+
+	valuation, err := s.repo.FindValuation(ctx, assetID, valuationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !valuation.IsPending() {
+		return nil, errors.New(errors.ErrCodeConflict, "Valuation is not awaiting approval", 409)
+	}
+
+	reviewerID := actor.UserID
+	valuation.DecidedBy = &reviewerID
+
+	if req.Decision == domain.ValuationStatusRejected {
+		valuation.RejectionReason = strings.TrimSpace(req.Reason)
+		if valuation.RejectionReason == "" {
+			return nil, errors.New(errors.ErrCodeValidation, "A reason is required to reject a valuation", 400)
+		}
+		if err := s.repo.RejectValuation(ctx, valuation); err != nil {
+			return nil, err
+		}
+		return valuation, nil
+	}
+
+	if err := s.repo.ApproveValuation(ctx, valuation, revaluationEntries); err != nil {
+		return nil, err
+	}
+	return valuation, nil
+}
+
+// revaluationEntries builds the ledger entries of an approved valuation. A
+// surplus raises the wakaf assets against wakaf equity; a deficit lowers
+// both. Nothing is posted when the value is unchanged.
+func revaluationEntries(valuation *domain.AssetValuation, previous int64, postedOn time.Time) []*domain.Ledger {
+	adjustment := valuation.ValuationValue - previous
+	if adjustment == 0 {
+		return nil
+	}
+
+	debit, credit := accountWakafAssets, accountWakafEquity
+	kind := "surplus"
+	if adjustment < 0 {
+		debit, credit = accountWakafEquity, accountWakafAssets
+		kind = "deficit"
+		adjustment = -adjustment
+	}
+
+	description := fmt.Sprintf("Revaluation %s of asset ID %d valued on %s (%s by %s)",
+		kind, valuation.AssetID, valuation.ValuationDate.Format("2006-01-02"), valuation.Method, valuation.ValuedBy)
+	if postedOn.After(valuation.ValuationDate) {
+		description += ", posted late as its period is closed"
+	}
+	return []*domain.Ledger{
+		{
+			AccountType:   "debit",
+			AccountName:   debit,
+			Amount:        adjustment,
+			Description:   description,
+			ReferenceType: domain.LedgerRefAssetRevaluation,
+			EntryDate:     postedOn,
+		},
+		{
+			AccountType:   "credit",
+			AccountName:   credit,
+			Amount:        adjustment,
+			Description:   description,
+			ReferenceType: domain.LedgerRefAssetRevaluation,
+			EntryDate:     postedOn,
+		},
+	}
+}
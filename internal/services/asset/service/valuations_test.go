@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+)
+
+func TestRevaluationEntries(t *testing.T) {
+	valuation := &domain.AssetValuation{
+		AssetID:        7,
+		ValuationValue: 1500000,
+		ValuationDate:  time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC),
+		Method:         "market_value",
+		ValuedBy:       "KJPP Rusli",
+	}
+
+	tests := []struct {
+		name       string
+		previous   int64
+		postedOn   time.Time
+		wantDebit  string
+		wantCredit string
+		wantAmount int64
+		want       string
+	}{
+		{
+			name:       "surplus",
+			previous:   1000000,
+			postedOn:   valuation.ValuationDate,
+			wantDebit:  accountWakafAssets,
+			wantCredit: accountWakafEquity,
+			wantAmount: 500000,
+			want:       "Revaluation surplus of asset ID 7 valued on 2026-03-20 (market_value by KJPP Rusli)",
+		},
+		{
+			name:       "deficit",
+			previous:   2000000,
+			postedOn:   valuation.ValuationDate,
+			wantDebit:  accountWakafEquity,
+			wantCredit: accountWakafAssets,
+			wantAmount: 500000,
+			want:       "Revaluation deficit of asset ID 7 valued on 2026-03-20 (market_value by KJPP Rusli)",
+		},
+		{
+			name:       "valuation date in a closed period",
+			previous:   1000000,
+			postedOn:   time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC),
+			wantDebit:  accountWakafAssets,
+			wantCredit: accountWakafEquity,
+			wantAmount: 500000,
+			want:       "Revaluation surplus of asset ID 7 valued on 2026-03-20 (market_value by KJPP Rusli), posted late as its period is closed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := revaluationEntries(valuation, tt.previous, tt.postedOn)
+			if len(ledger) != 2 {
+				t.Fatalf("got %d ledger entries, want 2", len(ledger))
+			}
+
+			debit, credit := ledger[0], ledger[1]
+			if debit.AccountType != "debit" || debit.AccountName != tt.wantDebit ||
+				credit.AccountType != "credit" || credit.AccountName != tt.wantCredit {
+				t.Errorf("accounts = %s %s, %s %s", debit.AccountType, debit.AccountName, credit.AccountType, credit.AccountName)
+			}
+
+			for _, entry := range ledger {
+				if entry.Amount != tt.wantAmount {
+					t.Errorf("%s amount = %d, want %d", entry.AccountName, entry.Amount, tt.wantAmount)
+				}
+				if !entry.EntryDate.Equal(tt.postedOn) {
+					t.Errorf("%s dated %s, want %s", entry.AccountName, entry.EntryDate.Format("2006-01-02"), tt.postedOn.Format("2006-01-02"))
+				}
+				if entry.Description != tt.want {
+					t.Errorf("description = %q, want %q", entry.Description, tt.want)
+				}
+			}
+		})
+	}
+
+	if ledger := revaluationEntries(valuation, valuation.ValuationValue, valuation.ValuationDate); ledger != nil {
+		t.Errorf("unchanged value posted %d entries, want none", len(ledger))
+	}
+}
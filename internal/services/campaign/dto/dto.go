@@ -0,0 +1,339 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+)
+
+// CreateCampaignRequest represents campaign creation request
+type CreateCampaignRequest struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	ShortDesc   string              `json:"short_desc"`
+	Type        domain.CampaignType `json:"type"`
+	GoalAmount  int64               `json:"goal_amount"`
+	ImageURL    string              `json:"image_url,omitempty"`
+	VideoURL    string              `json:"video_url,omitempty"`
+	Location    string              `json:"location,omitempty"`
+	StartDate   string              `json:"start_date"`         // YYYY-MM-DD
+	EndDate     string              `json:"end_date,omitempty"` // YYYY-MM-DD, omitted for endless campaigns
+	IsEndless   bool                `json:"is_endless"`
+	IsFeatured  bool                `json:"is_featured"` // admin only
+	IsUrgent    bool                `json:"is_urgent"`
+	NazirID     int64               `json:"nazir_id,omitempty"` // admin only, defaults to the creator
+
+	AutoPublish        bool   `json:"auto_publish"`                   // go live on start_date
+	AcceptOverflow     bool   `json:"accept_overflow"`                // keep accepting donations once completed
+	OverflowCampaignID *int64 `json:"overflow_campaign_id,omitempty"` // endless general fund receiving overflow
+}
+
+// UpdateCampaignRequest represents campaign update request; omitted fields are unchanged
+type UpdateCampaignRequest struct {
+	Title       *string              `json:"title,omitempty"`
+	Description *string              `json:"description,omitempty"`
+	ShortDesc   *string              `json:"short_desc,omitempty"`
+	Type        *domain.CampaignType `json:"type,omitempty"`
+	GoalAmount  *int64               `json:"goal_amount,omitempty"`
+	ImageURL    *string              `json:"image_url,omitempty"`
+	VideoURL    *string              `json:"video_url,omitempty"`
+	Location    *string              `json:"location,omitempty"`
+	StartDate   *string              `json:"start_date,omitempty"`
+	EndDate     *string              `json:"end_date,omitempty"`
+	IsEndless   *bool                `json:"is_endless,omitempty"`
+	IsFeatured  *bool                `json:"is_featured,omitempty"`
+	IsUrgent    *bool                `json:"is_urgent,omitempty"`
+
+	AutoPublish        *bool  `json:"auto_publish,omitempty"`
+	AcceptOverflow     *bool  `json:"accept_overflow,omitempty"`
+	OverflowCampaignID *int64 `json:"overflow_campaign_id,omitempty"`
+}
+
+// UpdateStatusRequest represents campaign status transition request
+type UpdateStatusRequest struct {
+	Status domain.CampaignStatus `json:"status"`
+}
+
+// MilestoneRequest represents milestone create or update request
+type MilestoneRequest struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	TargetAmount int64  `json:"target_amount"`
+}
+
+// ListCampaignsQuery represents campaign listing filters
+type ListCampaignsQuery struct {
+	Status     domain.CampaignStatus
+	Type       domain.CampaignType
+	NazirID    int64
+	IsFeatured *bool
+	IsUrgent   *bool
+	Locale     domain.Locale
+	Page       int
+	PerPage    int
+}
+
+// CampaignResponse represents a campaign with its progress and milestones
+type CampaignResponse struct {
+	*domain.Campaign
+	Progress       float64                    `json:"progress"`
+	Milestones     []*domain.Milestone        `json:"milestones,omitempty"`
+	PendingVersion *domain.CampaignVersion    `json:"pending_version,omitempty"` // material change awaiting re-approval
+	Locale         domain.Locale              `json:"locale,omitempty"`          // locale of the title shown
+	Direction      string                     `json:"dir,omitempty"`             // ltr or rtl
+	Locales        []domain.Locale            `json:"locales,omitempty"`         // locales the campaign is available in
+	Impact         []*ImpactIndicatorResponse `json:"impact,omitempty"`
+}
+
+// SearchCampaignsQuery represents full-text search terms and filters
+type SearchCampaignsQuery struct {
+	Query       string
+	Status      domain.CampaignStatus
+	Type        domain.CampaignType
+	Location    string
+	IsFeatured  *bool
+	IsUrgent    *bool
+	MinProgress *float64
+	MaxProgress *float64
+	Locale      domain.Locale
+	Page        int
+	PerPage     int
+}
+
+// SearchResult represents a campaign matched by a search
+type SearchResult struct {
+	*CampaignResponse
+	Score float64 `json:"score"`
+}
+
+// SearchResponse represents a page of search results with facet counts
+type SearchResponse struct {
+	Results []*SearchResult          `json:"results"`
+	Facets  *repository.SearchFacets `json:"facets"`
+}
+
+// CampaignUpdateRequest represents campaign update post create or edit request
+type CampaignUpdateRequest struct {
+	Title          string   `json:"title"`
+	Body           string   `json:"body"` // Markdown
+	Images         []string `json:"images,omitempty"`
+	MilestoneID    *int64   `json:"milestone_id,omitempty"`
+	DisbursementID *int64   `json:"disbursement_id,omitempty"`
+	Publish        bool     `json:"publish"` // on create, publish and notify subscribers at once
+}
+
+// SubscriptionRequest represents a donor's campaign update subscription settings
+type SubscriptionRequest struct {
+	Channel    string `json:"channel"`
+	Subscribed bool   `json:"subscribed"`
+}
+
+// UnsubscribeRequest represents an unsubscribe request from a notification link
+type UnsubscribeRequest struct {
+	Token string `json:"token"`
+}
+
+// ReviewRequest represents an admin's decision on a campaign awaiting review
+type ReviewRequest struct {
+	Decision      domain.CampaignReviewDecision `json:"decision"`
+	LandDocuments bool                          `json:"land_documents"`
+	AkadStated    bool                          `json:"akad_stated"`
+	GoalJustified bool                          `json:"goal_justified"`
+	Comment       string                        `json:"comment,omitempty"` // required when rejecting
+}
+
+// FundraiserRequest represents fundraiser page create or edit request
+type FundraiserRequest struct {
+	Title      string `json:"title"`
+	Story      string `json:"story"` // Markdown
+	GoalAmount int64  `json:"goal_amount"`
+}
+
+// FundraiserVisibilityRequest represents a nazir hiding or restoring a fundraiser page
+type FundraiserVisibilityRequest struct {
+	Hidden bool   `json:"hidden"`
+	Reason string `json:"reason,omitempty"` // required when hiding
+}
+
+// FundraiserResponse represents a fundraiser page with its progress
+type FundraiserResponse struct {
+	*domain.Fundraiser
+	Progress float64 `json:"progress"`
+}
+
+// LeaderboardQuery represents the period and page of a leaderboard
+type LeaderboardQuery struct {
+	Period domain.LeaderboardPeriod
+	From   string // YYYY-MM-DD, custom period only
+	To     string // YYYY-MM-DD inclusive, custom period only
+	Cursor string
+	Limit  int
+}
+
+// DonorWallResponse represents a page of a campaign's donor wall
+type DonorWallResponse struct {
+	Entries    []*domain.DonorWallEntry `json:"entries"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+}
+
+// LeaderboardResponse represents a page of a leaderboard over a period
+type LeaderboardResponse struct {
+	Period     domain.LeaderboardPeriod   `json:"period"`
+	From       *time.Time                 `json:"from,omitempty"`
+	To         *time.Time                 `json:"to,omitempty"` // exclusive
+	Entries    []*domain.LeaderboardEntry `json:"entries"`
+	NextCursor string                     `json:"next_cursor,omitempty"`
+}
+
+// MessageVisibilityRequest represents a nazir hiding or restoring a donor's message
+type MessageVisibilityRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+// BudgetLineRequest represents budget line create or edit request
+type BudgetLineRequest struct {
+	Category      domain.BudgetCategory `json:"category"`
+	Name          string                `json:"name"`
+	Description   string                `json:"description,omitempty"`
+	PlannedAmount int64                 `json:"planned_amount"`
+	Position      int                   `json:"position,omitempty"` // defaults to last on create
+}
+
+// BudgetLineReport represents a budget line with its spending against plan
+type BudgetLineReport struct {
+	*domain.BudgetLine
+	PendingAmount   int64   `json:"pending_amount"` // held for admin approval
+	Variance        int64   `json:"variance"`       // planned less spent, negative when over budget
+	VariancePercent float64 `json:"variance_percent"`
+	OverBudget      bool    `json:"over_budget"`
+}
+
+// BudgetReport represents a campaign's spend-versus-budget view
+type BudgetReport struct {
+	CampaignID      int64               `json:"campaign_id"`
+	GoalAmount      int64               `json:"goal_amount"`
+	RaisedAmount    int64               `json:"raised_amount"`
+	PlannedAmount   int64               `json:"planned_amount"`
+	SpentAmount     int64               `json:"spent_amount"`
+	PendingAmount   int64               `json:"pending_amount"`
+	Variance        int64               `json:"variance"`
+	VariancePercent float64             `json:"variance_percent"`
+	UnbudgetedGoal  int64               `json:"unbudgeted_goal"` // goal not covered by budget lines, negative when the plan exceeds it
+	AvailableFunds  int64               `json:"available_funds"` // raised less spent
+	Lines           []*BudgetLineReport `json:"lines"`
+}
+
+// DisbursementRequest represents a disbursement against a budget line
+type DisbursementRequest struct {
+	BudgetLineID int64  `json:"budget_line_id"`
+	Amount       int64  `json:"amount"`
+	Recipient    string `json:"recipient"`
+	Description  string `json:"description,omitempty"`
+	ReceiptURL   string `json:"receipt_url,omitempty"`
+	DisbursedAt  string `json:"disbursed_at"` // YYYY-MM-DD
+}
+
+// DisbursementReviewRequest represents an admin's decision on an over-budget disbursement
+type DisbursementReviewRequest struct {
+	Decision domain.CampaignReviewDecision `json:"decision"`
+	Reason   string                        `json:"reason,omitempty"` // required when rejecting
+}
+
+// TemplateRequest represents campaign template create or edit request
+type TemplateRequest struct {
+	Name         string                      `json:"name"`
+	Title        string                      `json:"title"`
+	Description  string                      `json:"description"`
+	ShortDesc    string                      `json:"short_desc"`
+	Type         domain.CampaignType         `json:"type"`
+	GoalAmount   int64                       `json:"goal_amount"`
+	ImageURL     string                      `json:"image_url,omitempty"`
+	VideoURL     string                      `json:"video_url,omitempty"`
+	Location     string                      `json:"location,omitempty"`
+	IsEndless    bool                        `json:"is_endless"`
+	DurationDays int                         `json:"duration_days,omitempty"` // sets end_date from start_date
+	Milestones   []domain.TemplateMilestone  `json:"milestones"`
+	BudgetLines  []domain.TemplateBudgetLine `json:"budget_lines"`
+	IsShared     bool                        `json:"is_shared"` // admin only
+}
+
+// SaveTemplateRequest represents request to save a campaign as a template
+type SaveTemplateRequest struct {
+	Name     string `json:"name"`
+	IsShared bool   `json:"is_shared"` // admin only
+}
+
+// CloneCampaignRequest represents request to start a draft campaign from a
+// template or an existing campaign; omitted fields are copied
+type CloneCampaignRequest struct {
+	Title      string `json:"title,omitempty"`
+	StartDate  string `json:"start_date"`         // YYYY-MM-DD
+	EndDate    string `json:"end_date,omitempty"` // YYYY-MM-DD, defaults to keeping the duration
+	GoalAmount int64  `json:"goal_amount,omitempty"`
+	NazirID    int64  `json:"nazir_id,omitempty"` // admin only, defaults to the creator
+}
+
+// TranslationRequest represents the text of a campaign, milestone or update
+// in another locale. Description is the update body for updates; ShortDesc
+// is only used by campaigns.
+type TranslationRequest struct {
+	Title       string `json:"title"`
+	ShortDesc   string `json:"short_desc,omitempty"`
+	Description string `json:"description"`
+}
+
+// TranslationCoverage represents the locales a campaign, milestone or update
+// is translated into
+type TranslationCoverage struct {
+	EntityType domain.TranslationEntity `json:"entity_type"`
+	EntityID   int64                    `json:"entity_id"`
+	Title      string                   `json:"title"`
+	Translated []domain.Locale          `json:"translated"`
+	Missing    []domain.Locale          `json:"missing"`    // no translation at all
+	Incomplete []domain.Locale          `json:"incomplete"` // translated, but a field written in the original is empty
+}
+
+// TranslationsResponse represents the editor view of a campaign's translations
+type TranslationsResponse struct {
+	CampaignID   int64                  `json:"campaign_id"`
+	Locales      []domain.Locale        `json:"locales"`  // locales content is translated into
+	Missing      []domain.Locale        `json:"missing"`  // locales with any missing or incomplete entity
+	Coverage     []*TranslationCoverage `json:"coverage"` // the campaign, then milestones and updates
+	Translations []*domain.Translation  `json:"translations"`
+}
+
+// ImpactIndicatorRequest represents impact indicator create or edit request
+type ImpactIndicatorRequest struct {
+	Name        string  `json:"name"`
+	Unit        string  `json:"unit"`
+	Description string  `json:"description,omitempty"`
+	TargetValue float64 `json:"target_value"`
+	Position    int     `json:"position,omitempty"` // defaults to last on create
+}
+
+// ImpactIndicatorResponse represents an impact indicator with its progress
+type ImpactIndicatorResponse struct {
+	*domain.ImpactIndicator
+	Progress float64 `json:"progress"`
+}
+
+// ImpactMeasurementRequest represents a reading of an indicator's cumulative value
+type ImpactMeasurementRequest struct {
+	Value        float64  `json:"value"`
+	Note         string   `json:"note,omitempty"`
+	EvidenceURLs []string `json:"evidence_urls"`
+	MeasuredAt   string   `json:"measured_at"` // YYYY-MM-DD
+}
+
+// ImpactReportQuery represents impact report filters
+type ImpactReportQuery struct {
+	Type     domain.CampaignType
+	TenantID *int64 // admin only
+}
+
+// ImpactReport represents impact indicators summed by name and unit
+type ImpactReport struct {
+	Totals   []*repository.ImpactTotal `json:"totals"`
+	ByTenant []*repository.ImpactTotal `json:"by_tenant,omitempty"` // admin only
+}
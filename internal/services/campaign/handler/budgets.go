@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+var budgetCategories = []string{
+	string(domain.BudgetCategoryLand),
+	string(domain.BudgetCategoryNotary),
+	string(domain.BudgetCategoryCertification),
+	string(domain.BudgetCategoryConstruction),
+	string(domain.BudgetCategoryEquipment),
+	string(domain.BudgetCategoryOperational),
+	string(domain.BudgetCategoryOther),
+}
+
+var disbursementStatuses = []string{
+	string(domain.DisbursementStatusPosted),
+	string(domain.DisbursementStatusPendingApproval),
+	string(domain.DisbursementStatusRejected),
+}
+
+// GetBudget handles the spend-versus-budget view of a campaign
+func (h *Handler) GetBudget(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	report, err := h.service.GetBudget(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, report)
+}
+
+// CreateBudgetLine handles adding a budget line
+func (h *Handler) CreateBudgetLine(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeBudgetLine(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	line, err := h.service.CreateBudgetLine(r.Context(), middleware.ActorFromContext(r.Context()), id, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, line)
+}
+
+// UpdateBudgetLine handles editing a budget line
+func (h *Handler) UpdateBudgetLine(w http.ResponseWriter, r *http.Request) {
+	id, lineID, err := budgetLinePath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeBudgetLine(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	line, err := h.service.UpdateBudgetLine(r.Context(), middleware.ActorFromContext(r.Context()), id, lineID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, line)
+}
+
+// DeleteBudgetLine handles deleting a budget line
+func (h *Handler) DeleteBudgetLine(w http.ResponseWriter, r *http.Request) {
+	id, lineID, err := budgetLinePath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := h.service.DeleteBudgetLine(r.Context(), middleware.ActorFromContext(r.Context()), id, lineID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// GetDisbursements handles listing the disbursements of a campaign
+func (h *Handler) GetDisbursements(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	status := domain.DisbursementStatus(r.URL.Query().Get("status"))
+	if status != "" {
+		v := validator.New()
+		v.In("status", string(status), disbursementStatuses)
+		if !v.IsValid() {
+			response.Error(w, v.Error())
+			return
+		}
+	}
+
+	page, perPage := request.Pagination(r)
+	disbursements, total, err := h.service.GetDisbursements(r.Context(), middleware.ActorFromContext(r.Context()), id, status, page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, disbursements, page, perPage, total)
+}
+
+// GetPendingDisbursements handles the admin queue of over-budget disbursements
+func (h *Handler) GetPendingDisbursements(w http.ResponseWriter, r *http.Request) {
+	page, perPage := request.Pagination(r)
+	disbursements, total, err := h.service.GetPendingDisbursements(r.Context(), middleware.ActorFromContext(r.Context()), page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, disbursements, page, perPage, total)
+}
+
+// CreateDisbursement handles recording a disbursement
+func (h *Handler) CreateDisbursement(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.DisbursementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Min("budget_line_id", req.BudgetLineID, 1)
+	v.Min("amount", req.Amount, 1)
+	v.Required("recipient", req.Recipient)
+	v.MaxLength("recipient", req.Recipient, 255)
+	v.MaxLength("description", req.Description, 2000)
+	v.Required("disbursed_at", req.DisbursedAt)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	disbursement, err := h.service.CreateDisbursement(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, disbursement)
+}
+
+// ReviewDisbursement handles approving or rejecting an over-budget disbursement
+func (h *Handler) ReviewDisbursement(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	disbursementID, err := request.PathID(r, "disbursementID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.DisbursementReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("decision", string(req.Decision))
+	v.In("decision", string(req.Decision), reviewDecisions)
+	if req.Decision == domain.CampaignReviewRejected {
+		v.Required("reason", req.Reason)
+	}
+	v.MaxLength("reason", req.Reason, 2000)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	disbursement, err := h.service.ReviewDisbursement(r.Context(), middleware.ActorFromContext(r.Context()), id, disbursementID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, disbursement)
+}
+
+// budgetLinePath parses the campaign and budget line IDs of a budget line route
+func budgetLinePath(r *http.Request) (int64, int64, error) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lineID, err := request.PathID(r, "lineID")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return id, lineID, nil
+}
+
+// decodeBudgetLine decodes and validates a budget line request body
+func decodeBudgetLine(r *http.Request) (*dto.BudgetLineRequest, error) {
+	var req dto.BudgetLineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400)
+	}
+
+	v := validator.New()
+	v.Required("category", string(req.Category))
+	v.In("category", string(req.Category), budgetCategories)
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, 255)
+	v.MaxLength("description", req.Description, 2000)
+	v.Min("planned_amount", req.PlannedAmount, 1)
+	v.Min("position", int64(req.Position), 0)
+
+	if !v.IsValid() {
+		return nil, v.Error()
+	}
+
+	return &req, nil
+}
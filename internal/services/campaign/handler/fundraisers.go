@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+	"github.com/gorilla/mux"
+)
+
+// GetFundraisers handles the fundraiser leaderboard of a campaign
+func (h *Handler) GetFundraisers(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	page, perPage := request.Pagination(r)
+	fundraisers, total, err := h.service.GetFundraisers(r.Context(), middleware.ActorFromContext(r.Context()), id, page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, fundraisers, page, perPage, total)
+}
+
+// GetFundraiser handles fundraiser page retrieval
+func (h *Handler) GetFundraiser(w http.ResponseWriter, r *http.Request) {
+	id, fundraiserID, err := fundraiserPath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	fundraiser, err := h.service.GetFundraiser(r.Context(), middleware.ActorFromContext(r.Context()), id, fundraiserID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, fundraiser)
+}
+
+// GetFundraiserBySlug handles fundraiser page retrieval by slug
+func (h *Handler) GetFundraiserBySlug(w http.ResponseWriter, r *http.Request) {
+	fundraiser, err := h.service.GetFundraiserBySlug(r.Context(), middleware.ActorFromContext(r.Context()), mux.Vars(r)["slug"])
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, fundraiser)
+}
+
+// GetMyFundraisers handles listing the caller's fundraiser pages
+func (h *Handler) GetMyFundraisers(w http.ResponseWriter, r *http.Request) {
+	fundraisers, err := h.service.GetMyFundraisers(r.Context(), middleware.ActorFromContext(r.Context()))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, fundraisers)
+}
+
+// CreateFundraiser handles fundraiser page creation
+func (h *Handler) CreateFundraiser(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeFundraiser(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	fundraiser, err := h.service.CreateFundraiser(r.Context(), middleware.ActorFromContext(r.Context()), id, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, fundraiser)
+}
+
+// UpdateFundraiser handles fundraiser page editing
+func (h *Handler) UpdateFundraiser(w http.ResponseWriter, r *http.Request) {
+	id, fundraiserID, err := fundraiserPath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeFundraiser(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	fundraiser, err := h.service.UpdateFundraiser(r.Context(), middleware.ActorFromContext(r.Context()), id, fundraiserID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, fundraiser)
+}
+
+// SetFundraiserVisibility handles hiding or restoring a fundraiser page
+func (h *Handler) SetFundraiserVisibility(w http.ResponseWriter, r *http.Request) {
+	id, fundraiserID, err := fundraiserPath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.FundraiserVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	if req.Hidden {
+		v.Required("reason", req.Reason)
+	}
+	v.MaxLength("reason", req.Reason, 1000)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	fundraiser, err := h.service.SetFundraiserVisibility(r.Context(), middleware.ActorFromContext(r.Context()), id, fundraiserID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, fundraiser)
+}
+
+// fundraiserPath parses the campaign and fundraiser IDs of a fundraiser route
+func fundraiserPath(r *http.Request) (int64, int64, error) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fundraiserID, err := request.PathID(r, "fundraiserID")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return id, fundraiserID, nil
+}
+
+// decodeFundraiser decodes and validates a fundraiser page request body
+func decodeFundraiser(r *http.Request) (*dto.FundraiserRequest, error) {
+	var req dto.FundraiserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400)
+	}
+
+	v := validator.New()
+	v.Required("title", req.Title)
+	v.MaxLength("title", req.Title, 255)
+	v.Required("story", req.Story)
+	v.MaxLength("story", req.Story, 20000)
+	v.Min("goal_amount", req.GoalAmount, 1)
+
+	if !v.IsValid() {
+		return nil, v.Error()
+	}
+
+	return &req, nil
+}
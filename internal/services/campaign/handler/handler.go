@@ -0,0 +1,554 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/services/campaign/service"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+	"github.com/gorilla/mux"
+)
+
+var campaignTypes = []string{
+	string(domain.CampaignTypeLand),
+	string(domain.CampaignTypeBuilding),
+	string(domain.CampaignTypeCash),
+	string(domain.CampaignTypeEducation),
+	string(domain.CampaignTypeHealthcare),
+	string(domain.CampaignTypeGeneral),
+}
+
+var campaignStatuses = []string{
+	string(domain.CampaignStatusDraft),
+	string(domain.CampaignStatusPendingReview),
+	string(domain.CampaignStatusActive),
+	string(domain.CampaignStatusPaused),
+	string(domain.CampaignStatusCompleted),
+	string(domain.CampaignStatusCancelled),
+}
+
+// Handler handles HTTP requests for wakaf campaigns
+type Handler struct {
+	service service.Service
+	auth    *middleware.Auth
+}
+
+// New creates a new campaign handler
+func New(service service.Service, auth *middleware.Auth) *Handler {
+	return &Handler{
+		service: service,
+		auth:    auth,
+	}
+}
+
+// Create handles campaign creation
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("title", req.Title)
+	v.MaxLength("title", req.Title, 255)
+	v.Required("description", req.Description)
+	v.MaxLength("short_desc", req.ShortDesc, 500)
+	v.Required("type", string(req.Type))
+	v.In("type", string(req.Type), campaignTypes)
+	v.Min("goal_amount", req.GoalAmount, 1)
+	v.Required("start_date", req.StartDate)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	campaign, err := h.service.Create(r.Context(), middleware.ActorFromContext(r.Context()), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, campaign)
+}
+
+// Update handles campaign update
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.UpdateCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	if req.Title != nil {
+		v.Required("title", *req.Title)
+		v.MaxLength("title", *req.Title, 255)
+	}
+	if req.ShortDesc != nil {
+		v.MaxLength("short_desc", *req.ShortDesc, 500)
+	}
+	if req.Type != nil {
+		v.In("type", string(*req.Type), campaignTypes)
+	}
+	if req.GoalAmount != nil {
+		v.Min("goal_amount", *req.GoalAmount, 1)
+	}
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	campaign, err := h.service.Update(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, campaign)
+}
+
+// UpdateStatus handles campaign status transitions
+func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.UpdateStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("status", string(req.Status))
+	v.In("status", string(req.Status), campaignStatuses)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	campaign, err := h.service.UpdateStatus(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, campaign)
+}
+
+// GetByID handles campaign retrieval by ID
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	campaign, err := h.service.GetByID(r.Context(), middleware.ActorFromContext(r.Context()), id, request.Locale(r))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	contentLanguage(w, campaign.Locale)
+	response.Success(w, campaign)
+}
+
+// GetBySlug handles campaign retrieval by slug
+func (h *Handler) GetBySlug(w http.ResponseWriter, r *http.Request) {
+	campaign, err := h.service.GetBySlug(r.Context(), middleware.ActorFromContext(r.Context()), mux.Vars(r)["slug"], request.Locale(r))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	contentLanguage(w, campaign.Locale)
+	response.Success(w, campaign)
+}
+
+// List handles campaign listing
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	query, err := listQuery(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	campaigns, total, err := h.service.List(r.Context(), middleware.ActorFromContext(r.Context()), query)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	contentLanguage(w, query.Locale)
+	response.Paginated(w, campaigns, query.Page, query.PerPage, total)
+}
+
+// Search handles full-text campaign search with facet counts
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	query, err := searchQuery(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	results, total, err := h.service.Search(r.Context(), middleware.ActorFromContext(r.Context()), query)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	contentLanguage(w, query.Locale)
+	response.Paginated(w, results, query.Page, query.PerPage, total)
+}
+
+// GetMilestones handles campaign milestone listing
+func (h *Handler) GetMilestones(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	locale := request.Locale(r)
+	milestones, err := h.service.GetMilestones(r.Context(), middleware.ActorFromContext(r.Context()), id, locale)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	contentLanguage(w, locale)
+	response.Success(w, milestones)
+}
+
+// CreateMilestone handles milestone creation
+func (h *Handler) CreateMilestone(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeMilestone(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	milestone, err := h.service.CreateMilestone(r.Context(), middleware.ActorFromContext(r.Context()), id, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, milestone)
+}
+
+// UpdateMilestone handles milestone update
+func (h *Handler) UpdateMilestone(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	milestoneID, err := request.PathID(r, "milestoneID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeMilestone(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	milestone, err := h.service.UpdateMilestone(r.Context(), middleware.ActorFromContext(r.Context()), id, milestoneID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, milestone)
+}
+
+// DeleteMilestone handles milestone deletion
+func (h *Handler) DeleteMilestone(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	milestoneID, err := request.PathID(r, "milestoneID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := h.service.DeleteMilestone(r.Context(), middleware.ActorFromContext(r.Context()), id, milestoneID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// RebuildTotals handles campaign totals rebuild
+func (h *Handler) RebuildTotals(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	change, err := h.service.RebuildTotals(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, change)
+}
+
+// RebuildAllTotals handles totals rebuild of every campaign
+func (h *Handler) RebuildAllTotals(w http.ResponseWriter, r *http.Request) {
+	drifted, err := h.service.RebuildAllTotals(r.Context(), middleware.ActorFromContext(r.Context()))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, drifted)
+}
+
+// RegisterRoutes registers HTTP routes under the campaigns prefix. Reads are
+// public; a token, when present, lets nazirs see their own drafts.
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	managers := middleware.RequireRole(domain.RoleAdmin, domain.RoleNazir)
+	admins := middleware.RequireRole(domain.RoleAdmin)
+
+	routes := r.NewRoute().Subrouter()
+	routes.Use(h.auth.Optional)
+	routes.HandleFunc("", h.List).Methods("GET")
+	routes.HandleFunc("/search", h.Search).Methods("GET")
+	routes.Handle("", managers(http.HandlerFunc(h.Create))).Methods("POST")
+	routes.HandleFunc("/{id:[0-9]+}", h.GetByID).Methods("GET")
+	routes.Handle("/{id:[0-9]+}", managers(http.HandlerFunc(h.Update))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/status", managers(http.HandlerFunc(h.UpdateStatus))).Methods("PUT")
+	routes.HandleFunc("/{id:[0-9]+}/milestones", h.GetMilestones).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/milestones", managers(http.HandlerFunc(h.CreateMilestone))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/milestones/{milestoneID:[0-9]+}", managers(http.HandlerFunc(h.UpdateMilestone))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/milestones/{milestoneID:[0-9]+}", managers(http.HandlerFunc(h.DeleteMilestone))).Methods("DELETE")
+	routes.Handle("/{id:[0-9]+}/totals/rebuild", admins(http.HandlerFunc(h.RebuildTotals))).Methods("POST")
+	routes.Handle("/totals/rebuild", admins(http.HandlerFunc(h.RebuildAllTotals))).Methods("POST")
+	routes.HandleFunc("/{id:[0-9]+}/updates", h.GetUpdates).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/updates", managers(http.HandlerFunc(h.CreateUpdate))).Methods("POST")
+	routes.HandleFunc("/{id:[0-9]+}/updates/{updateID:[0-9]+}", h.GetUpdate).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/updates/{updateID:[0-9]+}", managers(http.HandlerFunc(h.UpdateUpdate))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/updates/{updateID:[0-9]+}", managers(http.HandlerFunc(h.DeleteUpdate))).Methods("DELETE")
+	routes.Handle("/{id:[0-9]+}/updates/{updateID:[0-9]+}/publish", managers(http.HandlerFunc(h.PublishUpdate))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/subscription", h.auth.Authenticate(http.HandlerFunc(h.GetSubscription))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/subscription", h.auth.Authenticate(http.HandlerFunc(h.UpdateSubscription))).Methods("PUT")
+	routes.Handle("/updates/feed", h.auth.Authenticate(http.HandlerFunc(h.GetFeed))).Methods("GET")
+	routes.HandleFunc("/subscriptions/unsubscribe", h.Unsubscribe).Methods("POST")
+	routes.Handle("/reviews", admins(http.HandlerFunc(h.GetReviewQueue))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/review", admins(http.HandlerFunc(h.Review))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/reviews", managers(http.HandlerFunc(h.GetReviews))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/versions", managers(http.HandlerFunc(h.GetVersions))).Methods("GET")
+	routes.HandleFunc("/{id:[0-9]+}/fundraisers", h.GetFundraisers).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/fundraisers", h.auth.Authenticate(http.HandlerFunc(h.CreateFundraiser))).Methods("POST")
+	routes.HandleFunc("/{id:[0-9]+}/fundraisers/{fundraiserID:[0-9]+}", h.GetFundraiser).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/fundraisers/{fundraiserID:[0-9]+}", h.auth.Authenticate(http.HandlerFunc(h.UpdateFundraiser))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/fundraisers/{fundraiserID:[0-9]+}/visibility", managers(http.HandlerFunc(h.SetFundraiserVisibility))).Methods("PUT")
+	routes.HandleFunc("/{id:[0-9]+}/wall", h.GetDonorWall).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/wall/{donationID:[0-9]+}/message", managers(http.HandlerFunc(h.SetMessageVisibility))).Methods("PUT")
+	routes.HandleFunc("/{id:[0-9]+}/leaderboard/donors", h.GetTopDonors).Methods("GET")
+	routes.HandleFunc("/{id:[0-9]+}/leaderboard/fundraisers", h.GetTopFundraisers).Methods("GET")
+	routes.HandleFunc("/{id:[0-9]+}/budget", h.GetBudget).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/budget/lines", managers(http.HandlerFunc(h.CreateBudgetLine))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/budget/lines/{lineID:[0-9]+}", managers(http.HandlerFunc(h.UpdateBudgetLine))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/budget/lines/{lineID:[0-9]+}", managers(http.HandlerFunc(h.DeleteBudgetLine))).Methods("DELETE")
+	routes.Handle("/{id:[0-9]+}/disbursements", managers(http.HandlerFunc(h.GetDisbursements))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/disbursements", managers(http.HandlerFunc(h.CreateDisbursement))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/disbursements/{disbursementID:[0-9]+}/review", admins(http.HandlerFunc(h.ReviewDisbursement))).Methods("POST")
+	routes.Handle("/disbursements/pending", admins(http.HandlerFunc(h.GetPendingDisbursements))).Methods("GET")
+	routes.Handle("/templates", managers(http.HandlerFunc(h.GetTemplates))).Methods("GET")
+	routes.Handle("/templates", managers(http.HandlerFunc(h.CreateTemplate))).Methods("POST")
+	routes.Handle("/templates/{templateID:[0-9]+}", managers(http.HandlerFunc(h.GetTemplate))).Methods("GET")
+	routes.Handle("/templates/{templateID:[0-9]+}", managers(http.HandlerFunc(h.UpdateTemplate))).Methods("PUT")
+	routes.Handle("/templates/{templateID:[0-9]+}", managers(http.HandlerFunc(h.DeleteTemplate))).Methods("DELETE")
+	routes.Handle("/templates/{templateID:[0-9]+}/campaigns", managers(http.HandlerFunc(h.CreateFromTemplate))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/template", managers(http.HandlerFunc(h.SaveAsTemplate))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/clone", managers(http.HandlerFunc(h.Clone))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/translations", managers(http.HandlerFunc(h.GetTranslations))).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/translations/{locale:[a-z]{2}}", managers(http.HandlerFunc(h.SaveTranslation))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/translations/{locale:[a-z]{2}}", managers(http.HandlerFunc(h.DeleteTranslation))).Methods("DELETE")
+	routes.Handle("/{id:[0-9]+}/milestones/{milestoneID:[0-9]+}/translations/{locale:[a-z]{2}}", managers(http.HandlerFunc(h.SaveTranslation))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/milestones/{milestoneID:[0-9]+}/translations/{locale:[a-z]{2}}", managers(http.HandlerFunc(h.DeleteTranslation))).Methods("DELETE")
+	routes.Handle("/{id:[0-9]+}/updates/{updateID:[0-9]+}/translations/{locale:[a-z]{2}}", managers(http.HandlerFunc(h.SaveTranslation))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/updates/{updateID:[0-9]+}/translations/{locale:[a-z]{2}}", managers(http.HandlerFunc(h.DeleteTranslation))).Methods("DELETE")
+	routes.HandleFunc("/{id:[0-9]+}/impact", h.GetImpactIndicators).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/impact", managers(http.HandlerFunc(h.CreateImpactIndicator))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/impact/{indicatorID:[0-9]+}", managers(http.HandlerFunc(h.UpdateImpactIndicator))).Methods("PUT")
+	routes.Handle("/{id:[0-9]+}/impact/{indicatorID:[0-9]+}", managers(http.HandlerFunc(h.DeleteImpactIndicator))).Methods("DELETE")
+	routes.HandleFunc("/{id:[0-9]+}/impact/{indicatorID:[0-9]+}/measurements", h.GetImpactMeasurements).Methods("GET")
+	routes.Handle("/{id:[0-9]+}/impact/{indicatorID:[0-9]+}/measurements", managers(http.HandlerFunc(h.RecordImpactMeasurement))).Methods("POST")
+	routes.Handle("/{id:[0-9]+}/impact/{indicatorID:[0-9]+}/measurements/{measurementID:[0-9]+}", managers(http.HandlerFunc(h.DeleteImpactMeasurement))).Methods("DELETE")
+	routes.HandleFunc("/impact/report", h.GetImpactReport).Methods("GET")
+	routes.Handle("/fundraisers/mine", h.auth.Authenticate(http.HandlerFunc(h.GetMyFundraisers))).Methods("GET")
+	routes.HandleFunc("/fundraisers/{slug:[a-z0-9-]+}", h.GetFundraiserBySlug).Methods("GET")
+	routes.HandleFunc("/{slug:[a-z0-9-]+}", h.GetBySlug).Methods("GET")
+}
+
+// decodeMilestone decodes and validates a milestone request body
+func decodeMilestone(r *http.Request) (*dto.MilestoneRequest, error) {
+	var req dto.MilestoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400)
+	}
+
+	v := validator.New()
+	v.Required("title", req.Title)
+	v.MaxLength("title", req.Title, 255)
+	v.Min("target_amount", req.TargetAmount, 1)
+
+	if !v.IsValid() {
+		return nil, v.Error()
+	}
+
+	return &req, nil
+}
+
+// listQuery parses campaign listing filters
+func listQuery(r *http.Request) (*dto.ListCampaignsQuery, error) {
+	q := r.URL.Query()
+	query := &dto.ListCampaignsQuery{
+		Status: domain.CampaignStatus(q.Get("status")),
+		Type:   domain.CampaignType(q.Get("type")),
+		Locale: request.Locale(r),
+	}
+	query.Page, query.PerPage = request.Pagination(r)
+
+	v := validator.New()
+	if query.Status != "" {
+		v.In("status", string(query.Status), campaignStatuses)
+	}
+	if query.Type != "" {
+		v.In("type", string(query.Type), campaignTypes)
+	}
+	if !v.IsValid() {
+		return nil, v.Error()
+	}
+
+	if s := q.Get("nazir_id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, errors.New(errors.ErrCodeBadRequest, "Invalid nazir_id", 400)
+		}
+		query.NazirID = id
+	}
+
+	var err error
+	if query.IsFeatured, err = boolQuery(r, "is_featured"); err != nil {
+		return nil, err
+	}
+	if query.IsUrgent, err = boolQuery(r, "is_urgent"); err != nil {
+		return nil, err
+	}
+
+	return query, nil
+}
+
+// searchQuery parses search terms and filters
+func searchQuery(r *http.Request) (*dto.SearchCampaignsQuery, error) {
+	q := r.URL.Query()
+	query := &dto.SearchCampaignsQuery{
+		Query:    q.Get("q"),
+		Status:   domain.CampaignStatus(q.Get("status")),
+		Type:     domain.CampaignType(q.Get("type")),
+		Location: q.Get("location"),
+		Locale:   request.Locale(r),
+	}
+	query.Page, query.PerPage = request.Pagination(r)
+
+	v := validator.New()
+	v.MaxLength("q", query.Query, 200)
+	v.MaxLength("location", query.Location, 255)
+	if query.Status != "" {
+		v.In("status", string(query.Status), campaignStatuses)
+	}
+	if query.Type != "" {
+		v.In("type", string(query.Type), campaignTypes)
+	}
+	if !v.IsValid() {
+		return nil, v.Error()
+	}
+
+	var err error
+	if query.IsFeatured, err = boolQuery(r, "is_featured"); err != nil {
+		return nil, err
+	}
+	if query.IsUrgent, err = boolQuery(r, "is_urgent"); err != nil {
+		return nil, err
+	}
+	if query.MinProgress, err = progressQuery(r, "min_progress"); err != nil {
+		return nil, err
+	}
+	if query.MaxProgress, err = progressQuery(r, "max_progress"); err != nil {
+		return nil, err
+	}
+	if query.MinProgress != nil && query.MaxProgress != nil && *query.MinProgress > *query.MaxProgress {
+		return nil, errors.New(errors.ErrCodeValidation, "min_progress must not exceed max_progress", 400)
+	}
+
+	return query, nil
+}
+
+// progressQuery parses an optional progress percentage query parameter
+func progressQuery(r *http.Request, name string) (*float64, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return nil, nil
+	}
+
+	p, err := strconv.ParseFloat(s, 64)
+	if err != nil || p < 0 {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid "+name, 400)
+	}
+	return &p, nil
+}
+
+// boolQuery parses an optional boolean query parameter
+func boolQuery(r *http.Request, name string) (*bool, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid "+name, 400)
+	}
+	return &b, nil
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+// maxEvidenceURLs bounds the evidence attached to an impact measurement
+const maxEvidenceURLs = 10
+
+// GetImpactIndicators handles listing the impact indicators of a campaign
+func (h *Handler) GetImpactIndicators(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	indicators, err := h.service.GetImpactIndicators(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, indicators)
+}
+
+// CreateImpactIndicator handles adding an impact indicator
+func (h *Handler) CreateImpactIndicator(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeImpactIndicator(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	indicator, err := h.service.CreateImpactIndicator(r.Context(), middleware.ActorFromContext(r.Context()), id, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, indicator)
+}
+
+// UpdateImpactIndicator handles editing an impact indicator
+func (h *Handler) UpdateImpactIndicator(w http.ResponseWriter, r *http.Request) {
+	id, indicatorID, err := indicatorPath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeImpactIndicator(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	indicator, err := h.service.UpdateImpactIndicator(r.Context(), middleware.ActorFromContext(r.Context()), id, indicatorID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, indicator)
+}
+
+// DeleteImpactIndicator handles deleting an impact indicator
+func (h *Handler) DeleteImpactIndicator(w http.ResponseWriter, r *http.Request) {
+	id, indicatorID, err := indicatorPath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := h.service.DeleteImpactIndicator(r.Context(), middleware.ActorFromContext(r.Context()), id, indicatorID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// GetImpactMeasurements handles listing the measurements of an impact indicator
+func (h *Handler) GetImpactMeasurements(w http.ResponseWriter, r *http.Request) {
+	id, indicatorID, err := indicatorPath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	page, perPage := request.Pagination(r)
+	measurements, total, err := h.service.GetImpactMeasurements(r.Context(), middleware.ActorFromContext(r.Context()), id, indicatorID, page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, measurements, page, perPage, total)
+}
+
+// RecordImpactMeasurement handles recording an impact measurement with evidence
+func (h *Handler) RecordImpactMeasurement(w http.ResponseWriter, r *http.Request) {
+	id, indicatorID, err := indicatorPath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.ImpactMeasurementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	if req.Value < 0 {
+		v.AddError("value", "must not be negative")
+	}
+	v.MaxLength("note", req.Note, 2000)
+	v.Required("measured_at", req.MeasuredAt)
+	if len(req.EvidenceURLs) == 0 {
+		v.AddError("evidence_urls", "is required")
+	}
+	if len(req.EvidenceURLs) > maxEvidenceURLs {
+		v.AddError("evidence_urls", "must not have more than 10 URLs")
+	}
+	for _, evidence := range req.EvidenceURLs {
+		if u, err := url.Parse(evidence); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.AddError("evidence_urls", "must be http or https URLs")
+			break
+		}
+	}
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	measurement, err := h.service.RecordImpactMeasurement(r.Context(), middleware.ActorFromContext(r.Context()), id, indicatorID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, measurement)
+}
+
+// DeleteImpactMeasurement handles deleting a mistaken impact measurement
+func (h *Handler) DeleteImpactMeasurement(w http.ResponseWriter, r *http.Request) {
+	id, indicatorID, err := indicatorPath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	measurementID, err := request.PathID(r, "measurementID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	indicator, err := h.service.DeleteImpactMeasurement(r.Context(), middleware.ActorFromContext(r.Context()), id, indicatorID, measurementID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, indicator)
+}
+
+// GetImpactReport handles the impact report across campaigns and tenants
+func (h *Handler) GetImpactReport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := &dto.ImpactReportQuery{Type: domain.CampaignType(q.Get("type"))}
+
+	v := validator.New()
+	v.In("type", string(query.Type), campaignTypes)
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	if s := q.Get("tenant_id"); s != "" {
+		tenantID, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid tenant_id", 400))
+			return
+		}
+		query.TenantID = &tenantID
+	}
+
+	report, err := h.service.GetImpactReport(r.Context(), middleware.ActorFromContext(r.Context()), query)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, report)
+}
+
+// indicatorPath parses the campaign and indicator IDs of an impact indicator route
+func indicatorPath(r *http.Request) (int64, int64, error) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	indicatorID, err := request.PathID(r, "indicatorID")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return id, indicatorID, nil
+}
+
+// decodeImpactIndicator decodes and validates an impact indicator request body
+func decodeImpactIndicator(r *http.Request) (*dto.ImpactIndicatorRequest, error) {
+	var req dto.ImpactIndicatorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400)
+	}
+
+	v := validator.New()
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, 255)
+	v.Required("unit", req.Unit)
+	v.MaxLength("unit", req.Unit, 50)
+	v.MaxLength("description", req.Description, 2000)
+	if req.TargetValue <= 0 {
+		v.AddError("target_value", "must be greater than 0")
+	}
+	v.Min("position", int64(req.Position), 0)
+
+	if !v.IsValid() {
+		return nil, v.Error()
+	}
+
+	return &req, nil
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+var reviewDecisions = []string{
+	string(domain.CampaignReviewApproved),
+	string(domain.CampaignReviewRejected),
+}
+
+// GetReviewQueue handles the admin queue of campaigns awaiting review
+func (h *Handler) GetReviewQueue(w http.ResponseWriter, r *http.Request) {
+	page, perPage := request.Pagination(r)
+	queue, total, err := h.service.GetReviewQueue(r.Context(), middleware.ActorFromContext(r.Context()), page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, queue, page, perPage, total)
+}
+
+// Review handles approving or rejecting a campaign or a pending change
+func (h *Handler) Review(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("decision", string(req.Decision))
+	v.In("decision", string(req.Decision), reviewDecisions)
+	if req.Decision == domain.CampaignReviewRejected {
+		v.Required("comment", req.Comment)
+	}
+	v.MaxLength("comment", req.Comment, 2000)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	campaign, err := h.service.Review(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, campaign)
+}
+
+// GetReviews handles the review history of a campaign
+func (h *Handler) GetReviews(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	reviews, err := h.service.GetReviews(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, reviews)
+}
+
+// GetVersions handles the version history of an approved campaign
+func (h *Handler) GetVersions(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	page, perPage := request.Pagination(r)
+	versions, total, err := h.service.GetVersions(r.Context(), middleware.ActorFromContext(r.Context()), id, page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, versions, page, perPage, total)
+}
@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+// GetTemplates handles listing the templates available to the caller
+func (h *Handler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	page, perPage := request.Pagination(r)
+	templates, total, err := h.service.GetTemplates(r.Context(), middleware.ActorFromContext(r.Context()), page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, templates, page, perPage, total)
+}
+
+// GetTemplate handles getting a template
+func (h *Handler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := request.PathID(r, "templateID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	template, err := h.service.GetTemplate(r.Context(), middleware.ActorFromContext(r.Context()), templateID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, template)
+}
+
+// CreateTemplate handles creating a template
+func (h *Handler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeTemplate(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	template, err := h.service.CreateTemplate(r.Context(), middleware.ActorFromContext(r.Context()), req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, template)
+}
+
+// UpdateTemplate handles editing a template
+func (h *Handler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := request.PathID(r, "templateID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeTemplate(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	template, err := h.service.UpdateTemplate(r.Context(), middleware.ActorFromContext(r.Context()), templateID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, template)
+}
+
+// DeleteTemplate handles deleting a template
+func (h *Handler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := request.PathID(r, "templateID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := h.service.DeleteTemplate(r.Context(), middleware.ActorFromContext(r.Context()), templateID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// SaveAsTemplate handles saving a campaign as a template
+func (h *Handler) SaveAsTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.SaveTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.MaxLength("name", req.Name, 255)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	template, err := h.service.SaveAsTemplate(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, template)
+}
+
+// CreateFromTemplate handles starting a draft campaign from a template
+func (h *Handler) CreateFromTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := request.PathID(r, "templateID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeClone(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	campaign, err := h.service.CreateFromTemplate(r.Context(), middleware.ActorFromContext(r.Context()), templateID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, campaign)
+}
+
+// Clone handles copying a campaign into a new draft
+func (h *Handler) Clone(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeClone(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	campaign, err := h.service.Clone(r.Context(), middleware.ActorFromContext(r.Context()), id, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, campaign)
+}
+
+// decodeTemplate decodes and validates a template request body
+func decodeTemplate(r *http.Request) (*dto.TemplateRequest, error) {
+	var req dto.TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400)
+	}
+
+	v := validator.New()
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, 255)
+	v.Required("title", req.Title)
+	v.MaxLength("title", req.Title, 255)
+	v.Required("description", req.Description)
+	v.MaxLength("short_desc", req.ShortDesc, 500)
+	v.Required("type", string(req.Type))
+	v.In("type", string(req.Type), campaignTypes)
+	v.Min("goal_amount", req.GoalAmount, 1)
+	v.Min("duration_days", int64(req.DurationDays), 0)
+
+	for i, milestone := range req.Milestones {
+		field := fmt.Sprintf("milestones[%d]", i)
+		v.Required(field+".title", milestone.Title)
+		v.MaxLength(field+".title", milestone.Title, 255)
+		v.Min(field+".target_amount", milestone.TargetAmount, 1)
+	}
+
+	for i, line := range req.BudgetLines {
+		field := fmt.Sprintf("budget_lines[%d]", i)
+		v.Required(field+".category", string(line.Category))
+		v.In(field+".category", string(line.Category), budgetCategories)
+		v.Required(field+".name", line.Name)
+		v.MaxLength(field+".name", line.Name, 255)
+		v.MaxLength(field+".description", line.Description, 2000)
+		v.Min(field+".planned_amount", line.PlannedAmount, 1)
+	}
+
+	if !v.IsValid() {
+		return nil, v.Error()
+	}
+
+	return &req, nil
+}
+
+// decodeClone decodes and validates a request to start a draft campaign
+func decodeClone(r *http.Request) (*dto.CloneCampaignRequest, error) {
+	var req dto.CloneCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400)
+	}
+
+	v := validator.New()
+	v.MaxLength("title", req.Title, 255)
+	v.Required("start_date", req.StartDate)
+	v.Min("goal_amount", req.GoalAmount, 0)
+
+	if !v.IsValid() {
+		return nil, v.Error()
+	}
+
+	return &req, nil
+}
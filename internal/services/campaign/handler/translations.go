@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+	"github.com/gorilla/mux"
+)
+
+// GetTranslations handles the editor view of a campaign's translations
+func (h *Handler) GetTranslations(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	translations, err := h.service.GetTranslations(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, translations)
+}
+
+// SaveTranslation handles translating a campaign, milestone or update
+func (h *Handler) SaveTranslation(w http.ResponseWriter, r *http.Request) {
+	id, entityType, entityID, err := translationPath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.TranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("title", req.Title)
+	v.MaxLength("title", req.Title, 255)
+	v.MaxLength("short_desc", req.ShortDesc, 500)
+	if entityType == domain.TranslationEntityUpdate {
+		v.Required("description", req.Description)
+		v.MaxLength("description", req.Description, 50000)
+	}
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	locale := domain.Locale(mux.Vars(r)["locale"])
+	translation, err := h.service.SaveTranslation(r.Context(), middleware.ActorFromContext(r.Context()), id, entityType, entityID, locale, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, translation)
+}
+
+// DeleteTranslation handles removing a translation
+func (h *Handler) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	id, entityType, entityID, err := translationPath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	locale := domain.Locale(mux.Vars(r)["locale"])
+	if err := h.service.DeleteTranslation(r.Context(), middleware.ActorFromContext(r.Context()), id, entityType, entityID, locale); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// translationPath parses the campaign ID and the translated entity of a
+// translation route: the campaign itself, a milestone or an update
+func translationPath(r *http.Request) (int64, domain.TranslationEntity, int64, error) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		return 0, "", 0, err
+	}
+
+	vars := mux.Vars(r)
+	switch {
+	case vars["milestoneID"] != "":
+		milestoneID, err := request.PathID(r, "milestoneID")
+		return id, domain.TranslationEntityMilestone, milestoneID, err
+	case vars["updateID"] != "":
+		updateID, err := request.PathID(r, "updateID")
+		return id, domain.TranslationEntityUpdate, updateID, err
+	}
+	return id, domain.TranslationEntityCampaign, id, nil
+}
+
+// contentLanguage tells clients and caches which locale the content is in
+func contentLanguage(w http.ResponseWriter, locale domain.Locale) {
+	if locale == "" {
+		locale = domain.DefaultLocale
+	}
+	w.Header().Set("Content-Language", string(locale))
+	w.Header().Add("Vary", "Accept-Language")
+}
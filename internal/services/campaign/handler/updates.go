@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/notify"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+// maxUpdateImages bounds the images attached to a campaign update
+const maxUpdateImages = 10
+
+// GetUpdates handles the public update feed of a campaign
+func (h *Handler) GetUpdates(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	page, perPage := request.Pagination(r)
+	locale := request.Locale(r)
+	updates, total, err := h.service.GetUpdates(r.Context(), middleware.ActorFromContext(r.Context()), id, locale, page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	contentLanguage(w, locale)
+	response.Paginated(w, updates, page, perPage, total)
+}
+
+// GetUpdate handles campaign update retrieval
+func (h *Handler) GetUpdate(w http.ResponseWriter, r *http.Request) {
+	id, updateID, err := updatePath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	locale := request.Locale(r)
+	update, err := h.service.GetUpdate(r.Context(), middleware.ActorFromContext(r.Context()), id, updateID, locale)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	contentLanguage(w, locale)
+	response.Success(w, update)
+}
+
+// CreateUpdate handles campaign update creation
+func (h *Handler) CreateUpdate(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeUpdate(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	update, err := h.service.CreateUpdate(r.Context(), middleware.ActorFromContext(r.Context()), id, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, update)
+}
+
+// UpdateUpdate handles campaign update editing
+func (h *Handler) UpdateUpdate(w http.ResponseWriter, r *http.Request) {
+	id, updateID, err := updatePath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeUpdate(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	update, err := h.service.UpdateUpdate(r.Context(), middleware.ActorFromContext(r.Context()), id, updateID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, update)
+}
+
+// DeleteUpdate handles draft campaign update deletion
+func (h *Handler) DeleteUpdate(w http.ResponseWriter, r *http.Request) {
+	id, updateID, err := updatePath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := h.service.DeleteUpdate(r.Context(), middleware.ActorFromContext(r.Context()), id, updateID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// PublishUpdate handles campaign update publishing
+func (h *Handler) PublishUpdate(w http.ResponseWriter, r *http.Request) {
+	id, updateID, err := updatePath(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	update, err := h.service.PublishUpdate(r.Context(), middleware.ActorFromContext(r.Context()), id, updateID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, update)
+}
+
+// GetFeed handles the combined update feed of the campaigns a donor supports
+func (h *Handler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	page, perPage := request.Pagination(r)
+	locale := request.Locale(r)
+	updates, total, err := h.service.GetFeed(r.Context(), middleware.ActorFromContext(r.Context()), locale, page, perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	contentLanguage(w, locale)
+	response.Paginated(w, updates, page, perPage, total)
+}
+
+// GetSubscription handles retrieval of the caller's campaign subscription
+func (h *Handler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	subscription, err := h.service.GetSubscription(r.Context(), middleware.ActorFromContext(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, subscription)
+}
+
+// UpdateSubscription handles subscribing to or unsubscribing from campaign updates
+func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.SubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	if req.Channel == "" {
+		req.Channel = string(notify.ChannelEmail)
+	}
+
+	v := validator.New()
+	v.In("channel", req.Channel, notify.Channels)
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	subscription, err := h.service.UpdateSubscription(r.Context(), middleware.ActorFromContext(r.Context()), id, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, subscription)
+}
+
+// Unsubscribe handles unsubscribing with the token from a notification link
+func (h *Handler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	var req dto.UnsubscribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("token", req.Token)
+	v.MaxLength("token", req.Token, 64)
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	if err := h.service.Unsubscribe(r.Context(), &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// updatePath parses the campaign and update IDs of an update route
+func updatePath(r *http.Request) (int64, int64, error) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	updateID, err := request.PathID(r, "updateID")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return id, updateID, nil
+}
+
+// decodeUpdate decodes and validates a campaign update request body
+func decodeUpdate(r *http.Request) (*dto.CampaignUpdateRequest, error) {
+	var req dto.CampaignUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400)
+	}
+
+	v := validator.New()
+	v.Required("title", req.Title)
+	v.MaxLength("title", req.Title, 255)
+	v.Required("body", req.Body)
+	v.MaxLength("body", req.Body, 50000)
+	if len(req.Images) > maxUpdateImages {
+		v.AddError("images", "must not have more than 10 images")
+	}
+	for _, image := range req.Images {
+		if u, err := url.Parse(image); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.AddError("images", "must be http or https URLs")
+			break
+		}
+	}
+
+	if !v.IsValid() {
+		return nil, v.Error()
+	}
+
+	return &req, nil
+}
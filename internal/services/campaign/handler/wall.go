@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+)
+
+var leaderboardPeriods = []string{
+	string(domain.LeaderboardPeriodAll),
+	string(domain.LeaderboardPeriodWeek),
+	string(domain.LeaderboardPeriodMonth),
+	string(domain.LeaderboardPeriodYear),
+	string(domain.LeaderboardPeriodCustom),
+}
+
+// GetDonorWall handles the public donor wall of a campaign
+func (h *Handler) GetDonorWall(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	cursor, limit := request.Cursor(r)
+	wall, err := h.service.GetDonorWall(r.Context(), middleware.ActorFromContext(r.Context()), id, cursor, limit)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, wall)
+}
+
+// SetMessageVisibility handles hiding or restoring a donor's message on the wall
+func (h *Handler) SetMessageVisibility(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	donationID, err := request.PathID(r, "donationID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.MessageVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	if err := h.service.SetMessageVisibility(r.Context(), middleware.ActorFromContext(r.Context()), id, donationID, &req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// GetTopDonors handles the top-donor leaderboard of a campaign
+func (h *Handler) GetTopDonors(w http.ResponseWriter, r *http.Request) {
+	id, query, err := leaderboardQuery(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	leaderboard, err := h.service.GetTopDonors(r.Context(), middleware.ActorFromContext(r.Context()), id, query)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, leaderboard)
+}
+
+// GetTopFundraisers handles the top-fundraiser leaderboard of a campaign
+func (h *Handler) GetTopFundraisers(w http.ResponseWriter, r *http.Request) {
+	id, query, err := leaderboardQuery(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	leaderboard, err := h.service.GetTopFundraisers(r.Context(), middleware.ActorFromContext(r.Context()), id, query)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, leaderboard)
+}
+
+// leaderboardQuery parses the campaign ID, period and page of a leaderboard route
+func leaderboardQuery(r *http.Request) (int64, *dto.LeaderboardQuery, error) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		return 0, nil, err
+	}
+
+	q := r.URL.Query()
+	query := &dto.LeaderboardQuery{
+		Period: domain.LeaderboardPeriod(q.Get("period")),
+		From:   q.Get("from"),
+		To:     q.Get("to"),
+	}
+	query.Cursor, query.Limit = request.Cursor(r)
+
+	v := validator.New()
+	if query.Period != "" {
+		v.In("period", string(query.Period), leaderboardPeriods)
+	}
+	if !v.IsValid() {
+		return 0, nil, v.Error()
+	}
+
+	return id, query, nil
+}
@@ -0,0 +1,518 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/ledger/journal"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+const budgetLineColumns = `
+	id, campaign_id, category, name, COALESCE(description, ''), planned_amount, spent_amount, position,
+	created_at, updated_at
+`
+
+const disbursementColumns = `
+	id, campaign_id, budget_line_id, amount, recipient, COALESCE(description, ''), COALESCE(receipt_url, ''),
+	disbursed_at, status, over_budget, requested_by, decided_by, decided_at, COALESCE(rejection_reason, ''),
+	created_at, updated_at
+`
+
+// CreateBudgetLine adds a line to a campaign's budget plan, placed last
+// unless it has a position
+func (r *repository) CreateBudgetLine(ctx context.Context, line *domain.BudgetLine) error {
+	query := `
+		INSERT INTO campaign_budget_lines (campaign_id, category, name, description, planned_amount, position,
+		                                   created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5,
+		        COALESCE(NULLIF($6, 0), (SELECT COALESCE(MAX(position), 0) + 1 FROM campaign_budget_lines WHERE campaign_id = $1)),
+		        $7, $7)
+		RETURNING id, position
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(ctx, query,
+		line.CampaignID, line.Category, line.Name, line.Description, line.PlannedAmount, line.Position, now,
+	).Scan(&line.ID, &line.Position)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create budget line", 500)
+	}
+
+	line.CreatedAt = now
+	line.UpdatedAt = now
+	return nil
+}
+
+// UpdateBudgetLine updates a budget line; its spent amount only changes
+// through disbursements. Unless allowIncrease is set, the planned amount
+// cannot be raised once the campaign is approved or the line has
+// disbursements, so over-budget spending cannot skip admin approval. The
+// line is locked so no disbursement slips in between the check and the
+// update.
+func (r *repository) UpdateBudgetLine(ctx context.Context, line *domain.BudgetLine, allowIncrease bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	current, err := lockBudgetLine(ctx, tx, line.CampaignID, line.ID)
+	if err != nil {
+		return err
+	}
+
+	if !allowIncrease && line.PlannedAmount > current.PlannedAmount {
+		var approved, disbursed bool
+		query := `
+			SELECT approved_at IS NOT NULL,
+			       EXISTS (SELECT 1 FROM campaign_disbursements WHERE budget_line_id = $2)
+			FROM campaigns WHERE id = $1
+		`
+		if err := tx.QueryRowContext(ctx, query, line.CampaignID, line.ID).Scan(&approved, &disbursed); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to check budget line", 500)
+		}
+		if approved || disbursed {
+			return errors.New(errors.ErrCodeForbidden, "Only an admin can raise a planned amount once the campaign is approved or the line has disbursements", 403)
+		}
+	}
+
+	query := `
+		UPDATE campaign_budget_lines
+		SET category = $1, name = $2, description = $3, planned_amount = $4, position = $5, updated_at = $6
+		WHERE id = $7
+	`
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, query,
+		line.Category, line.Name, line.Description, line.PlannedAmount, line.Position, now, line.ID,
+	); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update budget line", 500)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit budget line", 500)
+	}
+
+	line.SpentAmount = current.SpentAmount
+	line.UpdatedAt = now
+	return nil
+}
+
+// DeleteBudgetLine deletes a budget line nothing was disbursed against
+func (r *repository) DeleteBudgetLine(ctx context.Context, campaignID, lineID int64) error {
+	query := `
+		DELETE FROM campaign_budget_lines
+		WHERE id = $1 AND campaign_id = $2
+		  AND NOT EXISTS (SELECT 1 FROM campaign_disbursements WHERE budget_line_id = $1)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, lineID, campaignID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete budget line", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Budget lines with disbursements cannot be deleted", 409)
+	}
+	return nil
+}
+
+// FindBudgetLine finds a budget line of a campaign
+func (r *repository) FindBudgetLine(ctx context.Context, campaignID, lineID int64) (*domain.BudgetLine, error) {
+	query := `SELECT ` + budgetLineColumns + ` FROM campaign_budget_lines WHERE id = $1 AND campaign_id = $2`
+
+	line, err := scanBudgetLine(r.db.QueryRowContext(ctx, query, lineID, campaignID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Budget line not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find budget line", 500)
+	}
+	return line, nil
+}
+
+// GetBudgetLines gets the budget plan of a campaign in position order
+func (r *repository) GetBudgetLines(ctx context.Context, campaignID int64) ([]*domain.BudgetLine, error) {
+	query := `SELECT ` + budgetLineColumns + ` FROM campaign_budget_lines WHERE campaign_id = $1 ORDER BY position, id`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get budget lines", 500)
+	}
+	defer rows.Close()
+
+	lines := make([]*domain.BudgetLine, 0)
+	for rows.Next() {
+		line, err := scanBudgetLine(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan budget line", 500)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// GetPendingDisbursementTotals gets the amount awaiting approval per budget
+// line of a campaign
+func (r *repository) GetPendingDisbursementTotals(ctx context.Context, campaignID int64) (map[int64]int64, error) {
+	query := `
+		SELECT budget_line_id, SUM(amount)
+		FROM campaign_disbursements
+		WHERE campaign_id = $1 AND status = $2
+		GROUP BY budget_line_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID, domain.DisbursementStatusPendingApproval)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get pending disbursements", 500)
+	}
+	defer rows.Close()
+
+	totals := make(map[int64]int64)
+	for rows.Next() {
+		var lineID, amount int64
+		if err := rows.Scan(&lineID, &amount); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan pending disbursements", 500)
+		}
+		totals[lineID] = amount
+	}
+
+	return totals, rows.Err()
+}
+
+// CreateDisbursement records a disbursement against a budget line. One that
+// takes the line over its planned amount, or spends from a line already over
+// it, is held for admin approval unless allowOverBudget is set; held
+// disbursements are not spent and post nothing. While one is held, the line
+// takes no further disbursements. The entries are posted to the ledger with
+// the campaign's available funds as balances.
+func (r *repository) CreateDisbursement(ctx context.Context, disbursement *domain.Disbursement, entries []*domain.Ledger, allowOverBudget bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	available, err := lockFunds(ctx, tx, disbursement.CampaignID)
+	if err != nil {
+		return err
+	}
+
+	line, err := lockBudgetLine(ctx, tx, disbursement.CampaignID, disbursement.BudgetLineID)
+	if err != nil {
+		return err
+	}
+
+	var held bool
+	query := `SELECT EXISTS (SELECT 1 FROM campaign_disbursements WHERE budget_line_id = $1 AND status = $2)`
+	if err := tx.QueryRowContext(ctx, query, line.ID, domain.DisbursementStatusPendingApproval).Scan(&held); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to check budget line", 500)
+	}
+	if held {
+		return errors.New(errors.ErrCodeConflict, "An over-budget disbursement on this budget line is awaiting admin approval", 409)
+	}
+
+	if disbursement.Amount > available {
+		return errors.New(errors.ErrCodeConflict, "Disbursement exceeds the campaign's available funds", 409)
+	}
+
+	now := time.Now()
+	disbursement.OverBudget = line.SpentAmount+disbursement.Amount > line.PlannedAmount
+	disbursement.Status = domain.DisbursementStatusPosted
+	if disbursement.OverBudget {
+		if allowOverBudget {
+			decidedBy := disbursement.RequestedBy
+			disbursement.DecidedBy = &decidedBy
+			disbursement.DecidedAt = &now
+		} else {
+			disbursement.Status = domain.DisbursementStatusPendingApproval
+		}
+	}
+
+	insert := `
+		INSERT INTO campaign_disbursements (campaign_id, budget_line_id, amount, recipient, description, receipt_url,
+		                                    disbursed_at, status, over_budget, requested_by, decided_by, decided_at,
+		                                    created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $13)
+		RETURNING id
+	`
+
+	err = tx.QueryRowContext(ctx, insert,
+		disbursement.CampaignID, disbursement.BudgetLineID, disbursement.Amount, disbursement.Recipient,
+		disbursement.Description, disbursement.ReceiptURL, disbursement.DisbursedAt, disbursement.Status,
+		disbursement.OverBudget, disbursement.RequestedBy, disbursement.DecidedBy, disbursement.DecidedAt, now,
+	).Scan(&disbursement.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.New(errors.ErrCodeConflict, "An over-budget disbursement on this budget line is awaiting admin approval", 409)
+		}
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create disbursement", 500)
+	}
+
+	if disbursement.IsPosted() {
+		if err := postDisbursement(ctx, tx, disbursement, available, entries, now); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit disbursement", 500)
+	}
+
+	disbursement.CreatedAt = now
+	disbursement.UpdatedAt = now
+	return nil
+}
+
+// ApproveDisbursement posts a held over-budget disbursement
+func (r *repository) ApproveDisbursement(ctx context.Context, disbursement *domain.Disbursement, entries []*domain.Ledger) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	available, err := lockFunds(ctx, tx, disbursement.CampaignID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := lockBudgetLine(ctx, tx, disbursement.CampaignID, disbursement.BudgetLineID); err != nil {
+		return err
+	}
+
+	if disbursement.Amount > available {
+		return errors.New(errors.ErrCodeConflict, "Disbursement exceeds the campaign's available funds", 409)
+	}
+
+	now := time.Now()
+	if err := decideDisbursement(ctx, tx, disbursement, domain.DisbursementStatusPosted, now); err != nil {
+		return err
+	}
+
+	if err := postDisbursement(ctx, tx, disbursement, available, entries, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit disbursement approval", 500)
+	}
+	return nil
+}
+
+// RejectDisbursement rejects a held over-budget disbursement, unblocking its line
+func (r *repository) RejectDisbursement(ctx context.Context, disbursement *domain.Disbursement) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	if err := decideDisbursement(ctx, tx, disbursement, domain.DisbursementStatusRejected, time.Now()); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit disbursement rejection", 500)
+	}
+	return nil
+}
+
+// FindDisbursement finds a disbursement of a campaign
+func (r *repository) FindDisbursement(ctx context.Context, campaignID, disbursementID int64) (*domain.Disbursement, error) {
+	query := `SELECT ` + disbursementColumns + ` FROM campaign_disbursements WHERE id = $1 AND campaign_id = $2`
+
+	disbursement, err := scanDisbursement(r.db.QueryRowContext(ctx, query, disbursementID, campaignID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Disbursement not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find disbursement", 500)
+	}
+	return disbursement, nil
+}
+
+// GetDisbursements gets the disbursements of a campaign, newest first,
+// optionally of one status
+func (r *repository) GetDisbursements(ctx context.Context, campaignID int64, status domain.DisbursementStatus, limit, offset int) ([]*domain.Disbursement, int64, error) {
+	where := ` WHERE campaign_id = $1`
+	args := []interface{}{campaignID}
+	if status != "" {
+		where += ` AND status = $2`
+		args = append(args, status)
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM campaign_disbursements`+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count disbursements", 500)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM campaign_disbursements%s ORDER BY disbursed_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		disbursementColumns, where, len(args)+1, len(args)+2)
+
+	disbursements, err := r.queryDisbursements(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return disbursements, total, nil
+}
+
+// GetPendingDisbursements gets over-budget disbursements awaiting approval
+// across campaigns, oldest first
+func (r *repository) GetPendingDisbursements(ctx context.Context, limit, offset int) ([]*domain.Disbursement, int64, error) {
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM campaign_disbursements WHERE status = $1`,
+		domain.DisbursementStatusPendingApproval,
+	).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count disbursements", 500)
+	}
+
+	query := `SELECT ` + disbursementColumns + ` FROM campaign_disbursements WHERE status = $1 ORDER BY created_at, id LIMIT $2 OFFSET $3`
+
+	disbursements, err := r.queryDisbursements(ctx, query, domain.DisbursementStatusPendingApproval, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return disbursements, total, nil
+}
+
+func (r *repository) queryDisbursements(ctx context.Context, query string, args ...interface{}) ([]*domain.Disbursement, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get disbursements", 500)
+	}
+	defer rows.Close()
+
+	disbursements := make([]*domain.Disbursement, 0)
+	for rows.Next() {
+		disbursement, err := scanDisbursement(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan disbursement", 500)
+		}
+		disbursements = append(disbursements, disbursement)
+	}
+
+	return disbursements, rows.Err()
+}
+
+// lockFunds locks a campaign against concurrent donations and disbursements
+// and gets its available funds: the amount raised less posted disbursements
+func lockFunds(ctx context.Context, tx *sql.Tx, campaignID int64) (int64, error) {
+	var raised int64
+	err := tx.QueryRowContext(ctx, `SELECT current_amount FROM campaigns WHERE id = $1 FOR UPDATE`, campaignID).Scan(&raised)
+	if err == sql.ErrNoRows {
+		return 0, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to lock campaign", 500)
+	}
+
+	var spent int64
+	query := `SELECT COALESCE(SUM(amount), 0) FROM campaign_disbursements WHERE campaign_id = $1 AND status = $2`
+	if err := tx.QueryRowContext(ctx, query, campaignID, domain.DisbursementStatusPosted).Scan(&spent); err != nil {
+		return 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get campaign funds", 500)
+	}
+
+	return raised - spent, nil
+}
+
+// lockBudgetLine locks a budget line of a campaign and gets its amounts
+func lockBudgetLine(ctx context.Context, tx *sql.Tx, campaignID, lineID int64) (*domain.BudgetLine, error) {
+	query := `SELECT id, planned_amount, spent_amount FROM campaign_budget_lines WHERE id = $1 AND campaign_id = $2 FOR UPDATE`
+
+	line := &domain.BudgetLine{CampaignID: campaignID}
+	err := tx.QueryRowContext(ctx, query, lineID, campaignID).Scan(&line.ID, &line.PlannedAmount, &line.SpentAmount)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Budget line not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to lock budget line", 500)
+	}
+	return line, nil
+}
+
+// decideDisbursement moves a held disbursement to next, provided it is still held
+func decideDisbursement(ctx context.Context, tx *sql.Tx, disbursement *domain.Disbursement, next domain.DisbursementStatus, now time.Time) error {
+	query := `
+		UPDATE campaign_disbursements
+		SET status = $1, decided_by = $2, decided_at = $3, rejection_reason = $4, updated_at = $3
+		WHERE id = $5 AND status = $6
+	`
+
+	result, err := tx.ExecContext(ctx, query,
+		next, disbursement.DecidedBy, now, disbursement.RejectionReason, disbursement.ID, domain.DisbursementStatusPendingApproval,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to decide disbursement", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Disbursement is no longer awaiting approval", 409)
+	}
+
+	disbursement.Status = next
+	disbursement.DecidedAt = &now
+	disbursement.UpdatedAt = now
+	return nil
+}
+
+// postDisbursement counts a disbursement as spent on its line and posts its
+// ledger entries
+func postDisbursement(ctx context.Context, tx *sql.Tx, disbursement *domain.Disbursement, available int64, entries []*domain.Ledger, now time.Time) error {
+	query := `UPDATE campaign_budget_lines SET spent_amount = spent_amount + $1, updated_at = $2 WHERE id = $3`
+	if _, err := tx.ExecContext(ctx, query, disbursement.Amount, now, disbursement.BudgetLineID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update budget line", 500)
+	}
+
+	for _, entry := range entries {
+		entry.ReferenceID = disbursement.ID
+		entry.BalanceBefore = available
+		entry.BalanceAfter = available - disbursement.Amount
+	}
+	return journal.Append(ctx, tx, entries...)
+}
+
+// scanBudgetLine scans a row selected with budgetLineColumns
+func scanBudgetLine(row rowScanner) (*domain.BudgetLine, error) {
+	line := &domain.BudgetLine{}
+	if err := row.Scan(
+		&line.ID, &line.CampaignID, &line.Category, &line.Name, &line.Description, &line.PlannedAmount,
+		&line.SpentAmount, &line.Position, &line.CreatedAt, &line.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// scanDisbursement scans a row selected with disbursementColumns
+func scanDisbursement(row rowScanner) (*domain.Disbursement, error) {
+	disbursement := &domain.Disbursement{}
+	var decidedBy sql.NullInt64
+	var decidedAt sql.NullTime
+
+	if err := row.Scan(
+		&disbursement.ID, &disbursement.CampaignID, &disbursement.BudgetLineID, &disbursement.Amount,
+		&disbursement.Recipient, &disbursement.Description, &disbursement.ReceiptURL, &disbursement.DisbursedAt,
+		&disbursement.Status, &disbursement.OverBudget, &disbursement.RequestedBy, &decidedBy, &decidedAt,
+		&disbursement.RejectionReason, &disbursement.CreatedAt, &disbursement.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if decidedBy.Valid {
+		disbursement.DecidedBy = &decidedBy.Int64
+	}
+	if decidedAt.Valid {
+		disbursement.DecidedAt = &decidedAt.Time
+	}
+	return disbursement, nil
+}
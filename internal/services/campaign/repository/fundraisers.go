@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/lib/pq"
+)
+
+// fundraiserColumns selects a fundraiser aliased f with its leaderboard rank
+// among the visible pages of its campaign; hidden pages rank 0
+const fundraiserColumns = `
+	f.id, f.campaign_id, f.user_id, f.title, f.slug, f.story, f.goal_amount, f.current_amount, f.donor_count,
+	f.status, COALESCE(f.hidden_reason, ''), f.hidden_by, f.hidden_at, f.created_at, f.updated_at,
+	CASE WHEN f.status = 'active' THEN (
+		SELECT COUNT(*) + 1 FROM campaign_fundraisers o
+		WHERE o.campaign_id = f.campaign_id AND o.status = 'active' AND o.current_amount > f.current_amount
+	) ELSE 0 END
+`
+
+// CreateFundraiser creates a fundraiser page
+func (r *repository) CreateFundraiser(ctx context.Context, fundraiser *domain.Fundraiser) error {
+	query := `
+		INSERT INTO campaign_fundraisers (campaign_id, user_id, title, slug, story, goal_amount, status,
+		                                  created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(ctx, query,
+		fundraiser.CampaignID, fundraiser.UserID, fundraiser.Title, fundraiser.Slug, fundraiser.Story,
+		fundraiser.GoalAmount, fundraiser.Status, now,
+	).Scan(&fundraiser.ID)
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && pqErr.Constraint == "uq_campaign_fundraisers_user" {
+			return errors.New(errors.ErrCodeConflict, "You already have a fundraiser page for this campaign", 409)
+		}
+		if isUniqueViolation(err) {
+			return errors.Wrap(err, errors.ErrCodeDuplicateEntry, "Fundraiser slug already exists", 409)
+		}
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create fundraiser", 500)
+	}
+
+	fundraiser.CreatedAt = now
+	fundraiser.UpdatedAt = now
+	return nil
+}
+
+// UpdateFundraiser updates the title, story and goal of a fundraiser page
+func (r *repository) UpdateFundraiser(ctx context.Context, fundraiser *domain.Fundraiser) error {
+	query := `UPDATE campaign_fundraisers SET title = $1, story = $2, goal_amount = $3, updated_at = $4 WHERE id = $5`
+
+	now := time.Now()
+	if _, err := r.db.ExecContext(ctx, query,
+		fundraiser.Title, fundraiser.Story, fundraiser.GoalAmount, now, fundraiser.ID,
+	); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update fundraiser", 500)
+	}
+
+	fundraiser.UpdatedAt = now
+	return nil
+}
+
+// SetFundraiserStatus hides or shows a fundraiser page
+func (r *repository) SetFundraiserStatus(ctx context.Context, fundraiser *domain.Fundraiser) error {
+	query := `
+		UPDATE campaign_fundraisers
+		SET status = $1, hidden_reason = $2, hidden_by = $3, hidden_at = $4, updated_at = $5
+		WHERE id = $6
+	`
+
+	now := time.Now()
+	if _, err := r.db.ExecContext(ctx, query,
+		fundraiser.Status, fundraiser.HiddenReason, fundraiser.HiddenBy, fundraiser.HiddenAt, now, fundraiser.ID,
+	); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update fundraiser status", 500)
+	}
+
+	fundraiser.UpdatedAt = now
+	return nil
+}
+
+// FindFundraiserByID finds a fundraiser page of a campaign
+func (r *repository) FindFundraiserByID(ctx context.Context, campaignID, fundraiserID int64) (*domain.Fundraiser, error) {
+	query := `SELECT ` + fundraiserColumns + ` FROM campaign_fundraisers f WHERE f.id = $1 AND f.campaign_id = $2`
+	return r.findFundraiser(ctx, query, fundraiserID, campaignID)
+}
+
+// FindFundraiserBySlug finds a fundraiser page by its slug
+func (r *repository) FindFundraiserBySlug(ctx context.Context, slug string) (*domain.Fundraiser, error) {
+	query := `SELECT ` + fundraiserColumns + ` FROM campaign_fundraisers f WHERE f.slug = $1`
+	return r.findFundraiser(ctx, query, slug)
+}
+
+// GetFundraisers gets the leaderboard of a campaign's fundraiser pages,
+// highest total first; hidden pages are listed last when included
+func (r *repository) GetFundraisers(ctx context.Context, campaignID int64, includeHidden bool, limit, offset int) ([]*domain.Fundraiser, int64, error) {
+	where := `WHERE f.campaign_id = $1`
+	if !includeHidden {
+		where += ` AND f.status = 'active'`
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM campaign_fundraisers f `+where, campaignID).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count fundraisers", 500)
+	}
+
+	query := `
+		SELECT ` + fundraiserColumns + `
+		FROM campaign_fundraisers f ` + where + `
+		ORDER BY f.status = 'active' DESC, f.current_amount DESC, f.id
+		LIMIT $2 OFFSET $3
+	`
+
+	fundraisers, err := r.queryFundraisers(ctx, query, campaignID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return fundraisers, total, nil
+}
+
+// GetUserFundraisers gets every fundraiser page of a user, newest first
+func (r *repository) GetUserFundraisers(ctx context.Context, userID int64) ([]*domain.Fundraiser, error) {
+	query := `SELECT ` + fundraiserColumns + ` FROM campaign_fundraisers f WHERE f.user_id = $1 ORDER BY f.created_at DESC, f.id DESC`
+	return r.queryFundraisers(ctx, query, userID)
+}
+
+// GetFundraiserSlugsWithPrefix gets fundraiser slugs equal to prefix or prefix-N
+func (r *repository) GetFundraiserSlugsWithPrefix(ctx context.Context, prefix string) ([]string, error) {
+	query := `SELECT slug FROM campaign_fundraisers WHERE slug = $1 OR slug LIKE $2`
+
+	pattern := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix) + "-%"
+	rows, err := r.db.QueryContext(ctx, query, prefix, pattern)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get fundraiser slugs", 500)
+	}
+	defer rows.Close()
+
+	slugs := make([]string, 0)
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan fundraiser slug", 500)
+		}
+		slugs = append(slugs, slug)
+	}
+
+	return slugs, rows.Err()
+}
+
+func (r *repository) findFundraiser(ctx context.Context, query string, args ...interface{}) (*domain.Fundraiser, error) {
+	fundraiser, err := scanFundraiser(r.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Fundraiser not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find fundraiser", 500)
+	}
+	return fundraiser, nil
+}
+
+func (r *repository) queryFundraisers(ctx context.Context, query string, args ...interface{}) ([]*domain.Fundraiser, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get fundraisers", 500)
+	}
+	defer rows.Close()
+
+	fundraisers := make([]*domain.Fundraiser, 0)
+	for rows.Next() {
+		fundraiser, err := scanFundraiser(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan fundraiser", 500)
+		}
+		fundraisers = append(fundraisers, fundraiser)
+	}
+
+	return fundraisers, rows.Err()
+}
+
+// refreshFundraiserTotals recomputes fundraiser totals from the campaign
+// donations credited to them, for one page (column "id") or every page of a
+// campaign (column "campaign_id")
+func refreshFundraiserTotals(ctx context.Context, tx *sql.Tx, column string, id int64, now time.Time) error {
+	query := `
+		UPDATE campaign_fundraisers f
+		SET current_amount = t.amount, donor_count = t.donors, updated_at = $2
+		FROM (
+			SELECT p.id, COALESCE(SUM(cd.amount), 0) AS amount, COUNT(DISTINCT cd.donor_key) AS donors
+			FROM campaign_fundraisers p
+			LEFT JOIN campaign_donations cd ON cd.fundraiser_id = p.id
+			WHERE p.` + column + ` = $1
+			GROUP BY p.id
+		) t
+		WHERE f.id = t.id
+	`
+
+	if _, err := tx.ExecContext(ctx, query, id, now); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update fundraiser totals", 500)
+	}
+	return nil
+}
+
+// scanFundraiser scans a row selected with fundraiserColumns
+func scanFundraiser(row rowScanner) (*domain.Fundraiser, error) {
+	fundraiser := &domain.Fundraiser{}
+	var hiddenBy sql.NullInt64
+	var hiddenAt sql.NullTime
+
+	if err := row.Scan(
+		&fundraiser.ID, &fundraiser.CampaignID, &fundraiser.UserID, &fundraiser.Title, &fundraiser.Slug,
+		&fundraiser.Story, &fundraiser.GoalAmount, &fundraiser.CurrentAmount, &fundraiser.DonorCount,
+		&fundraiser.Status, &fundraiser.HiddenReason, &hiddenBy, &hiddenAt, &fundraiser.CreatedAt,
+		&fundraiser.UpdatedAt, &fundraiser.Rank,
+	); err != nil {
+		return nil, err
+	}
+
+	if hiddenBy.Valid {
+		fundraiser.HiddenBy = &hiddenBy.Int64
+	}
+	if hiddenAt.Valid {
+		fundraiser.HiddenAt = &hiddenAt.Time
+	}
+	return fundraiser, nil
+}
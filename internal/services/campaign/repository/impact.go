@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/lib/pq"
+)
+
+const impactIndicatorColumns = `
+	id, campaign_id, name, unit, COALESCE(description, ''), target_value, current_value, position, last_measured_at,
+	created_at, updated_at
+`
+
+const impactMeasurementColumns = `
+	id, indicator_id, campaign_id, value, COALESCE(note, ''), evidence_urls, measured_at, recorded_by, created_at
+`
+
+// ImpactReportFilter filters the campaigns counted in the impact report;
+// empty fields match everything
+type ImpactReportFilter struct {
+	Statuses []domain.CampaignStatus
+	Type     domain.CampaignType
+	TenantID *int64
+}
+
+// ImpactTotal sums the indicators sharing a name and unit, across all
+// tenants or within one
+type ImpactTotal struct {
+	TenantID       *int64  `json:"tenant_id,omitempty"`
+	Name           string  `json:"name"`
+	Unit           string  `json:"unit"`
+	TargetValue    float64 `json:"target_value"`
+	CurrentValue   float64 `json:"current_value"`
+	CampaignCount  int     `json:"campaign_count"`
+	IndicatorCount int     `json:"indicator_count"`
+	ByTenant       bool    `json:"-"`
+}
+
+// CreateImpactIndicator adds an impact indicator to a campaign, placed last
+// unless it has a position
+func (r *repository) CreateImpactIndicator(ctx context.Context, indicator *domain.ImpactIndicator) error {
+	query := `
+		INSERT INTO campaign_impact_indicators (campaign_id, name, unit, description, target_value, position,
+		                                        created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5,
+		        COALESCE(NULLIF($6, 0), (SELECT COALESCE(MAX(position), 0) + 1 FROM campaign_impact_indicators WHERE campaign_id = $1)),
+		        $7, $7)
+		RETURNING id, position
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(ctx, query,
+		indicator.CampaignID, indicator.Name, indicator.Unit, indicator.Description, indicator.TargetValue,
+		indicator.Position, now,
+	).Scan(&indicator.ID, &indicator.Position)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create impact indicator", 500)
+	}
+
+	indicator.CreatedAt = now
+	indicator.UpdatedAt = now
+	return nil
+}
+
+// UpdateImpactIndicator updates an impact indicator; its current value only
+// changes through measurements
+func (r *repository) UpdateImpactIndicator(ctx context.Context, indicator *domain.ImpactIndicator) error {
+	query := `
+		UPDATE campaign_impact_indicators
+		SET name = $1, unit = $2, description = $3, target_value = $4, position = $5, updated_at = $6
+		WHERE id = $7
+	`
+
+	now := time.Now()
+	if _, err := r.db.ExecContext(ctx, query,
+		indicator.Name, indicator.Unit, indicator.Description, indicator.TargetValue, indicator.Position, now,
+		indicator.ID,
+	); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update impact indicator", 500)
+	}
+
+	indicator.UpdatedAt = now
+	return nil
+}
+
+// DeleteImpactIndicator deletes an impact indicator that has no measurements
+func (r *repository) DeleteImpactIndicator(ctx context.Context, campaignID, indicatorID int64) error {
+	query := `
+		DELETE FROM campaign_impact_indicators
+		WHERE id = $1 AND campaign_id = $2
+		  AND NOT EXISTS (SELECT 1 FROM campaign_impact_measurements WHERE indicator_id = $1)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, indicatorID, campaignID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete impact indicator", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Impact indicators with measurements cannot be deleted", 409)
+	}
+
+	return nil
+}
+
+// FindImpactIndicator finds an impact indicator of a campaign
+func (r *repository) FindImpactIndicator(ctx context.Context, campaignID, indicatorID int64) (*domain.ImpactIndicator, error) {
+	query := `SELECT ` + impactIndicatorColumns + ` FROM campaign_impact_indicators WHERE id = $1 AND campaign_id = $2`
+
+	indicator, err := scanImpactIndicator(r.db.QueryRowContext(ctx, query, indicatorID, campaignID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Impact indicator not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find impact indicator", 500)
+	}
+	return indicator, nil
+}
+
+// GetImpactIndicators gets the impact indicators of a campaign in display order
+func (r *repository) GetImpactIndicators(ctx context.Context, campaignID int64) ([]*domain.ImpactIndicator, error) {
+	query := `SELECT ` + impactIndicatorColumns + ` FROM campaign_impact_indicators WHERE campaign_id = $1 ORDER BY position, id`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get impact indicators", 500)
+	}
+	defer rows.Close()
+
+	indicators := make([]*domain.ImpactIndicator, 0)
+	for rows.Next() {
+		indicator, err := scanImpactIndicator(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan impact indicator", 500)
+		}
+		indicators = append(indicators, indicator)
+	}
+
+	return indicators, rows.Err()
+}
+
+// CreateImpactMeasurement records a measurement and moves the indicator to
+// its latest reading in the same transaction
+func (r *repository) CreateImpactMeasurement(ctx context.Context, measurement *domain.ImpactMeasurement) (*domain.ImpactIndicator, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO campaign_impact_measurements (indicator_id, campaign_id, value, note, evidence_urls, measured_at,
+		                                          recorded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	now := time.Now()
+	if err := tx.QueryRowContext(ctx, query,
+		measurement.IndicatorID, measurement.CampaignID, measurement.Value, measurement.Note,
+		pq.Array(measurement.EvidenceURLs), measurement.MeasuredAt, measurement.RecordedBy, now,
+	).Scan(&measurement.ID); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to record impact measurement", 500)
+	}
+	measurement.CreatedAt = now
+
+	indicator, err := refreshImpactIndicator(ctx, tx, measurement.IndicatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit impact measurement", 500)
+	}
+	return indicator, nil
+}
+
+// DeleteImpactMeasurement deletes a mistaken measurement; the indicator goes
+// back to the latest remaining reading
+func (r *repository) DeleteImpactMeasurement(ctx context.Context, indicatorID, measurementID int64) (*domain.ImpactIndicator, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`DELETE FROM campaign_impact_measurements WHERE id = $1 AND indicator_id = $2`, measurementID, indicatorID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete impact measurement", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return nil, errors.New(errors.ErrCodeNotFound, "Impact measurement not found", 404)
+	}
+
+	indicator, err := refreshImpactIndicator(ctx, tx, indicatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit impact measurement", 500)
+	}
+	return indicator, nil
+}
+
+// GetImpactMeasurements gets the measurements of an indicator, latest first
+func (r *repository) GetImpactMeasurements(ctx context.Context, indicatorID int64, limit, offset int) ([]*domain.ImpactMeasurement, int64, error) {
+	var total int64
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM campaign_impact_measurements WHERE indicator_id = $1`, indicatorID,
+	).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count impact measurements", 500)
+	}
+
+	query := `
+		SELECT ` + impactMeasurementColumns + `
+		FROM campaign_impact_measurements
+		WHERE indicator_id = $1
+		ORDER BY measured_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, indicatorID, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get impact measurements", 500)
+	}
+	defer rows.Close()
+
+	measurements := make([]*domain.ImpactMeasurement, 0)
+	for rows.Next() {
+		measurement := &domain.ImpactMeasurement{}
+		if err := rows.Scan(
+			&measurement.ID, &measurement.IndicatorID, &measurement.CampaignID, &measurement.Value,
+			&measurement.Note, pq.Array(&measurement.EvidenceURLs), &measurement.MeasuredAt,
+			&measurement.RecordedBy, &measurement.CreatedAt,
+		); err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan impact measurement", 500)
+		}
+		if measurement.EvidenceURLs == nil {
+			measurement.EvidenceURLs = []string{}
+		}
+		measurements = append(measurements, measurement)
+	}
+
+	return measurements, total, rows.Err()
+}
+
+// GetImpactReport sums impact indicators by name and unit over the filtered
+// campaigns, overall and per tenant. Names are matched case-insensitively.
+func (r *repository) GetImpactReport(ctx context.Context, filter *ImpactReportFilter) ([]*ImpactTotal, error) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		args = append(args, pq.Array(statuses))
+		conditions = append(conditions, fmt.Sprintf("c.status = ANY($%d)", len(args)))
+	}
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		conditions = append(conditions, fmt.Sprintf("c.type = $%d", len(args)))
+	}
+	if filter.TenantID != nil {
+		args = append(args, *filter.TenantID)
+		conditions = append(conditions, fmt.Sprintf("c.tenant_id = $%d", len(args)))
+	}
+
+	query := `
+		SELECT GROUPING(c.tenant_id) = 0, c.tenant_id, MIN(i.name), i.unit, SUM(i.target_value),
+		       SUM(i.current_value), COUNT(DISTINCT i.campaign_id), COUNT(*)
+		FROM campaign_impact_indicators i
+		JOIN campaigns c ON c.id = i.campaign_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		GROUP BY GROUPING SETS ((LOWER(i.name), i.unit), (c.tenant_id, LOWER(i.name), i.unit))
+		ORDER BY 1, c.tenant_id NULLS FIRST, LOWER(i.name), i.unit
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get impact report", 500)
+	}
+	defer rows.Close()
+
+	totals := make([]*ImpactTotal, 0)
+	for rows.Next() {
+		total := &ImpactTotal{}
+		var tenantID sql.NullInt64
+		if err := rows.Scan(
+			&total.ByTenant, &tenantID, &total.Name, &total.Unit, &total.TargetValue, &total.CurrentValue,
+			&total.CampaignCount, &total.IndicatorCount,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan impact report", 500)
+		}
+		if tenantID.Valid {
+			total.TenantID = &tenantID.Int64
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
+
+// refreshImpactIndicator sets an indicator to its latest measurement, or
+// back to zero once it has none
+func refreshImpactIndicator(ctx context.Context, tx *sql.Tx, indicatorID int64) (*domain.ImpactIndicator, error) {
+	query := `
+		UPDATE campaign_impact_indicators
+		SET current_value = COALESCE((
+		        SELECT value FROM campaign_impact_measurements
+		        WHERE indicator_id = $1
+		        ORDER BY measured_at DESC, id DESC
+		        LIMIT 1
+		    ), 0),
+		    last_measured_at = (SELECT MAX(measured_at) FROM campaign_impact_measurements WHERE indicator_id = $1),
+		    updated_at = $2
+		WHERE id = $1
+		RETURNING ` + impactIndicatorColumns
+
+	indicator, err := scanImpactIndicator(tx.QueryRowContext(ctx, query, indicatorID, time.Now()))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Impact indicator not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to update impact indicator", 500)
+	}
+	return indicator, nil
+}
+
+// scanImpactIndicator scans a row selected with impactIndicatorColumns
+func scanImpactIndicator(row rowScanner) (*domain.ImpactIndicator, error) {
+	indicator := &domain.ImpactIndicator{}
+	var lastMeasuredAt sql.NullTime
+
+	if err := row.Scan(
+		&indicator.ID, &indicator.CampaignID, &indicator.Name, &indicator.Unit, &indicator.Description,
+		&indicator.TargetValue, &indicator.CurrentValue, &indicator.Position, &lastMeasuredAt,
+		&indicator.CreatedAt, &indicator.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if lastMeasuredAt.Valid {
+		indicator.LastMeasuredAt = &lastMeasuredAt.Time
+	}
+	return indicator, nil
+}
@@ -0,0 +1,913 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/notify"
+	"github.com/lib/pq"
+)
+
+// ListFilter filters campaign listings; empty fields match everything
+type ListFilter struct {
+	Statuses   []domain.CampaignStatus
+	Type       domain.CampaignType
+	NazirID    int64
+	IsFeatured *bool
+	IsUrgent   *bool
+}
+
+// DonationRef is a successful donation counted towards campaign totals
+type DonationRef struct {
+	ID         int64
+	CampaignID int64
+	Amount     int64
+	DonorKey   string
+	UserID     int64
+	DonorEmail string
+}
+
+// TotalsChange describes campaign totals before and after a rebuild
+type TotalsChange struct {
+	CampaignID         int64 `json:"campaign_id"`
+	PreviousAmount     int64 `json:"previous_amount"`
+	CurrentAmount      int64 `json:"current_amount"`
+	PreviousDonorCount int   `json:"previous_donor_count"`
+	DonorCount         int   `json:"donor_count"`
+}
+
+// Repository defines campaign repository interface
+type Repository interface {
+	Create(ctx context.Context, campaign *domain.Campaign) error
+	Update(ctx context.Context, campaign *domain.Campaign, version *domain.CampaignVersion) error
+	UpdateStatus(ctx context.Context, campaign *domain.Campaign, next domain.CampaignStatus) error
+	FindByID(ctx context.Context, id int64) (*domain.Campaign, error)
+	FindBySlug(ctx context.Context, slug string) (*domain.Campaign, error)
+	List(ctx context.Context, filter *ListFilter, limit, offset int) ([]*domain.Campaign, int64, error)
+	Search(ctx context.Context, filter *SearchFilter, limit, offset int) ([]*SearchHit, int64, error)
+	SearchFacets(ctx context.Context, filter *SearchFilter) (*SearchFacets, error)
+	GetSlugsWithPrefix(ctx context.Context, prefix string) ([]string, error)
+	CreateMilestone(ctx context.Context, milestone *domain.Milestone) error
+	UpdateMilestone(ctx context.Context, milestone *domain.Milestone) error
+	DeleteMilestone(ctx context.Context, campaignID, milestoneID int64) error
+	FindMilestoneByID(ctx context.Context, campaignID, milestoneID int64) (*domain.Milestone, error)
+	GetMilestones(ctx context.Context, campaignID int64) ([]*domain.Milestone, error)
+	ApplyDonation(ctx context.Context, donation *DonationRef) (bool, []*domain.Milestone, error)
+	GetUnappliedDonations(ctx context.Context, limit int) ([]*DonationRef, error)
+	RebuildTotals(ctx context.Context, campaignID int64) (*TotalsChange, []*domain.Milestone, error)
+	GetCampaignIDs(ctx context.Context) ([]int64, error)
+	CreateUpdate(ctx context.Context, update *domain.CampaignUpdate) error
+	UpdateUpdate(ctx context.Context, update *domain.CampaignUpdate) error
+	DeleteUpdate(ctx context.Context, campaignID, updateID int64) error
+	FindUpdateByID(ctx context.Context, campaignID, updateID int64) (*domain.CampaignUpdate, error)
+	GetUpdates(ctx context.Context, campaignID int64, includeDrafts bool, limit, offset int) ([]*domain.CampaignUpdate, int64, error)
+	GetDonorFeed(ctx context.Context, donorKey string, limit, offset int) ([]*domain.CampaignUpdate, int64, error)
+	PublishUpdate(ctx context.Context, update *domain.CampaignUpdate, compose ComposeFunc) error
+	FindSubscription(ctx context.Context, campaignID int64, donorKey string) (*domain.CampaignSubscription, error)
+	SaveSubscription(ctx context.Context, subscription *domain.CampaignSubscription) error
+	Unsubscribe(ctx context.Context, token string) error
+	ActivateDue(ctx context.Context, day time.Time) ([]*domain.Campaign, error)
+	CompleteExpired(ctx context.Context, day time.Time) ([]*domain.Campaign, error)
+	GetEndingCampaigns(ctx context.Context, day time.Time, within int) ([]*EndingCampaign, error)
+	QueueNotifications(ctx context.Context, messages ...*notify.Message) error
+	GetReviewQueue(ctx context.Context, limit, offset int) ([]*domain.Campaign, int64, error)
+	ReviewCampaign(ctx context.Context, campaign *domain.Campaign, next domain.CampaignStatus, review *domain.CampaignReview, version *domain.CampaignVersion) error
+	ReviewVersion(ctx context.Context, campaign *domain.Campaign, version *domain.CampaignVersion, review *domain.CampaignReview) error
+	GetReviews(ctx context.Context, campaignID int64) ([]*domain.CampaignReview, error)
+	FindVersion(ctx context.Context, campaignID, versionID int64) (*domain.CampaignVersion, error)
+	FindPendingVersion(ctx context.Context, campaignID int64) (*domain.CampaignVersion, error)
+	GetVersions(ctx context.Context, campaignID int64, limit, offset int) ([]*domain.CampaignVersion, int64, error)
+	CreateFundraiser(ctx context.Context, fundraiser *domain.Fundraiser) error
+	UpdateFundraiser(ctx context.Context, fundraiser *domain.Fundraiser) error
+	SetFundraiserStatus(ctx context.Context, fundraiser *domain.Fundraiser) error
+	FindFundraiserByID(ctx context.Context, campaignID, fundraiserID int64) (*domain.Fundraiser, error)
+	FindFundraiserBySlug(ctx context.Context, slug string) (*domain.Fundraiser, error)
+	GetFundraisers(ctx context.Context, campaignID int64, includeHidden bool, limit, offset int) ([]*domain.Fundraiser, int64, error)
+	GetUserFundraisers(ctx context.Context, userID int64) ([]*domain.Fundraiser, error)
+	GetFundraiserSlugsWithPrefix(ctx context.Context, prefix string) ([]string, error)
+	GetDonorWall(ctx context.Context, campaignID int64, includeHidden bool, after *WallCursor, limit int) ([]*domain.DonorWallEntry, error)
+	SetDonationMessageHidden(ctx context.Context, campaignID, donationID int64, hiddenBy *int64) error
+	GetTopDonors(ctx context.Context, filter *LeaderboardFilter) ([]*domain.LeaderboardEntry, error)
+	GetTopFundraisers(ctx context.Context, filter *LeaderboardFilter) ([]*domain.LeaderboardEntry, error)
+	CreateBudgetLine(ctx context.Context, line *domain.BudgetLine) error
+	UpdateBudgetLine(ctx context.Context, line *domain.BudgetLine, allowIncrease bool) error
+	DeleteBudgetLine(ctx context.Context, campaignID, lineID int64) error
+	FindBudgetLine(ctx context.Context, campaignID, lineID int64) (*domain.BudgetLine, error)
+	GetBudgetLines(ctx context.Context, campaignID int64) ([]*domain.BudgetLine, error)
+	GetPendingDisbursementTotals(ctx context.Context, campaignID int64) (map[int64]int64, error)
+	CreateDisbursement(ctx context.Context, disbursement *domain.Disbursement, entries []*domain.Ledger, allowOverBudget bool) error
+	ApproveDisbursement(ctx context.Context, disbursement *domain.Disbursement, entries []*domain.Ledger) error
+	RejectDisbursement(ctx context.Context, disbursement *domain.Disbursement) error
+	FindDisbursement(ctx context.Context, campaignID, disbursementID int64) (*domain.Disbursement, error)
+	GetDisbursements(ctx context.Context, campaignID int64, status domain.DisbursementStatus, limit, offset int) ([]*domain.Disbursement, int64, error)
+	GetPendingDisbursements(ctx context.Context, limit, offset int) ([]*domain.Disbursement, int64, error)
+	CreateWithPlan(ctx context.Context, campaign *domain.Campaign, milestones []*domain.Milestone, lines []*domain.BudgetLine) error
+	CreateTemplate(ctx context.Context, template *domain.CampaignTemplate) error
+	UpdateTemplate(ctx context.Context, template *domain.CampaignTemplate) error
+	DeleteTemplate(ctx context.Context, id int64) error
+	FindTemplate(ctx context.Context, id int64) (*domain.CampaignTemplate, error)
+	GetTemplates(ctx context.Context, ownerID int64, tenantID *int64, limit, offset int) ([]*domain.CampaignTemplate, int64, error)
+	SaveTranslation(ctx context.Context, translation *domain.Translation) error
+	DeleteTranslation(ctx context.Context, entityType domain.TranslationEntity, entityID int64, locale domain.Locale) error
+	GetTranslations(ctx context.Context, campaignID int64) ([]*domain.Translation, error)
+	GetEntityTranslations(ctx context.Context, entityType domain.TranslationEntity, entityIDs []int64, locales []domain.Locale) ([]*domain.Translation, error)
+	CreateImpactIndicator(ctx context.Context, indicator *domain.ImpactIndicator) error
+	UpdateImpactIndicator(ctx context.Context, indicator *domain.ImpactIndicator) error
+	DeleteImpactIndicator(ctx context.Context, campaignID, indicatorID int64) error
+	FindImpactIndicator(ctx context.Context, campaignID, indicatorID int64) (*domain.ImpactIndicator, error)
+	GetImpactIndicators(ctx context.Context, campaignID int64) ([]*domain.ImpactIndicator, error)
+	CreateImpactMeasurement(ctx context.Context, measurement *domain.ImpactMeasurement) (*domain.ImpactIndicator, error)
+	DeleteImpactMeasurement(ctx context.Context, indicatorID, measurementID int64) (*domain.ImpactIndicator, error)
+	GetImpactMeasurements(ctx context.Context, indicatorID int64, limit, offset int) ([]*domain.ImpactMeasurement, int64, error)
+	GetImpactReport(ctx context.Context, filter *ImpactReportFilter) ([]*ImpactTotal, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// New creates a new campaign repository
+func New(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+const campaignColumns = `
+	id, title, slug, COALESCE(description, ''), COALESCE(short_desc, ''), type, status, goal_amount,
+	current_amount, donor_count, nazir_id, COALESCE(image_url, ''), COALESCE(video_url, ''),
+	COALESCE(location, ''), start_date, end_date, is_endless, is_featured, is_urgent, auto_publish,
+	accept_overflow, overflow_campaign_id, approved_at, approved_by, tenant_id, created_at, updated_at
+`
+
+// Create creates a new campaign
+func (r *repository) Create(ctx context.Context, campaign *domain.Campaign) error {
+	return insertCampaign(ctx, r.db, campaign)
+}
+
+// insertCampaign inserts a campaign on the database or in a transaction
+func insertCampaign(ctx context.Context, q queryRower, campaign *domain.Campaign) error {
+	query := `
+		INSERT INTO campaigns (title, slug, description, short_desc, type, status, goal_amount, current_amount,
+		                       donor_count, nazir_id, image_url, video_url, location, start_date, end_date,
+		                       is_endless, is_featured, is_urgent, auto_publish, accept_overflow,
+		                       overflow_campaign_id, tenant_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 0, 0, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+		        $21, $22)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := q.QueryRowContext(
+		ctx, query,
+		campaign.Title,
+		campaign.Slug,
+		campaign.Description,
+		campaign.ShortDesc,
+		campaign.Type,
+		campaign.Status,
+		campaign.GoalAmount,
+		campaign.NazirID,
+		campaign.ImageURL,
+		campaign.VideoURL,
+		campaign.Location,
+		campaign.StartDate,
+		campaign.EndDate,
+		campaign.IsEndless,
+		campaign.IsFeatured,
+		campaign.IsUrgent,
+		campaign.AutoPublish,
+		campaign.AcceptOverflow,
+		campaign.OverflowCampaignID,
+		campaign.TenantID,
+		now,
+		now,
+	).Scan(&campaign.ID)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.Wrap(err, errors.ErrCodeDuplicateEntry, "Campaign slug already exists", 409)
+		}
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create campaign", 500)
+	}
+
+	campaign.CreatedAt = now
+	campaign.UpdatedAt = now
+	return nil
+}
+
+// Update updates the editable fields of a campaign. A version, when given,
+// is recorded in the same transaction; a pending version replaces the change
+// already awaiting review.
+func (r *repository) Update(ctx context.Context, campaign *domain.Campaign, version *domain.CampaignVersion) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE campaigns
+		SET title = $1, slug = $2, description = $3, short_desc = $4, type = $5, goal_amount = $6,
+		    image_url = $7, video_url = $8, location = $9, start_date = $10, end_date = $11,
+		    is_endless = $12, is_featured = $13, is_urgent = $14, auto_publish = $15, accept_overflow = $16,
+		    overflow_campaign_id = $17, updated_at = $18
+		WHERE id = $19
+	`
+
+	now := time.Now()
+	_, err = tx.ExecContext(
+		ctx, query,
+		campaign.Title,
+		campaign.Slug,
+		campaign.Description,
+		campaign.ShortDesc,
+		campaign.Type,
+		campaign.GoalAmount,
+		campaign.ImageURL,
+		campaign.VideoURL,
+		campaign.Location,
+		campaign.StartDate,
+		campaign.EndDate,
+		campaign.IsEndless,
+		campaign.IsFeatured,
+		campaign.IsUrgent,
+		campaign.AutoPublish,
+		campaign.AcceptOverflow,
+		campaign.OverflowCampaignID,
+		now,
+		campaign.ID,
+	)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.Wrap(err, errors.ErrCodeDuplicateEntry, "Campaign slug already exists", 409)
+		}
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update campaign", 500)
+	}
+
+	if version != nil {
+		if version.IsPending() {
+			supersede := `UPDATE campaign_versions SET status = $1 WHERE campaign_id = $2 AND status = $3`
+			if _, err := tx.ExecContext(ctx, supersede, domain.CampaignVersionSuperseded, campaign.ID, domain.CampaignVersionPending); err != nil {
+				return errors.Wrap(err, errors.ErrCodeInternal, "Failed to supersede campaign version", 500)
+			}
+		}
+
+		if err := insertVersion(ctx, tx, version); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit campaign update", 500)
+	}
+
+	campaign.UpdatedAt = now
+	return nil
+}
+
+// UpdateStatus moves a campaign to next, provided nobody changed its status meanwhile
+func (r *repository) UpdateStatus(ctx context.Context, campaign *domain.Campaign, next domain.CampaignStatus) error {
+	query := `UPDATE campaigns SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query, next, now, campaign.ID, campaign.Status)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update campaign status", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Campaign status was changed by another request", 409)
+	}
+
+	campaign.Status = next
+	campaign.UpdatedAt = now
+	return nil
+}
+
+// FindByID finds campaign by ID
+func (r *repository) FindByID(ctx context.Context, id int64) (*domain.Campaign, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns WHERE id = $1`
+
+	campaign, err := scanCampaign(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find campaign", 500)
+	}
+
+	return campaign, nil
+}
+
+// FindBySlug finds campaign by slug
+func (r *repository) FindBySlug(ctx context.Context, slug string) (*domain.Campaign, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns WHERE slug = $1`
+
+	campaign, err := scanCampaign(r.db.QueryRowContext(ctx, query, slug))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find campaign", 500)
+	}
+
+	return campaign, nil
+}
+
+// List lists campaigns matching filter, featured and newest first
+func (r *repository) List(ctx context.Context, filter *ListFilter, limit, offset int) ([]*domain.Campaign, int64, error) {
+	where, args := filter.where()
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM campaigns` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count campaigns", 500)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM campaigns%s ORDER BY is_featured DESC, created_at DESC LIMIT $%d OFFSET $%d`,
+		campaignColumns, where, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to list campaigns", 500)
+	}
+	defer rows.Close()
+
+	campaigns := make([]*domain.Campaign, 0)
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan campaign", 500)
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	return campaigns, total, rows.Err()
+}
+
+// GetSlugsWithPrefix gets slugs equal to prefix or starting with "prefix-"
+func (r *repository) GetSlugsWithPrefix(ctx context.Context, prefix string) ([]string, error) {
+	query := `SELECT slug FROM campaigns WHERE slug = $1 OR slug LIKE $2`
+
+	pattern := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix) + "-%"
+	rows, err := r.db.QueryContext(ctx, query, prefix, pattern)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get campaign slugs", 500)
+	}
+	defer rows.Close()
+
+	slugs := make([]string, 0)
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan campaign slug", 500)
+		}
+		slugs = append(slugs, slug)
+	}
+
+	return slugs, rows.Err()
+}
+
+// CreateMilestone creates a new campaign milestone
+func (r *repository) CreateMilestone(ctx context.Context, milestone *domain.Milestone) error {
+	query := `
+		INSERT INTO campaign_milestones (campaign_id, title, description, target_amount, is_completed, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, FALSE, $5, $6)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(
+		ctx, query,
+		milestone.CampaignID,
+		milestone.Title,
+		milestone.Description,
+		milestone.TargetAmount,
+		now,
+		now,
+	).Scan(&milestone.ID)
+
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create milestone", 500)
+	}
+
+	milestone.CreatedAt = now
+	milestone.UpdatedAt = now
+	return nil
+}
+
+// UpdateMilestone updates a milestone that has not been completed
+func (r *repository) UpdateMilestone(ctx context.Context, milestone *domain.Milestone) error {
+	query := `
+		UPDATE campaign_milestones
+		SET title = $1, description = $2, target_amount = $3, updated_at = $4
+		WHERE id = $5 AND campaign_id = $6 AND is_completed = FALSE
+	`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query,
+		milestone.Title, milestone.Description, milestone.TargetAmount, now, milestone.ID, milestone.CampaignID,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update milestone", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Completed milestones cannot be changed", 409)
+	}
+
+	milestone.UpdatedAt = now
+	return nil
+}
+
+// DeleteMilestone deletes a milestone that has not been completed
+func (r *repository) DeleteMilestone(ctx context.Context, campaignID, milestoneID int64) error {
+	query := `DELETE FROM campaign_milestones WHERE id = $1 AND campaign_id = $2 AND is_completed = FALSE`
+
+	result, err := r.db.ExecContext(ctx, query, milestoneID, campaignID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete milestone", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Completed milestones cannot be deleted", 409)
+	}
+
+	return r.deleteEntityTranslations(ctx, domain.TranslationEntityMilestone, milestoneID)
+}
+
+// FindMilestoneByID finds a milestone of a campaign
+func (r *repository) FindMilestoneByID(ctx context.Context, campaignID, milestoneID int64) (*domain.Milestone, error) {
+	query := `
+		SELECT id, campaign_id, title, COALESCE(description, ''), target_amount, is_completed, completed_at, created_at, updated_at
+		FROM campaign_milestones
+		WHERE id = $1 AND campaign_id = $2
+	`
+
+	milestone, err := scanMilestone(r.db.QueryRowContext(ctx, query, milestoneID, campaignID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Milestone not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find milestone", 500)
+	}
+
+	return milestone, nil
+}
+
+// GetMilestones gets milestones of a campaign ordered by target amount
+func (r *repository) GetMilestones(ctx context.Context, campaignID int64) ([]*domain.Milestone, error) {
+	query := `
+		SELECT id, campaign_id, title, COALESCE(description, ''), target_amount, is_completed, completed_at, created_at, updated_at
+		FROM campaign_milestones
+		WHERE campaign_id = $1
+		ORDER BY target_amount, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get milestones", 500)
+	}
+	defer rows.Close()
+
+	milestones := make([]*domain.Milestone, 0)
+	for rows.Next() {
+		milestone, err := scanMilestone(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan milestone", 500)
+		}
+		milestones = append(milestones, milestone)
+	}
+
+	return milestones, rows.Err()
+}
+
+// donorKeySQL identifies a donor for DonorCount: the user account, else the
+// donor email, else the donation itself for anonymous guests. Keep in sync with DonorKey.
+const donorKeySQL = `
+	CASE WHEN COALESCE(d.user_id, 0) <> 0 THEN 'user:' || d.user_id
+	     WHEN TRIM(COALESCE(d.donor_email, '')) <> '' THEN 'email:' || LOWER(TRIM(d.donor_email))
+	     ELSE 'donation:' || d.id END
+`
+
+// DonorKey identifies a donor for DonorCount
+func DonorKey(userID int64, email string, donationID int64) string {
+	if userID != 0 {
+		return fmt.Sprintf("user:%d", userID)
+	}
+	if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+		return "email:" + email
+	}
+	return fmt.Sprintf("donation:%d", donationID)
+}
+
+// ApplyDonation adds a successful donation to its campaign totals, completes
+// the milestones it crosses and subscribes the donor to campaign updates, all
+// in one transaction. It reports false when the donation was already applied.
+func (r *repository) ApplyDonation(ctx context.Context, donation *DonationRef) (bool, []*domain.Milestone, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	// Lock the campaign so concurrent donations from one donor count once
+	campaign, err := lockCampaignForDonation(ctx, tx, donation.CampaignID)
+	if err != nil {
+		return false, nil, err
+	}
+
+	// Donations paid after a campaign completed in overflow mode go to its
+	// fallback fund; the donation records the campaign it was made to
+	if campaign.InOverflow() {
+		var applied bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM campaign_donations WHERE donation_id = $1)`, donation.ID).Scan(&applied); err != nil {
+			return false, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to check campaign donation", 500)
+		}
+		if applied {
+			return false, nil, nil
+		}
+
+		move := `UPDATE donations SET overflow_from_campaign_id = campaign_id, campaign_id = $1 WHERE id = $2 AND campaign_id = $3`
+		if _, err := tx.ExecContext(ctx, move, *campaign.OverflowCampaignID, donation.ID, donation.CampaignID); err != nil {
+			return false, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to move overflow donation", 500)
+		}
+
+		donation.CampaignID = *campaign.OverflowCampaignID
+		if err := lockCampaign(ctx, tx, donation.CampaignID); err != nil {
+			return false, nil, err
+		}
+	}
+
+	// The fundraiser page is credited only when it belongs to the campaign,
+	// so a donation moved to the overflow fund leaves the page out
+	insert := `
+		INSERT INTO campaign_donations (donation_id, campaign_id, donor_key, amount, fundraiser_id, applied_at)
+		VALUES ($1, $2, $3, $4, (
+			SELECT f.id FROM donations d
+			JOIN campaign_fundraisers f ON f.id = d.fundraiser_id AND f.campaign_id = $2
+			WHERE d.id = $1
+		), $5)
+		ON CONFLICT (donation_id) DO NOTHING
+		RETURNING fundraiser_id
+	`
+
+	now := time.Now()
+	var fundraiserID sql.NullInt64
+	err = tx.QueryRowContext(ctx, insert, donation.ID, donation.CampaignID, donation.DonorKey, donation.Amount, now).Scan(&fundraiserID)
+	if err == sql.ErrNoRows {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to record campaign donation", 500)
+	}
+
+	newDonorQuery := `
+		SELECT NOT EXISTS (
+			SELECT 1 FROM campaign_donations
+			WHERE campaign_id = $1 AND donor_key = $2 AND donation_id <> $3
+		)
+	`
+
+	var newDonor bool
+	if err := tx.QueryRowContext(ctx, newDonorQuery, donation.CampaignID, donation.DonorKey, donation.ID).Scan(&newDonor); err != nil {
+		return false, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to check campaign donor", 500)
+	}
+
+	donorIncrement := 0
+	if newDonor {
+		donorIncrement = 1
+	}
+
+	update := `
+		UPDATE campaigns
+		SET current_amount = current_amount + $1, donor_count = donor_count + $2, updated_at = $3
+		WHERE id = $4
+		RETURNING current_amount
+	`
+
+	var currentAmount int64
+	if err := tx.QueryRowContext(ctx, update, donation.Amount, donorIncrement, now, donation.CampaignID).Scan(&currentAmount); err != nil {
+		return false, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to update campaign totals", 500)
+	}
+
+	reached, err := completeMilestones(ctx, tx, donation.CampaignID, currentAmount, now)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if fundraiserID.Valid {
+		if err := refreshFundraiserTotals(ctx, tx, "id", fundraiserID.Int64, now); err != nil {
+			return false, nil, err
+		}
+	}
+
+	if err := subscribeDonor(ctx, tx, donation, now); err != nil {
+		return false, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit campaign totals", 500)
+	}
+
+	return true, reached, nil
+}
+
+// GetUnappliedDonations gets successful donations not yet counted in campaign totals
+func (r *repository) GetUnappliedDonations(ctx context.Context, limit int) ([]*DonationRef, error) {
+	query := `
+		SELECT d.id, d.campaign_id, d.amount, ` + donorKeySQL + `, COALESCE(d.user_id, 0), COALESCE(d.donor_email, '')
+		FROM donations d
+		WHERE d.status = $1
+		  AND NOT EXISTS (SELECT 1 FROM campaign_donations cd WHERE cd.donation_id = d.id)
+		ORDER BY d.id
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.PaymentStatusSuccess, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get unapplied donations", 500)
+	}
+	defer rows.Close()
+
+	donations := make([]*DonationRef, 0)
+	for rows.Next() {
+		d := &DonationRef{}
+		if err := rows.Scan(&d.ID, &d.CampaignID, &d.Amount, &d.DonorKey, &d.UserID, &d.DonorEmail); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan donation", 500)
+		}
+		donations = append(donations, d)
+	}
+
+	return donations, rows.Err()
+}
+
+// RebuildTotals recomputes campaign totals from its successful donations,
+// dropping donations that were refunded since they were applied
+func (r *repository) RebuildTotals(ctx context.Context, campaignID int64) (*TotalsChange, []*domain.Milestone, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	if err := lockCampaign(ctx, tx, campaignID); err != nil {
+		return nil, nil, err
+	}
+
+	change := &TotalsChange{CampaignID: campaignID}
+	before := `SELECT current_amount, donor_count FROM campaigns WHERE id = $1`
+	if err := tx.QueryRowContext(ctx, before, campaignID).Scan(&change.PreviousAmount, &change.PreviousDonorCount); err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to read campaign totals", 500)
+	}
+
+	prune := `
+		DELETE FROM campaign_donations cd
+		WHERE cd.campaign_id = $1
+		  AND NOT EXISTS (
+		      SELECT 1 FROM donations d
+		      WHERE d.id = cd.donation_id AND d.campaign_id = $1 AND d.status = $2
+		  )
+	`
+	if _, err := tx.ExecContext(ctx, prune, campaignID, domain.PaymentStatusSuccess); err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to prune campaign donations", 500)
+	}
+
+	now := time.Now()
+	backfill := `
+		INSERT INTO campaign_donations (donation_id, campaign_id, donor_key, amount, fundraiser_id, applied_at)
+		SELECT d.id, d.campaign_id, ` + donorKeySQL + `, d.amount, f.id, $3
+		FROM donations d
+		LEFT JOIN campaign_fundraisers f ON f.id = d.fundraiser_id AND f.campaign_id = d.campaign_id
+		WHERE d.campaign_id = $1 AND d.status = $2
+		ON CONFLICT (donation_id) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, backfill, campaignID, domain.PaymentStatusSuccess, now); err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to backfill campaign donations", 500)
+	}
+
+	update := `
+		UPDATE campaigns c
+		SET current_amount = t.amount, donor_count = t.donors, updated_at = $2
+		FROM (
+			SELECT COALESCE(SUM(amount), 0) AS amount, COUNT(DISTINCT donor_key) AS donors
+			FROM campaign_donations WHERE campaign_id = $1
+		) t
+		WHERE c.id = $1
+		RETURNING c.current_amount, c.donor_count
+	`
+	if err := tx.QueryRowContext(ctx, update, campaignID, now).Scan(&change.CurrentAmount, &change.DonorCount); err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to rebuild campaign totals", 500)
+	}
+
+	reached, err := completeMilestones(ctx, tx, campaignID, change.CurrentAmount, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := refreshFundraiserTotals(ctx, tx, "campaign_id", campaignID, now); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit campaign totals", 500)
+	}
+
+	return change, reached, nil
+}
+
+// GetCampaignIDs gets the IDs of all campaigns
+func (r *repository) GetCampaignIDs(ctx context.Context) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id FROM campaigns ORDER BY id`)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get campaigns", 500)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan campaign", 500)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// lockCampaign locks a campaign row for the rest of the transaction
+func lockCampaign(ctx context.Context, tx *sql.Tx, campaignID int64) error {
+	var id int64
+	err := tx.QueryRowContext(ctx, `SELECT id FROM campaigns WHERE id = $1 FOR UPDATE`, campaignID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to lock campaign", 500)
+	}
+	return nil
+}
+
+// lockCampaignForDonation locks a campaign and reads its overflow settings
+func lockCampaignForDonation(ctx context.Context, tx *sql.Tx, campaignID int64) (*domain.Campaign, error) {
+	query := `SELECT id, status, accept_overflow, overflow_campaign_id FROM campaigns WHERE id = $1 FOR UPDATE`
+
+	campaign := &domain.Campaign{}
+	var overflowCampaignID sql.NullInt64
+	err := tx.QueryRowContext(ctx, query, campaignID).Scan(&campaign.ID, &campaign.Status, &campaign.AcceptOverflow, &overflowCampaignID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to lock campaign", 500)
+	}
+
+	if overflowCampaignID.Valid {
+		campaign.OverflowCampaignID = &overflowCampaignID.Int64
+	}
+	return campaign, nil
+}
+
+// completeMilestones completes open milestones whose target has been reached
+func completeMilestones(ctx context.Context, tx *sql.Tx, campaignID, currentAmount int64, now time.Time) ([]*domain.Milestone, error) {
+	query := `
+		UPDATE campaign_milestones
+		SET is_completed = TRUE, completed_at = $1, updated_at = $1
+		WHERE campaign_id = $2 AND is_completed = FALSE AND target_amount <= $3
+		RETURNING id, campaign_id, title, COALESCE(description, ''), target_amount, is_completed, completed_at, created_at, updated_at
+	`
+
+	rows, err := tx.QueryContext(ctx, query, now, campaignID, currentAmount)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to complete milestones", 500)
+	}
+	defer rows.Close()
+
+	reached := make([]*domain.Milestone, 0)
+	for rows.Next() {
+		milestone, err := scanMilestone(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan milestone", 500)
+		}
+		reached = append(reached, milestone)
+	}
+
+	return reached, rows.Err()
+}
+
+// where builds the WHERE clause and its arguments
+func (f *ListFilter) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, s := range f.Statuses {
+			statuses[i] = string(s)
+		}
+		add("status = ANY($%d)", pq.Array(statuses))
+	}
+	if f.Type != "" {
+		add("type = $%d", f.Type)
+	}
+	if f.NazirID != 0 {
+		add("nazir_id = $%d", f.NazirID)
+	}
+	if f.IsFeatured != nil {
+		add("is_featured = $%d", *f.IsFeatured)
+	}
+	if f.IsUrgent != nil {
+		add("is_urgent = $%d", *f.IsUrgent)
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryRower runs a single-row query on the database or in a transaction
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// scanCampaign scans a row selected with campaignColumns
+func scanCampaign(row rowScanner) (*domain.Campaign, error) {
+	campaign := &domain.Campaign{}
+	var endDate sql.NullTime
+	var approvedAt sql.NullTime
+	var overflowCampaignID, approvedBy, tenantID sql.NullInt64
+
+	if err := row.Scan(
+		&campaign.ID, &campaign.Title, &campaign.Slug, &campaign.Description, &campaign.ShortDesc,
+		&campaign.Type, &campaign.Status, &campaign.GoalAmount, &campaign.CurrentAmount, &campaign.DonorCount,
+		&campaign.NazirID, &campaign.ImageURL, &campaign.VideoURL, &campaign.Location, &campaign.StartDate,
+		&endDate, &campaign.IsEndless, &campaign.IsFeatured, &campaign.IsUrgent, &campaign.AutoPublish,
+		&campaign.AcceptOverflow, &overflowCampaignID, &approvedAt, &approvedBy, &tenantID, &campaign.CreatedAt,
+		&campaign.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if endDate.Valid {
+		campaign.EndDate = &endDate.Time
+	}
+	if overflowCampaignID.Valid {
+		campaign.OverflowCampaignID = &overflowCampaignID.Int64
+	}
+	if approvedAt.Valid {
+		campaign.ApprovedAt = &approvedAt.Time
+	}
+	if approvedBy.Valid {
+		campaign.ApprovedBy = &approvedBy.Int64
+	}
+	if tenantID.Valid {
+		campaign.TenantID = &tenantID.Int64
+	}
+
+	return campaign, nil
+}
+
+// scanMilestone scans a milestone row
+func scanMilestone(row rowScanner) (*domain.Milestone, error) {
+	milestone := &domain.Milestone{}
+	var completedAt sql.NullTime
+
+	if err := row.Scan(
+		&milestone.ID, &milestone.CampaignID, &milestone.Title, &milestone.Description, &milestone.TargetAmount,
+		&milestone.IsCompleted, &completedAt, &milestone.CreatedAt, &milestone.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if completedAt.Valid {
+		milestone.CompletedAt = &completedAt.Time
+	}
+
+	return milestone, nil
+}
+
+// isUniqueViolation checks for a PostgreSQL unique_violation error
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
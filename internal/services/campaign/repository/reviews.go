@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/lib/pq"
+)
+
+const versionColumns = `
+	id, campaign_id, version, snapshot, changed_fields, material, status, edited_by, created_at
+`
+
+const reviewColumns = `
+	id, campaign_id, version_id, decision, land_documents, akad_stated, goal_justified,
+	COALESCE(comment, ''), reviewer_id, created_at
+`
+
+// GetReviewQueue gets campaigns awaiting review, either submitted for their
+// first approval or with a material change pending, oldest first
+func (r *repository) GetReviewQueue(ctx context.Context, limit, offset int) ([]*domain.Campaign, int64, error) {
+	where := `
+		WHERE status = $1
+		   OR EXISTS (SELECT 1 FROM campaign_versions v WHERE v.campaign_id = campaigns.id AND v.status = $2)
+	`
+	args := []interface{}{domain.CampaignStatusPendingReview, domain.CampaignVersionPending}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM campaigns `+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count review queue", 500)
+	}
+
+	query := `SELECT ` + campaignColumns + ` FROM campaigns ` + where + ` ORDER BY updated_at, id LIMIT $3 OFFSET $4`
+	campaigns, err := r.queryCampaigns(ctx, query, "Failed to get review queue", append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return campaigns, total, nil
+}
+
+// ReviewCampaign records the decision on a campaign submitted for review and
+// moves it to next, provided it is still pending review. The approved state
+// is recorded as a version when given.
+func (r *repository) ReviewCampaign(ctx context.Context, campaign *domain.Campaign, next domain.CampaignStatus, review *domain.CampaignReview, version *domain.CampaignVersion) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE campaigns
+		SET status = $1, approved_at = $2, approved_by = $3, auto_publish = $4, updated_at = $5
+		WHERE id = $6 AND status = $7
+	`
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, query,
+		next, campaign.ApprovedAt, campaign.ApprovedBy, campaign.AutoPublish, now, campaign.ID, domain.CampaignStatusPendingReview,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to review campaign", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Campaign is no longer pending review", 409)
+	}
+
+	if version != nil {
+		if err := insertVersion(ctx, tx, version); err != nil {
+			return err
+		}
+	}
+
+	if err := insertReview(ctx, tx, review); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit campaign review", 500)
+	}
+
+	campaign.Status = next
+	campaign.UpdatedAt = now
+	return nil
+}
+
+// ReviewVersion records the decision on a pending change. An approved change
+// applies its material fields to the campaign.
+func (r *repository) ReviewVersion(ctx context.Context, campaign *domain.Campaign, version *domain.CampaignVersion, review *domain.CampaignReview) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	next := domain.CampaignVersionRejected
+	if review.Decision == domain.CampaignReviewApproved {
+		next = domain.CampaignVersionApproved
+	}
+
+	result, err := tx.ExecContext(ctx, `UPDATE campaign_versions SET status = $1 WHERE id = $2 AND status = $3`,
+		next, version.ID, domain.CampaignVersionPending,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to review campaign version", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Campaign change is no longer pending review", 409)
+	}
+
+	now := time.Now()
+	if next == domain.CampaignVersionApproved {
+		query := `
+			UPDATE campaigns
+			SET goal_amount = $1, type = $2, approved_at = $3, approved_by = $4, updated_at = $5
+			WHERE id = $6
+		`
+		if _, err := tx.ExecContext(ctx, query,
+			campaign.GoalAmount, campaign.Type, campaign.ApprovedAt, campaign.ApprovedBy, now, campaign.ID,
+		); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to apply campaign version", 500)
+		}
+	}
+
+	if err := insertReview(ctx, tx, review); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit campaign review", 500)
+	}
+
+	version.Status = next
+	campaign.UpdatedAt = now
+	return nil
+}
+
+// GetReviews gets the review history of a campaign, newest first
+func (r *repository) GetReviews(ctx context.Context, campaignID int64) ([]*domain.CampaignReview, error) {
+	query := `SELECT ` + reviewColumns + ` FROM campaign_reviews WHERE campaign_id = $1 ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get campaign reviews", 500)
+	}
+	defer rows.Close()
+
+	reviews := make([]*domain.CampaignReview, 0)
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan campaign review", 500)
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+// FindVersion finds a version of a campaign
+func (r *repository) FindVersion(ctx context.Context, campaignID, versionID int64) (*domain.CampaignVersion, error) {
+	query := `SELECT ` + versionColumns + ` FROM campaign_versions WHERE id = $1 AND campaign_id = $2`
+	return r.findVersion(ctx, query, versionID, campaignID)
+}
+
+// FindPendingVersion finds the change of a campaign awaiting review
+func (r *repository) FindPendingVersion(ctx context.Context, campaignID int64) (*domain.CampaignVersion, error) {
+	query := `SELECT ` + versionColumns + ` FROM campaign_versions WHERE campaign_id = $1 AND status = $2`
+	return r.findVersion(ctx, query, campaignID, domain.CampaignVersionPending)
+}
+
+// GetVersions gets the version history of a campaign, newest first
+func (r *repository) GetVersions(ctx context.Context, campaignID int64, limit, offset int) ([]*domain.CampaignVersion, int64, error) {
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM campaign_versions WHERE campaign_id = $1`, campaignID).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count campaign versions", 500)
+	}
+
+	query := `SELECT ` + versionColumns + ` FROM campaign_versions WHERE campaign_id = $1 ORDER BY version DESC LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get campaign versions", 500)
+	}
+	defer rows.Close()
+
+	versions := make([]*domain.CampaignVersion, 0)
+	for rows.Next() {
+		version, err := scanVersion(rows)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan campaign version", 500)
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, total, rows.Err()
+}
+
+func (r *repository) findVersion(ctx context.Context, query string, args ...interface{}) (*domain.CampaignVersion, error) {
+	version, err := scanVersion(r.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign version not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find campaign version", 500)
+	}
+	return version, nil
+}
+
+// insertVersion records a campaign version numbered after the latest one.
+// Callers hold the campaign row lock through their update, so numbers do not
+// collide.
+func insertVersion(ctx context.Context, tx *sql.Tx, version *domain.CampaignVersion) error {
+	query := `
+		INSERT INTO campaign_versions (campaign_id, version, snapshot, changed_fields, material, status, edited_by, created_at)
+		VALUES ($1, (SELECT COALESCE(MAX(version), 0) + 1 FROM campaign_versions WHERE campaign_id = $1),
+		        $2, $3, $4, $5, $6, $7)
+		RETURNING id, version
+	`
+
+	now := time.Now()
+	err := tx.QueryRowContext(ctx, query,
+		version.CampaignID, []byte(version.Snapshot), pq.Array(version.ChangedFields), version.Material,
+		version.Status, version.EditedBy, now,
+	).Scan(&version.ID, &version.Version)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to record campaign version", 500)
+	}
+
+	version.CreatedAt = now
+	return nil
+}
+
+// insertReview records a review decision
+func insertReview(ctx context.Context, tx *sql.Tx, review *domain.CampaignReview) error {
+	query := `
+		INSERT INTO campaign_reviews (campaign_id, version_id, decision, land_documents, akad_stated, goal_justified,
+		                              comment, reviewer_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := tx.QueryRowContext(ctx, query,
+		review.CampaignID, review.VersionID, review.Decision, review.LandDocuments, review.AkadStated,
+		review.GoalJustified, review.Comment, review.ReviewerID, now,
+	).Scan(&review.ID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to record campaign review", 500)
+	}
+
+	review.CreatedAt = now
+	return nil
+}
+
+// scanVersion scans a row selected with versionColumns
+func scanVersion(row rowScanner) (*domain.CampaignVersion, error) {
+	version := &domain.CampaignVersion{}
+	var snapshot []byte
+
+	if err := row.Scan(
+		&version.ID, &version.CampaignID, &version.Version, &snapshot, pq.Array(&version.ChangedFields),
+		&version.Material, &version.Status, &version.EditedBy, &version.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	version.Snapshot = snapshot
+	return version, nil
+}
+
+// scanReview scans a row selected with reviewColumns
+func scanReview(row rowScanner) (*domain.CampaignReview, error) {
+	review := &domain.CampaignReview{}
+	var versionID sql.NullInt64
+
+	if err := row.Scan(
+		&review.ID, &review.CampaignID, &versionID, &review.Decision, &review.LandDocuments, &review.AkadStated,
+		&review.GoalJustified, &review.Comment, &review.ReviewerID, &review.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if versionID.Valid {
+		review.VersionID = &versionID.Int64
+	}
+	return review, nil
+}
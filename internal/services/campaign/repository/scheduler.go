@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/notify"
+)
+
+// EndingCampaign is an active campaign close to its end date with the
+// contact details of its nazir
+type EndingCampaign struct {
+	Campaign   *domain.Campaign
+	DaysLeft   int
+	NazirEmail string
+	NazirPhone string
+}
+
+// ActivateDue publishes approved scheduled drafts whose start date has come
+// and whose end date has not passed
+func (r *repository) ActivateDue(ctx context.Context, day time.Time) ([]*domain.Campaign, error) {
+	query := `
+		UPDATE campaigns
+		SET status = $1, updated_at = $2
+		WHERE status = $3 AND auto_publish AND approved_at IS NOT NULL AND start_date <= $4
+		  AND (is_endless OR end_date IS NULL OR end_date >= $4)
+		RETURNING ` + campaignColumns
+
+	return r.queryCampaigns(ctx, query, "Failed to activate scheduled campaigns",
+		domain.CampaignStatusActive, time.Now(), domain.CampaignStatusDraft, day,
+	)
+}
+
+// CompleteExpired completes active and paused campaigns whose end date has passed
+func (r *repository) CompleteExpired(ctx context.Context, day time.Time) ([]*domain.Campaign, error) {
+	query := `
+		UPDATE campaigns
+		SET status = $1, updated_at = $2
+		WHERE status IN ($3, $4) AND NOT is_endless AND end_date < $5
+		RETURNING ` + campaignColumns
+
+	return r.queryCampaigns(ctx, query, "Failed to complete expired campaigns",
+		domain.CampaignStatusCompleted, time.Now(), domain.CampaignStatusActive, domain.CampaignStatusPaused, day,
+	)
+}
+
+// GetEndingCampaigns gets active campaigns ending within the given number of days
+func (r *repository) GetEndingCampaigns(ctx context.Context, day time.Time, within int) ([]*EndingCampaign, error) {
+	query := `
+		SELECT ` + campaignColumns + `, end_date - $2::date,
+		       COALESCE((SELECT u.email FROM users u WHERE u.id = campaigns.nazir_id), ''),
+		       COALESCE((SELECT u.phone FROM users u WHERE u.id = campaigns.nazir_id), '')
+		FROM campaigns
+		WHERE status = $1 AND NOT is_endless AND end_date >= $2 AND end_date <= $2::date + $3::int
+		ORDER BY end_date, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.CampaignStatusActive, day, within)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get ending campaigns", 500)
+	}
+	defer rows.Close()
+
+	ending := make([]*EndingCampaign, 0)
+	for rows.Next() {
+		e := &EndingCampaign{}
+		campaign, err := scanCampaign(extraScanner{rows, []interface{}{&e.DaysLeft, &e.NazirEmail, &e.NazirPhone}})
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan campaign", 500)
+		}
+		e.Campaign = campaign
+		ending = append(ending, e)
+	}
+
+	return ending, rows.Err()
+}
+
+// QueueNotifications queues messages in the notification outbox
+func (r *repository) QueueNotifications(ctx context.Context, messages ...*notify.Message) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	if err := notify.Enqueue(ctx, tx, messages...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to queue notifications", 500)
+	}
+	return nil
+}
+
+// queryCampaigns runs a query returning campaign rows
+func (r *repository) queryCampaigns(ctx context.Context, query, failure string, args ...interface{}) ([]*domain.Campaign, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, failure, 500)
+	}
+	defer rows.Close()
+
+	campaigns := make([]*domain.Campaign, 0)
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan campaign", 500)
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	return campaigns, rows.Err()
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/lib/pq"
+)
+
+// maxLocationFacets bounds the location facet, which is free text
+const maxLocationFacets = 20
+
+// Facet names; each facet skips its own filter when counting
+const (
+	facetType     = "type"
+	facetStatus   = "status"
+	facetLocation = "location"
+	facetUrgent   = "is_urgent"
+)
+
+// SearchFilter filters a campaign search; empty fields match everything
+type SearchFilter struct {
+	Query       string
+	Visible     []domain.CampaignStatus // statuses the caller may see, empty for all
+	Status      domain.CampaignStatus
+	Type        domain.CampaignType
+	Location    string
+	IsFeatured  *bool
+	IsUrgent    *bool
+	MinProgress *float64 // percent of goal
+	MaxProgress *float64
+}
+
+// SearchHit is a campaign matched by a search with its ranking score
+type SearchHit struct {
+	Campaign *domain.Campaign
+	Score    float64
+}
+
+// FacetCount counts search matches sharing a facet value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// SearchFacets counts matches per facet value. Each facet ignores its own
+// filter so clients can offer the alternatives.
+type SearchFacets struct {
+	Type     []FacetCount `json:"type"`
+	Status   []FacetCount `json:"status"`
+	Location []FacetCount `json:"location"`
+	IsUrgent []FacetCount `json:"is_urgent"`
+}
+
+// searchQuery parses search terms with the Indonesian configuration from migration 000007
+const searchQuery = `websearch_to_tsquery('waqfwise_indonesian', waqfwise_indonesian_clean($1))`
+
+// searchScore ranks matches by relevance boosted by urgency and momentum.
+// Relevance is ts_rank_cd normalised to [0, 1). Urgency adds 0.3 for urgent
+// campaigns and up to 0.2 as the end date nears within two weeks. Momentum
+// adds up to 0.5 from donations applied in the last seven days, by count and
+// by share of the goal. Without search terms relevance is 1, so browsing
+// ranks by urgency and momentum alone.
+const searchScore = `
+	(CASE WHEN numnode(q.query) = 0 THEN 1 ELSE ts_rank_cd(c.search_vector, q.query, 32) END) * (
+		1
+		+ CASE WHEN c.is_urgent THEN 0.3 ELSE 0 END
+		+ CASE WHEN c.end_date >= CURRENT_DATE AND c.end_date < CURRENT_DATE + 14
+		       THEN 0.2 * (14 - (c.end_date - CURRENT_DATE)) / 14.0 ELSE 0 END
+		+ LEAST(0.5, 0.1 * ln(1 + COALESCE(m.donations, 0))
+		       + CASE WHEN c.goal_amount > 0 THEN COALESCE(m.amount, 0)::float8 / c.goal_amount ELSE 0 END)
+	)
+`
+
+// recentDonations aggregates donations applied to each campaign in the last week
+const recentDonations = `
+	LEFT JOIN (
+		SELECT campaign_id, COUNT(*) AS donations, SUM(amount) AS amount
+		FROM campaign_donations
+		WHERE applied_at > NOW() - INTERVAL '7 days'
+		GROUP BY campaign_id
+	) m ON m.campaign_id = c.id
+`
+
+// Search finds campaigns matching filter, best score first
+func (r *repository) Search(ctx context.Context, filter *SearchFilter, limit, offset int) ([]*SearchHit, int64, error) {
+	where, args := filter.where("")
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM campaigns c, ` + searchQuery + ` q(query)` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count campaigns", 500)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, %s AS score
+		FROM campaigns c
+		CROSS JOIN %s q(query)
+		%s
+		%s
+		ORDER BY score DESC, c.is_featured DESC, c.created_at DESC
+		LIMIT $%d OFFSET $%d`,
+		campaignColumns, searchScore, searchQuery, recentDonations, where, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to search campaigns", 500)
+	}
+	defer rows.Close()
+
+	hits := make([]*SearchHit, 0)
+	for rows.Next() {
+		hit := &SearchHit{}
+		campaign, err := scanCampaign(extraScanner{rows, []interface{}{&hit.Score}})
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan campaign", 500)
+		}
+		hit.Campaign = campaign
+		hits = append(hits, hit)
+	}
+
+	return hits, total, rows.Err()
+}
+
+// SearchFacets counts campaigns matching filter per type, status, location and urgency
+func (r *repository) SearchFacets(ctx context.Context, filter *SearchFilter) (*SearchFacets, error) {
+	facets := &SearchFacets{}
+
+	var err error
+	if facets.Type, err = r.facet(ctx, filter, facetType, `c.type`, 0); err != nil {
+		return nil, err
+	}
+	if facets.Status, err = r.facet(ctx, filter, facetStatus, `c.status`, 0); err != nil {
+		return nil, err
+	}
+	if facets.Location, err = r.facet(ctx, filter, facetLocation, `NULLIF(c.location, '')`, maxLocationFacets); err != nil {
+		return nil, err
+	}
+	if facets.IsUrgent, err = r.facet(ctx, filter, facetUrgent, `c.is_urgent::text`, 0); err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+// facet counts matches grouped by column, ignoring the facet's own filter
+func (r *repository) facet(ctx context.Context, filter *SearchFilter, name, column string, limit int) ([]FacetCount, error) {
+	where, args := filter.where(name)
+
+	query := `SELECT ` + column + `, COUNT(*) FROM campaigns c, ` + searchQuery + ` q(query)` + where +
+		` GROUP BY 1 HAVING ` + column + ` IS NOT NULL ORDER BY 2 DESC, 1`
+	if limit > 0 {
+		query += ` LIMIT ` + strconv.Itoa(limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count campaign facets", 500)
+	}
+	defer rows.Close()
+
+	counts := make([]FacetCount, 0)
+	for rows.Next() {
+		var count FacetCount
+		if err := rows.Scan(&count.Value, &count.Count); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan campaign facet", 500)
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// where builds the search WHERE clause, leaving out the filter of the skipped
+// facet. The search terms are always $1 so the query can reference them.
+func (f *SearchFilter) where(skip string) (string, []interface{}) {
+	conds := []string{`(numnode(q.query) = 0 OR c.search_vector @@ q.query)`}
+	args := []interface{}{f.Query}
+
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if len(f.Visible) > 0 {
+		statuses := make([]string, len(f.Visible))
+		for i, s := range f.Visible {
+			statuses[i] = string(s)
+		}
+		add("c.status = ANY($%d)", pq.Array(statuses))
+	}
+	if f.Status != "" && skip != facetStatus {
+		add("c.status = $%d", f.Status)
+	}
+	if f.Type != "" && skip != facetType {
+		add("c.type = $%d", f.Type)
+	}
+	if f.Location != "" && skip != facetLocation {
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(f.Location) + "%"
+		add("c.location ILIKE $%d", pattern)
+	}
+	if f.IsFeatured != nil {
+		add("c.is_featured = $%d", *f.IsFeatured)
+	}
+	if f.IsUrgent != nil && skip != facetUrgent {
+		add("c.is_urgent = $%d", *f.IsUrgent)
+	}
+	if f.MinProgress != nil {
+		add("c.goal_amount > 0 AND c.current_amount * 100.0 / c.goal_amount >= $%d", *f.MinProgress)
+	}
+	if f.MaxProgress != nil {
+		add("(c.goal_amount = 0 OR c.current_amount * 100.0 / c.goal_amount <= $%d)", *f.MaxProgress)
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// extraScanner scans columns selected after those a scan helper expects
+type extraScanner struct {
+	row   rowScanner
+	extra []interface{}
+}
+
+func (s extraScanner) Scan(dest ...interface{}) error {
+	return s.row.Scan(append(dest, s.extra...)...)
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+const templateColumns = `
+	id, name, title, description, COALESCE(short_desc, ''), type, goal_amount, COALESCE(image_url, ''),
+	COALESCE(video_url, ''), COALESCE(location, ''), is_endless, COALESCE(duration_days, 0), milestones,
+	budget_lines, owner_id, is_shared, tenant_id, created_at, updated_at
+`
+
+// CreateWithPlan creates a draft campaign together with its milestones and
+// budget lines, as copied from a template or another campaign
+func (r *repository) CreateWithPlan(ctx context.Context, campaign *domain.Campaign, milestones []*domain.Milestone, lines []*domain.BudgetLine) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	if err := insertCampaign(ctx, tx, campaign); err != nil {
+		return err
+	}
+
+	milestoneQuery := `
+		INSERT INTO campaign_milestones (campaign_id, title, description, target_amount, is_completed, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, FALSE, $5, $5)
+		RETURNING id
+	`
+	for _, milestone := range milestones {
+		milestone.CampaignID = campaign.ID
+		if err := tx.QueryRowContext(ctx, milestoneQuery,
+			milestone.CampaignID, milestone.Title, milestone.Description, milestone.TargetAmount, campaign.CreatedAt,
+		).Scan(&milestone.ID); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create milestone", 500)
+		}
+		milestone.CreatedAt = campaign.CreatedAt
+		milestone.UpdatedAt = campaign.CreatedAt
+	}
+
+	lineQuery := `
+		INSERT INTO campaign_budget_lines (campaign_id, category, name, description, planned_amount, position,
+		                                   created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		RETURNING id
+	`
+	for _, line := range lines {
+		line.CampaignID = campaign.ID
+		if err := tx.QueryRowContext(ctx, lineQuery,
+			line.CampaignID, line.Category, line.Name, line.Description, line.PlannedAmount, line.Position, campaign.CreatedAt,
+		).Scan(&line.ID); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create budget line", 500)
+		}
+		line.CreatedAt = campaign.CreatedAt
+		line.UpdatedAt = campaign.CreatedAt
+	}
+
+	if err := tx.Commit(); err != nil {
+		if isUniqueViolation(err) {
+			return errors.Wrap(err, errors.ErrCodeDuplicateEntry, "Campaign slug already exists", 409)
+		}
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit campaign", 500)
+	}
+	return nil
+}
+
+// CreateTemplate creates a campaign template
+func (r *repository) CreateTemplate(ctx context.Context, template *domain.CampaignTemplate) error {
+	milestones, lines, err := marshalTemplatePlan(template)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO campaign_templates (name, title, description, short_desc, type, goal_amount, image_url, video_url,
+		                                location, is_endless, duration_days, milestones, budget_lines, owner_id,
+		                                is_shared, tenant_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, 0), $12, $13, $14, $15, $16, $17, $17)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err = r.db.QueryRowContext(ctx, query,
+		template.Name, template.Title, template.Description, template.ShortDesc, template.Type, template.GoalAmount,
+		template.ImageURL, template.VideoURL, template.Location, template.IsEndless, template.DurationDays,
+		milestones, lines, template.OwnerID, template.IsShared, template.TenantID, now,
+	).Scan(&template.ID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create campaign template", 500)
+	}
+
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	return nil
+}
+
+// UpdateTemplate updates a campaign template; its owner and tenant stay fixed
+func (r *repository) UpdateTemplate(ctx context.Context, template *domain.CampaignTemplate) error {
+	milestones, lines, err := marshalTemplatePlan(template)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE campaign_templates
+		SET name = $1, title = $2, description = $3, short_desc = $4, type = $5, goal_amount = $6, image_url = $7,
+		    video_url = $8, location = $9, is_endless = $10, duration_days = NULLIF($11, 0), milestones = $12,
+		    budget_lines = $13, is_shared = $14, updated_at = $15
+		WHERE id = $16
+	`
+
+	now := time.Now()
+	if _, err := r.db.ExecContext(ctx, query,
+		template.Name, template.Title, template.Description, template.ShortDesc, template.Type, template.GoalAmount,
+		template.ImageURL, template.VideoURL, template.Location, template.IsEndless, template.DurationDays,
+		milestones, lines, template.IsShared, now, template.ID,
+	); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update campaign template", 500)
+	}
+
+	template.UpdatedAt = now
+	return nil
+}
+
+// DeleteTemplate deletes a campaign template; campaigns made from it are kept
+func (r *repository) DeleteTemplate(ctx context.Context, id int64) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM campaign_templates WHERE id = $1`, id); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete campaign template", 500)
+	}
+	return nil
+}
+
+// FindTemplate finds a campaign template by ID
+func (r *repository) FindTemplate(ctx context.Context, id int64) (*domain.CampaignTemplate, error) {
+	query := `SELECT ` + templateColumns + ` FROM campaign_templates WHERE id = $1`
+
+	template, err := scanTemplate(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign template not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find campaign template", 500)
+	}
+	return template, nil
+}
+
+// GetTemplates gets the templates a manager may use, by name: their own and
+// those shared within their tenant. A zero ownerID lists every template.
+func (r *repository) GetTemplates(ctx context.Context, ownerID int64, tenantID *int64, limit, offset int) ([]*domain.CampaignTemplate, int64, error) {
+	where := ""
+	args := []interface{}{}
+	if ownerID != 0 {
+		where = ` WHERE owner_id = $1 OR (is_shared AND tenant_id IS NOT DISTINCT FROM $2)`
+		args = append(args, ownerID, tenantID)
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM campaign_templates`+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count campaign templates", 500)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM campaign_templates%s ORDER BY name, id LIMIT $%d OFFSET $%d`,
+		templateColumns, where, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get campaign templates", 500)
+	}
+	defer rows.Close()
+
+	templates := make([]*domain.CampaignTemplate, 0)
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan campaign template", 500)
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, total, rows.Err()
+}
+
+// marshalTemplatePlan encodes the milestones and budget lines of a template
+func marshalTemplatePlan(template *domain.CampaignTemplate) ([]byte, []byte, error) {
+	milestones, err := json.Marshal(template.Milestones)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to encode template milestones", 500)
+	}
+
+	lines, err := json.Marshal(template.BudgetLines)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to encode template budget lines", 500)
+	}
+
+	return milestones, lines, nil
+}
+
+// scanTemplate scans a row selected with templateColumns
+func scanTemplate(row rowScanner) (*domain.CampaignTemplate, error) {
+	template := &domain.CampaignTemplate{}
+	var milestones, lines []byte
+	var tenantID sql.NullInt64
+
+	if err := row.Scan(
+		&template.ID, &template.Name, &template.Title, &template.Description, &template.ShortDesc, &template.Type,
+		&template.GoalAmount, &template.ImageURL, &template.VideoURL, &template.Location, &template.IsEndless,
+		&template.DurationDays, &milestones, &lines, &template.OwnerID, &template.IsShared, &tenantID,
+		&template.CreatedAt, &template.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(milestones, &template.Milestones); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(lines, &template.BudgetLines); err != nil {
+		return nil, err
+	}
+	if tenantID.Valid {
+		template.TenantID = &tenantID.Int64
+	}
+	return template, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/lib/pq"
+)
+
+const translationColumns = `
+	id, campaign_id, entity_type, entity_id, locale, title, short_desc, description, updated_by, created_at,
+	updated_at
+`
+
+// SaveTranslation creates or replaces the translation of an entity into a locale
+func (r *repository) SaveTranslation(ctx context.Context, translation *domain.Translation) error {
+	query := `
+		INSERT INTO campaign_translations (campaign_id, entity_type, entity_id, locale, title, short_desc, description,
+		                                   updated_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		ON CONFLICT (entity_type, entity_id, locale)
+		DO UPDATE SET title = EXCLUDED.title, short_desc = EXCLUDED.short_desc, description = EXCLUDED.description,
+		              updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(ctx, query,
+		translation.CampaignID, translation.EntityType, translation.EntityID, translation.Locale, translation.Title,
+		translation.ShortDesc, translation.Description, translation.UpdatedBy, now,
+	).Scan(&translation.ID, &translation.CreatedAt)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to save translation", 500)
+	}
+
+	translation.UpdatedAt = now
+	return nil
+}
+
+// DeleteTranslation deletes the translation of an entity into a locale
+func (r *repository) DeleteTranslation(ctx context.Context, entityType domain.TranslationEntity, entityID int64, locale domain.Locale) error {
+	query := `DELETE FROM campaign_translations WHERE entity_type = $1 AND entity_id = $2 AND locale = $3`
+
+	result, err := r.db.ExecContext(ctx, query, entityType, entityID, locale)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete translation", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeNotFound, "Translation not found", 404)
+	}
+
+	return nil
+}
+
+// GetTranslations gets every translation of a campaign and its milestones and updates
+func (r *repository) GetTranslations(ctx context.Context, campaignID int64) ([]*domain.Translation, error) {
+	query := `SELECT ` + translationColumns + ` FROM campaign_translations WHERE campaign_id = $1 ORDER BY entity_type, entity_id, locale`
+
+	return r.queryTranslations(ctx, query, campaignID)
+}
+
+// GetEntityTranslations gets the translations of entities of one type into the given locales
+func (r *repository) GetEntityTranslations(ctx context.Context, entityType domain.TranslationEntity, entityIDs []int64, locales []domain.Locale) ([]*domain.Translation, error) {
+	if len(entityIDs) == 0 || len(locales) == 0 {
+		return nil, nil
+	}
+
+	codes := make([]string, len(locales))
+	for i, locale := range locales {
+		codes[i] = string(locale)
+	}
+
+	query := `
+		SELECT ` + translationColumns + `
+		FROM campaign_translations
+		WHERE entity_type = $1 AND entity_id = ANY($2) AND locale = ANY($3)
+	`
+
+	return r.queryTranslations(ctx, query, entityType, pq.Array(entityIDs), pq.Array(codes))
+}
+
+// deleteEntityTranslations deletes the translations of a deleted milestone or update
+func (r *repository) deleteEntityTranslations(ctx context.Context, entityType domain.TranslationEntity, entityID int64) error {
+	query := `DELETE FROM campaign_translations WHERE entity_type = $1 AND entity_id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, entityType, entityID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete translations", 500)
+	}
+	return nil
+}
+
+// queryTranslations runs a query selecting translationColumns
+func (r *repository) queryTranslations(ctx context.Context, query string, args ...interface{}) ([]*domain.Translation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get translations", 500)
+	}
+	defer rows.Close()
+
+	translations := make([]*domain.Translation, 0)
+	for rows.Next() {
+		translation := &domain.Translation{}
+		if err := rows.Scan(
+			&translation.ID, &translation.CampaignID, &translation.EntityType, &translation.EntityID,
+			&translation.Locale, &translation.Title, &translation.ShortDesc, &translation.Description,
+			&translation.UpdatedBy, &translation.CreatedAt, &translation.UpdatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan translation", 500)
+		}
+		translations = append(translations, translation)
+	}
+
+	return translations, rows.Err()
+}
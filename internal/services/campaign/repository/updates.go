@@ -0,0 +1,429 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/notify"
+	"github.com/lib/pq"
+)
+
+// ComposeFunc builds the notification for one subscriber, or nil to skip it
+type ComposeFunc func(subscription *domain.CampaignSubscription) *notify.Message
+
+const updateColumns = `
+	u.id, u.campaign_id, u.title, u.body, u.images, u.milestone_id, u.disbursement_id, u.status,
+	u.author_id, u.published_at, u.created_at, u.updated_at
+`
+
+const subscriptionColumns = `
+	s.id, s.campaign_id, s.donor_key, s.user_id, COALESCE(NULLIF(s.email, ''), u.email, ''),
+	COALESCE(u.phone, ''), s.channel, s.token, s.unsubscribed_at, s.created_at, s.updated_at
+`
+
+// CreateUpdate creates a campaign update
+func (r *repository) CreateUpdate(ctx context.Context, update *domain.CampaignUpdate) error {
+	query := `
+		INSERT INTO campaign_updates (campaign_id, title, body, images, milestone_id, disbursement_id, status,
+		                              author_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(
+		ctx, query,
+		update.CampaignID,
+		update.Title,
+		update.Body,
+		pq.Array(update.Images),
+		update.MilestoneID,
+		update.DisbursementID,
+		update.Status,
+		update.AuthorID,
+		now,
+		now,
+	).Scan(&update.ID)
+
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create campaign update", 500)
+	}
+
+	update.CreatedAt = now
+	update.UpdatedAt = now
+	return nil
+}
+
+// UpdateUpdate updates the content of a campaign update
+func (r *repository) UpdateUpdate(ctx context.Context, update *domain.CampaignUpdate) error {
+	query := `
+		UPDATE campaign_updates
+		SET title = $1, body = $2, images = $3, milestone_id = $4, disbursement_id = $5, updated_at = $6
+		WHERE id = $7 AND campaign_id = $8
+	`
+
+	now := time.Now()
+	_, err := r.db.ExecContext(ctx, query,
+		update.Title, update.Body, pq.Array(update.Images), update.MilestoneID, update.DisbursementID, now,
+		update.ID, update.CampaignID,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update campaign update", 500)
+	}
+
+	update.UpdatedAt = now
+	return nil
+}
+
+// DeleteUpdate deletes a campaign update that has not been published
+func (r *repository) DeleteUpdate(ctx context.Context, campaignID, updateID int64) error {
+	query := `DELETE FROM campaign_updates WHERE id = $1 AND campaign_id = $2 AND status = $3`
+
+	result, err := r.db.ExecContext(ctx, query, updateID, campaignID, domain.CampaignUpdateStatusDraft)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete campaign update", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Published updates cannot be deleted", 409)
+	}
+
+	return r.deleteEntityTranslations(ctx, domain.TranslationEntityUpdate, updateID)
+}
+
+// FindUpdateByID finds an update of a campaign
+func (r *repository) FindUpdateByID(ctx context.Context, campaignID, updateID int64) (*domain.CampaignUpdate, error) {
+	query := `SELECT ` + updateColumns + ` FROM campaign_updates u WHERE u.id = $1 AND u.campaign_id = $2`
+
+	update, err := scanUpdate(r.db.QueryRowContext(ctx, query, updateID, campaignID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign update not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find campaign update", 500)
+	}
+
+	return update, nil
+}
+
+// GetUpdates gets the updates of a campaign, newest first. Drafts are only
+// included when asked for.
+func (r *repository) GetUpdates(ctx context.Context, campaignID int64, includeDrafts bool, limit, offset int) ([]*domain.CampaignUpdate, int64, error) {
+	where := ` WHERE u.campaign_id = $1`
+	args := []interface{}{campaignID}
+	if !includeDrafts {
+		where += ` AND u.status = $2`
+		args = append(args, domain.CampaignUpdateStatusPublished)
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM campaign_updates u`+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count campaign updates", 500)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM campaign_updates u%s ORDER BY COALESCE(u.published_at, u.created_at) DESC, u.id DESC LIMIT $%d OFFSET $%d`,
+		updateColumns, where, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get campaign updates", 500)
+	}
+	defer rows.Close()
+
+	updates := make([]*domain.CampaignUpdate, 0)
+	for rows.Next() {
+		update, err := scanUpdate(rows)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan campaign update", 500)
+		}
+		updates = append(updates, update)
+	}
+
+	return updates, total, rows.Err()
+}
+
+// GetDonorFeed gets published updates of the campaigns a donor has given to, newest first
+func (r *repository) GetDonorFeed(ctx context.Context, donorKey string, limit, offset int) ([]*domain.CampaignUpdate, int64, error) {
+	where := `
+		WHERE u.status = $1
+		  AND c.status = ANY($2)
+		  AND u.campaign_id IN (SELECT campaign_id FROM campaign_donations WHERE donor_key = $3)
+	`
+
+	statuses := make([]string, len(domain.PublicCampaignStatuses))
+	for i, s := range domain.PublicCampaignStatuses {
+		statuses[i] = string(s)
+	}
+	args := []interface{}{domain.CampaignUpdateStatusPublished, pq.Array(statuses), donorKey}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM campaign_updates u JOIN campaigns c ON c.id = u.campaign_id` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count donor feed", 500)
+	}
+
+	query := `
+		SELECT ` + updateColumns + `, c.title, c.slug
+		FROM campaign_updates u
+		JOIN campaigns c ON c.id = u.campaign_id` + where + `
+		ORDER BY u.published_at DESC, u.id DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get donor feed", 500)
+	}
+	defer rows.Close()
+
+	updates := make([]*domain.CampaignUpdate, 0)
+	for rows.Next() {
+		var title, slug string
+		update, err := scanUpdate(extraScanner{rows, []interface{}{&title, &slug}})
+		if err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan campaign update", 500)
+		}
+		update.CampaignTitle = title
+		update.CampaignSlug = slug
+		updates = append(updates, update)
+	}
+
+	return updates, total, rows.Err()
+}
+
+// PublishUpdate publishes a draft update and queues a notification for each
+// active subscriber in the same transaction
+func (r *repository) PublishUpdate(ctx context.Context, update *domain.CampaignUpdate, compose ComposeFunc) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	publish := `
+		UPDATE campaign_updates
+		SET status = $1, published_at = $2, updated_at = $2
+		WHERE id = $3 AND campaign_id = $4 AND status = $5
+	`
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, publish,
+		domain.CampaignUpdateStatusPublished, now, update.ID, update.CampaignID, domain.CampaignUpdateStatusDraft,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to publish campaign update", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Campaign update is already published", 409)
+	}
+
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM campaign_subscriptions s
+		LEFT JOIN users u ON u.id = s.user_id
+		WHERE s.campaign_id = $1 AND s.unsubscribed_at IS NULL
+		ORDER BY s.id
+	`
+
+	subscriptions, err := querySubscriptions(ctx, tx, query, update.CampaignID)
+	if err != nil {
+		return err
+	}
+
+	messages := make([]*notify.Message, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if msg := compose(subscription); msg != nil {
+			messages = append(messages, msg)
+		}
+	}
+
+	if err := notify.Enqueue(ctx, tx, messages...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit campaign update", 500)
+	}
+
+	update.Status = domain.CampaignUpdateStatusPublished
+	update.PublishedAt = &now
+	update.UpdatedAt = now
+	return nil
+}
+
+// FindSubscription finds a donor's subscription to a campaign
+func (r *repository) FindSubscription(ctx context.Context, campaignID int64, donorKey string) (*domain.CampaignSubscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM campaign_subscriptions s
+		LEFT JOIN users u ON u.id = s.user_id
+		WHERE s.campaign_id = $1 AND s.donor_key = $2
+	`
+
+	subscription, err := scanSubscription(r.db.QueryRowContext(ctx, query, campaignID, donorKey))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Subscription not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find subscription", 500)
+	}
+
+	return subscription, nil
+}
+
+// SaveSubscription creates or updates a donor's subscription to a campaign
+func (r *repository) SaveSubscription(ctx context.Context, subscription *domain.CampaignSubscription) error {
+	query := `
+		INSERT INTO campaign_subscriptions (campaign_id, donor_key, user_id, email, channel, unsubscribed_at,
+		                                    created_at, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $7)
+		ON CONFLICT (campaign_id, donor_key)
+		DO UPDATE SET channel = EXCLUDED.channel, unsubscribed_at = EXCLUDED.unsubscribed_at, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(
+		ctx, query,
+		subscription.CampaignID,
+		subscription.DonorKey,
+		subscription.UserID,
+		subscription.Email,
+		subscription.Channel,
+		subscription.UnsubscribedAt,
+		now,
+	).Scan(&subscription.ID, &subscription.CreatedAt)
+
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to save subscription", 500)
+	}
+
+	subscription.UpdatedAt = now
+	return nil
+}
+
+// Unsubscribe ends the subscription with the given unsubscribe token
+func (r *repository) Unsubscribe(ctx context.Context, token string) error {
+	query := `
+		UPDATE campaign_subscriptions
+		SET unsubscribed_at = COALESCE(unsubscribed_at, $1), updated_at = $1
+		WHERE token = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), token)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to unsubscribe", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeNotFound, "Subscription not found", 404)
+	}
+
+	return nil
+}
+
+// subscribeDonor subscribes a donor to campaign updates unless they already
+// have a subscription, e.g. one they cancelled. Anonymous guests without an
+// email cannot be reached and are skipped.
+func subscribeDonor(ctx context.Context, tx *sql.Tx, donation *DonationRef, now time.Time) error {
+	if strings.HasPrefix(donation.DonorKey, "donation:") {
+		return nil
+	}
+
+	query := `
+		INSERT INTO campaign_subscriptions (campaign_id, donor_key, user_id, email, channel, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, ''), $5, $6, $6)
+		ON CONFLICT (campaign_id, donor_key) DO NOTHING
+	`
+
+	_, err := tx.ExecContext(ctx, query,
+		donation.CampaignID, donation.DonorKey, donation.UserID, strings.TrimSpace(donation.DonorEmail),
+		notify.ChannelEmail, now,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to subscribe donor", 500)
+	}
+	return nil
+}
+
+// querySubscriptions runs a query selecting subscriptionColumns
+func querySubscriptions(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]*domain.CampaignSubscription, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get subscriptions", 500)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*domain.CampaignSubscription, 0)
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan subscription", 500)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// scanUpdate scans a row selected with updateColumns
+func scanUpdate(row rowScanner) (*domain.CampaignUpdate, error) {
+	update := &domain.CampaignUpdate{}
+	var milestoneID, disbursementID sql.NullInt64
+	var publishedAt sql.NullTime
+
+	if err := row.Scan(
+		&update.ID, &update.CampaignID, &update.Title, &update.Body, pq.Array(&update.Images), &milestoneID,
+		&disbursementID, &update.Status, &update.AuthorID, &publishedAt, &update.CreatedAt, &update.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if milestoneID.Valid {
+		update.MilestoneID = &milestoneID.Int64
+	}
+	if disbursementID.Valid {
+		update.DisbursementID = &disbursementID.Int64
+	}
+	if publishedAt.Valid {
+		update.PublishedAt = &publishedAt.Time
+	}
+	if update.Images == nil {
+		update.Images = []string{}
+	}
+
+	return update, nil
+}
+
+// scanSubscription scans a row selected with subscriptionColumns
+func scanSubscription(row rowScanner) (*domain.CampaignSubscription, error) {
+	subscription := &domain.CampaignSubscription{}
+	var userID sql.NullInt64
+	var unsubscribedAt sql.NullTime
+
+	if err := row.Scan(
+		&subscription.ID, &subscription.CampaignID, &subscription.DonorKey, &userID, &subscription.Email,
+		&subscription.Phone, &subscription.Channel, &subscription.Token, &unsubscribedAt,
+		&subscription.CreatedAt, &subscription.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if userID.Valid {
+		subscription.UserID = &userID.Int64
+	}
+	if unsubscribedAt.Valid {
+		subscription.UnsubscribedAt = &unsubscribedAt.Time
+	}
+
+	return subscription, nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// WallCursor marks the last donor wall entry of a page
+type WallCursor struct {
+	DonatedAt  time.Time
+	DonationID int64
+}
+
+// LeaderboardCursor marks the last leaderboard entry of a page
+type LeaderboardCursor struct {
+	Amount int64
+	Key    string
+}
+
+// LeaderboardFilter selects the donations a leaderboard ranks; nil bounds are open
+type LeaderboardFilter struct {
+	CampaignID int64
+	From       *time.Time // inclusive
+	To         *time.Time // exclusive
+	After      *LeaderboardCursor
+	Limit      int
+}
+
+// GetDonorWall gets the donations counted towards a campaign, newest first.
+// Names of anonymous donors are never selected. Hidden messages are dropped
+// unless includeHidden is set.
+func (r *repository) GetDonorWall(ctx context.Context, campaignID int64, includeHidden bool, after *WallCursor, limit int) ([]*domain.DonorWallEntry, error) {
+	where := `WHERE cd.campaign_id = $1`
+	args := []interface{}{campaignID}
+	if after != nil {
+		where += ` AND (cd.applied_at, cd.donation_id) < ($2, $3)`
+		args = append(args, after.DonatedAt, after.DonationID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT cd.donation_id, CASE WHEN d.is_anonymous THEN '' ELSE COALESCE(d.donor_name, '') END, d.is_anonymous,
+		       cd.amount, COALESCE(d.message, ''), cd.message_hidden_at IS NOT NULL, cd.fundraiser_id, cd.applied_at
+		FROM campaign_donations cd
+		JOIN donations d ON d.id = cd.donation_id
+		%s
+		ORDER BY cd.applied_at DESC, cd.donation_id DESC
+		LIMIT $%d
+	`, where, len(args)+1)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get donor wall", 500)
+	}
+	defer rows.Close()
+
+	entries := make([]*domain.DonorWallEntry, 0)
+	for rows.Next() {
+		entry := &domain.DonorWallEntry{}
+		var fundraiserID sql.NullInt64
+
+		if err := rows.Scan(
+			&entry.DonationID, &entry.DonorName, &entry.IsAnonymous, &entry.Amount, &entry.Message,
+			&entry.MessageHidden, &fundraiserID, &entry.DonatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan donor wall entry", 500)
+		}
+
+		if fundraiserID.Valid {
+			entry.FundraiserID = &fundraiserID.Int64
+		}
+		if entry.MessageHidden && !includeHidden {
+			entry.Message = ""
+			entry.MessageHidden = false
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// SetDonationMessageHidden hides a donor's message from a campaign's wall, or
+// shows it again when hiddenBy is nil
+func (r *repository) SetDonationMessageHidden(ctx context.Context, campaignID, donationID int64, hiddenBy *int64) error {
+	var hiddenAt *time.Time
+	if hiddenBy != nil {
+		now := time.Now()
+		hiddenAt = &now
+	}
+
+	query := `UPDATE campaign_donations SET message_hidden_by = $1, message_hidden_at = $2 WHERE campaign_id = $3 AND donation_id = $4`
+
+	result, err := r.db.ExecContext(ctx, query, hiddenBy, hiddenAt, campaignID, donationID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to update donation message", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeNotFound, "Donation not found", 404)
+	}
+	return nil
+}
+
+// GetTopDonors ranks donors by the amount they gave. Donations of a donor
+// group by donor key, except anonymous ones, which rank on their own so that
+// neither the donor nor their total is revealed. Keys are hashed as they end
+// up in cursors.
+func (r *repository) GetTopDonors(ctx context.Context, filter *LeaderboardFilter) ([]*domain.LeaderboardEntry, error) {
+	totals := `
+		SELECT md5(CASE WHEN d.is_anonymous THEN 'donation:' || d.id ELSE cd.donor_key END) AS key,
+		       CASE WHEN BOOL_OR(d.is_anonymous) THEN ''
+		            ELSE COALESCE((ARRAY_AGG(d.donor_name ORDER BY cd.applied_at DESC)
+		                           FILTER (WHERE COALESCE(d.donor_name, '') <> ''))[1], '') END AS name,
+		       BOOL_OR(d.is_anonymous) AS anonymous, NULL::BIGINT AS fundraiser_id, '' AS slug,
+		       SUM(cd.amount) AS amount, COUNT(*) AS count
+		FROM campaign_donations cd
+		JOIN donations d ON d.id = cd.donation_id
+		WHERE cd.campaign_id = $1
+		  AND ($2::timestamptz IS NULL OR cd.applied_at >= $2)
+		  AND ($3::timestamptz IS NULL OR cd.applied_at < $3)
+		GROUP BY 1
+	`
+	return r.queryLeaderboard(ctx, totals, "Failed to get top donors", filter)
+}
+
+// GetTopFundraisers ranks the visible fundraiser pages of a campaign by the
+// amount raised through them
+func (r *repository) GetTopFundraisers(ctx context.Context, filter *LeaderboardFilter) ([]*domain.LeaderboardEntry, error) {
+	totals := `
+		SELECT f.id::text AS key, f.title AS name, FALSE AS anonymous, f.id AS fundraiser_id, f.slug,
+		       SUM(cd.amount) AS amount, COUNT(DISTINCT cd.donor_key) AS count
+		FROM campaign_fundraisers f
+		JOIN campaign_donations cd ON cd.fundraiser_id = f.id
+		WHERE f.campaign_id = $1 AND f.status = 'active'
+		  AND ($2::timestamptz IS NULL OR cd.applied_at >= $2)
+		  AND ($3::timestamptz IS NULL OR cd.applied_at < $3)
+		GROUP BY f.id
+	`
+	return r.queryLeaderboard(ctx, totals, "Failed to get top fundraisers", filter)
+}
+
+// queryLeaderboard ranks the totals subquery, highest amount first, and reads
+// the page after the cursor. Ties share a rank and page in key order.
+func (r *repository) queryLeaderboard(ctx context.Context, totals, failureMsg string, filter *LeaderboardFilter) ([]*domain.LeaderboardEntry, error) {
+	args := []interface{}{filter.CampaignID, filter.From, filter.To}
+
+	where := ""
+	if filter.After != nil {
+		where = `WHERE amount < $4 OR (amount = $4 AND key > $5)`
+		args = append(args, filter.After.Amount, filter.After.Key)
+	}
+
+	query := fmt.Sprintf(`
+		WITH totals AS (%s),
+		ranked AS (SELECT *, RANK() OVER (ORDER BY amount DESC) AS rank FROM totals)
+		SELECT rank, key, name, anonymous, fundraiser_id, slug, amount, count
+		FROM ranked %s
+		ORDER BY amount DESC, key
+		LIMIT $%d
+	`, totals, where, len(args)+1)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, filter.Limit)...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, failureMsg, 500)
+	}
+	defer rows.Close()
+
+	entries := make([]*domain.LeaderboardEntry, 0)
+	for rows.Next() {
+		entry := &domain.LeaderboardEntry{}
+		var fundraiserID sql.NullInt64
+
+		if err := rows.Scan(
+			&entry.Rank, &entry.Key, &entry.Name, &entry.IsAnonymous, &fundraiserID, &entry.FundraiserSlug,
+			&entry.Amount, &entry.Count,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan leaderboard entry", 500)
+		}
+
+		if fundraiserID.Valid {
+			entry.FundraiserID = &fundraiserID.Int64
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
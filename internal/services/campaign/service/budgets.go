@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// Ledger accounts a disbursement moves money between
+const (
+	accountCampaignFund = "campaign_fund"
+	accountCash         = "cash_account"
+)
+
+// GetBudget gets the spend-versus-budget view of a visible campaign
+func (s *service) GetBudget(ctx context.Context, actor *domain.Actor, campaignID int64) (*dto.BudgetReport, error) {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView(actor, campaign) {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+
+	lines, err := s.repo.GetBudgetLines(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.repo.GetPendingDisbursementTotals(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.BudgetReport{
+		CampaignID:   campaign.ID,
+		GoalAmount:   campaign.GoalAmount,
+		RaisedAmount: campaign.CurrentAmount,
+		Lines:        make([]*dto.BudgetLineReport, len(lines)),
+	}
+
+	for i, line := range lines {
+		report.Lines[i] = &dto.BudgetLineReport{
+			BudgetLine:      line,
+			PendingAmount:   pending[line.ID],
+			Variance:        line.Variance(),
+			VariancePercent: percentOf(line.Variance(), line.PlannedAmount),
+			OverBudget:      line.IsOverBudget(),
+		}
+		report.PlannedAmount += line.PlannedAmount
+		report.SpentAmount += line.SpentAmount
+		report.PendingAmount += pending[line.ID]
+	}
+
+	report.Variance = report.PlannedAmount - report.SpentAmount
+	report.VariancePercent = percentOf(report.Variance, report.PlannedAmount)
+	report.UnbudgetedGoal = report.GoalAmount - report.PlannedAmount
+	report.AvailableFunds = report.RaisedAmount - report.SpentAmount
+
+	return report, nil
+}
+
+// CreateBudgetLine adds a line to a campaign's budget plan. Once the
+// campaign is approved only admins can add lines, so a nazir cannot widen
+// the plan to avoid over-budget approval.
+func (s *service) CreateBudgetLine(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.BudgetLineRequest) (*domain.BudgetLine, error) {
+	campaign, err := s.authorize(ctx, actor, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.IsApproved() && !actor.IsAdmin() {
+		return nil, errors.New(errors.ErrCodeForbidden, "Only an admin can add budget lines once the campaign is approved", 403)
+	}
+
+	line := &domain.BudgetLine{CampaignID: campaignID}
+	applyBudgetLine(line, req)
+
+	if err := s.repo.CreateBudgetLine(ctx, line); err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// UpdateBudgetLine edits a budget line. Lowering the planned amount below
+// what was spent leaves the line over budget; raising it is left to admins
+// once the campaign is approved or the line has disbursements.
+func (s *service) UpdateBudgetLine(ctx context.Context, actor *domain.Actor, campaignID, lineID int64, req *dto.BudgetLineRequest) (*domain.BudgetLine, error) {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return nil, err
+	}
+
+	line, err := s.repo.FindBudgetLine(ctx, campaignID, lineID)
+	if err != nil {
+		return nil, err
+	}
+
+	position := line.Position
+	applyBudgetLine(line, req)
+	if req.Position == 0 {
+		line.Position = position
+	}
+
+	if err := s.repo.UpdateBudgetLine(ctx, line, actor.IsAdmin()); err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// DeleteBudgetLine deletes a budget line nothing was disbursed against
+func (s *service) DeleteBudgetLine(ctx context.Context, actor *domain.Actor, campaignID, lineID int64) error {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.FindBudgetLine(ctx, campaignID, lineID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteBudgetLine(ctx, campaignID, lineID)
+}
+
+// GetDisbursements gets the disbursements of a campaign
+func (s *service) GetDisbursements(ctx context.Context, actor *domain.Actor, campaignID int64, status domain.DisbursementStatus, page, perPage int) ([]*domain.Disbursement, int64, error) {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.repo.GetDisbursements(ctx, campaignID, status, perPage, (page-1)*perPage)
+}
+
+// GetPendingDisbursements lists over-budget disbursements awaiting an admin
+func (s *service) GetPendingDisbursements(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*domain.Disbursement, int64, error) {
+	if !actor.IsAdmin() {
+		return nil, 0, errors.ErrForbidden
+	}
+
+	return s.repo.GetPendingDisbursements(ctx, perPage, (page-1)*perPage)
+}
+
+// CreateDisbursement pays out campaign funds against a budget line and posts
+// it to the ledger. A nazir's disbursement that takes its line over budget is
+// held until an admin approves it; an admin's is approved as it is made.
+func (s *service) CreateDisbursement(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.DisbursementRequest) (*domain.Disbursement, error) {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return nil, err
+	}
+
+	line, err := s.repo.FindBudgetLine(ctx, campaignID, req.BudgetLineID)
+	if err != nil {
+		if errors.GetErrorCode(err) == errors.ErrCodeNotFound {
+			return nil, errors.New(errors.ErrCodeValidation, "budget_line_id is not a budget line of this campaign", 400)
+		}
+		return nil, err
+	}
+
+	disbursedAt, err := time.Parse("2006-01-02", req.DisbursedAt)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeValidation, "disbursed_at must be a date (YYYY-MM-DD)", 400)
+	}
+	if disbursedAt.After(today()) {
+		return nil, errors.New(errors.ErrCodeValidation, "disbursed_at must not be in the future", 400)
+	}
+
+	disbursement := &domain.Disbursement{
+		CampaignID:   campaignID,
+		BudgetLineID: line.ID,
+		Amount:       req.Amount,
+		Recipient:    strings.TrimSpace(req.Recipient),
+		Description:  req.Description,
+		ReceiptURL:   req.ReceiptURL,
+		DisbursedAt:  disbursedAt,
+		RequestedBy:  actor.UserID,
+	}
+
+	if err := s.repo.CreateDisbursement(ctx, disbursement, disbursementEntries(line, disbursement), actor.IsAdmin()); err != nil {
+		return nil, err
+	}
+	return disbursement, nil
+}
+
+// ReviewDisbursement approves or rejects an over-budget disbursement. An
+// approved one is spent and posted to the ledger; either way the line takes
+// disbursements again.
+func (s *service) ReviewDisbursement(ctx context.Context, actor *domain.Actor, campaignID, disbursementID int64, req *dto.DisbursementReviewRequest) (*domain.Disbursement, error) {
+	if !actor.IsAdmin() {
+		return nil, errors.ErrForbidden
+	}
+
+	disbursement, err := s.repo.FindDisbursement(ctx, campaignID, disbursementID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !disbursement.IsPendingApproval() {
+		return nil, errors.New(errors.ErrCodeConflict, "Disbursement is not awaiting approval", 409)
+	}
+
+	reviewerID := actor.UserID
+	disbursement.DecidedBy = &reviewerID
+
+	if req.Decision == domain.CampaignReviewRejected {
+		disbursement.RejectionReason = req.Reason
+		if err := s.repo.RejectDisbursement(ctx, disbursement); err != nil {
+			return nil, err
+		}
+		return disbursement, nil
+	}
+
+	line, err := s.repo.FindBudgetLine(ctx, campaignID, disbursement.BudgetLineID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ApproveDisbursement(ctx, disbursement, disbursementEntries(line, disbursement)); err != nil {
+		return nil, err
+	}
+	return disbursement, nil
+}
+
+// applyBudgetLine copies request fields onto a budget line
+func applyBudgetLine(line *domain.BudgetLine, req *dto.BudgetLineRequest) {
+	line.Category = req.Category
+	line.Name = strings.TrimSpace(req.Name)
+	line.Description = req.Description
+	line.PlannedAmount = req.PlannedAmount
+	line.Position = req.Position
+}
+
+// disbursementEntries builds the ledger entries paying a disbursement out of
+// the campaign fund; the repository fills in the balances
+func disbursementEntries(line *domain.BudgetLine, disbursement *domain.Disbursement) []*domain.Ledger {
+	description := fmt.Sprintf("Disbursement to %s for %s of campaign ID %d", disbursement.Recipient, line.Name, disbursement.CampaignID)
+
+	return []*domain.Ledger{
+		{
+			AccountType:   "debit",
+			AccountName:   accountCampaignFund,
+			Amount:        disbursement.Amount,
+			Description:   description,
+			ReferenceType: domain.LedgerRefDisbursement,
+			EntryDate:     disbursement.DisbursedAt,
+		},
+		{
+			AccountType:   "credit",
+			AccountName:   accountCash,
+			Amount:        disbursement.Amount,
+			Description:   description,
+			ReferenceType: domain.LedgerRefDisbursement,
+			EntryDate:     disbursement.DisbursedAt,
+		},
+	}
+}
+
+// percentOf expresses part as a percentage of whole
+func percentOf(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// GetFundraisers gets the fundraiser leaderboard of a visible campaign; its
+// managers also see hidden pages
+func (s *service) GetFundraisers(ctx context.Context, actor *domain.Actor, campaignID int64, page, perPage int) ([]*dto.FundraiserResponse, int64, error) {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if !canView(actor, campaign) {
+		return nil, 0, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+
+	fundraisers, total, err := s.repo.GetFundraisers(ctx, campaignID, canManage(actor, campaign), perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return toFundraiserResponses(fundraisers), total, nil
+}
+
+// GetFundraiser gets a fundraiser page of a campaign
+func (s *service) GetFundraiser(ctx context.Context, actor *domain.Actor, campaignID, fundraiserID int64) (*dto.FundraiserResponse, error) {
+	fundraiser, err := s.repo.FindFundraiserByID(ctx, campaignID, fundraiserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.viewFundraiser(ctx, actor, fundraiser)
+}
+
+// GetFundraiserBySlug gets a fundraiser page by its slug
+func (s *service) GetFundraiserBySlug(ctx context.Context, actor *domain.Actor, slug string) (*dto.FundraiserResponse, error) {
+	fundraiser, err := s.repo.FindFundraiserBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.viewFundraiser(ctx, actor, fundraiser)
+}
+
+// GetMyFundraisers gets the actor's own fundraiser pages
+func (s *service) GetMyFundraisers(ctx context.Context, actor *domain.Actor) ([]*dto.FundraiserResponse, error) {
+	fundraisers, err := s.repo.GetUserFundraisers(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toFundraiserResponses(fundraisers), nil
+}
+
+// CreateFundraiser creates the actor's fundraiser page for an active campaign
+func (s *service) CreateFundraiser(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.FundraiserRequest) (*dto.FundraiserResponse, error) {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView(actor, campaign) {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+	if !campaign.IsActive() {
+		return nil, errors.New(errors.ErrCodeConflict, "Fundraiser pages can only be created for active campaigns", 409)
+	}
+
+	fundraiser := &domain.Fundraiser{
+		CampaignID: campaignID,
+		UserID:     actor.UserID,
+		Title:      strings.TrimSpace(req.Title),
+		Story:      req.Story,
+		GoalAmount: req.GoalAmount,
+		Status:     domain.FundraiserStatusActive,
+	}
+
+	base := slugify(fundraiser.Title)
+	for attempt := 1; ; attempt++ {
+		taken, err := s.repo.GetFundraiserSlugsWithPrefix(ctx, base)
+		if err != nil {
+			return nil, err
+		}
+		fundraiser.Slug = uniqueSlug(base, taken)
+
+		err = s.repo.CreateFundraiser(ctx, fundraiser)
+		if err == nil {
+			break
+		}
+		if errors.GetErrorCode(err) != errors.ErrCodeDuplicateEntry || attempt == slugAttempts {
+			return nil, err
+		}
+	}
+
+	return toFundraiserResponse(fundraiser), nil
+}
+
+// UpdateFundraiser edits a fundraiser page; only its owner may. The slug
+// stays fixed so shared links keep working.
+func (s *service) UpdateFundraiser(ctx context.Context, actor *domain.Actor, campaignID, fundraiserID int64, req *dto.FundraiserRequest) (*dto.FundraiserResponse, error) {
+	fundraiser, err := s.repo.FindFundraiserByID(ctx, campaignID, fundraiserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if fundraiser.UserID != actor.UserID {
+		return nil, errors.New(errors.ErrCodeForbidden, "Only the owner of this fundraiser page can edit it", 403)
+	}
+
+	fundraiser.Title = strings.TrimSpace(req.Title)
+	fundraiser.Story = req.Story
+	fundraiser.GoalAmount = req.GoalAmount
+
+	if err := s.repo.UpdateFundraiser(ctx, fundraiser); err != nil {
+		return nil, err
+	}
+
+	return toFundraiserResponse(fundraiser), nil
+}
+
+// SetFundraiserVisibility hides a fundraiser page from donors, or restores it.
+// Donations already made through a hidden page still count.
+func (s *service) SetFundraiserVisibility(ctx context.Context, actor *domain.Actor, campaignID, fundraiserID int64, req *dto.FundraiserVisibilityRequest) (*dto.FundraiserResponse, error) {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return nil, err
+	}
+
+	fundraiser, err := s.repo.FindFundraiserByID(ctx, campaignID, fundraiserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Hidden {
+		now, moderatorID := time.Now(), actor.UserID
+		fundraiser.Status = domain.FundraiserStatusHidden
+		fundraiser.HiddenReason = req.Reason
+		fundraiser.HiddenBy = &moderatorID
+		fundraiser.HiddenAt = &now
+	} else {
+		fundraiser.Status = domain.FundraiserStatusActive
+		fundraiser.HiddenReason = ""
+		fundraiser.HiddenBy = nil
+		fundraiser.HiddenAt = nil
+	}
+
+	if err := s.repo.SetFundraiserStatus(ctx, fundraiser); err != nil {
+		return nil, err
+	}
+
+	// Reload for the leaderboard rank, which depends on the visibility
+	return s.GetFundraiser(ctx, actor, campaignID, fundraiserID)
+}
+
+// viewFundraiser checks the actor may see a fundraiser page: visible pages of
+// public campaigns, or any page for its owner and the campaign managers
+func (s *service) viewFundraiser(ctx context.Context, actor *domain.Actor, fundraiser *domain.Fundraiser) (*dto.FundraiserResponse, error) {
+	if actor != nil && fundraiser.UserID == actor.UserID {
+		return toFundraiserResponse(fundraiser), nil
+	}
+
+	campaign, err := s.repo.FindByID(ctx, fundraiser.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManage(actor, campaign) && !(campaign.IsPublic() && fundraiser.IsVisible()) {
+		return nil, errors.New(errors.ErrCodeNotFound, "Fundraiser not found", 404)
+	}
+
+	return toFundraiserResponse(fundraiser), nil
+}
+
+func toFundraiserResponse(fundraiser *domain.Fundraiser) *dto.FundraiserResponse {
+	return &dto.FundraiserResponse{
+		Fundraiser: fundraiser,
+		Progress:   fundraiser.Progress(),
+	}
+}
+
+func toFundraiserResponses(fundraisers []*domain.Fundraiser) []*dto.FundraiserResponse {
+	responses := make([]*dto.FundraiserResponse, len(fundraisers))
+	for i, fundraiser := range fundraisers {
+		responses[i] = toFundraiserResponse(fundraiser)
+	}
+	return responses
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/services/campaign/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// GetImpactIndicators gets the impact indicators of a visible campaign
+func (s *service) GetImpactIndicators(ctx context.Context, actor *domain.Actor, campaignID int64) ([]*dto.ImpactIndicatorResponse, error) {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView(actor, campaign) {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+
+	indicators, err := s.repo.GetImpactIndicators(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	return toImpactResponses(indicators), nil
+}
+
+// CreateImpactIndicator adds an impact indicator to a campaign
+func (s *service) CreateImpactIndicator(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.ImpactIndicatorRequest) (*dto.ImpactIndicatorResponse, error) {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return nil, err
+	}
+
+	indicator := &domain.ImpactIndicator{CampaignID: campaignID}
+	applyImpactIndicator(indicator, req)
+
+	if err := s.repo.CreateImpactIndicator(ctx, indicator); err != nil {
+		return nil, err
+	}
+	return toImpactResponse(indicator), nil
+}
+
+// UpdateImpactIndicator edits an impact indicator; its measurements are kept
+func (s *service) UpdateImpactIndicator(ctx context.Context, actor *domain.Actor, campaignID, indicatorID int64, req *dto.ImpactIndicatorRequest) (*dto.ImpactIndicatorResponse, error) {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return nil, err
+	}
+
+	indicator, err := s.repo.FindImpactIndicator(ctx, campaignID, indicatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	position := indicator.Position
+	applyImpactIndicator(indicator, req)
+	if req.Position == 0 {
+		indicator.Position = position
+	}
+
+	if err := s.repo.UpdateImpactIndicator(ctx, indicator); err != nil {
+		return nil, err
+	}
+	return toImpactResponse(indicator), nil
+}
+
+// DeleteImpactIndicator deletes an impact indicator nothing was measured for
+func (s *service) DeleteImpactIndicator(ctx context.Context, actor *domain.Actor, campaignID, indicatorID int64) error {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.FindImpactIndicator(ctx, campaignID, indicatorID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteImpactIndicator(ctx, campaignID, indicatorID)
+}
+
+// GetImpactMeasurements gets the measurements of an indicator of a visible
+// campaign, latest first
+func (s *service) GetImpactMeasurements(ctx context.Context, actor *domain.Actor, campaignID, indicatorID int64, page, perPage int) ([]*domain.ImpactMeasurement, int64, error) {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if !canView(actor, campaign) {
+		return nil, 0, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+
+	if _, err := s.repo.FindImpactIndicator(ctx, campaignID, indicatorID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.repo.GetImpactMeasurements(ctx, indicatorID, perPage, (page-1)*perPage)
+}
+
+// RecordImpactMeasurement records a reading of an indicator's cumulative
+// value. The indicator shows the reading with the latest date, so a
+// backdated measurement fills in history without changing it.
+func (s *service) RecordImpactMeasurement(ctx context.Context, actor *domain.Actor, campaignID, indicatorID int64, req *dto.ImpactMeasurementRequest) (*domain.ImpactMeasurement, error) {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindImpactIndicator(ctx, campaignID, indicatorID); err != nil {
+		return nil, err
+	}
+
+	measuredAt, err := time.Parse("2006-01-02", req.MeasuredAt)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeValidation, "measured_at must be a date (YYYY-MM-DD)", 400)
+	}
+	if measuredAt.After(today()) {
+		return nil, errors.New(errors.ErrCodeValidation, "measured_at must not be in the future", 400)
+	}
+
+	measurement := &domain.ImpactMeasurement{
+		IndicatorID:  indicatorID,
+		CampaignID:   campaignID,
+		Value:        req.Value,
+		Note:         req.Note,
+		EvidenceURLs: req.EvidenceURLs,
+		MeasuredAt:   measuredAt,
+		RecordedBy:   actor.UserID,
+	}
+
+	if _, err := s.repo.CreateImpactMeasurement(ctx, measurement); err != nil {
+		return nil, err
+	}
+	return measurement, nil
+}
+
+// DeleteImpactMeasurement deletes a mistaken measurement and returns the
+// indicator at its latest remaining reading
+func (s *service) DeleteImpactMeasurement(ctx context.Context, actor *domain.Actor, campaignID, indicatorID, measurementID int64) (*dto.ImpactIndicatorResponse, error) {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindImpactIndicator(ctx, campaignID, indicatorID); err != nil {
+		return nil, err
+	}
+
+	indicator, err := s.repo.DeleteImpactMeasurement(ctx, indicatorID, measurementID)
+	if err != nil {
+		return nil, err
+	}
+	return toImpactResponse(indicator), nil
+}
+
+// GetImpactReport sums the impact indicators of public campaigns by name and
+// unit. Admins also get the totals of each tenant.
+func (s *service) GetImpactReport(ctx context.Context, actor *domain.Actor, query *dto.ImpactReportQuery) (*dto.ImpactReport, error) {
+	isAdmin := actor != nil && actor.IsAdmin()
+	if query.TenantID != nil && !isAdmin {
+		return nil, errors.ErrForbidden
+	}
+
+	totals, err := s.repo.GetImpactReport(ctx, &repository.ImpactReportFilter{
+		Statuses: domain.PublicCampaignStatuses,
+		Type:     query.Type,
+		TenantID: query.TenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.ImpactReport{Totals: make([]*repository.ImpactTotal, 0)}
+	for _, total := range totals {
+		switch {
+		case !total.ByTenant:
+			report.Totals = append(report.Totals, total)
+		case isAdmin:
+			report.ByTenant = append(report.ByTenant, total)
+		}
+	}
+	return report, nil
+}
+
+// applyImpactIndicator copies request fields onto an impact indicator
+func applyImpactIndicator(indicator *domain.ImpactIndicator, req *dto.ImpactIndicatorRequest) {
+	indicator.Name = strings.TrimSpace(req.Name)
+	indicator.Unit = strings.TrimSpace(req.Unit)
+	indicator.Description = req.Description
+	indicator.TargetValue = req.TargetValue
+	indicator.Position = req.Position
+}
+
+func toImpactResponse(indicator *domain.ImpactIndicator) *dto.ImpactIndicatorResponse {
+	return &dto.ImpactIndicatorResponse{
+		ImpactIndicator: indicator,
+		Progress:        indicator.Progress(),
+	}
+}
+
+func toImpactResponses(indicators []*domain.ImpactIndicator) []*dto.ImpactIndicatorResponse {
+	responses := make([]*dto.ImpactIndicatorResponse, len(indicators))
+	for i, indicator := range indicators {
+		responses[i] = toImpactResponse(indicator)
+	}
+	return responses
+}
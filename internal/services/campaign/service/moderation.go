@@ -0,0 +1,75 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// blockedWords are masked in donor messages shown publicly. Matching ignores
+// case, digits standing in for letters and stretched letters, so "4NJIIING"
+// is caught as well.
+var blockedWords = wordSet(
+	"anjing", "anjir", "asu", "babi", "bajingan", "bangsat", "bego", "brengsek", "goblok", "jancok",
+	"jancuk", "kampret", "keparat", "kontol", "memek", "ngentot", "tai", "tolol",
+	"bastard", "bitch", "fuck", "shit",
+)
+
+// letterSubstitutes maps characters commonly typed in place of letters
+var letterSubstitutes = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '@': 'a', '$': 's',
+}
+
+// filterMessage masks blocked words in a donor message, keeping their first
+// letter, e.g. "dasar goblok" becomes "dasar g*****"
+func filterMessage(message string) string {
+	var b strings.Builder
+	word := make([]rune, 0, 16)
+
+	flush := func() {
+		if len(word) > 0 && blockedWords[normalizeWord(word)] {
+			b.WriteRune(word[0])
+			b.WriteString(strings.Repeat("*", len(word)-1))
+		} else {
+			b.WriteString(string(word))
+		}
+		word = word[:0]
+	}
+
+	for _, r := range message {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || letterSubstitutes[r] != 0 {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		b.WriteRune(r)
+	}
+	flush()
+
+	return b.String()
+}
+
+// normalizeWord lowercases a word, replaces letter substitutes and collapses
+// repeated letters
+func normalizeWord(word []rune) string {
+	var b strings.Builder
+	var last rune
+	for _, r := range word {
+		r = unicode.ToLower(r)
+		if letter, ok := letterSubstitutes[r]; ok {
+			r = letter
+		}
+		if r != last {
+			b.WriteRune(r)
+			last = r
+		}
+	}
+	return b.String()
+}
+
+func wordSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[normalizeWord([]rune(word))] = true
+	}
+	return set
+}
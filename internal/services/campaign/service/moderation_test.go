@@ -0,0 +1,33 @@
+package service
+
+import "testing"
+
+func TestFilterMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"clean message", "Semoga berkah dan lancar pembangunannya", "Semoga berkah dan lancar pembangunannya"},
+		{"empty", "", ""},
+		{"blocked word", "dasar goblok", "dasar g*****"},
+		{"upper case", "GOBLOK!", "G*****!"},
+		{"digits for letters", "4NJIIING", "4*******"},
+		{"symbols for letters", "b@bi $hit", "b*** $***"},
+		{"stretched letters", "bangsaaaat", "b*********"},
+		{"punctuation is kept", "babi, tolol. (tai)", "b***, t****. (t**)"},
+		{"every occurrence", "asu asu", "a** a**"},
+		{"words containing a blocked word are kept", "asumsi di pantai Kuta", "asumsi di pantai Kuta"},
+		{"blocked word glued to digits is a different word", "tai2", "tai2"},
+		{"non-ASCII letters", "Jazākallāh khairan, ténang", "Jazākallāh khairan, ténang"},
+		{"numbers alone are kept", "Rp 500.000 untuk 2024", "Rp 500.000 untuk 2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterMessage(tt.message); got != tt.want {
+				t.Errorf("filterMessage(%q) = %q, want %q", tt.message, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// GetReviewQueue lists campaigns submitted for approval and campaigns with a
+// material change awaiting re-approval, oldest first
+func (s *service) GetReviewQueue(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*dto.CampaignResponse, int64, error) {
+	if !actor.IsAdmin() {
+		return nil, 0, errors.ErrForbidden
+	}
+
+	campaigns, total, err := s.repo.GetReviewQueue(ctx, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	queue := make([]*dto.CampaignResponse, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		resp := toResponse(campaign, nil)
+		if campaign.Status != domain.CampaignStatusPendingReview {
+			if resp.PendingVersion, err = s.pendingVersion(ctx, campaign.ID); err != nil {
+				return nil, 0, err
+			}
+		}
+		queue = append(queue, resp)
+	}
+
+	return queue, total, nil
+}
+
+// Review approves or rejects a campaign pending review, or the material change
+// awaiting re-approval on an approved campaign. Approving requires every
+// checklist item; land documents only apply to land and building campaigns.
+func (s *service) Review(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.ReviewRequest) (*dto.CampaignResponse, error) {
+	if !actor.IsAdmin() {
+		return nil, errors.ErrForbidden
+	}
+
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &domain.CampaignReview{
+		CampaignID:    campaignID,
+		Decision:      req.Decision,
+		LandDocuments: req.LandDocuments,
+		AkadStated:    req.AkadStated,
+		GoalJustified: req.GoalJustified,
+		Comment:       req.Comment,
+		ReviewerID:    actor.UserID,
+	}
+
+	if campaign.Status == domain.CampaignStatusPendingReview {
+		if err := s.reviewCampaign(ctx, actor, campaign, review); err != nil {
+			return nil, err
+		}
+		return s.withMilestones(ctx, campaign)
+	}
+
+	version, err := s.pendingVersion(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil {
+		return nil, errors.New(errors.ErrCodeConflict, "Campaign is not awaiting review", 409)
+	}
+
+	if err := s.reviewVersion(ctx, actor, campaign, version, review); err != nil {
+		return nil, err
+	}
+	return s.withMilestones(ctx, campaign)
+}
+
+// GetReviews gets the review history of a campaign
+func (s *service) GetReviews(ctx context.Context, actor *domain.Actor, campaignID int64) ([]*domain.CampaignReview, error) {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetReviews(ctx, campaignID)
+}
+
+// GetVersions gets the versions recorded since a campaign was first approved
+func (s *service) GetVersions(ctx context.Context, actor *domain.Actor, campaignID int64, page, perPage int) ([]*domain.CampaignVersion, int64, error) {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.repo.GetVersions(ctx, campaignID, perPage, (page-1)*perPage)
+}
+
+// reviewCampaign decides on a campaign submitted for approval. An approved
+// campaign goes live at once, or waits as a scheduled draft when its start
+// date is still ahead; a rejected one returns to draft for the nazir to fix.
+func (s *service) reviewCampaign(ctx context.Context, actor *domain.Actor, campaign *domain.Campaign, review *domain.CampaignReview) error {
+	if review.Decision == domain.CampaignReviewRejected {
+		return s.repo.ReviewCampaign(ctx, campaign, domain.CampaignStatusDraft, review, nil)
+	}
+
+	if err := checkChecklist(review, campaign.Type); err != nil {
+		return err
+	}
+
+	if campaign.EndDate != nil && !campaign.IsEndless && campaign.EndDate.Before(today()) {
+		return errors.New(errors.ErrCodeBadRequest, "Campaign end date has passed", 400)
+	}
+
+	next := domain.CampaignStatusActive
+	if campaign.StartDate.After(today()) {
+		next = domain.CampaignStatusDraft
+		campaign.AutoPublish = true
+	}
+
+	now, reviewerID := time.Now(), actor.UserID
+	campaign.ApprovedAt = &now
+	campaign.ApprovedBy = &reviewerID
+
+	approved := *campaign
+	approved.Status = next
+	snapshot, err := json.Marshal(&approved)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to record campaign version", 500)
+	}
+
+	version := &domain.CampaignVersion{
+		CampaignID:    campaign.ID,
+		Snapshot:      snapshot,
+		ChangedFields: []string{},
+		Status:        domain.CampaignVersionApproved,
+		EditedBy:      actor.UserID,
+	}
+
+	return s.repo.ReviewCampaign(ctx, campaign, next, review, version)
+}
+
+// reviewVersion decides on a material change; approving applies it
+func (s *service) reviewVersion(ctx context.Context, actor *domain.Actor, campaign *domain.Campaign, version *domain.CampaignVersion, review *domain.CampaignReview) error {
+	review.VersionID = &version.ID
+
+	if review.Decision == domain.CampaignReviewApproved {
+		var proposed domain.Campaign
+		if err := json.Unmarshal(version.Snapshot, &proposed); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to read campaign version", 500)
+		}
+
+		if err := checkChecklist(review, proposed.Type); err != nil {
+			return err
+		}
+
+		now, reviewerID := time.Now(), actor.UserID
+		campaign.GoalAmount = proposed.GoalAmount
+		campaign.Type = proposed.Type
+		campaign.ApprovedAt = &now
+		campaign.ApprovedBy = &reviewerID
+	}
+
+	return s.repo.ReviewVersion(ctx, campaign, version, review)
+}
+
+// pendingVersion gets the change awaiting review, or nil when there is none
+func (s *service) pendingVersion(ctx context.Context, campaignID int64) (*domain.CampaignVersion, error) {
+	version, err := s.repo.FindPendingVersion(ctx, campaignID)
+	if errors.GetErrorCode(err) == errors.ErrCodeNotFound {
+		return nil, nil
+	}
+	return version, err
+}
+
+// checkChecklist checks every review checklist item applying to the campaign type
+func checkChecklist(review *domain.CampaignReview, campaignType domain.CampaignType) error {
+	if !review.AkadStated || !review.GoalJustified || (domain.NeedsLandDocuments(campaignType) && !review.LandDocuments) {
+		return errors.New(errors.ErrCodeValidation, "Every checklist item must be confirmed before approving", 400)
+	}
+	return nil
+}
+
+// newVersion builds the version recording an edit to an approved campaign, or
+// nil when the campaign was never approved or nothing changed. A material
+// change by a nazir is held back: the campaign keeps its approved goal and
+// type, and the edit waits as a pending version. Admins review campaigns, so
+// their edits apply at once.
+func newVersion(actor *domain.Actor, before, after *domain.Campaign) (*domain.CampaignVersion, error) {
+	if !before.IsApproved() {
+		return nil, nil
+	}
+
+	changed := changedFields(before, after)
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	version := &domain.CampaignVersion{
+		CampaignID:    after.ID,
+		ChangedFields: changed,
+		Material:      isMaterial(changed),
+		Status:        domain.CampaignVersionApplied,
+		EditedBy:      actor.UserID,
+	}
+
+	snapshot, err := json.Marshal(after)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to record campaign version", 500)
+	}
+	version.Snapshot = snapshot
+
+	if version.Material && !actor.IsAdmin() {
+		version.Status = domain.CampaignVersionPending
+		after.GoalAmount = before.GoalAmount
+		after.Type = before.Type
+	}
+
+	return version, nil
+}
+
+// changedFields lists the JSON names of the editable fields that differ
+func changedFields(before, after *domain.Campaign) []string {
+	changed := make([]string, 0)
+	add := func(field string, differs bool) {
+		if differs {
+			changed = append(changed, field)
+		}
+	}
+
+	add("title", before.Title != after.Title)
+	add("slug", before.Slug != after.Slug)
+	add("description", before.Description != after.Description)
+	add("short_desc", before.ShortDesc != after.ShortDesc)
+	add("type", before.Type != after.Type)
+	add("goal_amount", before.GoalAmount != after.GoalAmount)
+	add("image_url", before.ImageURL != after.ImageURL)
+	add("video_url", before.VideoURL != after.VideoURL)
+	add("location", before.Location != after.Location)
+	add("start_date", !before.StartDate.Equal(after.StartDate))
+	add("end_date", !sameDate(before.EndDate, after.EndDate))
+	add("is_endless", before.IsEndless != after.IsEndless)
+	add("is_featured", before.IsFeatured != after.IsFeatured)
+	add("is_urgent", before.IsUrgent != after.IsUrgent)
+	add("auto_publish", before.AutoPublish != after.AutoPublish)
+	add("accept_overflow", before.AcceptOverflow != after.AcceptOverflow)
+	add("overflow_campaign_id", !sameID(before.OverflowCampaignID, after.OverflowCampaignID))
+
+	return changed
+}
+
+// isMaterial checks if any changed field requires re-approval
+func isMaterial(changed []string) bool {
+	for _, field := range changed {
+		for _, material := range domain.CampaignMaterialFields {
+			if field == material {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func sameID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
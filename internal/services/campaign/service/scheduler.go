@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/notify"
+)
+
+// reminderWindows are the days before the end date on which the nazir is
+// reminded, largest first
+var reminderWindows = []int{7, 1}
+
+// Scheduler publishes scheduled campaigns on their start date, completes
+// campaigns past their end date and reminds nazirs of campaigns about to end.
+// Every step is idempotent, so ticks may overlap or be missed.
+type Scheduler struct {
+	repo      repository.Repository
+	publicURL string
+}
+
+// NewScheduler creates a new campaign scheduler. publicURL is the donor-facing
+// site linked from reminders.
+func NewScheduler(repo repository.Repository, publicURL string) *Scheduler {
+	return &Scheduler{
+		repo:      repo,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// SchedulerResult counts what one scheduler tick changed. Reminders counts
+// campaigns in a reminder window; reminders already sent are not resent.
+type SchedulerResult struct {
+	Activated int `json:"activated"`
+	Completed int `json:"completed"`
+	Reminders int `json:"reminders"`
+}
+
+// Tick runs every scheduler step for the current day
+func (s *Scheduler) Tick(ctx context.Context) (*SchedulerResult, error) {
+	day := today()
+	result := &SchedulerResult{}
+
+	activated, err := s.repo.ActivateDue(ctx, day)
+	if err != nil {
+		return result, err
+	}
+	result.Activated = len(activated)
+
+	completed, err := s.repo.CompleteExpired(ctx, day)
+	if err != nil {
+		return result, err
+	}
+	result.Completed = len(completed)
+
+	ending, err := s.repo.GetEndingCampaigns(ctx, day, reminderWindows[0])
+	if err != nil {
+		return result, err
+	}
+
+	for _, e := range ending {
+		messages := s.endingReminders(e)
+		if len(messages) == 0 {
+			continue
+		}
+		if err := s.repo.QueueNotifications(ctx, messages...); err != nil {
+			return result, err
+		}
+		result.Reminders++
+	}
+
+	return result, nil
+}
+
+// Run ticks on an interval until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if result, err := s.Tick(ctx); err != nil {
+			log.Printf("campaign scheduler: tick failed: %v", err)
+		} else if result.Activated > 0 || result.Completed > 0 {
+			log.Printf("campaign scheduler: activated %d, completed %d campaign(s)", result.Activated, result.Completed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// endingReminders builds the reminders to the nazir of a campaign about to
+// end. The dedupe key holds the window and end date, so each window is sent
+// once and moving the end date starts over.
+func (s *Scheduler) endingReminders(e *repository.EndingCampaign) []*notify.Message {
+	window := reminderWindows[0]
+	for _, w := range reminderWindows {
+		if e.DaysLeft <= w {
+			window = w
+		}
+	}
+
+	campaign := e.Campaign
+	endDate := campaign.EndDate.Format("2006-01-02")
+	dedupe := fmt.Sprintf("campaign_ending:%d:%d:%s", campaign.ID, window, endDate)
+
+	when := fmt.Sprintf("%d hari lagi", e.DaysLeft)
+	if e.DaysLeft == 0 {
+		when = "hari ini"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Assalamu'alaikum,\n\nProgram wakaf \"%s\" akan berakhir %s (%s).\n\n", campaign.Title, when, endDate)
+	fmt.Fprintf(&b, "Terkumpul Rp%d dari target Rp%d (%.0f%%) dari %d wakif.\n\n", campaign.CurrentAmount, campaign.GoalAmount, campaign.Progress(), campaign.DonorCount)
+	b.WriteString("Perpanjang tanggal berakhir atau aktifkan penyaluran kelebihan dana ke dana umum bila program perlu tetap menerima wakaf.\n\n")
+	fmt.Fprintf(&b, "Lihat program: %s/campaigns/%s\n", s.publicURL, campaign.Slug)
+
+	messages := make([]*notify.Message, 0, 2)
+	if e.NazirEmail != "" {
+		messages = append(messages, &notify.Message{
+			Channel:       notify.ChannelEmail,
+			To:            e.NazirEmail,
+			Subject:       "Program wakaf segera berakhir: " + campaign.Title,
+			Body:          b.String(),
+			DedupeKey:     dedupe + ":email",
+			ReferenceType: "campaign",
+			ReferenceID:   campaign.ID,
+		})
+	}
+	if e.NazirPhone != "" {
+		messages = append(messages, &notify.Message{
+			Channel:       notify.ChannelWhatsApp,
+			To:            e.NazirPhone,
+			Body:          b.String(),
+			DedupeKey:     dedupe + ":whatsapp",
+			ReferenceType: "campaign",
+			ReferenceID:   campaign.ID,
+		})
+	}
+
+	return messages
+}
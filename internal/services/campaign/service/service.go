@@ -0,0 +1,678 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/services/campaign/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/events"
+)
+
+// slugAttempts bounds retries when a concurrent create takes the same slug
+const slugAttempts = 3
+
+// Service defines campaign service interface
+type Service interface {
+	Create(ctx context.Context, actor *domain.Actor, req *dto.CreateCampaignRequest) (*dto.CampaignResponse, error)
+	Update(ctx context.Context, actor *domain.Actor, id int64, req *dto.UpdateCampaignRequest) (*dto.CampaignResponse, error)
+	UpdateStatus(ctx context.Context, actor *domain.Actor, id int64, req *dto.UpdateStatusRequest) (*dto.CampaignResponse, error)
+	GetByID(ctx context.Context, actor *domain.Actor, id int64, locale domain.Locale) (*dto.CampaignResponse, error)
+	GetBySlug(ctx context.Context, actor *domain.Actor, slug string, locale domain.Locale) (*dto.CampaignResponse, error)
+	List(ctx context.Context, actor *domain.Actor, query *dto.ListCampaignsQuery) ([]*dto.CampaignResponse, int64, error)
+	Search(ctx context.Context, actor *domain.Actor, query *dto.SearchCampaignsQuery) (*dto.SearchResponse, int64, error)
+	GetMilestones(ctx context.Context, actor *domain.Actor, campaignID int64, locale domain.Locale) ([]*domain.Milestone, error)
+	CreateMilestone(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.MilestoneRequest) (*domain.Milestone, error)
+	UpdateMilestone(ctx context.Context, actor *domain.Actor, campaignID, milestoneID int64, req *dto.MilestoneRequest) (*domain.Milestone, error)
+	DeleteMilestone(ctx context.Context, actor *domain.Actor, campaignID, milestoneID int64) error
+	RebuildTotals(ctx context.Context, actor *domain.Actor, campaignID int64) (*repository.TotalsChange, error)
+	RebuildAllTotals(ctx context.Context, actor *domain.Actor) ([]*repository.TotalsChange, error)
+	GetUpdates(ctx context.Context, actor *domain.Actor, campaignID int64, locale domain.Locale, page, perPage int) ([]*domain.CampaignUpdate, int64, error)
+	GetUpdate(ctx context.Context, actor *domain.Actor, campaignID, updateID int64, locale domain.Locale) (*domain.CampaignUpdate, error)
+	CreateUpdate(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.CampaignUpdateRequest) (*domain.CampaignUpdate, error)
+	UpdateUpdate(ctx context.Context, actor *domain.Actor, campaignID, updateID int64, req *dto.CampaignUpdateRequest) (*domain.CampaignUpdate, error)
+	DeleteUpdate(ctx context.Context, actor *domain.Actor, campaignID, updateID int64) error
+	PublishUpdate(ctx context.Context, actor *domain.Actor, campaignID, updateID int64) (*domain.CampaignUpdate, error)
+	GetFeed(ctx context.Context, actor *domain.Actor, locale domain.Locale, page, perPage int) ([]*domain.CampaignUpdate, int64, error)
+	GetSubscription(ctx context.Context, actor *domain.Actor, campaignID int64) (*domain.CampaignSubscription, error)
+	UpdateSubscription(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.SubscriptionRequest) (*domain.CampaignSubscription, error)
+	Unsubscribe(ctx context.Context, req *dto.UnsubscribeRequest) error
+	GetReviewQueue(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*dto.CampaignResponse, int64, error)
+	Review(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.ReviewRequest) (*dto.CampaignResponse, error)
+	GetReviews(ctx context.Context, actor *domain.Actor, campaignID int64) ([]*domain.CampaignReview, error)
+	GetVersions(ctx context.Context, actor *domain.Actor, campaignID int64, page, perPage int) ([]*domain.CampaignVersion, int64, error)
+	GetFundraisers(ctx context.Context, actor *domain.Actor, campaignID int64, page, perPage int) ([]*dto.FundraiserResponse, int64, error)
+	GetFundraiser(ctx context.Context, actor *domain.Actor, campaignID, fundraiserID int64) (*dto.FundraiserResponse, error)
+	GetFundraiserBySlug(ctx context.Context, actor *domain.Actor, slug string) (*dto.FundraiserResponse, error)
+	GetMyFundraisers(ctx context.Context, actor *domain.Actor) ([]*dto.FundraiserResponse, error)
+	CreateFundraiser(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.FundraiserRequest) (*dto.FundraiserResponse, error)
+	UpdateFundraiser(ctx context.Context, actor *domain.Actor, campaignID, fundraiserID int64, req *dto.FundraiserRequest) (*dto.FundraiserResponse, error)
+	SetFundraiserVisibility(ctx context.Context, actor *domain.Actor, campaignID, fundraiserID int64, req *dto.FundraiserVisibilityRequest) (*dto.FundraiserResponse, error)
+	GetDonorWall(ctx context.Context, actor *domain.Actor, campaignID int64, cursor string, limit int) (*dto.DonorWallResponse, error)
+	SetMessageVisibility(ctx context.Context, actor *domain.Actor, campaignID, donationID int64, req *dto.MessageVisibilityRequest) error
+	GetTopDonors(ctx context.Context, actor *domain.Actor, campaignID int64, query *dto.LeaderboardQuery) (*dto.LeaderboardResponse, error)
+	GetTopFundraisers(ctx context.Context, actor *domain.Actor, campaignID int64, query *dto.LeaderboardQuery) (*dto.LeaderboardResponse, error)
+	GetBudget(ctx context.Context, actor *domain.Actor, campaignID int64) (*dto.BudgetReport, error)
+	CreateBudgetLine(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.BudgetLineRequest) (*domain.BudgetLine, error)
+	UpdateBudgetLine(ctx context.Context, actor *domain.Actor, campaignID, lineID int64, req *dto.BudgetLineRequest) (*domain.BudgetLine, error)
+	DeleteBudgetLine(ctx context.Context, actor *domain.Actor, campaignID, lineID int64) error
+	GetDisbursements(ctx context.Context, actor *domain.Actor, campaignID int64, status domain.DisbursementStatus, page, perPage int) ([]*domain.Disbursement, int64, error)
+	GetPendingDisbursements(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*domain.Disbursement, int64, error)
+	CreateDisbursement(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.DisbursementRequest) (*domain.Disbursement, error)
+	ReviewDisbursement(ctx context.Context, actor *domain.Actor, campaignID, disbursementID int64, req *dto.DisbursementReviewRequest) (*domain.Disbursement, error)
+	GetTemplates(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*domain.CampaignTemplate, int64, error)
+	GetTemplate(ctx context.Context, actor *domain.Actor, templateID int64) (*domain.CampaignTemplate, error)
+	CreateTemplate(ctx context.Context, actor *domain.Actor, req *dto.TemplateRequest) (*domain.CampaignTemplate, error)
+	UpdateTemplate(ctx context.Context, actor *domain.Actor, templateID int64, req *dto.TemplateRequest) (*domain.CampaignTemplate, error)
+	DeleteTemplate(ctx context.Context, actor *domain.Actor, templateID int64) error
+	SaveAsTemplate(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.SaveTemplateRequest) (*domain.CampaignTemplate, error)
+	CreateFromTemplate(ctx context.Context, actor *domain.Actor, templateID int64, req *dto.CloneCampaignRequest) (*dto.CampaignResponse, error)
+	Clone(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.CloneCampaignRequest) (*dto.CampaignResponse, error)
+	GetTranslations(ctx context.Context, actor *domain.Actor, campaignID int64) (*dto.TranslationsResponse, error)
+	SaveTranslation(ctx context.Context, actor *domain.Actor, campaignID int64, entityType domain.TranslationEntity, entityID int64, locale domain.Locale, req *dto.TranslationRequest) (*domain.Translation, error)
+	DeleteTranslation(ctx context.Context, actor *domain.Actor, campaignID int64, entityType domain.TranslationEntity, entityID int64, locale domain.Locale) error
+	GetImpactIndicators(ctx context.Context, actor *domain.Actor, campaignID int64) ([]*dto.ImpactIndicatorResponse, error)
+	CreateImpactIndicator(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.ImpactIndicatorRequest) (*dto.ImpactIndicatorResponse, error)
+	UpdateImpactIndicator(ctx context.Context, actor *domain.Actor, campaignID, indicatorID int64, req *dto.ImpactIndicatorRequest) (*dto.ImpactIndicatorResponse, error)
+	DeleteImpactIndicator(ctx context.Context, actor *domain.Actor, campaignID, indicatorID int64) error
+	GetImpactMeasurements(ctx context.Context, actor *domain.Actor, campaignID, indicatorID int64, page, perPage int) ([]*domain.ImpactMeasurement, int64, error)
+	RecordImpactMeasurement(ctx context.Context, actor *domain.Actor, campaignID, indicatorID int64, req *dto.ImpactMeasurementRequest) (*domain.ImpactMeasurement, error)
+	DeleteImpactMeasurement(ctx context.Context, actor *domain.Actor, campaignID, indicatorID, measurementID int64) (*dto.ImpactIndicatorResponse, error)
+	GetImpactReport(ctx context.Context, actor *domain.Actor, query *dto.ImpactReportQuery) (*dto.ImpactReport, error)
+}
+
+type service struct {
+	repo      repository.Repository
+	totals    *Totals
+	publicURL string
+}
+
+// New creates a new campaign service. publicURL is the donor-facing site
+// linked from notifications.
+func New(repo repository.Repository, publisher events.Publisher, publicURL string) Service {
+	return &service{
+		repo:      repo,
+		totals:    NewTotals(repo, publisher),
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// Create creates a draft campaign owned by the calling nazir
+func (s *service) Create(ctx context.Context, actor *domain.Actor, req *dto.CreateCampaignRequest) (*dto.CampaignResponse, error) {
+	campaign := &domain.Campaign{
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		ShortDesc:   req.ShortDesc,
+		Type:        req.Type,
+		Status:      domain.CampaignStatusDraft,
+		GoalAmount:  req.GoalAmount,
+		NazirID:     actor.UserID,
+		ImageURL:    req.ImageURL,
+		VideoURL:    req.VideoURL,
+		Location:    req.Location,
+		IsEndless:   req.IsEndless,
+		IsUrgent:    req.IsUrgent,
+		TenantID:    actor.TenantID,
+
+		AutoPublish:        req.AutoPublish,
+		AcceptOverflow:     req.AcceptOverflow,
+		OverflowCampaignID: req.OverflowCampaignID,
+	}
+
+	if actor.IsAdmin() {
+		campaign.IsFeatured = req.IsFeatured
+		if req.NazirID != 0 {
+			campaign.NazirID = req.NazirID
+		}
+	} else if req.IsFeatured || req.NazirID != 0 {
+		return nil, errors.New(errors.ErrCodeForbidden, "Only admins can feature campaigns or assign another nazir", 403)
+	}
+
+	if err := applySchedule(campaign, req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOverflow(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	base := slugify(campaign.Title)
+	for attempt := 1; ; attempt++ {
+		taken, err := s.repo.GetSlugsWithPrefix(ctx, base)
+		if err != nil {
+			return nil, err
+		}
+		campaign.Slug = uniqueSlug(base, taken)
+
+		err = s.repo.Create(ctx, campaign)
+		if err == nil {
+			break
+		}
+		if errors.GetErrorCode(err) != errors.ErrCodeDuplicateEntry || attempt == slugAttempts {
+			return nil, err
+		}
+	}
+
+	return toResponse(campaign, nil), nil
+}
+
+// Update updates campaign details. The slug follows the title only while the
+// campaign is a draft so that shared links keep working once it is published.
+func (s *service) Update(ctx context.Context, actor *domain.Actor, id int64, req *dto.UpdateCampaignRequest) (*dto.CampaignResponse, error) {
+	campaign, err := s.authorize(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.IsClosed() {
+		return nil, errors.New(errors.ErrCodeConflict, "Completed or cancelled campaigns cannot be changed", 409)
+	}
+
+	if req.IsFeatured != nil && !actor.IsAdmin() {
+		return nil, errors.New(errors.ErrCodeForbidden, "Only admins can feature campaigns", 403)
+	}
+
+	before := *campaign
+	titleChanged := false
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		titleChanged = title != campaign.Title
+		campaign.Title = title
+	}
+	if req.Description != nil {
+		campaign.Description = *req.Description
+	}
+	if req.ShortDesc != nil {
+		campaign.ShortDesc = *req.ShortDesc
+	}
+	if req.Type != nil {
+		campaign.Type = *req.Type
+	}
+	if req.GoalAmount != nil {
+		campaign.GoalAmount = *req.GoalAmount
+	}
+	if req.ImageURL != nil {
+		campaign.ImageURL = *req.ImageURL
+	}
+	if req.VideoURL != nil {
+		campaign.VideoURL = *req.VideoURL
+	}
+	if req.Location != nil {
+		campaign.Location = *req.Location
+	}
+	if req.IsFeatured != nil {
+		campaign.IsFeatured = *req.IsFeatured
+	}
+	if req.IsUrgent != nil {
+		campaign.IsUrgent = *req.IsUrgent
+	}
+	if req.AutoPublish != nil {
+		campaign.AutoPublish = *req.AutoPublish
+	}
+	if req.AcceptOverflow != nil {
+		campaign.AcceptOverflow = *req.AcceptOverflow
+	}
+	if req.OverflowCampaignID != nil {
+		campaign.OverflowCampaignID = req.OverflowCampaignID
+	}
+
+	if req.StartDate != nil || req.EndDate != nil || req.IsEndless != nil {
+		start := campaign.StartDate.Format("2006-01-02")
+		if req.StartDate != nil {
+			start = *req.StartDate
+		}
+		end := ""
+		if campaign.EndDate != nil {
+			end = campaign.EndDate.Format("2006-01-02")
+		}
+		if req.EndDate != nil {
+			end = *req.EndDate
+		}
+		if req.IsEndless != nil {
+			campaign.IsEndless = *req.IsEndless
+		}
+		if err := applySchedule(campaign, start, end); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.checkOverflow(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	if titleChanged && campaign.Status == domain.CampaignStatusDraft {
+		taken, err := s.repo.GetSlugsWithPrefix(ctx, slugify(campaign.Title))
+		if err != nil {
+			return nil, err
+		}
+		campaign.Slug = uniqueSlug(slugify(campaign.Title), withoutSlug(taken, campaign.Slug))
+	}
+
+	version, err := newVersion(actor, &before, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, campaign, version); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.withMilestones(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+	if version != nil && version.IsPending() {
+		resp.PendingVersion = version
+	}
+	return resp, nil
+}
+
+// UpdateStatus moves a campaign through its lifecycle
+func (s *service) UpdateStatus(ctx context.Context, actor *domain.Actor, id int64, req *dto.UpdateStatusRequest) (*dto.CampaignResponse, error) {
+	campaign, err := s.authorize(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.Status == req.Status {
+		return s.withMilestones(ctx, campaign)
+	}
+
+	if !campaign.CanTransitionTo(req.Status) {
+		return nil, errors.New(errors.ErrCodeConflict, "Campaign cannot move from "+string(campaign.Status)+" to "+string(req.Status), 409)
+	}
+
+	if campaign.Status == domain.CampaignStatusPendingReview && req.Status == domain.CampaignStatusActive {
+		return nil, errors.New(errors.ErrCodeConflict, "Campaigns pending review go live when an admin approves them", 409)
+	}
+
+	if req.Status == domain.CampaignStatusActive && campaign.EndDate != nil && !campaign.IsEndless &&
+		campaign.EndDate.Before(today()) {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Campaign end date has passed", 400)
+	}
+
+	if err := s.repo.UpdateStatus(ctx, campaign, req.Status); err != nil {
+		return nil, err
+	}
+
+	return s.withMilestones(ctx, campaign)
+}
+
+// GetByID gets a campaign in the locale; drafts and cancelled campaigns are
+// only visible to their managers
+func (s *service) GetByID(ctx context.Context, actor *domain.Actor, id int64, locale domain.Locale) (*dto.CampaignResponse, error) {
+	campaign, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView(actor, campaign) {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+
+	return s.localized(ctx, campaign, locale)
+}
+
+// GetBySlug gets a campaign by its slug in the locale
+func (s *service) GetBySlug(ctx context.Context, actor *domain.Actor, slug string, locale domain.Locale) (*dto.CampaignResponse, error) {
+	campaign, err := s.repo.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView(actor, campaign) {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+
+	return s.localized(ctx, campaign, locale)
+}
+
+// List lists campaigns. Donors and anonymous visitors only see public
+// campaigns; nazirs also see their own drafts when filtering by themselves.
+func (s *service) List(ctx context.Context, actor *domain.Actor, query *dto.ListCampaignsQuery) ([]*dto.CampaignResponse, int64, error) {
+	filter := &repository.ListFilter{
+		Type:       query.Type,
+		NazirID:    query.NazirID,
+		IsFeatured: query.IsFeatured,
+		IsUrgent:   query.IsUrgent,
+	}
+
+	seesAll := actor != nil && (actor.IsAdmin() || (query.NazirID != 0 && query.NazirID == actor.UserID))
+	switch {
+	case query.Status != "":
+		filter.Statuses = []domain.CampaignStatus{query.Status}
+		probe := &domain.Campaign{Status: query.Status}
+		if !seesAll && !probe.IsPublic() {
+			return nil, 0, errors.ErrForbidden
+		}
+	case !seesAll:
+		filter.Statuses = domain.PublicCampaignStatuses
+	}
+
+	campaigns, total, err := s.repo.List(ctx, filter, query.PerPage, (query.Page-1)*query.PerPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*dto.CampaignResponse, len(campaigns))
+	for i, campaign := range campaigns {
+		responses[i] = toResponse(campaign, nil)
+	}
+
+	if err := s.localizeCampaigns(ctx, query.Locale, responses...); err != nil {
+		return nil, 0, err
+	}
+
+	return responses, total, nil
+}
+
+// Search ranks campaigns by full-text relevance, urgency and momentum. The
+// same visibility rules as List apply: only admins search beyond public
+// campaigns.
+func (s *service) Search(ctx context.Context, actor *domain.Actor, query *dto.SearchCampaignsQuery) (*dto.SearchResponse, int64, error) {
+	filter := &repository.SearchFilter{
+		Query:       strings.TrimSpace(query.Query),
+		Status:      query.Status,
+		Type:        query.Type,
+		Location:    strings.TrimSpace(query.Location),
+		IsFeatured:  query.IsFeatured,
+		IsUrgent:    query.IsUrgent,
+		MinProgress: query.MinProgress,
+		MaxProgress: query.MaxProgress,
+	}
+
+	if actor == nil || !actor.IsAdmin() {
+		filter.Visible = domain.PublicCampaignStatuses
+		probe := &domain.Campaign{Status: query.Status}
+		if query.Status != "" && !probe.IsPublic() {
+			return nil, 0, errors.ErrForbidden
+		}
+	}
+
+	hits, total, err := s.repo.Search(ctx, filter, query.PerPage, (query.Page-1)*query.PerPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	facets, err := s.repo.SearchFacets(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]*dto.SearchResult, len(hits))
+	responses := make([]*dto.CampaignResponse, len(hits))
+	for i, hit := range hits {
+		responses[i] = toResponse(hit.Campaign, nil)
+		results[i] = &dto.SearchResult{
+			CampaignResponse: responses[i],
+			Score:            hit.Score,
+		}
+	}
+
+	if err := s.localizeCampaigns(ctx, query.Locale, responses...); err != nil {
+		return nil, 0, err
+	}
+
+	return &dto.SearchResponse{Results: results, Facets: facets}, total, nil
+}
+
+// GetMilestones gets the milestones of a visible campaign in the locale
+func (s *service) GetMilestones(ctx context.Context, actor *domain.Actor, campaignID int64, locale domain.Locale) ([]*domain.Milestone, error) {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView(actor, campaign) {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+
+	milestones, err := s.repo.GetMilestones(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.localizeMilestones(ctx, locale, milestones); err != nil {
+		return nil, err
+	}
+	return milestones, nil
+}
+
+// CreateMilestone adds a milestone to a campaign
+func (s *service) CreateMilestone(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.MilestoneRequest) (*domain.Milestone, error) {
+	campaign, err := s.authorize(ctx, actor, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateMilestone(campaign, req); err != nil {
+		return nil, err
+	}
+
+	milestone := &domain.Milestone{
+		CampaignID:   campaignID,
+		Title:        strings.TrimSpace(req.Title),
+		Description:  req.Description,
+		TargetAmount: req.TargetAmount,
+	}
+
+	if err := s.repo.CreateMilestone(ctx, milestone); err != nil {
+		return nil, err
+	}
+
+	return milestone, nil
+}
+
+// UpdateMilestone updates a milestone that has not been reached yet
+func (s *service) UpdateMilestone(ctx context.Context, actor *domain.Actor, campaignID, milestoneID int64, req *dto.MilestoneRequest) (*domain.Milestone, error) {
+	campaign, err := s.authorize(ctx, actor, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	milestone, err := s.repo.FindMilestoneByID(ctx, campaignID, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateMilestone(campaign, req); err != nil {
+		return nil, err
+	}
+
+	milestone.Title = strings.TrimSpace(req.Title)
+	milestone.Description = req.Description
+	milestone.TargetAmount = req.TargetAmount
+
+	if err := s.repo.UpdateMilestone(ctx, milestone); err != nil {
+		return nil, err
+	}
+
+	return milestone, nil
+}
+
+// DeleteMilestone removes a milestone that has not been reached yet
+func (s *service) DeleteMilestone(ctx context.Context, actor *domain.Actor, campaignID, milestoneID int64) error {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.FindMilestoneByID(ctx, campaignID, milestoneID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteMilestone(ctx, campaignID, milestoneID)
+}
+
+// RebuildTotals recomputes the totals of a campaign from its donations
+func (s *service) RebuildTotals(ctx context.Context, actor *domain.Actor, campaignID int64) (*repository.TotalsChange, error) {
+	if !actor.IsAdmin() {
+		return nil, errors.ErrForbidden
+	}
+
+	return s.totals.Rebuild(ctx, campaignID)
+}
+
+// RebuildAllTotals recomputes all campaign totals and returns the campaigns that drifted
+func (s *service) RebuildAllTotals(ctx context.Context, actor *domain.Actor) ([]*repository.TotalsChange, error) {
+	if !actor.IsAdmin() {
+		return nil, errors.ErrForbidden
+	}
+
+	return s.totals.RebuildAll(ctx)
+}
+
+// authorize loads a campaign and checks the actor may manage it
+func (s *service) authorize(ctx context.Context, actor *domain.Actor, id int64) (*domain.Campaign, error) {
+	campaign, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManage(actor, campaign) {
+		return nil, errors.New(errors.ErrCodeForbidden, "Only the nazir of this campaign can manage it", 403)
+	}
+
+	return campaign, nil
+}
+
+// withMilestones builds a campaign response including its milestones and
+// impact indicators
+func (s *service) withMilestones(ctx context.Context, campaign *domain.Campaign) (*dto.CampaignResponse, error) {
+	milestones, err := s.repo.GetMilestones(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	indicators, err := s.repo.GetImpactIndicators(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toResponse(campaign, milestones)
+	resp.Impact = toImpactResponses(indicators)
+	return resp, nil
+}
+
+// localized builds a campaign response including its milestones in the locale
+func (s *service) localized(ctx context.Context, campaign *domain.Campaign, locale domain.Locale) (*dto.CampaignResponse, error) {
+	resp, err := s.withMilestones(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.localizeCampaigns(ctx, locale, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// canManage checks if the actor is an admin or the owning nazir
+func canManage(actor *domain.Actor, campaign *domain.Campaign) bool {
+	if actor == nil {
+		return false
+	}
+	return actor.IsAdmin() || (actor.Role == domain.RoleNazir && campaign.NazirID == actor.UserID)
+}
+
+// canView checks if the actor may see the campaign
+func canView(actor *domain.Actor, campaign *domain.Campaign) bool {
+	return campaign.IsPublic() || canManage(actor, campaign)
+}
+
+// applySchedule parses and validates the campaign start and end dates
+func applySchedule(campaign *domain.Campaign, startDate, endDate string) error {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return errors.New(errors.ErrCodeValidation, "start_date must be a date (YYYY-MM-DD)", 400)
+	}
+	campaign.StartDate = start
+
+	if campaign.IsEndless {
+		campaign.EndDate = nil
+		return nil
+	}
+
+	if endDate == "" {
+		return errors.New(errors.ErrCodeValidation, "end_date is required unless the campaign is endless", 400)
+	}
+
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return errors.New(errors.ErrCodeValidation, "end_date must be a date (YYYY-MM-DD)", 400)
+	}
+	if end.Before(start) {
+		return errors.New(errors.ErrCodeValidation, "end_date must not be before start_date", 400)
+	}
+
+	campaign.EndDate = &end
+	return nil
+}
+
+// checkOverflow checks the fallback fund of a campaign accepting overflow:
+// an endless general campaign that does not overflow itself
+func (s *service) checkOverflow(ctx context.Context, campaign *domain.Campaign) error {
+	if !campaign.AcceptOverflow {
+		return nil
+	}
+
+	if campaign.OverflowCampaignID == nil {
+		return errors.New(errors.ErrCodeValidation, "overflow_campaign_id is required when accepting overflow", 400)
+	}
+	if *campaign.OverflowCampaignID == campaign.ID {
+		return errors.New(errors.ErrCodeValidation, "overflow_campaign_id must be another campaign", 400)
+	}
+
+	fund, err := s.repo.FindByID(ctx, *campaign.OverflowCampaignID)
+	if err != nil {
+		if errors.GetErrorCode(err) == errors.ErrCodeNotFound {
+			return errors.New(errors.ErrCodeValidation, "overflow_campaign_id is not a campaign", 400)
+		}
+		return err
+	}
+
+	if fund.Type != domain.CampaignTypeGeneral || !fund.IsEndless || fund.AcceptOverflow || fund.IsClosed() {
+		return errors.New(errors.ErrCodeValidation, "overflow_campaign_id must be an open, endless general fund", 400)
+	}
+	if (fund.TenantID == nil) != (campaign.TenantID == nil) || (fund.TenantID != nil && *fund.TenantID != *campaign.TenantID) {
+		return errors.New(errors.ErrCodeValidation, "overflow_campaign_id must belong to the same tenant", 400)
+	}
+	return nil
+}
+
+// validateMilestone checks a milestone fits within the campaign goal
+func validateMilestone(campaign *domain.Campaign, req *dto.MilestoneRequest) error {
+	if campaign.IsClosed() {
+		return errors.New(errors.ErrCodeConflict, "Completed or cancelled campaigns cannot be changed", 409)
+	}
+	if req.TargetAmount > campaign.GoalAmount {
+		return errors.New(errors.ErrCodeValidation, "target_amount must not exceed the campaign goal", 400)
+	}
+	return nil
+}
+
+// withoutSlug removes the campaign's own slug from the taken list
+func withoutSlug(taken []string, own string) []string {
+	filtered := taken[:0]
+	for _, slug := range taken {
+		if slug != own {
+			filtered = append(filtered, slug)
+		}
+	}
+	return filtered
+}
+
+func toResponse(campaign *domain.Campaign, milestones []*domain.Milestone) *dto.CampaignResponse {
+	return &dto.CampaignResponse{
+		Campaign:   campaign,
+		Progress:   campaign.Progress(),
+		Milestones: milestones,
+	}
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
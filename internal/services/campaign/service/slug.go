@@ -0,0 +1,84 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxSlugLength keeps campaign URLs readable
+const maxSlugLength = 80
+
+// reservedSlugs are route segments that a campaign or fundraiser slug must not shadow
+var reservedSlugs = map[string]bool{
+	"mine":      true,
+	"reviews":   true,
+	"search":    true,
+	"templates": true,
+	"totals":    true,
+}
+
+// foldAccents maps accented Latin letters to their ASCII base letter
+var foldAccents = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a",
+	"è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "ý", "y", "ÿ", "y",
+)
+
+// slugify turns a title into a URL slug, e.g. "Wakaf Masjid Al-Ikhlas" to
+// "wakaf-masjid-al-ikhlas". Slugs are never purely numeric so they cannot be
+// confused with campaign IDs, nor equal to a reserved route segment.
+func slugify(title string) string {
+	title = foldAccents.Replace(strings.ToLower(title))
+
+	var b strings.Builder
+	hyphen := false
+	for _, r := range title {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		if !hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+			hyphen = true
+		}
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+		if i := strings.LastIndexByte(slug, '-'); i > maxSlugLength/2 {
+			slug = slug[:i]
+		}
+	}
+
+	if slug == "" {
+		return "wakaf"
+	}
+	if _, err := strconv.ParseInt(slug, 10, 64); err == nil || reservedSlugs[slug] {
+		return "wakaf-" + slug
+	}
+	return slug
+}
+
+// uniqueSlug appends the lowest free numeric suffix to base
+func uniqueSlug(base string, taken []string) string {
+	used := make(map[string]bool, len(taken))
+	for _, slug := range taken {
+		used[slug] = true
+	}
+
+	if !used[base] {
+		return base
+	}
+	for n := 2; ; n++ {
+		candidate := base + "-" + strconv.Itoa(n)
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// GetTemplates lists the templates the actor may use: their own and those
+// shared within their tenant. Admins see every template.
+func (s *service) GetTemplates(ctx context.Context, actor *domain.Actor, page, perPage int) ([]*domain.CampaignTemplate, int64, error) {
+	if actor.IsAdmin() {
+		return s.repo.GetTemplates(ctx, 0, nil, perPage, (page-1)*perPage)
+	}
+	return s.repo.GetTemplates(ctx, actor.UserID, actor.TenantID, perPage, (page-1)*perPage)
+}
+
+// GetTemplate gets a template the actor may use
+func (s *service) GetTemplate(ctx context.Context, actor *domain.Actor, templateID int64) (*domain.CampaignTemplate, error) {
+	template, err := s.repo.FindTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canUseTemplate(actor, template) {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign template not found", 404)
+	}
+	return template, nil
+}
+
+// CreateTemplate creates a template owned by the actor
+func (s *service) CreateTemplate(ctx context.Context, actor *domain.Actor, req *dto.TemplateRequest) (*domain.CampaignTemplate, error) {
+	if req.IsShared && !actor.IsAdmin() {
+		return nil, errors.New(errors.ErrCodeForbidden, "Only admins can share templates", 403)
+	}
+
+	template := &domain.CampaignTemplate{OwnerID: actor.UserID, TenantID: actor.TenantID}
+	if err := applyTemplate(template, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// UpdateTemplate replaces a template; campaigns already made from it keep
+// what they copied
+func (s *service) UpdateTemplate(ctx context.Context, actor *domain.Actor, templateID int64, req *dto.TemplateRequest) (*domain.CampaignTemplate, error) {
+	template, err := s.authorizeTemplate(ctx, actor, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.IsShared != template.IsShared && !actor.IsAdmin() {
+		return nil, errors.New(errors.ErrCodeForbidden, "Only admins can share templates", 403)
+	}
+
+	if err := applyTemplate(template, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// DeleteTemplate deletes a template
+func (s *service) DeleteTemplate(ctx context.Context, actor *domain.Actor, templateID int64) error {
+	if _, err := s.authorizeTemplate(ctx, actor, templateID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteTemplate(ctx, templateID)
+}
+
+// SaveAsTemplate saves a campaign's content, milestones and budget plan as a
+// template; its duration is kept so new campaigns can derive their end date
+func (s *service) SaveAsTemplate(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.SaveTemplateRequest) (*domain.CampaignTemplate, error) {
+	campaign, err := s.authorize(ctx, actor, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.IsShared && !actor.IsAdmin() {
+		return nil, errors.New(errors.ErrCodeForbidden, "Only admins can share templates", 403)
+	}
+
+	milestones, err := s.repo.GetMilestones(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.repo.GetBudgetLines(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = campaign.Title
+	}
+
+	template := &domain.CampaignTemplate{
+		Name:         name,
+		Title:        campaign.Title,
+		Description:  campaign.Description,
+		ShortDesc:    campaign.ShortDesc,
+		Type:         campaign.Type,
+		GoalAmount:   campaign.GoalAmount,
+		ImageURL:     campaign.ImageURL,
+		VideoURL:     campaign.VideoURL,
+		Location:     campaign.Location,
+		IsEndless:    campaign.IsEndless,
+		DurationDays: durationDays(campaign),
+		Milestones:   make([]domain.TemplateMilestone, len(milestones)),
+		BudgetLines:  make([]domain.TemplateBudgetLine, len(lines)),
+		OwnerID:      actor.UserID,
+		IsShared:     req.IsShared,
+		TenantID:     actor.TenantID,
+	}
+
+	for i, milestone := range milestones {
+		template.Milestones[i] = domain.TemplateMilestone{
+			Title:        milestone.Title,
+			Description:  milestone.Description,
+			TargetAmount: milestone.TargetAmount,
+		}
+	}
+	for i, line := range lines {
+		template.BudgetLines[i] = domain.TemplateBudgetLine{
+			Category:      line.Category,
+			Name:          line.Name,
+			Description:   line.Description,
+			PlannedAmount: line.PlannedAmount,
+		}
+	}
+
+	if err := s.repo.CreateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// CreateFromTemplate starts a draft campaign from a template. Without an end
+// date the template's duration is applied to the start date.
+func (s *service) CreateFromTemplate(ctx context.Context, actor *domain.Actor, templateID int64, req *dto.CloneCampaignRequest) (*dto.CampaignResponse, error) {
+	template, err := s.GetTemplate(ctx, actor, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := &domain.Campaign{
+		Title:       template.Title,
+		Description: template.Description,
+		ShortDesc:   template.ShortDesc,
+		Type:        template.Type,
+		Status:      domain.CampaignStatusDraft,
+		GoalAmount:  template.GoalAmount,
+		NazirID:     actor.UserID,
+		ImageURL:    template.ImageURL,
+		VideoURL:    template.VideoURL,
+		Location:    template.Location,
+		IsEndless:   template.IsEndless,
+		TenantID:    actor.TenantID,
+	}
+
+	milestones := make([]*domain.Milestone, len(template.Milestones))
+	for i, milestone := range template.Milestones {
+		milestones[i] = &domain.Milestone{
+			Title:        milestone.Title,
+			Description:  milestone.Description,
+			TargetAmount: milestone.TargetAmount,
+		}
+	}
+
+	lines := make([]*domain.BudgetLine, len(template.BudgetLines))
+	for i, line := range template.BudgetLines {
+		lines[i] = &domain.BudgetLine{
+			Category:      line.Category,
+			Name:          line.Name,
+			Description:   line.Description,
+			PlannedAmount: line.PlannedAmount,
+		}
+	}
+
+	return s.createDraft(ctx, actor, campaign, template.DurationDays, req, milestones, lines)
+}
+
+// Clone copies a campaign into a new draft with fresh totals. Milestones
+// start unreached and budget lines unspent; without an end date the source
+// campaign's duration is kept. Updates, donations and fundraisers stay with
+// the source campaign.
+func (s *service) Clone(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.CloneCampaignRequest) (*dto.CampaignResponse, error) {
+	source, err := s.authorize(ctx, actor, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceMilestones, err := s.repo.GetMilestones(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceLines, err := s.repo.GetBudgetLines(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := &domain.Campaign{
+		Title:       source.Title,
+		Description: source.Description,
+		ShortDesc:   source.ShortDesc,
+		Type:        source.Type,
+		Status:      domain.CampaignStatusDraft,
+		GoalAmount:  source.GoalAmount,
+		NazirID:     source.NazirID,
+		ImageURL:    source.ImageURL,
+		VideoURL:    source.VideoURL,
+		Location:    source.Location,
+		IsEndless:   source.IsEndless,
+		IsUrgent:    source.IsUrgent,
+		TenantID:    source.TenantID,
+
+		AcceptOverflow:     source.AcceptOverflow,
+		OverflowCampaignID: source.OverflowCampaignID,
+	}
+
+	milestones := make([]*domain.Milestone, len(sourceMilestones))
+	for i, milestone := range sourceMilestones {
+		milestones[i] = &domain.Milestone{
+			Title:        milestone.Title,
+			Description:  milestone.Description,
+			TargetAmount: milestone.TargetAmount,
+		}
+	}
+
+	lines := make([]*domain.BudgetLine, len(sourceLines))
+	for i, line := range sourceLines {
+		lines[i] = &domain.BudgetLine{
+			Category:      line.Category,
+			Name:          line.Name,
+			Description:   line.Description,
+			PlannedAmount: line.PlannedAmount,
+		}
+	}
+
+	return s.createDraft(ctx, actor, campaign, durationDays(source), req, milestones, lines)
+}
+
+// createDraft applies the request's title, goal, nazir and dates to a copied
+// campaign and creates it together with its milestones and budget lines
+func (s *service) createDraft(ctx context.Context, actor *domain.Actor, campaign *domain.Campaign, duration int, req *dto.CloneCampaignRequest, milestones []*domain.Milestone, lines []*domain.BudgetLine) (*dto.CampaignResponse, error) {
+	if title := strings.TrimSpace(req.Title); title != "" {
+		campaign.Title = title
+	}
+	if req.GoalAmount != 0 {
+		campaign.GoalAmount = req.GoalAmount
+	}
+
+	if req.NazirID != 0 {
+		if !actor.IsAdmin() {
+			return nil, errors.New(errors.ErrCodeForbidden, "Only admins can assign another nazir", 403)
+		}
+		campaign.NazirID = req.NazirID
+	}
+
+	endDate := req.EndDate
+	if endDate == "" && !campaign.IsEndless && duration > 0 {
+		start, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return nil, errors.New(errors.ErrCodeValidation, "start_date must be a date (YYYY-MM-DD)", 400)
+		}
+		endDate = start.AddDate(0, 0, duration).Format("2006-01-02")
+	}
+
+	if err := applySchedule(campaign, req.StartDate, endDate); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOverflow(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	for _, milestone := range milestones {
+		if milestone.TargetAmount > campaign.GoalAmount {
+			return nil, errors.New(errors.ErrCodeValidation, "goal_amount must not be below a milestone target", 400)
+		}
+	}
+	for i, line := range lines {
+		line.Position = i + 1
+	}
+
+	base := slugify(campaign.Title)
+	for attempt := 1; ; attempt++ {
+		taken, err := s.repo.GetSlugsWithPrefix(ctx, base)
+		if err != nil {
+			return nil, err
+		}
+		campaign.Slug = uniqueSlug(base, taken)
+
+		err = s.repo.CreateWithPlan(ctx, campaign, milestones, lines)
+		if err == nil {
+			break
+		}
+		if errors.GetErrorCode(err) != errors.ErrCodeDuplicateEntry || attempt == slugAttempts {
+			return nil, err
+		}
+	}
+
+	return toResponse(campaign, milestones), nil
+}
+
+// authorizeTemplate loads a template and checks the actor may edit it
+func (s *service) authorizeTemplate(ctx context.Context, actor *domain.Actor, templateID int64) (*domain.CampaignTemplate, error) {
+	template, err := s.GetTemplate(ctx, actor, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !actor.IsAdmin() && template.OwnerID != actor.UserID {
+		return nil, errors.New(errors.ErrCodeForbidden, "Only the owner of this template can change it", 403)
+	}
+	return template, nil
+}
+
+// applyTemplate copies request fields onto a template
+func applyTemplate(template *domain.CampaignTemplate, req *dto.TemplateRequest) error {
+	for i, milestone := range req.Milestones {
+		if milestone.TargetAmount > req.GoalAmount {
+			return errors.New(errors.ErrCodeValidation, fmt.Sprintf("milestones[%d].target_amount must not exceed the goal", i), 400)
+		}
+	}
+
+	template.Name = strings.TrimSpace(req.Name)
+	template.Title = strings.TrimSpace(req.Title)
+	template.Description = req.Description
+	template.ShortDesc = req.ShortDesc
+	template.Type = req.Type
+	template.GoalAmount = req.GoalAmount
+	template.ImageURL = req.ImageURL
+	template.VideoURL = req.VideoURL
+	template.Location = req.Location
+	template.IsEndless = req.IsEndless
+	template.DurationDays = req.DurationDays
+	template.Milestones = req.Milestones
+	template.BudgetLines = req.BudgetLines
+	template.IsShared = req.IsShared
+
+	if template.IsEndless {
+		template.DurationDays = 0
+	}
+	if template.Milestones == nil {
+		template.Milestones = []domain.TemplateMilestone{}
+	}
+	if template.BudgetLines == nil {
+		template.BudgetLines = []domain.TemplateBudgetLine{}
+	}
+	return nil
+}
+
+// canUseTemplate checks if the actor owns the template or it is shared
+// within the actor's tenant
+func canUseTemplate(actor *domain.Actor, template *domain.CampaignTemplate) bool {
+	if actor.IsAdmin() || template.OwnerID == actor.UserID {
+		return true
+	}
+	return template.IsShared && sameTenant(template.TenantID, actor.TenantID)
+}
+
+// sameTenant compares optional tenant IDs
+func sameTenant(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// durationDays is the number of days from a campaign's start to its end
+// date, zero for endless campaigns
+func durationDays(campaign *domain.Campaign) int {
+	if campaign.IsEndless || campaign.EndDate == nil {
+		return 0
+	}
+	return int(campaign.EndDate.Sub(campaign.StartDate).Hours() / 24)
+}
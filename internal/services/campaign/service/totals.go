@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/events"
+)
+
+// catchUpBatch bounds the donations applied per catch-up pass
+const catchUpBatch = 500
+
+// Totals keeps campaign CurrentAmount, DonorCount and milestones in step with
+// successful donations. Donations are applied at most once, so the event
+// handler and the catch-up loop can both see the same donation.
+type Totals struct {
+	repo      repository.Repository
+	publisher events.Publisher
+}
+
+// NewTotals creates a new campaign totals tracker
+func NewTotals(repo repository.Repository, publisher events.Publisher) *Totals {
+	return &Totals{
+		repo:      repo,
+		publisher: publisher,
+	}
+}
+
+// HandleDonationSucceeded applies a donation.succeeded event
+func (t *Totals) HandleDonationSucceeded(ctx context.Context, event events.Event) error {
+	payload, ok := event.Payload.(events.DonationSucceededPayload)
+	if !ok {
+		return nil
+	}
+
+	return t.apply(ctx, &repository.DonationRef{
+		ID:         payload.DonationID,
+		CampaignID: payload.CampaignID,
+		Amount:     payload.Amount,
+		DonorKey:   repository.DonorKey(payload.UserID, payload.DonorEmail, payload.DonationID),
+		UserID:     payload.UserID,
+		DonorEmail: payload.DonorEmail,
+	})
+}
+
+// CatchUp applies successful donations whose events were missed, e.g. when
+// the payment service runs in another process
+func (t *Totals) CatchUp(ctx context.Context) (int, error) {
+	applied := 0
+	for {
+		donations, err := t.repo.GetUnappliedDonations(ctx, catchUpBatch)
+		if err != nil {
+			return applied, err
+		}
+
+		for _, donation := range donations {
+			if err := t.apply(ctx, donation); err != nil {
+				return applied, err
+			}
+			applied++
+		}
+
+		if len(donations) < catchUpBatch {
+			return applied, nil
+		}
+	}
+}
+
+// Run catches up on an interval until ctx is cancelled
+func (t *Totals) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := t.CatchUp(ctx); err != nil {
+			log.Printf("campaign totals: catch-up failed: %v", err)
+		} else if n > 0 {
+			log.Printf("campaign totals: applied %d missed donation(s)", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rebuild recomputes the totals of a campaign from its donations
+func (t *Totals) Rebuild(ctx context.Context, campaignID int64) (*repository.TotalsChange, error) {
+	change, reached, err := t.repo.RebuildTotals(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	t.publishReached(ctx, change.CurrentAmount, reached)
+	return change, nil
+}
+
+// RebuildAll recomputes the totals of every campaign and returns those that drifted
+func (t *Totals) RebuildAll(ctx context.Context) ([]*repository.TotalsChange, error) {
+	ids, err := t.repo.GetCampaignIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	drifted := make([]*repository.TotalsChange, 0)
+	for _, id := range ids {
+		change, err := t.Rebuild(ctx, id)
+		if err != nil {
+			return drifted, err
+		}
+		if change.PreviousAmount != change.CurrentAmount || change.PreviousDonorCount != change.DonorCount {
+			drifted = append(drifted, change)
+		}
+	}
+
+	return drifted, nil
+}
+
+// apply adds one donation to its campaign and announces reached milestones
+func (t *Totals) apply(ctx context.Context, donation *repository.DonationRef) error {
+	applied, reached, err := t.repo.ApplyDonation(ctx, donation)
+	if err != nil || !applied {
+		return err
+	}
+
+	if len(reached) > 0 {
+		campaign, err := t.repo.FindByID(ctx, donation.CampaignID)
+		if err != nil {
+			return err
+		}
+		t.publishReached(ctx, campaign.CurrentAmount, reached)
+	}
+
+	return nil
+}
+
+// publishReached emits a milestone reached event per completed milestone
+func (t *Totals) publishReached(ctx context.Context, currentAmount int64, reached []*domain.Milestone) {
+	for _, milestone := range reached {
+		t.publisher.Publish(ctx, events.Event{
+			Type: events.MilestoneReached,
+			Key:  strconv.FormatInt(milestone.ID, 10),
+			Payload: events.MilestoneReachedPayload{
+				CampaignID:    milestone.CampaignID,
+				MilestoneID:   milestone.ID,
+				Title:         milestone.Title,
+				TargetAmount:  milestone.TargetAmount,
+				CurrentAmount: currentAmount,
+				CompletedAt:   *milestone.CompletedAt,
+			},
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// coveragePageSize is the number of updates read at a time for the
+// translation coverage report
+const coveragePageSize = 100
+
+// GetTranslations gets a campaign's translations and which locales each of
+// the campaign, its milestones and its updates is still missing
+func (s *service) GetTranslations(ctx context.Context, actor *domain.Actor, campaignID int64) (*dto.TranslationsResponse, error) {
+	campaign, err := s.authorize(ctx, actor, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, err := s.repo.GetTranslations(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	milestones, err := s.repo.GetMilestones(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []*domain.CampaignUpdate
+	for offset := 0; ; offset += coveragePageSize {
+		page, _, err := s.repo.GetUpdates(ctx, campaignID, true, coveragePageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, page...)
+		if len(page) < coveragePageSize {
+			break
+		}
+	}
+
+	byEntity := groupTranslations(translations)
+	coverage := []*dto.TranslationCoverage{
+		translationCoverage(domain.TranslationEntityCampaign, campaign.ID, byEntity, campaign.Title, campaign.ShortDesc, campaign.Description),
+	}
+	for _, milestone := range milestones {
+		coverage = append(coverage, translationCoverage(domain.TranslationEntityMilestone, milestone.ID, byEntity, milestone.Title, "", milestone.Description))
+	}
+	for _, update := range updates {
+		coverage = append(coverage, translationCoverage(domain.TranslationEntityUpdate, update.ID, byEntity, update.Title, "", update.Body))
+	}
+
+	resp := &dto.TranslationsResponse{
+		CampaignID:   campaign.ID,
+		Locales:      translationLocales(),
+		Missing:      make([]domain.Locale, 0),
+		Coverage:     coverage,
+		Translations: translations,
+	}
+	for _, locale := range resp.Locales {
+		for _, entity := range coverage {
+			if containsLocale(entity.Missing, locale) || containsLocale(entity.Incomplete, locale) {
+				resp.Missing = append(resp.Missing, locale)
+				break
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// SaveTranslation creates or replaces the translation of the campaign, or of
+// one of its milestones or updates, into a locale
+func (s *service) SaveTranslation(ctx context.Context, actor *domain.Actor, campaignID int64, entityType domain.TranslationEntity, entityID int64, locale domain.Locale, req *dto.TranslationRequest) (*domain.Translation, error) {
+	if err := s.authorizeTranslation(ctx, actor, campaignID, entityType, entityID, locale); err != nil {
+		return nil, err
+	}
+
+	translation := &domain.Translation{
+		CampaignID:  campaignID,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		Locale:      locale,
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		UpdatedBy:   actor.UserID,
+	}
+	if entityType == domain.TranslationEntityCampaign {
+		translation.ShortDesc = req.ShortDesc
+	}
+
+	if err := s.repo.SaveTranslation(ctx, translation); err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+// DeleteTranslation removes a translation; readers of that locale fall back
+// to the next locale in the chain
+func (s *service) DeleteTranslation(ctx context.Context, actor *domain.Actor, campaignID int64, entityType domain.TranslationEntity, entityID int64, locale domain.Locale) error {
+	if err := s.authorizeTranslation(ctx, actor, campaignID, entityType, entityID, locale); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteTranslation(ctx, entityType, entityID, locale)
+}
+
+// authorizeTranslation checks the actor manages the campaign, the entity
+// belongs to it and the locale is one content is translated into
+func (s *service) authorizeTranslation(ctx context.Context, actor *domain.Actor, campaignID int64, entityType domain.TranslationEntity, entityID int64, locale domain.Locale) error {
+	if !locale.IsSupported() || locale == domain.DefaultLocale {
+		return errors.New(errors.ErrCodeValidation, "locale must be one of "+joinLocales(translationLocales()), 400)
+	}
+
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return err
+	}
+
+	switch entityType {
+	case domain.TranslationEntityMilestone:
+		_, err := s.repo.FindMilestoneByID(ctx, campaignID, entityID)
+		return err
+	case domain.TranslationEntityUpdate:
+		_, err := s.repo.FindUpdateByID(ctx, campaignID, entityID)
+		return err
+	}
+	return nil
+}
+
+// localizeCampaigns shows campaigns and their milestones in the locale,
+// field by field along its fallback chain
+func (s *service) localizeCampaigns(ctx context.Context, locale domain.Locale, responses ...*dto.CampaignResponse) error {
+	ids := make([]int64, len(responses))
+	var milestones []*domain.Milestone
+	for i, resp := range responses {
+		ids[i] = resp.ID
+		milestones = append(milestones, resp.Milestones...)
+	}
+
+	translations, err := s.localizer(ctx, domain.TranslationEntityCampaign, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, resp := range responses {
+		resp.Title, resp.Locale = translations.text(locale, resp.ID, resp.Title, translationTitle)
+		resp.ShortDesc, _ = translations.text(locale, resp.ID, resp.ShortDesc, translationShortDesc)
+		resp.Description, _ = translations.text(locale, resp.ID, resp.Description, translationDescription)
+		resp.Direction = resp.Locale.Direction()
+		resp.Locales = translations.available(resp.ID)
+	}
+
+	return s.localizeMilestones(ctx, locale, milestones)
+}
+
+// localizeMilestones shows milestones in the locale
+func (s *service) localizeMilestones(ctx context.Context, locale domain.Locale, milestones []*domain.Milestone) error {
+	if locale == domain.DefaultLocale || len(milestones) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(milestones))
+	for i, milestone := range milestones {
+		ids[i] = milestone.ID
+	}
+
+	translations, err := s.localizer(ctx, domain.TranslationEntityMilestone, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, milestone := range milestones {
+		milestone.Title, _ = translations.text(locale, milestone.ID, milestone.Title, translationTitle)
+		milestone.Description, _ = translations.text(locale, milestone.ID, milestone.Description, translationDescription)
+	}
+	return nil
+}
+
+// localizeUpdates shows updates, and the campaign titles of a donor feed, in
+// the locale
+func (s *service) localizeUpdates(ctx context.Context, locale domain.Locale, updates []*domain.CampaignUpdate) error {
+	if locale == domain.DefaultLocale || len(updates) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(updates))
+	campaignIDs := make([]int64, len(updates))
+	for i, update := range updates {
+		ids[i] = update.ID
+		campaignIDs[i] = update.CampaignID
+	}
+
+	translations, err := s.localizer(ctx, domain.TranslationEntityUpdate, ids)
+	if err != nil {
+		return err
+	}
+
+	campaigns, err := s.localizer(ctx, domain.TranslationEntityCampaign, campaignIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, update := range updates {
+		update.Title, _ = translations.text(locale, update.ID, update.Title, translationTitle)
+		update.Body, _ = translations.text(locale, update.ID, update.Body, translationDescription)
+		if update.CampaignTitle != "" {
+			update.CampaignTitle, _ = campaigns.text(locale, update.CampaignID, update.CampaignTitle, translationTitle)
+		}
+	}
+	return nil
+}
+
+// localizer loads the translations of entities of one type
+func (s *service) localizer(ctx context.Context, entityType domain.TranslationEntity, ids []int64) (localizer, error) {
+	translations, err := s.repo.GetEntityTranslations(ctx, entityType, ids, translationLocales())
+	if err != nil {
+		return nil, err
+	}
+
+	return localizer(groupTranslations(translations)[entityType]), nil
+}
+
+// localizer holds translations by entity ID and locale
+type localizer map[int64]map[domain.Locale]*domain.Translation
+
+// text picks the first non-empty translation of a field along the locale's
+// fallback chain, ending with the original text in the default locale
+func (l localizer) text(locale domain.Locale, id int64, original string, field func(*domain.Translation) string) (string, domain.Locale) {
+	for _, candidate := range locale.Fallbacks() {
+		if candidate == domain.DefaultLocale {
+			break
+		}
+		if translation := l[id][candidate]; translation != nil && field(translation) != "" {
+			return field(translation), candidate
+		}
+	}
+	return original, domain.DefaultLocale
+}
+
+// available lists the default locale and the locales an entity is translated into
+func (l localizer) available(id int64) []domain.Locale {
+	locales := []domain.Locale{domain.DefaultLocale}
+	for _, locale := range translationLocales() {
+		if l[id][locale] != nil {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+func translationTitle(t *domain.Translation) string       { return t.Title }
+func translationShortDesc(t *domain.Translation) string   { return t.ShortDesc }
+func translationDescription(t *domain.Translation) string { return t.Description }
+
+// groupTranslations indexes translations by entity type, entity ID and locale
+func groupTranslations(translations []*domain.Translation) map[domain.TranslationEntity]map[int64]map[domain.Locale]*domain.Translation {
+	grouped := make(map[domain.TranslationEntity]map[int64]map[domain.Locale]*domain.Translation)
+	for _, translation := range translations {
+		if grouped[translation.EntityType] == nil {
+			grouped[translation.EntityType] = make(map[int64]map[domain.Locale]*domain.Translation)
+		}
+		byID := grouped[translation.EntityType]
+		if byID[translation.EntityID] == nil {
+			byID[translation.EntityID] = make(map[domain.Locale]*domain.Translation)
+		}
+		byID[translation.EntityID][translation.Locale] = translation
+	}
+	return grouped
+}
+
+// translationCoverage reports the locales an entity is missing. A
+// translation is incomplete when a field written in the original is empty.
+func translationCoverage(entityType domain.TranslationEntity, id int64, grouped map[domain.TranslationEntity]map[int64]map[domain.Locale]*domain.Translation, title, shortDesc, description string) *dto.TranslationCoverage {
+	coverage := &dto.TranslationCoverage{
+		EntityType: entityType,
+		EntityID:   id,
+		Title:      title,
+		Translated: make([]domain.Locale, 0),
+		Missing:    make([]domain.Locale, 0),
+		Incomplete: make([]domain.Locale, 0),
+	}
+
+	for _, locale := range translationLocales() {
+		translation := grouped[entityType][id][locale]
+		switch {
+		case translation == nil:
+			coverage.Missing = append(coverage.Missing, locale)
+		case translation.Title == "" || (shortDesc != "" && translation.ShortDesc == "") ||
+			(description != "" && translation.Description == ""):
+			coverage.Incomplete = append(coverage.Incomplete, locale)
+		default:
+			coverage.Translated = append(coverage.Translated, locale)
+		}
+	}
+	return coverage
+}
+
+// translationLocales lists the supported locales other than the default
+func translationLocales() []domain.Locale {
+	locales := make([]domain.Locale, 0, len(domain.SupportedLocales))
+	for _, locale := range domain.SupportedLocales {
+		if locale != domain.DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+func containsLocale(locales []domain.Locale, locale domain.Locale) bool {
+	for _, l := range locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+func joinLocales(locales []domain.Locale) string {
+	codes := make([]string, len(locales))
+	for i, locale := range locales {
+		codes[i] = string(locale)
+	}
+	return strings.Join(codes, ", ")
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/services/campaign/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/notify"
+)
+
+// excerptLength bounds the update text quoted in notifications
+const excerptLength = 300
+
+// GetUpdates gets the updates of a visible campaign; its managers also see drafts
+func (s *service) GetUpdates(ctx context.Context, actor *domain.Actor, campaignID int64, locale domain.Locale, page, perPage int) ([]*domain.CampaignUpdate, int64, error) {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if !canView(actor, campaign) {
+		return nil, 0, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+
+	updates, total, err := s.repo.GetUpdates(ctx, campaignID, canManage(actor, campaign), perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.localizeUpdates(ctx, locale, updates); err != nil {
+		return nil, 0, err
+	}
+	return updates, total, nil
+}
+
+// GetUpdate gets a published update, or a draft for the campaign managers
+func (s *service) GetUpdate(ctx context.Context, actor *domain.Actor, campaignID, updateID int64, locale domain.Locale) (*domain.CampaignUpdate, error) {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	update, err := s.repo.FindUpdateByID(ctx, campaignID, updateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManage(actor, campaign) && !(campaign.IsPublic() && update.IsPublished()) {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign update not found", 404)
+	}
+
+	if err := s.localizeUpdates(ctx, locale, []*domain.CampaignUpdate{update}); err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+// CreateUpdate creates a draft update, publishing it at once when asked
+func (s *service) CreateUpdate(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.CampaignUpdateRequest) (*domain.CampaignUpdate, error) {
+	campaign, err := s.authorize(ctx, actor, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Publish && !campaign.IsPublic() {
+		return nil, errors.New(errors.ErrCodeConflict, "Updates can only be published on public campaigns", 409)
+	}
+
+	update := &domain.CampaignUpdate{
+		CampaignID: campaignID,
+		Status:     domain.CampaignUpdateStatusDraft,
+		AuthorID:   actor.UserID,
+	}
+	if err := s.applyUpdate(ctx, update, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateUpdate(ctx, update); err != nil {
+		return nil, err
+	}
+
+	if req.Publish {
+		if err := s.publish(ctx, campaign, update); err != nil {
+			return nil, err
+		}
+	}
+
+	return update, nil
+}
+
+// UpdateUpdate edits an update. Editing a published update does not notify
+// subscribers again.
+func (s *service) UpdateUpdate(ctx context.Context, actor *domain.Actor, campaignID, updateID int64, req *dto.CampaignUpdateRequest) (*domain.CampaignUpdate, error) {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return nil, err
+	}
+
+	update, err := s.repo.FindUpdateByID(ctx, campaignID, updateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyUpdate(ctx, update, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateUpdate(ctx, update); err != nil {
+		return nil, err
+	}
+
+	return update, nil
+}
+
+// DeleteUpdate deletes a draft update
+func (s *service) DeleteUpdate(ctx context.Context, actor *domain.Actor, campaignID, updateID int64) error {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteUpdate(ctx, campaignID, updateID)
+}
+
+// PublishUpdate publishes a draft update and notifies the campaign subscribers
+func (s *service) PublishUpdate(ctx context.Context, actor *domain.Actor, campaignID, updateID int64) (*domain.CampaignUpdate, error) {
+	campaign, err := s.authorize(ctx, actor, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	update, err := s.repo.FindUpdateByID(ctx, campaignID, updateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.publish(ctx, campaign, update); err != nil {
+		return nil, err
+	}
+
+	return update, nil
+}
+
+// GetFeed gets the published updates of every campaign the actor has donated to
+func (s *service) GetFeed(ctx context.Context, actor *domain.Actor, locale domain.Locale, page, perPage int) ([]*domain.CampaignUpdate, int64, error) {
+	updates, total, err := s.repo.GetDonorFeed(ctx, repository.DonorKey(actor.UserID, "", 0), perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.localizeUpdates(ctx, locale, updates); err != nil {
+		return nil, 0, err
+	}
+	return updates, total, nil
+}
+
+// GetSubscription gets the actor's subscription to a campaign
+func (s *service) GetSubscription(ctx context.Context, actor *domain.Actor, campaignID int64) (*domain.CampaignSubscription, error) {
+	return s.repo.FindSubscription(ctx, campaignID, repository.DonorKey(actor.UserID, "", 0))
+}
+
+// UpdateSubscription subscribes the actor to a visible campaign, changes the
+// channel, or unsubscribes. WhatsApp falls back to email when the account
+// has no phone number.
+func (s *service) UpdateSubscription(ctx context.Context, actor *domain.Actor, campaignID int64, req *dto.SubscriptionRequest) (*domain.CampaignSubscription, error) {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView(actor, campaign) {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+
+	userID := actor.UserID
+	subscription := &domain.CampaignSubscription{
+		CampaignID: campaignID,
+		DonorKey:   repository.DonorKey(userID, "", 0),
+		UserID:     &userID,
+		Channel:    req.Channel,
+	}
+	if !req.Subscribed {
+		now := time.Now()
+		subscription.UnsubscribedAt = &now
+	}
+
+	if err := s.repo.SaveSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindSubscription(ctx, campaignID, subscription.DonorKey)
+}
+
+// Unsubscribe ends a subscription from the token in a notification link
+func (s *service) Unsubscribe(ctx context.Context, req *dto.UnsubscribeRequest) error {
+	return s.repo.Unsubscribe(ctx, req.Token)
+}
+
+// applyUpdate copies request fields onto update and checks the linked
+// milestone and disbursement
+func (s *service) applyUpdate(ctx context.Context, update *domain.CampaignUpdate, req *dto.CampaignUpdateRequest) error {
+	if req.MilestoneID != nil {
+		if _, err := s.repo.FindMilestoneByID(ctx, update.CampaignID, *req.MilestoneID); err != nil {
+			if errors.GetErrorCode(err) == errors.ErrCodeNotFound {
+				return errors.New(errors.ErrCodeValidation, "milestone_id is not a milestone of this campaign", 400)
+			}
+			return err
+		}
+	}
+
+	if req.DisbursementID != nil {
+		disbursement, err := s.repo.FindDisbursement(ctx, update.CampaignID, *req.DisbursementID)
+		if err != nil && errors.GetErrorCode(err) != errors.ErrCodeNotFound {
+			return err
+		}
+		if err != nil || !disbursement.IsPosted() {
+			return errors.New(errors.ErrCodeValidation, "disbursement_id is not a posted disbursement of this campaign", 400)
+		}
+	}
+
+	update.Title = strings.TrimSpace(req.Title)
+	update.Body = req.Body
+	update.Images = req.Images
+	if update.Images == nil {
+		update.Images = []string{}
+	}
+	update.MilestoneID = req.MilestoneID
+	update.DisbursementID = req.DisbursementID
+	return nil
+}
+
+// publish publishes update and queues notifications to the subscribers
+func (s *service) publish(ctx context.Context, campaign *domain.Campaign, update *domain.CampaignUpdate) error {
+	if !campaign.IsPublic() {
+		return errors.New(errors.ErrCodeConflict, "Updates can only be published on public campaigns", 409)
+	}
+
+	return s.repo.PublishUpdate(ctx, update, func(subscription *domain.CampaignSubscription) *notify.Message {
+		return s.updateNotification(campaign, update, subscription)
+	})
+}
+
+// updateNotification builds the notification of a published update for one
+// subscriber, falling back to the other channel when the preferred one has
+// no address
+func (s *service) updateNotification(campaign *domain.Campaign, update *domain.CampaignUpdate, subscription *domain.CampaignSubscription) *notify.Message {
+	msg := &notify.Message{
+		Channel:       notify.Channel(subscription.Channel),
+		Subject:       "Kabar terbaru: " + campaign.Title,
+		DedupeKey:     fmt.Sprintf("campaign_update:%d:%d", update.ID, subscription.ID),
+		ReferenceType: "campaign_update",
+		ReferenceID:   update.ID,
+	}
+
+	switch {
+	case msg.Channel == notify.ChannelWhatsApp && subscription.Phone != "":
+		msg.To = subscription.Phone
+	case subscription.Email != "":
+		msg.Channel, msg.To = notify.ChannelEmail, subscription.Email
+	case subscription.Phone != "":
+		msg.Channel, msg.To = notify.ChannelWhatsApp, subscription.Phone
+	default:
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Assalamu'alaikum,\n\nAda kabar terbaru dari program wakaf \"%s\" yang Anda dukung.\n\n", campaign.Title)
+	fmt.Fprintf(&b, "%s\n\n%s\n\n", update.Title, excerpt(update.Body, excerptLength))
+	fmt.Fprintf(&b, "Baca selengkapnya: %s/campaigns/%s/updates/%d\n", s.publicURL, campaign.Slug, update.ID)
+	fmt.Fprintf(&b, "\nBerhenti menerima kabar program ini: %s/unsubscribe?token=%s\n", s.publicURL, subscription.Token)
+	msg.Body = b.String()
+
+	return msg
+}
+
+// excerpt shortens text to at most n runes at a word boundary
+func excerpt(text string, n int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= n {
+		return string(runes)
+	}
+
+	cut := string(runes[:n])
+	if i := strings.LastIndexAny(cut, " \n"); i > n/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "…"
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/campaign/dto"
+	"github.com/akordium-id/waqfwise/internal/services/campaign/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// leaderboardFetcher reads a page of a leaderboard from the repository
+type leaderboardFetcher func(ctx context.Context, filter *repository.LeaderboardFilter) ([]*domain.LeaderboardEntry, error)
+
+// errInvalidCursor is returned for a cursor not issued by a previous page
+var errInvalidCursor = errors.New(errors.ErrCodeBadRequest, "Invalid cursor", 400)
+
+// GetDonorWall gets a page of the donor wall of a visible campaign, newest
+// first. Anonymous donors show as "Hamba Allah" and messages pass the
+// moderation filter; messages hidden by a manager are left out, except for the
+// campaign's managers.
+func (s *service) GetDonorWall(ctx context.Context, actor *domain.Actor, campaignID int64, cursor string, limit int) (*dto.DonorWallResponse, error) {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView(actor, campaign) {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+
+	var after *repository.WallCursor
+	if cursor != "" {
+		parts, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		nanos, errAt := strconv.ParseInt(parts[0], 10, 64)
+		donationID, errID := strconv.ParseInt(parts[1], 10, 64)
+		if errAt != nil || errID != nil {
+			return nil, errInvalidCursor
+		}
+		after = &repository.WallCursor{DonatedAt: time.Unix(0, nanos), DonationID: donationID}
+	}
+
+	// Read one entry past the page to learn whether another page follows
+	entries, err := s.repo.GetDonorWall(ctx, campaignID, canManage(actor, campaign), after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.DonorWallResponse{Entries: entries}
+	if len(entries) > limit {
+		resp.Entries = entries[:limit]
+		last := resp.Entries[limit-1]
+		resp.NextCursor = encodeCursor(strconv.FormatInt(last.DonatedAt.UnixNano(), 10), strconv.FormatInt(last.DonationID, 10))
+	}
+
+	for _, entry := range resp.Entries {
+		entry.DonorName = domain.PublicDonorName(entry.DonorName, entry.IsAnonymous)
+		entry.Message = filterMessage(entry.Message)
+	}
+
+	return resp, nil
+}
+
+// SetMessageVisibility hides a donor's message from the wall, or restores it.
+// The donation itself stays listed.
+func (s *service) SetMessageVisibility(ctx context.Context, actor *domain.Actor, campaignID, donationID int64, req *dto.MessageVisibilityRequest) error {
+	if _, err := s.authorize(ctx, actor, campaignID); err != nil {
+		return err
+	}
+
+	var hiddenBy *int64
+	if req.Hidden {
+		moderatorID := actor.UserID
+		hiddenBy = &moderatorID
+	}
+
+	return s.repo.SetDonationMessageHidden(ctx, campaignID, donationID, hiddenBy)
+}
+
+// GetTopDonors gets a page of the top donors of a visible campaign over a period
+func (s *service) GetTopDonors(ctx context.Context, actor *domain.Actor, campaignID int64, query *dto.LeaderboardQuery) (*dto.LeaderboardResponse, error) {
+	resp, err := s.leaderboard(ctx, actor, campaignID, query, s.repo.GetTopDonors)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range resp.Entries {
+		entry.Name = domain.PublicDonorName(entry.Name, entry.IsAnonymous)
+	}
+	return resp, nil
+}
+
+// GetTopFundraisers gets a page of the top visible fundraiser pages of a
+// visible campaign over a period
+func (s *service) GetTopFundraisers(ctx context.Context, actor *domain.Actor, campaignID int64, query *dto.LeaderboardQuery) (*dto.LeaderboardResponse, error) {
+	return s.leaderboard(ctx, actor, campaignID, query, s.repo.GetTopFundraisers)
+}
+
+// leaderboard resolves the period and cursor of a leaderboard query and reads
+// its page with fetch
+func (s *service) leaderboard(ctx context.Context, actor *domain.Actor, campaignID int64, query *dto.LeaderboardQuery, fetch leaderboardFetcher) (*dto.LeaderboardResponse, error) {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView(actor, campaign) {
+		return nil, errors.New(errors.ErrCodeNotFound, "Campaign not found", 404)
+	}
+
+	period, from, to, err := leaderboardPeriod(query)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &repository.LeaderboardFilter{
+		CampaignID: campaignID,
+		From:       from,
+		To:         to,
+		Limit:      query.Limit + 1,
+	}
+
+	if query.Cursor != "" {
+		parts, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return nil, errInvalidCursor
+		}
+		filter.After = &repository.LeaderboardCursor{Amount: amount, Key: parts[1]}
+	}
+
+	entries, err := fetch(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.LeaderboardResponse{Period: period, From: from, To: to, Entries: entries}
+	if len(entries) > query.Limit {
+		resp.Entries = entries[:query.Limit]
+		last := resp.Entries[query.Limit-1]
+		resp.NextCursor = encodeCursor(strconv.FormatInt(last.Amount, 10), last.Key)
+	}
+
+	return resp, nil
+}
+
+// leaderboardPeriod resolves a leaderboard period to its bounds; to is
+// exclusive. Calendar periods run up to now. Giving from or to without a
+// period selects a custom one.
+func leaderboardPeriod(query *dto.LeaderboardQuery) (domain.LeaderboardPeriod, *time.Time, *time.Time, error) {
+	period := query.Period
+	if period == "" {
+		period = domain.LeaderboardPeriodAll
+		if query.From != "" || query.To != "" {
+			period = domain.LeaderboardPeriodCustom
+		}
+	}
+
+	day := today()
+	var from time.Time
+	switch period {
+	case domain.LeaderboardPeriodAll:
+		return period, nil, nil, nil
+	case domain.LeaderboardPeriodWeek:
+		from = day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case domain.LeaderboardPeriodMonth:
+		from = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case domain.LeaderboardPeriodYear:
+		from = time.Date(day.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	case domain.LeaderboardPeriodCustom:
+		return customPeriod(query.From, query.To)
+	default:
+		return "", nil, nil, errors.New(errors.ErrCodeValidation, "period must be one of all, week, month, year, custom", 400)
+	}
+
+	return period, &from, nil, nil
+}
+
+// customPeriod parses the inclusive dates of a custom period; either may be
+// omitted to leave that end open
+func customPeriod(fromDate, toDate string) (domain.LeaderboardPeriod, *time.Time, *time.Time, error) {
+	if fromDate == "" && toDate == "" {
+		return "", nil, nil, errors.New(errors.ErrCodeValidation, "A custom period needs from or to", 400)
+	}
+
+	var from, to *time.Time
+	if fromDate != "" {
+		t, err := time.Parse("2006-01-02", fromDate)
+		if err != nil {
+			return "", nil, nil, errors.New(errors.ErrCodeValidation, "from must be a date (YYYY-MM-DD)", 400)
+		}
+		from = &t
+	}
+	if toDate != "" {
+		t, err := time.Parse("2006-01-02", toDate)
+		if err != nil {
+			return "", nil, nil, errors.New(errors.ErrCodeValidation, "to must be a date (YYYY-MM-DD)", 400)
+		}
+		t = t.AddDate(0, 0, 1)
+		to = &t
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return "", nil, nil, errors.New(errors.ErrCodeValidation, "from must not be after to", 400)
+	}
+
+	return domain.LeaderboardPeriodCustom, from, to, nil
+}
+
+// encodeCursor joins the sort values of the last entry of a page into an
+// opaque cursor
+func encodeCursor(values ...string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(values, "|")))
+}
+
+// decodeCursor splits a two-value cursor made by encodeCursor
+func decodeCursor(cursor string) ([]string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, errInvalidCursor
+	}
+	return parts, nil
+}
@@ -0,0 +1,53 @@
+package dto
+
+import "github.com/akordium-id/waqfwise/internal/shared/domain"
+
+// RecordIncomeRequest represents productive wakaf income recording request
+type RecordIncomeRequest struct {
+	Source      domain.IncomeSource `json:"source"`
+	Amount      int64               `json:"amount"`
+	ReceivedAt  string              `json:"received_at"` // YYYY-MM-DD
+	Description string              `json:"description,omitempty"`
+}
+
+// RuleRequest represents a single distribution rule
+type RuleRequest struct {
+	BeneficiaryType domain.BeneficiaryType `json:"beneficiary_type"`
+	BeneficiaryName string                 `json:"beneficiary_name"`
+	ShareBPS        int                    `json:"share_bps"` // basis points, 10000 = 100%
+}
+
+// SetRulesRequest replaces the distribution rules of an asset
+type SetRulesRequest struct {
+	Rules []RuleRequest `json:"rules"`
+}
+
+// RunDistributionRequest represents distribution run request for a period
+type RunDistributionRequest struct {
+	PeriodStart string `json:"period_start"` // YYYY-MM-DD, inclusive
+	PeriodEnd   string `json:"period_end"`   // YYYY-MM-DD, inclusive
+}
+
+// RunResponse represents a distribution run with its allocations
+type RunResponse struct {
+	Run         *domain.DistributionRun          `json:"run"`
+	Allocations []*domain.DistributionAllocation `json:"allocations"`
+}
+
+// SkippedAsset represents an asset left out of a period run
+type SkippedAsset struct {
+	AssetID int64  `json:"asset_id"`
+	Reason  string `json:"reason"`
+}
+
+// PeriodRunResponse represents the result of distributing all assets for a period
+type PeriodRunResponse struct {
+	Runs    []*RunResponse `json:"runs"`
+	Skipped []SkippedAsset `json:"skipped"`
+}
+
+// IncomeListResponse represents asset income with its undistributed balance
+type IncomeListResponse struct {
+	Incomes              []*domain.AssetIncome `json:"incomes"`
+	UndistributedBalance int64                 `json:"undistributed_balance"`
+}
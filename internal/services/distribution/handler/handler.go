@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/distribution/dto"
+	"github.com/akordium-id/waqfwise/internal/services/distribution/service"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+	"github.com/gorilla/mux"
+)
+
+// Handler handles HTTP requests for productive wakaf income distribution
+type Handler struct {
+	service service.Service
+	auth    *middleware.Auth
+}
+
+// New creates a new distribution handler
+func New(service service.Service, auth *middleware.Auth) *Handler {
+	return &Handler{
+		service: service,
+		auth:    auth,
+	}
+}
+
+// RecordIncome handles asset income recording
+func (h *Handler) RecordIncome(w http.ResponseWriter, r *http.Request) {
+	assetID, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.RecordIncomeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("source", string(req.Source))
+	v.In("source", string(req.Source), []string{
+		string(domain.IncomeSourceRent),
+		string(domain.IncomeSourceProfitShare),
+		string(domain.IncomeSourceSales),
+		string(domain.IncomeSourceOther),
+	})
+	v.Min("amount", req.Amount, 1)
+	v.Required("received_at", req.ReceivedAt)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	income, err := h.service.RecordIncome(r.Context(), middleware.ActorFromContext(r.Context()), assetID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, income)
+}
+
+// GetIncomes handles asset income listing
+func (h *Handler) GetIncomes(w http.ResponseWriter, r *http.Request) {
+	assetID, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	page, perPage := request.Pagination(r)
+	incomes, total, err := h.service.GetIncomes(r.Context(), middleware.ActorFromContext(r.Context()), assetID, perPage, (page-1)*perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, incomes, page, perPage, total)
+}
+
+// GetRules handles distribution rule retrieval
+func (h *Handler) GetRules(w http.ResponseWriter, r *http.Request) {
+	assetID, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	rules, err := h.service.GetRules(r.Context(), middleware.ActorFromContext(r.Context()), assetID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, rules)
+}
+
+// SetRules handles distribution rule replacement
+func (h *Handler) SetRules(w http.ResponseWriter, r *http.Request) {
+	assetID, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	var req dto.SetRulesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	rules, err := h.service.SetRules(r.Context(), middleware.ActorFromContext(r.Context()), assetID, &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, rules)
+}
+
+// RunDistribution handles distribution of a single asset's income
+func (h *Handler) RunDistribution(w http.ResponseWriter, r *http.Request) {
+	assetID, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	req, err := decodeRunRequest(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	run, err := h.service.RunDistribution(r.Context(), middleware.ActorFromContext(r.Context()), assetID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, run)
+}
+
+// RunPeriod handles the periodic distribution run across all assets
+func (h *Handler) RunPeriod(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeRunRequest(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	result, err := h.service.RunPeriod(r.Context(), middleware.ActorFromContext(r.Context()), req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
+// GetRuns handles distribution run listing of an asset
+func (h *Handler) GetRuns(w http.ResponseWriter, r *http.Request) {
+	assetID, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	page, perPage := request.Pagination(r)
+	runs, total, err := h.service.GetRuns(r.Context(), middleware.ActorFromContext(r.Context()), assetID, perPage, (page-1)*perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, runs, page, perPage, total)
+}
+
+// GetRun handles distribution run retrieval
+func (h *Handler) GetRun(w http.ResponseWriter, r *http.Request) {
+	runID, err := request.PathID(r, "runID")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	run, err := h.service.GetRun(r.Context(), middleware.ActorFromContext(r.Context()), runID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, run)
+}
+
+// RegisterRoutes registers HTTP routes under the assets prefix
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	readers := middleware.RequireRole(domain.RoleAdmin, domain.RoleNazir, domain.RoleAuditor)
+	managers := middleware.RequireRole(domain.RoleAdmin, domain.RoleNazir)
+	admins := middleware.RequireRole(domain.RoleAdmin)
+
+	protected := r.PathPrefix("/").Subrouter()
+	protected.Use(h.auth.Authenticate)
+	protected.Handle("/{id:[0-9]+}/incomes", readers(http.HandlerFunc(h.GetIncomes))).Methods("GET")
+	protected.Handle("/{id:[0-9]+}/incomes", managers(http.HandlerFunc(h.RecordIncome))).Methods("POST")
+	protected.Handle("/{id:[0-9]+}/distribution-rules", readers(http.HandlerFunc(h.GetRules))).Methods("GET")
+	protected.Handle("/{id:[0-9]+}/distribution-rules", managers(http.HandlerFunc(h.SetRules))).Methods("PUT")
+	protected.Handle("/{id:[0-9]+}/distributions", readers(http.HandlerFunc(h.GetRuns))).Methods("GET")
+	protected.Handle("/{id:[0-9]+}/distributions", managers(http.HandlerFunc(h.RunDistribution))).Methods("POST")
+	protected.Handle("/distributions/run", admins(http.HandlerFunc(h.RunPeriod))).Methods("POST")
+	protected.Handle("/distributions/{runID:[0-9]+}", readers(http.HandlerFunc(h.GetRun))).Methods("GET")
+}
+
+// decodeRunRequest decodes and validates a distribution run request
+func decodeRunRequest(r *http.Request) (*dto.RunDistributionRequest, error) {
+	var req dto.RunDistributionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400)
+	}
+
+	v := validator.New()
+	v.Required("period_start", req.PeriodStart)
+	v.Required("period_end", req.PeriodEnd)
+
+	if !v.IsValid() {
+		return nil, v.Error()
+	}
+
+	return &req, nil
+}
@@ -0,0 +1,488 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/ledger/journal"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/lib/pq"
+)
+
+// AssetRef is the subset of an asset needed to record and distribute its income
+type AssetRef struct {
+	ID      int64
+	Name    string
+	Status  domain.AssetStatus
+	NazirID int64
+}
+
+// Repository defines distribution repository interface
+type Repository interface {
+	FindAsset(ctx context.Context, assetID int64) (*AssetRef, error)
+	CreateIncome(ctx context.Context, income *domain.AssetIncome, entries []*domain.Ledger) error
+	GetIncomesByAsset(ctx context.Context, assetID int64, limit, offset int) ([]*domain.AssetIncome, int64, error)
+	GetUndistributedIncomes(ctx context.Context, assetID int64, from, to time.Time) ([]*domain.AssetIncome, error)
+	GetUndistributedBalance(ctx context.Context, assetID int64) (int64, error)
+	GetAssetIDsWithUndistributedIncome(ctx context.Context, from, to time.Time) ([]int64, error)
+	GetActiveRules(ctx context.Context, assetID int64) ([]*domain.DistributionRule, error)
+	ReplaceRules(ctx context.Context, assetID int64, rules []*domain.DistributionRule) error
+	CreateRun(ctx context.Context, run *domain.DistributionRun, allocations []*domain.DistributionAllocation, incomeIDs []int64, entries []*domain.Ledger) error
+	FindRunByID(ctx context.Context, id int64) (*domain.DistributionRun, error)
+	GetRunsByAsset(ctx context.Context, assetID int64, limit, offset int) ([]*domain.DistributionRun, int64, error)
+	GetAllocationsByRun(ctx context.Context, runID int64) ([]*domain.DistributionAllocation, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// New creates a new distribution repository
+func New(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// FindAsset finds an asset together with the nazir managing its campaign
+func (r *repository) FindAsset(ctx context.Context, assetID int64) (*AssetRef, error) {
+	query := `
+		SELECT a.id, a.name, a.status, COALESCE(c.nazir_id, 0)
+		FROM assets a
+		LEFT JOIN campaigns c ON c.id = a.campaign_id
+		WHERE a.id = $1
+	`
+
+	asset := &AssetRef{}
+	err := r.db.QueryRowContext(ctx, query, assetID).Scan(&asset.ID, &asset.Name, &asset.Status, &asset.NazirID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Asset not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find asset", 500)
+	}
+
+	return asset, nil
+}
+
+// CreateIncome records asset income and its ledger entries atomically
+func (r *repository) CreateIncome(ctx context.Context, income *domain.AssetIncome, entries []*domain.Ledger) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO asset_incomes (asset_id, source, amount, received_at, description, recorded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err = tx.QueryRowContext(
+		ctx, query,
+		income.AssetID,
+		income.Source,
+		income.Amount,
+		income.ReceivedAt,
+		income.Description,
+		income.RecordedBy,
+		now,
+	).Scan(&income.ID)
+
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create asset income", 500)
+	}
+
+	for _, entry := range entries {
+		entry.ReferenceID = income.ID
+	}
+	if err := journal.Append(ctx, tx, entries...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit asset income", 500)
+	}
+
+	income.CreatedAt = now
+	return nil
+}
+
+// GetIncomesByAsset gets income records of an asset
+func (r *repository) GetIncomesByAsset(ctx context.Context, assetID int64, limit, offset int) ([]*domain.AssetIncome, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM asset_incomes WHERE asset_id = $1`
+	if err := r.db.QueryRowContext(ctx, countQuery, assetID).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count asset incomes", 500)
+	}
+
+	query := `
+		SELECT id, asset_id, source, amount, received_at, description, recorded_by, distribution_run_id, created_at
+		FROM asset_incomes
+		WHERE asset_id = $1
+		ORDER BY received_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get asset incomes", 500)
+	}
+	defer rows.Close()
+
+	incomes, err := scanIncomes(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return incomes, total, nil
+}
+
+// GetUndistributedIncomes gets income received in [from, to) that has not been distributed
+func (r *repository) GetUndistributedIncomes(ctx context.Context, assetID int64, from, to time.Time) ([]*domain.AssetIncome, error) {
+	query := `
+		SELECT id, asset_id, source, amount, received_at, description, recorded_by, distribution_run_id, created_at
+		FROM asset_incomes
+		WHERE asset_id = $1 AND distribution_run_id IS NULL
+		  AND received_at >= $2 AND received_at < $3
+		ORDER BY received_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get undistributed incomes", 500)
+	}
+	defer rows.Close()
+
+	return scanIncomes(rows)
+}
+
+// GetUndistributedBalance gets total income of an asset awaiting distribution
+func (r *repository) GetUndistributedBalance(ctx context.Context, assetID int64) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM asset_incomes
+		WHERE asset_id = $1 AND distribution_run_id IS NULL
+	`
+
+	var balance int64
+	if err := r.db.QueryRowContext(ctx, query, assetID).Scan(&balance); err != nil {
+		return 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get undistributed balance", 500)
+	}
+
+	return balance, nil
+}
+
+// GetAssetIDsWithUndistributedIncome gets assets having undistributed income in [from, to)
+func (r *repository) GetAssetIDsWithUndistributedIncome(ctx context.Context, from, to time.Time) ([]int64, error) {
+	query := `
+		SELECT DISTINCT asset_id
+		FROM asset_incomes
+		WHERE distribution_run_id IS NULL AND received_at >= $1 AND received_at < $2
+		ORDER BY asset_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get assets with income", 500)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan asset ID", 500)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetActiveRules gets active distribution rules of an asset
+func (r *repository) GetActiveRules(ctx context.Context, assetID int64) ([]*domain.DistributionRule, error) {
+	query := `
+		SELECT id, asset_id, beneficiary_type, beneficiary_name, share_bps, is_active, created_at, updated_at
+		FROM distribution_rules
+		WHERE asset_id = $1 AND is_active = TRUE
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get distribution rules", 500)
+	}
+	defer rows.Close()
+
+	rules := make([]*domain.DistributionRule, 0)
+	for rows.Next() {
+		rule := &domain.DistributionRule{}
+		if err := rows.Scan(
+			&rule.ID, &rule.AssetID, &rule.BeneficiaryType, &rule.BeneficiaryName,
+			&rule.ShareBPS, &rule.IsActive, &rule.CreatedAt, &rule.UpdatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan distribution rule", 500)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// ReplaceRules deactivates current rules of an asset and stores the new set.
+// Old rules are kept so past allocations still reference them.
+func (r *repository) ReplaceRules(ctx context.Context, assetID int64, rules []*domain.DistributionRule) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	deactivate := `UPDATE distribution_rules SET is_active = FALSE, updated_at = $1 WHERE asset_id = $2 AND is_active = TRUE`
+	if _, err := tx.ExecContext(ctx, deactivate, now, assetID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to deactivate distribution rules", 500)
+	}
+
+	query := `
+		INSERT INTO distribution_rules (asset_id, beneficiary_type, beneficiary_name, share_bps, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, TRUE, $5, $6)
+		RETURNING id
+	`
+
+	for _, rule := range rules {
+		if err := tx.QueryRowContext(
+			ctx, query,
+			assetID,
+			rule.BeneficiaryType,
+			rule.BeneficiaryName,
+			rule.ShareBPS,
+			now,
+			now,
+		).Scan(&rule.ID); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create distribution rule", 500)
+		}
+
+		rule.AssetID = assetID
+		rule.IsActive = true
+		rule.CreatedAt = now
+		rule.UpdatedAt = now
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit distribution rules", 500)
+	}
+
+	return nil
+}
+
+// CreateRun stores a distribution run, marks its income as distributed and posts
+// its ledger entries in one transaction
+func (r *repository) CreateRun(ctx context.Context, run *domain.DistributionRun, allocations []*domain.DistributionAllocation, incomeIDs []int64, entries []*domain.Ledger) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO distribution_runs (asset_id, period_start, period_end, total_income, nazir_amount,
+		                               beneficiary_amount, income_count, executed_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err = tx.QueryRowContext(
+		ctx, query,
+		run.AssetID,
+		run.PeriodStart,
+		run.PeriodEnd,
+		run.TotalIncome,
+		run.NazirAmount,
+		run.BeneficiaryAmount,
+		run.IncomeCount,
+		run.ExecutedBy,
+		now,
+	).Scan(&run.ID)
+
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create distribution run", 500)
+	}
+
+	// Claim the income rows; a concurrent run that got there first leaves fewer rows to update
+	claim := `
+		UPDATE asset_incomes SET distribution_run_id = $1
+		WHERE id = ANY($2) AND distribution_run_id IS NULL
+	`
+	result, err := tx.ExecContext(ctx, claim, run.ID, pq.Array(incomeIDs))
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to mark income as distributed", 500)
+	}
+
+	claimed, _ := result.RowsAffected()
+	if claimed != int64(len(incomeIDs)) {
+		return errors.New(errors.ErrCodeConflict, "Income has already been distributed", 409)
+	}
+
+	allocQuery := `
+		INSERT INTO distribution_allocations (run_id, rule_id, beneficiary_type, beneficiary_name, share_bps, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	for _, alloc := range allocations {
+		alloc.RunID = run.ID
+		if err := tx.QueryRowContext(
+			ctx, allocQuery,
+			alloc.RunID,
+			alloc.RuleID,
+			alloc.BeneficiaryType,
+			alloc.BeneficiaryName,
+			alloc.ShareBPS,
+			alloc.Amount,
+			now,
+		).Scan(&alloc.ID); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create distribution allocation", 500)
+		}
+		alloc.CreatedAt = now
+	}
+
+	for _, entry := range entries {
+		entry.ReferenceID = run.ID
+	}
+	if err := journal.Append(ctx, tx, entries...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit distribution run", 500)
+	}
+
+	run.CreatedAt = now
+	return nil
+}
+
+// FindRunByID finds distribution run by ID
+func (r *repository) FindRunByID(ctx context.Context, id int64) (*domain.DistributionRun, error) {
+	query := `
+		SELECT id, asset_id, period_start, period_end, total_income, nazir_amount,
+		       beneficiary_amount, income_count, executed_by, created_at
+		FROM distribution_runs
+		WHERE id = $1
+	`
+
+	run := &domain.DistributionRun{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&run.ID, &run.AssetID, &run.PeriodStart, &run.PeriodEnd, &run.TotalIncome,
+		&run.NazirAmount, &run.BeneficiaryAmount, &run.IncomeCount, &run.ExecutedBy, &run.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Distribution run not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find distribution run", 500)
+	}
+
+	return run, nil
+}
+
+// GetRunsByAsset gets distribution runs of an asset
+func (r *repository) GetRunsByAsset(ctx context.Context, assetID int64, limit, offset int) ([]*domain.DistributionRun, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM distribution_runs WHERE asset_id = $1`
+	if err := r.db.QueryRowContext(ctx, countQuery, assetID).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count distribution runs", 500)
+	}
+
+	query := `
+		SELECT id, asset_id, period_start, period_end, total_income, nazir_amount,
+		       beneficiary_amount, income_count, executed_by, created_at
+		FROM distribution_runs
+		WHERE asset_id = $1
+		ORDER BY period_end DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get distribution runs", 500)
+	}
+	defer rows.Close()
+
+	runs := make([]*domain.DistributionRun, 0)
+	for rows.Next() {
+		run := &domain.DistributionRun{}
+		if err := rows.Scan(
+			&run.ID, &run.AssetID, &run.PeriodStart, &run.PeriodEnd, &run.TotalIncome,
+			&run.NazirAmount, &run.BeneficiaryAmount, &run.IncomeCount, &run.ExecutedBy, &run.CreatedAt,
+		); err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan distribution run", 500)
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, total, rows.Err()
+}
+
+// GetAllocationsByRun gets allocations of a distribution run
+func (r *repository) GetAllocationsByRun(ctx context.Context, runID int64) ([]*domain.DistributionAllocation, error) {
+	query := `
+		SELECT id, run_id, rule_id, beneficiary_type, beneficiary_name, share_bps, amount, created_at
+		FROM distribution_allocations
+		WHERE run_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, runID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get distribution allocations", 500)
+	}
+	defer rows.Close()
+
+	allocations := make([]*domain.DistributionAllocation, 0)
+	for rows.Next() {
+		alloc := &domain.DistributionAllocation{}
+		if err := rows.Scan(
+			&alloc.ID, &alloc.RunID, &alloc.RuleID, &alloc.BeneficiaryType,
+			&alloc.BeneficiaryName, &alloc.ShareBPS, &alloc.Amount, &alloc.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan distribution allocation", 500)
+		}
+		allocations = append(allocations, alloc)
+	}
+
+	return allocations, rows.Err()
+}
+
+// scanIncomes scans asset income rows
+func scanIncomes(rows *sql.Rows) ([]*domain.AssetIncome, error) {
+	incomes := make([]*domain.AssetIncome, 0)
+	for rows.Next() {
+		income := &domain.AssetIncome{}
+		var description sql.NullString
+		var runID sql.NullInt64
+
+		if err := rows.Scan(
+			&income.ID, &income.AssetID, &income.Source, &income.Amount, &income.ReceivedAt,
+			&description, &income.RecordedBy, &runID, &income.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan asset income", 500)
+		}
+
+		if description.Valid {
+			income.Description = description.String
+		}
+		if runID.Valid {
+			income.DistributionRunID = &runID.Int64
+		}
+
+		incomes = append(incomes, income)
+	}
+
+	return incomes, rows.Err()
+}
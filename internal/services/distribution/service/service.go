@@ -0,0 +1,472 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/distribution/dto"
+	"github.com/akordium-id/waqfwise/internal/services/distribution/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// Ledger accounts used by productive wakaf income
+const (
+	accountCash             = "cash_account"
+	accountWakafIncome      = "wakaf_income"
+	accountNazirShare       = "nazir_share_payable"
+	accountMauqufAlaihShare = "mauquf_alaih_payable"
+)
+
+// Service defines distribution service interface
+type Service interface {
+	RecordIncome(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.RecordIncomeRequest) (*domain.AssetIncome, error)
+	GetIncomes(ctx context.Context, actor *domain.Actor, assetID int64, limit, offset int) (*dto.IncomeListResponse, int64, error)
+	GetRules(ctx context.Context, actor *domain.Actor, assetID int64) ([]*domain.DistributionRule, error)
+	SetRules(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.SetRulesRequest) ([]*domain.DistributionRule, error)
+	RunDistribution(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.RunDistributionRequest) (*dto.RunResponse, error)
+	RunPeriod(ctx context.Context, actor *domain.Actor, req *dto.RunDistributionRequest) (*dto.PeriodRunResponse, error)
+	GetRun(ctx context.Context, actor *domain.Actor, runID int64) (*dto.RunResponse, error)
+	GetRuns(ctx context.Context, actor *domain.Actor, assetID int64, limit, offset int) ([]*domain.DistributionRun, int64, error)
+}
+
+type service struct {
+	repo repository.Repository
+}
+
+// New creates a new distribution service
+func New(repo repository.Repository) Service {
+	return &service{repo: repo}
+}
+
+// RecordIncome records income produced by an asset and posts it to the ledger
+func (s *service) RecordIncome(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.RecordIncomeRequest) (*domain.AssetIncome, error) {
+	asset, err := s.authorizeAsset(ctx, actor, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if asset.Status == domain.AssetStatusDisposed {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Cannot record income for a disposed asset", 400)
+	}
+
+	if req.Amount <= 0 {
+		return nil, errors.New(errors.ErrCodeValidation, "Amount must be greater than zero", 400)
+	}
+
+	receivedAt, err := parseDate(req.ReceivedAt)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeValidation, "received_at must be a date (YYYY-MM-DD)", 400)
+	}
+
+	balance, err := s.repo.GetUndistributedBalance(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	income := &domain.AssetIncome{
+		AssetID:     assetID,
+		Source:      req.Source,
+		Amount:      req.Amount,
+		ReceivedAt:  receivedAt,
+		Description: req.Description,
+		RecordedBy:  actor.UserID,
+	}
+
+	// Debit cash, credit undistributed wakaf income of the asset
+	entries := []*domain.Ledger{
+		{
+			AccountType:   "debit",
+			AccountName:   accountCash,
+			Amount:        req.Amount,
+			BalanceBefore: balance,
+			BalanceAfter:  balance + req.Amount,
+			Description:   fmt.Sprintf("%s income from asset %s", req.Source, asset.Name),
+			ReferenceType: domain.LedgerRefAssetIncome,
+			EntryDate:     receivedAt,
+		},
+		{
+			AccountType:   "credit",
+			AccountName:   accountWakafIncome,
+			Amount:        req.Amount,
+			BalanceBefore: balance,
+			BalanceAfter:  balance + req.Amount,
+			Description:   fmt.Sprintf("Hasil wakaf of asset ID %d awaiting distribution", assetID),
+			ReferenceType: domain.LedgerRefAssetIncome,
+			EntryDate:     receivedAt,
+		},
+	}
+
+	if err := s.repo.CreateIncome(ctx, income, entries); err != nil {
+		return nil, err
+	}
+
+	return income, nil
+}
+
+// GetIncomes gets income records of an asset with its undistributed balance
+func (s *service) GetIncomes(ctx context.Context, actor *domain.Actor, assetID int64, limit, offset int) (*dto.IncomeListResponse, int64, error) {
+	if _, err := s.viewableAsset(ctx, actor, assetID); err != nil {
+		return nil, 0, err
+	}
+
+	incomes, total, err := s.repo.GetIncomesByAsset(ctx, assetID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	balance, err := s.repo.GetUndistributedBalance(ctx, assetID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &dto.IncomeListResponse{
+		Incomes:              incomes,
+		UndistributedBalance: balance,
+	}, total, nil
+}
+
+// GetRules gets active distribution rules of an asset
+func (s *service) GetRules(ctx context.Context, actor *domain.Actor, assetID int64) ([]*domain.DistributionRule, error) {
+	if _, err := s.viewableAsset(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetActiveRules(ctx, assetID)
+}
+
+// SetRules replaces the distribution rules of an asset
+func (s *service) SetRules(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.SetRulesRequest) ([]*domain.DistributionRule, error) {
+	if _, err := s.authorizeAsset(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	rules := make([]*domain.DistributionRule, len(req.Rules))
+	for i, r := range req.Rules {
+		rules[i] = &domain.DistributionRule{
+			AssetID:         assetID,
+			BeneficiaryType: r.BeneficiaryType,
+			BeneficiaryName: strings.TrimSpace(r.BeneficiaryName),
+			ShareBPS:        r.ShareBPS,
+		}
+	}
+
+	if err := validateRules(rules); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReplaceRules(ctx, assetID, rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// RunDistribution distributes undistributed income of an asset for a period
+func (s *service) RunDistribution(ctx context.Context, actor *domain.Actor, assetID int64, req *dto.RunDistributionRequest) (*dto.RunResponse, error) {
+	if _, err := s.authorizeAsset(ctx, actor, assetID); err != nil {
+		return nil, err
+	}
+
+	from, to, err := parsePeriod(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.distribute(ctx, actor, assetID, from, to)
+}
+
+// RunPeriod distributes income of every asset that has undistributed income in a period
+func (s *service) RunPeriod(ctx context.Context, actor *domain.Actor, req *dto.RunDistributionRequest) (*dto.PeriodRunResponse, error) {
+	if !actor.IsAdmin() {
+		return nil, errors.ErrForbidden
+	}
+
+	from, to, err := parsePeriod(req)
+	if err != nil {
+		return nil, err
+	}
+
+	assetIDs, err := s.repo.GetAssetIDsWithUndistributedIncome(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.PeriodRunResponse{
+		Runs:    make([]*dto.RunResponse, 0, len(assetIDs)),
+		Skipped: make([]dto.SkippedAsset, 0),
+	}
+
+	for _, assetID := range assetIDs {
+		run, err := s.distribute(ctx, actor, assetID, from, to)
+		if err != nil {
+			// One misconfigured asset must not block distribution to the others
+			result.Skipped = append(result.Skipped, dto.SkippedAsset{AssetID: assetID, Reason: errorMessage(err)})
+			continue
+		}
+		result.Runs = append(result.Runs, run)
+	}
+
+	return result, nil
+}
+
+// GetRun gets a distribution run with its allocations
+func (s *service) GetRun(ctx context.Context, actor *domain.Actor, runID int64) (*dto.RunResponse, error) {
+	run, err := s.repo.FindRunByID(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.viewableAsset(ctx, actor, run.AssetID); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.New(errors.ErrCodeNotFound, "Distribution run not found", 404)
+		}
+		return nil, err
+	}
+
+	allocations, err := s.repo.GetAllocationsByRun(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RunResponse{Run: run, Allocations: allocations}, nil
+}
+
+// GetRuns gets distribution runs of an asset
+func (s *service) GetRuns(ctx context.Context, actor *domain.Actor, assetID int64, limit, offset int) ([]*domain.DistributionRun, int64, error) {
+	if _, err := s.viewableAsset(ctx, actor, assetID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.repo.GetRunsByAsset(ctx, assetID, limit, offset)
+}
+
+// distribute computes the splits of undistributed income in [from, to) and persists the run.
+// Only recorded income is distributed, so the wakaf principal is never touched.
+func (s *service) distribute(ctx context.Context, actor *domain.Actor, assetID int64, from, to time.Time) (*dto.RunResponse, error) {
+	rules, err := s.repo.GetActiveRules(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Asset has no distribution rules", 400)
+	}
+
+	incomes, err := s.repo.GetUndistributedIncomes(ctx, assetID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(incomes) == 0 {
+		return nil, errors.New(errors.ErrCodeBadRequest, "No undistributed income in period", 400)
+	}
+
+	var total int64
+	incomeIDs := make([]int64, len(incomes))
+	for i, income := range incomes {
+		total += income.Amount
+		incomeIDs[i] = income.ID
+	}
+
+	allocations, err := allocate(total, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := s.repo.GetUndistributedBalance(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &domain.DistributionRun{
+		AssetID:     assetID,
+		PeriodStart: from,
+		PeriodEnd:   to.AddDate(0, 0, -1),
+		TotalIncome: total,
+		IncomeCount: len(incomes),
+		ExecutedBy:  actor.UserID,
+	}
+
+	// Debit the distributed income, credit each beneficiary's payable
+	entries := []*domain.Ledger{{
+		AccountType:   "debit",
+		AccountName:   accountWakafIncome,
+		Amount:        total,
+		BalanceBefore: balance,
+		BalanceAfter:  balance - total,
+		Description:   fmt.Sprintf("Distribution of hasil wakaf of asset ID %d", assetID),
+		ReferenceType: domain.LedgerRefDistributionRun,
+	}}
+
+	for _, alloc := range allocations {
+		account := accountMauqufAlaihShare
+		if alloc.BeneficiaryType == domain.BeneficiaryNazir {
+			account = accountNazirShare
+			run.NazirAmount += alloc.Amount
+		} else {
+			run.BeneficiaryAmount += alloc.Amount
+		}
+
+		if alloc.Amount == 0 {
+			continue
+		}
+
+		entries = append(entries, &domain.Ledger{
+			AccountType:   "credit",
+			AccountName:   account,
+			Amount:        alloc.Amount,
+			BalanceBefore: balance,
+			BalanceAfter:  balance - total,
+			Description:   fmt.Sprintf("Share for %s (%s)", alloc.BeneficiaryName, alloc.BeneficiaryType),
+			ReferenceType: domain.LedgerRefDistributionRun,
+		})
+	}
+
+	if err := s.repo.CreateRun(ctx, run, allocations, incomeIDs, entries); err != nil {
+		return nil, err
+	}
+
+	return &dto.RunResponse{Run: run, Allocations: allocations}, nil
+}
+
+// authorizeAsset loads an asset and checks the actor may manage its income
+func (s *service) authorizeAsset(ctx context.Context, actor *domain.Actor, assetID int64) (*repository.AssetRef, error) {
+	asset, err := s.repo.FindAsset(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !actor.IsAdmin() && asset.NazirID != actor.UserID {
+		return nil, errors.New(errors.ErrCodeForbidden, "Only the nazir of this asset can manage its income", 403)
+	}
+
+	return asset, nil
+}
+
+// viewableAsset loads an asset whose income the actor may see. Nazirs only see
+// their own assets; other nazirs' assets are reported as not found.
+func (s *service) viewableAsset(ctx context.Context, actor *domain.Actor, assetID int64) (*repository.AssetRef, error) {
+	asset, err := s.repo.FindAsset(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if actor.Role == domain.RoleNazir && asset.NazirID != actor.UserID {
+		return nil, errors.New(errors.ErrCodeNotFound, "Asset not found", 404)
+	}
+
+	return asset, nil
+}
+
+// validateRules checks rules cover 100% of income and respect the nazir cap
+func validateRules(rules []*domain.DistributionRule) error {
+	if len(rules) == 0 {
+		return errors.New(errors.ErrCodeValidation, "At least one distribution rule is required", 400)
+	}
+
+	var totalBPS, nazirBPS, beneficiaries int
+	for _, rule := range rules {
+		if rule.BeneficiaryName == "" {
+			return errors.New(errors.ErrCodeValidation, "beneficiary_name is required", 400)
+		}
+		if rule.ShareBPS <= 0 {
+			return errors.New(errors.ErrCodeValidation, "share_bps must be greater than zero", 400)
+		}
+
+		switch rule.BeneficiaryType {
+		case domain.BeneficiaryNazir:
+			nazirBPS += rule.ShareBPS
+		case domain.BeneficiaryMauqufAlaih:
+			beneficiaries++
+		default:
+			return errors.New(errors.ErrCodeValidation, "beneficiary_type must be one of: mauquf_alaih, nazir", 400)
+		}
+
+		totalBPS += rule.ShareBPS
+	}
+
+	if beneficiaries == 0 {
+		return errors.New(errors.ErrCodeValidation, "At least one mauquf alaih beneficiary is required", 400)
+	}
+
+	if nazirBPS > domain.NazirShareCapBPS {
+		return errors.New(errors.ErrCodeValidation, "Nazir share cannot exceed 10% of wakaf income", 400)
+	}
+
+	if totalBPS != domain.FullShareBPS {
+		return errors.New(errors.ErrCodeValidation, "Distribution shares must add up to 10000 basis points (100%)", 400)
+	}
+
+	return nil
+}
+
+// allocate splits total across rules. Rounding remainders go to the largest
+// mauquf alaih share so the nazir never exceeds the legal cap.
+func allocate(total int64, rules []*domain.DistributionRule) ([]*domain.DistributionAllocation, error) {
+	if err := validateRules(rules); err != nil {
+		return nil, err
+	}
+
+	allocations := make([]*domain.DistributionAllocation, len(rules))
+	var allocated, nazirAmount int64
+	remainderIdx := -1
+
+	for i, rule := range rules {
+		amount := total * int64(rule.ShareBPS) / domain.FullShareBPS
+		allocations[i] = &domain.DistributionAllocation{
+			RuleID:          rule.ID,
+			BeneficiaryType: rule.BeneficiaryType,
+			BeneficiaryName: rule.BeneficiaryName,
+			ShareBPS:        rule.ShareBPS,
+			Amount:          amount,
+		}
+		allocated += amount
+
+		if rule.IsNazir() {
+			nazirAmount += amount
+		} else if remainderIdx < 0 || rule.ShareBPS > rules[remainderIdx].ShareBPS {
+			remainderIdx = i
+		}
+	}
+
+	allocations[remainderIdx].Amount += total - allocated
+
+	if nazirAmount > total*domain.NazirShareCapBPS/domain.FullShareBPS {
+		return nil, errors.New(errors.ErrCodeValidation, "Nazir share exceeds the 10% cap", 400)
+	}
+
+	return allocations, nil
+}
+
+// parseDate parses a YYYY-MM-DD date
+func parseDate(value string) (time.Time, error) {
+	return time.Parse("2006-01-02", value)
+}
+
+// parsePeriod parses an inclusive date period into a half-open [from, to) range
+func parsePeriod(req *dto.RunDistributionRequest) (time.Time, time.Time, error) {
+	from, err := parseDate(req.PeriodStart)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New(errors.ErrCodeValidation, "period_start must be a date (YYYY-MM-DD)", 400)
+	}
+
+	end, err := parseDate(req.PeriodEnd)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New(errors.ErrCodeValidation, "period_end must be a date (YYYY-MM-DD)", 400)
+	}
+
+	if end.Before(from) {
+		return time.Time{}, time.Time{}, errors.New(errors.ErrCodeValidation, "period_end must not be before period_start", 400)
+	}
+
+	return from, end.AddDate(0, 0, 1), nil
+}
+
+// errorMessage extracts a client-facing message from an error
+func errorMessage(err error) string {
+	if appErr, ok := err.(*errors.AppError); ok {
+		return appErr.Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+)
+
+func rule(id int64, beneficiaryType domain.BeneficiaryType, shareBPS int) *domain.DistributionRule {
+	return &domain.DistributionRule{
+		ID:              id,
+		BeneficiaryType: beneficiaryType,
+		BeneficiaryName: "beneficiary",
+		ShareBPS:        shareBPS,
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []*domain.DistributionRule
+		wantErr bool
+	}{
+		{
+			name:  "beneficiary takes all",
+			rules: []*domain.DistributionRule{rule(1, domain.BeneficiaryMauqufAlaih, 10000)},
+		},
+		{
+			name: "nazir at the cap",
+			rules: []*domain.DistributionRule{
+				rule(1, domain.BeneficiaryNazir, 1000),
+				rule(2, domain.BeneficiaryMauqufAlaih, 9000),
+			},
+		},
+		{
+			name: "nazir over the cap",
+			rules: []*domain.DistributionRule{
+				rule(1, domain.BeneficiaryNazir, 1001),
+				rule(2, domain.BeneficiaryMauqufAlaih, 8999),
+			},
+			wantErr: true,
+		},
+		{
+			name: "nazir cap counts every nazir rule",
+			rules: []*domain.DistributionRule{
+				rule(1, domain.BeneficiaryNazir, 600),
+				rule(2, domain.BeneficiaryNazir, 600),
+				rule(3, domain.BeneficiaryMauqufAlaih, 8800),
+			},
+			wantErr: true,
+		},
+		{
+			name: "shares under 100%",
+			rules: []*domain.DistributionRule{
+				rule(1, domain.BeneficiaryNazir, 1000),
+				rule(2, domain.BeneficiaryMauqufAlaih, 8999),
+			},
+			wantErr: true,
+		},
+		{
+			name: "shares over 100%",
+			rules: []*domain.DistributionRule{
+				rule(1, domain.BeneficiaryMauqufAlaih, 5000),
+				rule(2, domain.BeneficiaryMauqufAlaih, 5001),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "nazir only",
+			rules:   []*domain.DistributionRule{rule(1, domain.BeneficiaryNazir, 10000)},
+			wantErr: true,
+		},
+		{
+			name:    "no rules",
+			rules:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "zero share",
+			rules:   []*domain.DistributionRule{rule(1, domain.BeneficiaryMauqufAlaih, 10000), rule(2, domain.BeneficiaryMauqufAlaih, 0)},
+			wantErr: true,
+		},
+		{
+			name:    "unknown beneficiary type",
+			rules:   []*domain.DistributionRule{rule(1, "investor", 10000)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRules(tt.rules)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name  string
+		total int64
+		rules []*domain.DistributionRule
+		want  []int64
+	}{
+		{
+			name:  "even split",
+			total: 1000000,
+			rules: []*domain.DistributionRule{
+				rule(1, domain.BeneficiaryNazir, 1000),
+				rule(2, domain.BeneficiaryMauqufAlaih, 9000),
+			},
+			want: []int64{100000, 900000},
+		},
+		{
+			name:  "remainder goes to the largest beneficiary share",
+			total: 10001,
+			rules: []*domain.DistributionRule{
+				rule(1, domain.BeneficiaryMauqufAlaih, 3000),
+				rule(2, domain.BeneficiaryNazir, 1000),
+				rule(3, domain.BeneficiaryMauqufAlaih, 6000),
+			},
+			want: []int64{3000, 1000, 6001},
+		},
+		{
+			name:  "remainder never goes to the nazir",
+			total: 99,
+			rules: []*domain.DistributionRule{
+				rule(1, domain.BeneficiaryNazir, 1000),
+				rule(2, domain.BeneficiaryMauqufAlaih, 4500),
+				rule(3, domain.BeneficiaryMauqufAlaih, 4500),
+			},
+			want: []int64{9, 46, 44},
+		},
+		{
+			name:  "nothing to distribute",
+			total: 0,
+			rules: []*domain.DistributionRule{rule(1, domain.BeneficiaryMauqufAlaih, 10000)},
+			want:  []int64{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocations, err := allocate(tt.total, tt.rules)
+			if err != nil {
+				t.Fatalf("allocate() error = %v", err)
+			}
+
+			var sum int64
+			for i, allocation := range allocations {
+				if allocation.Amount != tt.want[i] {
+					t.Errorf("allocation %d = %d, want %d", i, allocation.Amount, tt.want[i])
+				}
+				if allocation.RuleID != tt.rules[i].ID {
+					t.Errorf("allocation %d rule = %d, want %d", i, allocation.RuleID, tt.rules[i].ID)
+				}
+				sum += allocation.Amount
+			}
+			if sum != tt.total {
+				t.Errorf("allocations add up to %d, want %d", sum, tt.total)
+			}
+		})
+	}
+}
+
+func TestAllocateRejectsInvalidRules(t *testing.T) {
+	rules := []*domain.DistributionRule{
+		rule(1, domain.BeneficiaryNazir, 2000),
+		rule(2, domain.BeneficiaryMauqufAlaih, 8000),
+	}
+
+	if _, err := allocate(1000000, rules); err == nil {
+		t.Fatal("allocate() with a nazir share over the cap, want error")
+	}
+}
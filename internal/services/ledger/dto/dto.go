@@ -0,0 +1,8 @@
+package dto
+
+// CreatePeriodRequest represents accounting period creation request
+type CreatePeriodRequest struct {
+	Name      string `json:"name,omitempty"` // defaults to YYYY-MM of start_date
+	StartDate string `json:"start_date"`     // YYYY-MM-DD
+	EndDate   string `json:"end_date"`       // YYYY-MM-DD, inclusive
+}
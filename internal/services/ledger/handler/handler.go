@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/ledger/dto"
+	"github.com/akordium-id/waqfwise/internal/services/ledger/service"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+	"github.com/gorilla/mux"
+)
+
+// Handler handles HTTP requests for accounting periods and ledger integrity
+type Handler struct {
+	service service.Service
+	auth    *middleware.Auth
+}
+
+// New creates a new ledger handler
+func New(service service.Service, auth *middleware.Auth) *Handler {
+	return &Handler{
+		service: service,
+		auth:    auth,
+	}
+}
+
+// ListPeriods handles accounting period listing
+func (h *Handler) ListPeriods(w http.ResponseWriter, r *http.Request) {
+	periods, err := h.service.ListPeriods(r.Context())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, periods)
+}
+
+// CreatePeriod handles accounting period creation
+func (h *Handler) CreatePeriod(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreatePeriodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("start_date", req.StartDate)
+	v.Required("end_date", req.EndDate)
+	v.MaxLength("name", req.Name, 50)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	period, err := h.service.CreatePeriod(r.Context(), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, period)
+}
+
+// ClosePeriod handles accounting period close
+func (h *Handler) ClosePeriod(w http.ResponseWriter, r *http.Request) {
+	periodID, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	period, err := h.service.ClosePeriod(r.Context(), middleware.ActorFromContext(r.Context()), periodID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, period)
+}
+
+// Verify handles ledger hash chain verification
+func (h *Handler) Verify(w http.ResponseWriter, r *http.Request) {
+	report, err := h.service.Verify(r.Context())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, report)
+}
+
+// RegisterRoutes registers HTTP routes under the ledger prefix
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	readers := middleware.RequireRole(domain.RoleAdmin, domain.RoleAuditor)
+	admins := middleware.RequireRole(domain.RoleAdmin)
+
+	protected := r.PathPrefix("/").Subrouter()
+	protected.Use(h.auth.Authenticate)
+	protected.Handle("/periods", readers(http.HandlerFunc(h.ListPeriods))).Methods("GET")
+	protected.Handle("/periods", admins(http.HandlerFunc(h.CreatePeriod))).Methods("POST")
+	protected.Handle("/periods/{id:[0-9]+}/close", admins(http.HandlerFunc(h.ClosePeriod))).Methods("POST")
+	protected.Handle("/verify", readers(http.HandlerFunc(h.Verify))).Methods("GET")
+}
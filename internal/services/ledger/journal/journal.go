@@ -0,0 +1,329 @@
+package journal
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// GenesisHash is the previous hash of the first chained ledger entry
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// chainLockKey serializes appends to the ledger hash chain (pg advisory lock)
+const chainLockKey int64 = 7_042_004_001
+
+// Issue describes a break in the ledger hash chain
+type Issue struct {
+	Sequence int64  `json:"sequence"`
+	LedgerID int64  `json:"ledger_id,omitempty"`
+	Problem  string `json:"problem"`
+}
+
+// Report is the result of walking the ledger hash chain
+type Report struct {
+	Valid            bool      `json:"valid"`
+	CheckedEntries   int64     `json:"checked_entries"`
+	UnchainedEntries int64     `json:"unchained_entries"` // rows posted before chaining was introduced
+	HeadSequence     int64     `json:"head_sequence"`
+	HeadHash         string    `json:"head_hash"`
+	Issues           []Issue   `json:"issues"`
+	VerifiedAt       time.Time `json:"verified_at"`
+}
+
+// Head is the latest entry of the hash chain
+type Head struct {
+	Sequence int64
+	Hash     string
+}
+
+// Lock takes the chain lock for the rest of the transaction
+func Lock(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, chainLockKey); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to lock ledger", 500)
+	}
+	return nil
+}
+
+// GetHead gets the latest chained ledger entry. Callers must hold the chain lock.
+func GetHead(ctx context.Context, tx *sql.Tx) (*Head, error) {
+	query := `SELECT sequence, hash FROM ledgers WHERE sequence IS NOT NULL ORDER BY sequence DESC LIMIT 1`
+
+	head := &Head{}
+	err := tx.QueryRowContext(ctx, query).Scan(&head.Sequence, &head.Hash)
+	if err == sql.ErrNoRows {
+		return &Head{Sequence: 0, Hash: GenesisHash}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get ledger head", 500)
+	}
+
+	return head, nil
+}
+
+// Append posts entries to the hash-chained ledger inside tx. Entries dated in a
+// closed accounting period are rejected; adjustments must go into an open period.
+func Append(ctx context.Context, tx *sql.Tx, entries ...*domain.Ledger) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := Lock(ctx, tx); err != nil {
+		return err
+	}
+
+	head, err := GetHead(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// Postgres keeps microseconds; truncate so the stored row hashes the same
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	query := `
+		INSERT INTO ledgers (donation_id, account_type, account_name, amount, balance_before,
+		                     balance_after, description, reference_type, reference_id, entry_date,
+		                     sequence, prev_hash, hash, created_at)
+		VALUES (NULLIF($1, 0), $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id
+	`
+
+	for _, entry := range entries {
+		if entry.EntryDate.IsZero() {
+			entry.EntryDate = now
+		}
+		entry.EntryDate = truncateDate(entry.EntryDate)
+
+		if err := checkPeriodOpen(ctx, tx, entry.EntryDate); err != nil {
+			return err
+		}
+
+		entry.Sequence = head.Sequence + 1
+		entry.PrevHash = head.Hash
+		entry.CreatedAt = now
+		entry.Hash = Hash(entry)
+
+		err := tx.QueryRowContext(
+			ctx, query,
+			entry.DonationID,
+			entry.AccountType,
+			entry.AccountName,
+			entry.Amount,
+			entry.BalanceBefore,
+			entry.BalanceAfter,
+			entry.Description,
+			entry.ReferenceType,
+			entry.ReferenceID,
+			entry.EntryDate,
+			entry.Sequence,
+			entry.PrevHash,
+			entry.Hash,
+			entry.CreatedAt,
+		).Scan(&entry.ID)
+
+		if err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create ledger entry", 500)
+		}
+
+		head = &Head{Sequence: entry.Sequence, Hash: entry.Hash}
+	}
+
+	return nil
+}
+
+// Hash computes the chain hash of an entry from its content and previous hash
+func Hash(entry *domain.Ledger) string {
+	// Fixed field order; every value is a string so the encoding is stable
+	canonical := []string{
+		strconv.FormatInt(entry.Sequence, 10),
+		entry.PrevHash,
+		strconv.FormatInt(entry.DonationID, 10),
+		entry.AccountType,
+		entry.AccountName,
+		strconv.FormatInt(entry.Amount, 10),
+		strconv.FormatInt(entry.BalanceBefore, 10),
+		strconv.FormatInt(entry.BalanceAfter, 10),
+		entry.Description,
+		entry.ReferenceType,
+		strconv.FormatInt(entry.ReferenceID, 10),
+		entry.EntryDate.Format("2006-01-02"),
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+
+	data, _ := json.Marshal(canonical)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify walks the whole ledger hash chain and reports any tampering
+func Verify(ctx context.Context, db *sql.DB) (*Report, error) {
+	report := &Report{
+		Valid:      true,
+		HeadHash:   GenesisHash,
+		Issues:     make([]Issue, 0),
+		VerifiedAt: time.Now(),
+	}
+
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM ledgers WHERE sequence IS NULL`).Scan(&report.UnchainedEntries); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count unchained ledger entries", 500)
+	}
+
+	query := `
+		SELECT id, COALESCE(donation_id, 0), account_type, account_name, amount, balance_before,
+		       balance_after, COALESCE(description, ''), COALESCE(reference_type, ''),
+		       COALESCE(reference_id, 0), entry_date, sequence, prev_hash, hash, created_at
+		FROM ledgers
+		WHERE sequence IS NOT NULL
+		ORDER BY sequence
+	`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to read ledger", 500)
+	}
+	defer rows.Close()
+
+	expected := Head{Sequence: 0, Hash: GenesisHash}
+	hashes := make(map[int64]string)
+
+	for rows.Next() {
+		entry := &domain.Ledger{}
+		if err := rows.Scan(
+			&entry.ID, &entry.DonationID, &entry.AccountType, &entry.AccountName, &entry.Amount,
+			&entry.BalanceBefore, &entry.BalanceAfter, &entry.Description, &entry.ReferenceType,
+			&entry.ReferenceID, &entry.EntryDate, &entry.Sequence, &entry.PrevHash, &entry.Hash,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan ledger entry", 500)
+		}
+
+		report.checkEntry(entry, expected)
+
+		expected = Head{Sequence: entry.Sequence, Hash: entry.Hash}
+		hashes[entry.Sequence] = entry.Hash
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to read ledger", 500)
+	}
+
+	report.HeadSequence = expected.Sequence
+	report.HeadHash = expected.Hash
+
+	// Closed periods pin the chain head at close; a rewritten chain will not match
+	periodRows, err := db.QueryContext(ctx, `
+		SELECT name, closing_sequence, closing_hash
+		FROM accounting_periods
+		WHERE status = $1 AND closing_sequence > 0
+		ORDER BY start_date
+	`, domain.PeriodStatusClosed)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to read accounting periods", 500)
+	}
+	defer periodRows.Close()
+
+	for periodRows.Next() {
+		var name, closingHash string
+		var closingSequence int64
+		if err := periodRows.Scan(&name, &closingSequence, &closingHash); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan accounting period", 500)
+		}
+
+		if hashes[closingSequence] != closingHash {
+			report.Valid = false
+			report.Issues = append(report.Issues, Issue{
+				Sequence: closingSequence,
+				Problem:  fmt.Sprintf("chain no longer matches the hash recorded when period %s was closed", name),
+			})
+		}
+	}
+
+	return report, periodRows.Err()
+}
+
+// checkEntry checks an entry against the chain head before it
+func (r *Report) checkEntry(entry *domain.Ledger, expected Head) {
+	r.CheckedEntries++
+
+	if entry.Sequence != expected.Sequence+1 {
+		r.addIssue(entry, fmt.Sprintf("sequence gap: expected %d, entries may have been deleted", expected.Sequence+1))
+	}
+	if entry.PrevHash != expected.Hash {
+		r.addIssue(entry, "previous hash does not match the preceding entry")
+	}
+	if Hash(entry) != entry.Hash {
+		r.addIssue(entry, "entry content does not match its hash, entry may have been modified")
+	}
+}
+
+// addIssue records a chain break
+func (r *Report) addIssue(entry *domain.Ledger, problem string) {
+	r.Valid = false
+	r.Issues = append(r.Issues, Issue{
+		Sequence: entry.Sequence,
+		LedgerID: entry.ID,
+		Problem:  problem,
+	})
+}
+
+// IsClosed checks if a date falls in a closed accounting period, so
+// postings dated then would be rejected by Append
+func IsClosed(ctx context.Context, tx *sql.Tx, date time.Time) (bool, error) {
+	name, err := closedPeriod(ctx, tx, truncateDate(date))
+	return name != "", err
+}
+
+// checkPeriodOpen rejects postings into a closed accounting period
+func checkPeriodOpen(ctx context.Context, tx *sql.Tx, date time.Time) error {
+	name, err := closedPeriod(ctx, tx, date)
+	if err != nil {
+		return err
+	}
+
+	if name != "" {
+		return errors.New(
+			errors.ErrCodePeriodClosed,
+			fmt.Sprintf("Accounting period %s is closed; post an adjustment into the open period instead", name),
+			409,
+		)
+	}
+
+	return nil
+}
+
+// closedPeriod returns the name of the closed accounting period a date falls
+// in, or "" when its period is open or there is none
+func closedPeriod(ctx context.Context, tx *sql.Tx, date time.Time) (string, error) {
+	query := `
+		SELECT name, status FROM accounting_periods
+		WHERE start_date <= $1 AND end_date >= $1
+		LIMIT 1
+	`
+
+	var name string
+	var status domain.PeriodStatus
+	err := tx.QueryRowContext(ctx, query, date).Scan(&name, &status)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, errors.ErrCodeInternal, "Failed to check accounting period", 500)
+	}
+
+	if status != domain.PeriodStatusClosed {
+		return "", nil
+	}
+	return name, nil
+}
+
+// truncateDate drops the time of day, keeping the calendar date
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
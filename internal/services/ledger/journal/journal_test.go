@@ -0,0 +1,154 @@
+package journal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+)
+
+// chain links entries the way Append does
+func chain(entries ...*domain.Ledger) []*domain.Ledger {
+	head := Head{Sequence: 0, Hash: GenesisHash}
+	for _, entry := range entries {
+		entry.Sequence = head.Sequence + 1
+		entry.PrevHash = head.Hash
+		entry.Hash = Hash(entry)
+		head = Head{Sequence: entry.Sequence, Hash: entry.Hash}
+	}
+	return entries
+}
+
+// walk checks entries in order the way Verify does
+func walk(entries []*domain.Ledger) *Report {
+	report := &Report{Valid: true, Issues: make([]Issue, 0)}
+	expected := Head{Sequence: 0, Hash: GenesisHash}
+	for _, entry := range entries {
+		report.checkEntry(entry, expected)
+		expected = Head{Sequence: entry.Sequence, Hash: entry.Hash}
+	}
+	return report
+}
+
+func testEntries() []*domain.Ledger {
+	createdAt := time.Date(2026, 3, 14, 9, 30, 0, 123456000, time.UTC)
+	entryDate := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	return chain(
+		&domain.Ledger{ID: 1, DonationID: 7, AccountType: "debit", AccountName: "cash_account", Amount: 100000, BalanceAfter: 100000, Description: "Donation received", ReferenceType: domain.LedgerRefDonation, ReferenceID: 7, EntryDate: entryDate, CreatedAt: createdAt},
+		&domain.Ledger{ID: 2, DonationID: 7, AccountType: "credit", AccountName: "campaign_fund", Amount: 100000, BalanceAfter: 100000, Description: "Campaign fund", ReferenceType: domain.LedgerRefDonation, ReferenceID: 7, EntryDate: entryDate, CreatedAt: createdAt},
+		&domain.Ledger{ID: 3, AccountType: "debit", AccountName: "depreciation_expense", Amount: 2500, Description: "Depreciation", ReferenceType: domain.LedgerRefAssetDepreciation, ReferenceID: 4, EntryDate: entryDate, CreatedAt: createdAt},
+	)
+}
+
+func TestHash(t *testing.T) {
+	entry := testEntries()[0]
+	want := Hash(entry)
+
+	if len(want) != 64 {
+		t.Fatalf("Hash() = %q, want 64 hex characters", want)
+	}
+
+	tests := []struct {
+		name   string
+		change func(entry *domain.Ledger)
+	}{
+		{"amount", func(e *domain.Ledger) { e.Amount++ }},
+		{"account", func(e *domain.Ledger) { e.AccountName = "gateway_fee_expense" }},
+		{"account type", func(e *domain.Ledger) { e.AccountType = "credit" }},
+		{"description", func(e *domain.Ledger) { e.Description += " " }},
+		{"reference", func(e *domain.Ledger) { e.ReferenceID++ }},
+		{"entry date", func(e *domain.Ledger) { e.EntryDate = e.EntryDate.AddDate(0, 0, -1) }},
+		{"created at", func(e *domain.Ledger) { e.CreatedAt = e.CreatedAt.Add(time.Microsecond) }},
+		{"sequence", func(e *domain.Ledger) { e.Sequence++ }},
+		{"previous hash", func(e *domain.Ledger) { e.PrevHash = Hash(e) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := *entry
+			tt.change(&changed)
+			if Hash(&changed) == want {
+				t.Errorf("Hash() did not change when the %s changed", tt.name)
+			}
+		})
+	}
+
+	t.Run("ignores ledger id and time zone", func(t *testing.T) {
+		same := *entry
+		same.ID = 99
+		same.CreatedAt = entry.CreatedAt.In(time.FixedZone("WIB", 7*60*60))
+		if Hash(&same) != want {
+			t.Error("Hash() changed for the same entry content")
+		}
+	})
+}
+
+func TestCheckEntry(t *testing.T) {
+	tests := []struct {
+		name       string
+		tamper     func(entries []*domain.Ledger) []*domain.Ledger
+		wantIssues []int64 // sequences reported
+	}{
+		{
+			name:   "untouched chain",
+			tamper: func(entries []*domain.Ledger) []*domain.Ledger { return entries },
+		},
+		{
+			name: "amount edited",
+			tamper: func(entries []*domain.Ledger) []*domain.Ledger {
+				entries[1].Amount = 1
+				return entries
+			},
+			wantIssues: []int64{2},
+		},
+		{
+			name: "edited and rehashed",
+			tamper: func(entries []*domain.Ledger) []*domain.Ledger {
+				entries[0].Amount = 1
+				entries[0].Hash = Hash(entries[0])
+				return entries
+			},
+			wantIssues: []int64{2}, // the next entry no longer links to it
+		},
+		{
+			name: "entry deleted",
+			tamper: func(entries []*domain.Ledger) []*domain.Ledger {
+				return append(entries[:1], entries[2:]...)
+			},
+			wantIssues: []int64{3, 3}, // sequence gap and broken link
+		},
+		{
+			name: "last entry deleted is only caught by a closed period",
+			tamper: func(entries []*domain.Ledger) []*domain.Ledger {
+				return entries[:2]
+			},
+		},
+		{
+			name: "entries swapped",
+			tamper: func(entries []*domain.Ledger) []*domain.Ledger {
+				entries[1], entries[2] = entries[2], entries[1]
+				return entries
+			},
+			wantIssues: []int64{3, 3, 2, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := walk(tt.tamper(testEntries()))
+
+			if report.Valid != (len(tt.wantIssues) == 0) {
+				t.Errorf("Valid = %v with issues %+v", report.Valid, report.Issues)
+			}
+			if len(report.Issues) != len(tt.wantIssues) {
+				t.Fatalf("issues = %+v, want at sequences %v", report.Issues, tt.wantIssues)
+			}
+			for i, issue := range report.Issues {
+				if issue.Sequence != tt.wantIssues[i] {
+					t.Errorf("issue %d at sequence %d, want %d", i, issue.Sequence, tt.wantIssues[i])
+				}
+			}
+		})
+	}
+}
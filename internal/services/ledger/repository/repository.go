@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/ledger/journal"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/lib/pq"
+)
+
+// Repository defines ledger repository interface
+type Repository interface {
+	CreatePeriod(ctx context.Context, period *domain.AccountingPeriod) error
+	FindPeriodByID(ctx context.Context, id int64) (*domain.AccountingPeriod, error)
+	ListPeriods(ctx context.Context) ([]*domain.AccountingPeriod, error)
+	HasOverlappingPeriod(ctx context.Context, start, end time.Time) (bool, error)
+	HasOpenPeriodBefore(ctx context.Context, start time.Time) (bool, error)
+	ClosePeriod(ctx context.Context, period *domain.AccountingPeriod, closedBy int64) error
+	VerifyChain(ctx context.Context) (*journal.Report, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// New creates a new ledger repository
+func New(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// CreatePeriod creates a new accounting period
+func (r *repository) CreatePeriod(ctx context.Context, period *domain.AccountingPeriod) error {
+	query := `
+		INSERT INTO accounting_periods (name, start_date, end_date, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(
+		ctx, query,
+		period.Name,
+		period.StartDate,
+		period.EndDate,
+		period.Status,
+		now,
+		now,
+	).Scan(&period.ID)
+
+	if err != nil {
+		if isOverlapViolation(err) {
+			return errors.New(errors.ErrCodeConflict, "Period overlaps an existing accounting period", 409)
+		}
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create accounting period", 500)
+	}
+
+	period.CreatedAt = now
+	period.UpdatedAt = now
+	return nil
+}
+
+// FindPeriodByID finds accounting period by ID
+func (r *repository) FindPeriodByID(ctx context.Context, id int64) (*domain.AccountingPeriod, error) {
+	query := `
+		SELECT id, name, start_date, end_date, status, closing_sequence, closing_hash,
+		       closed_by, closed_at, created_at, updated_at
+		FROM accounting_periods
+		WHERE id = $1
+	`
+
+	period, err := scanPeriod(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Accounting period not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find accounting period", 500)
+	}
+
+	return period, nil
+}
+
+// ListPeriods lists all accounting periods, latest first
+func (r *repository) ListPeriods(ctx context.Context) ([]*domain.AccountingPeriod, error) {
+	query := `
+		SELECT id, name, start_date, end_date, status, closing_sequence, closing_hash,
+		       closed_by, closed_at, created_at, updated_at
+		FROM accounting_periods
+		ORDER BY start_date DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to list accounting periods", 500)
+	}
+	defer rows.Close()
+
+	periods := make([]*domain.AccountingPeriod, 0)
+	for rows.Next() {
+		period, err := scanPeriod(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan accounting period", 500)
+		}
+		periods = append(periods, period)
+	}
+
+	return periods, rows.Err()
+}
+
+// HasOverlappingPeriod checks if any period intersects [start, end]
+func (r *repository) HasOverlappingPeriod(ctx context.Context, start, end time.Time) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM accounting_periods WHERE start_date <= $2 AND end_date >= $1)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, start, end).Scan(&exists); err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to check accounting periods", 500)
+	}
+
+	return exists, nil
+}
+
+// HasOpenPeriodBefore checks if an earlier period is still open
+func (r *repository) HasOpenPeriodBefore(ctx context.Context, start time.Time) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM accounting_periods WHERE start_date < $1 AND status = $2)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, start, domain.PeriodStatusOpen).Scan(&exists); err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to check accounting periods", 500)
+	}
+
+	return exists, nil
+}
+
+// ClosePeriod closes a period and pins the current ledger chain head. The chain
+// lock keeps postings out while the period is being closed.
+func (r *repository) ClosePeriod(ctx context.Context, period *domain.AccountingPeriod, closedBy int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	if err := journal.Lock(ctx, tx); err != nil {
+		return err
+	}
+
+	head, err := journal.GetHead(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE accounting_periods
+		SET status = $1, closing_sequence = $2, closing_hash = $3, closed_by = $4, closed_at = $5, updated_at = $5
+		WHERE id = $6 AND status = $7
+	`
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, query,
+		domain.PeriodStatusClosed, head.Sequence, head.Hash, closedBy, now, period.ID, domain.PeriodStatusOpen,
+	)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to close accounting period", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New(errors.ErrCodeConflict, "Accounting period is already closed", 409)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit period close", 500)
+	}
+
+	period.Status = domain.PeriodStatusClosed
+	period.ClosingSequence = &head.Sequence
+	period.ClosingHash = head.Hash
+	period.ClosedBy = &closedBy
+	period.ClosedAt = &now
+	period.UpdatedAt = now
+	return nil
+}
+
+// VerifyChain walks the ledger hash chain
+func (r *repository) VerifyChain(ctx context.Context) (*journal.Report, error) {
+	return journal.Verify(ctx, r.db)
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPeriod scans an accounting period row
+func scanPeriod(row rowScanner) (*domain.AccountingPeriod, error) {
+	period := &domain.AccountingPeriod{}
+	var closingSequence, closedBy sql.NullInt64
+	var closingHash sql.NullString
+	var closedAt sql.NullTime
+
+	if err := row.Scan(
+		&period.ID, &period.Name, &period.StartDate, &period.EndDate, &period.Status,
+		&closingSequence, &closingHash, &closedBy, &closedAt, &period.CreatedAt, &period.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if closingSequence.Valid {
+		period.ClosingSequence = &closingSequence.Int64
+	}
+	if closingHash.Valid {
+		period.ClosingHash = closingHash.String
+	}
+	if closedBy.Valid {
+		period.ClosedBy = &closedBy.Int64
+	}
+	if closedAt.Valid {
+		period.ClosedAt = &closedAt.Time
+	}
+
+	return period, nil
+}
+
+// isOverlapViolation checks for a violation of the accounting period exclusion constraint
+func isOverlapViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23P01" && pqErr.Constraint == "excl_accounting_periods_overlap"
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/ledger/dto"
+	"github.com/akordium-id/waqfwise/internal/services/ledger/journal"
+	"github.com/akordium-id/waqfwise/internal/services/ledger/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// Service defines ledger service interface
+type Service interface {
+	CreatePeriod(ctx context.Context, req *dto.CreatePeriodRequest) (*domain.AccountingPeriod, error)
+	ListPeriods(ctx context.Context) ([]*domain.AccountingPeriod, error)
+	ClosePeriod(ctx context.Context, actor *domain.Actor, periodID int64) (*domain.AccountingPeriod, error)
+	Verify(ctx context.Context) (*journal.Report, error)
+}
+
+type service struct {
+	repo repository.Repository
+}
+
+// New creates a new ledger service
+func New(repo repository.Repository) Service {
+	return &service{repo: repo}
+}
+
+// CreatePeriod opens a new accounting period
+func (s *service) CreatePeriod(ctx context.Context, req *dto.CreatePeriodRequest) (*domain.AccountingPeriod, error) {
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeValidation, "start_date must be a date (YYYY-MM-DD)", 400)
+	}
+
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeValidation, "end_date must be a date (YYYY-MM-DD)", 400)
+	}
+
+	if end.Before(start) {
+		return nil, errors.New(errors.ErrCodeValidation, "end_date must not be before start_date", 400)
+	}
+
+	overlaps, err := s.repo.HasOverlappingPeriod(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if overlaps {
+		return nil, errors.New(errors.ErrCodeConflict, "Period overlaps an existing accounting period", 409)
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = start.Format("2006-01")
+	}
+
+	period := &domain.AccountingPeriod{
+		Name:      name,
+		StartDate: start,
+		EndDate:   end,
+		Status:    domain.PeriodStatusOpen,
+	}
+
+	if err := s.repo.CreatePeriod(ctx, period); err != nil {
+		return nil, err
+	}
+
+	return period, nil
+}
+
+// ListPeriods lists accounting periods
+func (s *service) ListPeriods(ctx context.Context) ([]*domain.AccountingPeriod, error) {
+	return s.repo.ListPeriods(ctx)
+}
+
+// ClosePeriod closes an ended period. Periods close in order so that an open
+// period always exists behind the closed ones for adjustments.
+func (s *service) ClosePeriod(ctx context.Context, actor *domain.Actor, periodID int64) (*domain.AccountingPeriod, error) {
+	period, err := s.repo.FindPeriodByID(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+
+	if period.IsClosed() {
+		return nil, errors.New(errors.ErrCodeConflict, "Accounting period is already closed", 409)
+	}
+
+	today := time.Now()
+	if !period.EndDate.Before(time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)) {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Accounting period has not ended yet", 400)
+	}
+
+	openBefore, err := s.repo.HasOpenPeriodBefore(ctx, period.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	if openBefore {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Earlier accounting periods must be closed first", 400)
+	}
+
+	if err := s.repo.ClosePeriod(ctx, period, actor.UserID); err != nil {
+		return nil, err
+	}
+
+	return period, nil
+}
+
+// Verify walks the ledger hash chain and reports tampering
+func (s *service) Verify(ctx context.Context) (*journal.Report, error) {
+	return s.repo.VerifyChain(ctx)
+}
@@ -5,6 +5,7 @@ import "github.com/akordium-id/waqfwise/internal/shared/domain"
 // CreateDonationRequest represents donation creation request
 type CreateDonationRequest struct {
 	CampaignID      int64                   `json:"campaign_id"`
+	FundraiserID    *int64                  `json:"fundraiser_id,omitempty"` // donating through a fundraiser page
 	Amount          int64                   `json:"amount"`
 	PaymentMethod   domain.PaymentMethod    `json:"payment_method"`
 	PaymentGateway  domain.PaymentGateway   `json:"payment_gateway"`
@@ -28,6 +29,7 @@ type PaymentCallbackRequest struct {
 type DonationResponse struct {
 	ID              int64                   `json:"id"`
 	CampaignID      int64                   `json:"campaign_id"`
+	FundraiserID    *int64                  `json:"fundraiser_id,omitempty"`
 	UserID          int64                   `json:"user_id"`
 	Amount          int64                   `json:"amount"`
 	Status          domain.PaymentStatus    `json:"status"`
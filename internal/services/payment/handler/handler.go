@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"github.com/akordium-id/waqfwise/internal/services/payment/dto"
+	"github.com/akordium-id/waqfwise/internal/services/payment/service"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+	"github.com/gorilla/mux"
+)
+
+var (
+	callbackGateways = []string{
+		string(domain.PaymentGatewayMidtrans),
+		string(domain.PaymentGatewayXendit),
+		string(domain.PaymentGatewayManual),
+	}
+	callbackStatuses = []string{
+		string(domain.PaymentStatusPending),
+		string(domain.PaymentStatusProcessing),
+		string(domain.PaymentStatusSuccess),
+		string(domain.PaymentStatusFailed),
+		string(domain.PaymentStatusCancelled),
+	}
+)
+
+// Handler handles HTTP requests for payment gateway callbacks
+type Handler struct {
+	settlement    *service.Settlement
+	callbackToken string
+}
+
+// New creates a new payment handler. Callbacks are rejected while no
+// callback token is configured.
+func New(settlement *service.Settlement, callbackToken string) *Handler {
+	return &Handler{
+		settlement:    settlement,
+		callbackToken: callbackToken,
+	}
+}
+
+// Callback handles a payment gateway callback, normalized by the
+// integration service and signed with the shared callback token
+func (h *Handler) Callback(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("X-Callback-Token")
+	if h.callbackToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.callbackToken)) != 1 {
+		response.Error(w, errors.ErrUnauthorized)
+		return
+	}
+
+	var req dto.PaymentCallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Required("gateway", string(req.Gateway))
+	v.In("gateway", string(req.Gateway), callbackGateways)
+	v.Required("transaction_id", req.TransactionID)
+	v.Required("status", req.Status)
+	v.In("status", req.Status, callbackStatuses)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	donation, err := h.settlement.HandleCallback(r.Context(), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, donation)
+}
+
+// RegisterRoutes registers HTTP routes under the payments prefix
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/callback", h.Callback).Methods("POST")
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/akordium-id/waqfwise/internal/services/ledger/journal"
 	"github.com/akordium-id/waqfwise/internal/shared/domain"
 	"github.com/akordium-id/waqfwise/internal/shared/errors"
 )
@@ -15,6 +16,8 @@ type Repository interface {
 	FindDonationByID(ctx context.Context, id int64) (*domain.Donation, error)
 	FindDonationByTransactionID(ctx context.Context, txID string) (*domain.Donation, error)
 	UpdateDonationStatus(ctx context.Context, id int64, status domain.PaymentStatus) error
+	SettleDonation(ctx context.Context, donation *domain.Donation, compose DonationEntriesFunc) (bool, error)
+	MarkDonationFailed(ctx context.Context, donation *domain.Donation, status domain.PaymentStatus) (bool, error)
 	CreatePaymentLog(ctx context.Context, log *domain.PaymentLog) error
 	CreateLedgerEntry(ctx context.Context, entry *domain.Ledger) error
 	GetCampaignBalance(ctx context.Context, campaignID int64) (int64, error)
@@ -35,10 +38,10 @@ func New(db *sql.DB) Repository {
 // CreateDonation creates a new donation
 func (r *repository) CreateDonation(ctx context.Context, donation *domain.Donation) error {
 	query := `
-		INSERT INTO donations (campaign_id, user_id, amount, status, payment_method, payment_gateway,
+		INSERT INTO donations (campaign_id, fundraiser_id, user_id, amount, status, payment_method, payment_gateway,
 		                       transaction_id, is_anonymous, donor_name, donor_email, message,
 		                       is_recurring, recurring_period, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING id
 	`
 
@@ -46,6 +49,7 @@ func (r *repository) CreateDonation(ctx context.Context, donation *domain.Donati
 	err := r.db.QueryRowContext(
 		ctx, query,
 		donation.CampaignID,
+		donation.FundraiserID,
 		donation.UserID,
 		donation.Amount,
 		donation.Status,
@@ -74,7 +78,7 @@ func (r *repository) CreateDonation(ctx context.Context, donation *domain.Donati
 // FindDonationByID finds donation by ID
 func (r *repository) FindDonationByID(ctx context.Context, id int64) (*domain.Donation, error) {
 	query := `
-		SELECT id, campaign_id, user_id, amount, status, payment_method, payment_gateway,
+		SELECT id, campaign_id, fundraiser_id, user_id, amount, status, payment_method, payment_gateway,
 		       transaction_id, gateway_ref, is_anonymous, donor_name, donor_email, message,
 		       is_recurring, recurring_period, receipt_url, paid_at, created_at, updated_at
 		FROM donations
@@ -83,11 +87,13 @@ func (r *repository) FindDonationByID(ctx context.Context, id int64) (*domain.Do
 
 	donation := &domain.Donation{}
 	var paidAt sql.NullTime
+	var fundraiserID sql.NullInt64
 	var gatewayRef, donorName, donorEmail, message, recurringPeriod, receiptURL sql.NullString
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&donation.ID,
 		&donation.CampaignID,
+		&fundraiserID,
 		&donation.UserID,
 		&donation.Amount,
 		&donation.Status,
@@ -114,6 +120,9 @@ func (r *repository) FindDonationByID(ctx context.Context, id int64) (*domain.Do
 		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find donation", 500)
 	}
 
+	if fundraiserID.Valid {
+		donation.FundraiserID = &fundraiserID.Int64
+	}
 	if gatewayRef.Valid {
 		donation.GatewayRef = gatewayRef.String
 	}
@@ -142,7 +151,7 @@ func (r *repository) FindDonationByID(ctx context.Context, id int64) (*domain.Do
 // FindDonationByTransactionID finds donation by transaction ID
 func (r *repository) FindDonationByTransactionID(ctx context.Context, txID string) (*domain.Donation, error) {
 	query := `
-		SELECT id, campaign_id, user_id, amount, status, payment_method, payment_gateway,
+		SELECT id, campaign_id, fundraiser_id, user_id, amount, status, payment_method, payment_gateway,
 		       transaction_id, gateway_ref, is_anonymous, donor_name, donor_email, message,
 		       is_recurring, recurring_period, receipt_url, paid_at, created_at, updated_at
 		FROM donations
@@ -151,11 +160,13 @@ func (r *repository) FindDonationByTransactionID(ctx context.Context, txID strin
 
 	donation := &domain.Donation{}
 	var paidAt sql.NullTime
+	var fundraiserID sql.NullInt64
 	var gatewayRef, donorName, donorEmail, message, recurringPeriod, receiptURL sql.NullString
 
 	err := r.db.QueryRowContext(ctx, query, txID).Scan(
 		&donation.ID,
 		&donation.CampaignID,
+		&fundraiserID,
 		&donation.UserID,
 		&donation.Amount,
 		&donation.Status,
@@ -183,6 +194,9 @@ func (r *repository) FindDonationByTransactionID(ctx context.Context, txID strin
 	}
 
 	// Handle nullable fields
+	if fundraiserID.Valid {
+		donation.FundraiserID = &fundraiserID.Int64
+	}
 	if gatewayRef.Valid {
 		donation.GatewayRef = gatewayRef.String
 	}
@@ -225,6 +239,87 @@ func (r *repository) UpdateDonationStatus(ctx context.Context, id int64, status
 	return nil
 }
 
+// DonationEntriesFunc builds the ledger entries of a paid donation given the
+// campaign balance before it
+type DonationEntriesFunc func(donation *domain.Donation, balance int64) []*domain.Ledger
+
+// SettleDonation marks a donation as paid and posts its ledger entries in
+// one transaction, so a paid donation is always booked. Only a donation still
+// awaiting payment is settled; it reports false when the donation is already
+// final, so replayed callbacks are no-ops and a late success never revives a
+// failed, cancelled or refunded donation. A failed posting leaves the
+// donation unpaid for the replay.
+func (r *repository) SettleDonation(ctx context.Context, donation *domain.Donation, compose DonationEntriesFunc) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE donations SET status = $1, paid_at = $2, updated_at = $2
+		WHERE id = $3 AND status IN ($4, $5)
+	`
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, query,
+		domain.PaymentStatusSuccess, now, donation.ID, domain.PaymentStatusPending, domain.PaymentStatusProcessing,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to update donation status", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return false, nil
+	}
+
+	var balance int64
+	if err := tx.QueryRowContext(ctx, campaignBalanceQuery, donation.CampaignID).Scan(&balance); err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get campaign balance", 500)
+	}
+
+	if err := journal.Append(ctx, tx, compose(donation, balance)...); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit donation settlement", 500)
+	}
+
+	donation.Status = domain.PaymentStatusSuccess
+	donation.PaidAt = &now
+	donation.UpdatedAt = now
+	return true, nil
+}
+
+// MarkDonationFailed moves a donation still awaiting payment to a failed
+// status. It reports false when the donation was already settled, so a late
+// failure callback never undoes a payment.
+func (r *repository) MarkDonationFailed(ctx context.Context, donation *domain.Donation, status domain.PaymentStatus) (bool, error) {
+	query := `
+		UPDATE donations SET status = $1, updated_at = $2
+		WHERE id = $3 AND status IN ($4, $5)
+	`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query,
+		status, now, donation.ID, domain.PaymentStatusPending, domain.PaymentStatusProcessing,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrCodeInternal, "Failed to update donation status", 500)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return false, nil
+	}
+
+	donation.Status = status
+	donation.UpdatedAt = now
+	return true, nil
+}
+
 // CreatePaymentLog creates payment log
 func (r *repository) CreatePaymentLog(ctx context.Context, log *domain.PaymentLog) error {
 	query := `
@@ -254,45 +349,37 @@ func (r *repository) CreatePaymentLog(ctx context.Context, log *domain.PaymentLo
 	return nil
 }
 
-// CreateLedgerEntry creates ledger entry
+// CreateLedgerEntry appends ledger entry to the hash-chained ledger
 func (r *repository) CreateLedgerEntry(ctx context.Context, entry *domain.Ledger) error {
-	query := `
-		INSERT INTO ledgers (donation_id, account_type, account_name, amount, balance_before,
-		                     balance_after, description, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id
-	`
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
 
-	err := r.db.QueryRowContext(
-		ctx, query,
-		entry.DonationID,
-		entry.AccountType,
-		entry.AccountName,
-		entry.Amount,
-		entry.BalanceBefore,
-		entry.BalanceAfter,
-		entry.Description,
-		time.Now(),
-	).Scan(&entry.ID)
+	if err := journal.Append(ctx, tx, entry); err != nil {
+		return err
+	}
 
-	if err != nil {
-		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create ledger entry", 500)
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit ledger entry", 500)
 	}
 
 	return nil
 }
 
+// campaignBalanceQuery sums the ledger entries of a campaign's donations
+const campaignBalanceQuery = `
+	SELECT COALESCE(SUM(CASE WHEN account_type = 'credit' THEN amount ELSE -amount END), 0)
+	FROM ledgers l
+	JOIN donations d ON l.donation_id = d.id
+	WHERE d.campaign_id = $1
+`
+
 // GetCampaignBalance gets campaign balance from ledger
 func (r *repository) GetCampaignBalance(ctx context.Context, campaignID int64) (int64, error) {
-	query := `
-		SELECT COALESCE(SUM(CASE WHEN account_type = 'credit' THEN amount ELSE -amount END), 0)
-		FROM ledgers l
-		JOIN donations d ON l.donation_id = d.id
-		WHERE d.campaign_id = $1
-	`
-
 	var balance int64
-	err := r.db.QueryRowContext(ctx, query, campaignID).Scan(&balance)
+	err := r.db.QueryRowContext(ctx, campaignBalanceQuery, campaignID).Scan(&balance)
 	if err != nil {
 		return 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get campaign balance", 500)
 	}
@@ -340,7 +427,7 @@ func (r *repository) GetDonationsByUser(ctx context.Context, userID int64, limit
 
 	// Get donations
 	query := `
-		SELECT id, campaign_id, user_id, amount, status, payment_method, payment_gateway,
+		SELECT id, campaign_id, fundraiser_id, user_id, amount, status, payment_method, payment_gateway,
 		       transaction_id, is_anonymous, message, created_at
 		FROM donations
 		WHERE user_id = $1
@@ -357,16 +444,20 @@ func (r *repository) GetDonationsByUser(ctx context.Context, userID int64, limit
 	donations := make([]*domain.Donation, 0)
 	for rows.Next() {
 		d := &domain.Donation{}
+		var fundraiserID sql.NullInt64
 		var message sql.NullString
 
 		if err := rows.Scan(
-			&d.ID, &d.CampaignID, &d.UserID, &d.Amount, &d.Status,
+			&d.ID, &d.CampaignID, &fundraiserID, &d.UserID, &d.Amount, &d.Status,
 			&d.PaymentMethod, &d.PaymentGateway, &d.TransactionID,
 			&d.IsAnonymous, &message, &d.CreatedAt,
 		); err != nil {
 			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan donation", 500)
 		}
 
+		if fundraiserID.Valid {
+			d.FundraiserID = &fundraiserID.Int64
+		}
 		if message.Valid {
 			d.Message = message.String
 		}
@@ -377,7 +468,8 @@ func (r *repository) GetDonationsByUser(ctx context.Context, userID int64, limit
 	return donations, total, nil
 }
 
-// GetDonationsByCampaign gets donations by campaign
+// GetDonationsByCampaign gets successful donations to a campaign, with
+// anonymous donors masked
 func (r *repository) GetDonationsByCampaign(ctx context.Context, campaignID int64, limit, offset int) ([]*domain.Donation, int64, error) {
 	// Get total count
 	var total int64
@@ -388,7 +480,7 @@ func (r *repository) GetDonationsByCampaign(ctx context.Context, campaignID int6
 
 	// Get donations
 	query := `
-		SELECT id, campaign_id, user_id, amount, status, payment_method, payment_gateway,
+		SELECT id, campaign_id, fundraiser_id, user_id, amount, status, payment_method, payment_gateway,
 		       transaction_id, is_anonymous, donor_name, message, created_at
 		FROM donations
 		WHERE campaign_id = $1 AND status = $2
@@ -405,16 +497,20 @@ func (r *repository) GetDonationsByCampaign(ctx context.Context, campaignID int6
 	donations := make([]*domain.Donation, 0)
 	for rows.Next() {
 		d := &domain.Donation{}
+		var fundraiserID sql.NullInt64
 		var donorName, message sql.NullString
 
 		if err := rows.Scan(
-			&d.ID, &d.CampaignID, &d.UserID, &d.Amount, &d.Status,
+			&d.ID, &d.CampaignID, &fundraiserID, &d.UserID, &d.Amount, &d.Status,
 			&d.PaymentMethod, &d.PaymentGateway, &d.TransactionID,
 			&d.IsAnonymous, &donorName, &message, &d.CreatedAt,
 		); err != nil {
 			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan donation", 500)
 		}
 
+		if fundraiserID.Valid {
+			d.FundraiserID = &fundraiserID.Int64
+		}
 		if donorName.Valid {
 			d.DonorName = donorName.String
 		}
@@ -422,6 +518,12 @@ func (r *repository) GetDonationsByCampaign(ctx context.Context, campaignID int6
 			d.Message = message.String
 		}
 
+		// Never identify anonymous donors, whoever the caller is
+		if d.IsAnonymous {
+			d.DonorName = domain.AnonymousDonorName
+			d.UserID = 0
+		}
+
 		donations = append(donations, d)
 	}
 
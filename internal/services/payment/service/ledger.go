@@ -20,50 +20,59 @@ func NewLedgerManager(repo repository.Repository) *LedgerManager {
 
 // RecordDonation records donation in ledger using double-entry bookkeeping
 func (l *LedgerManager) RecordDonation(ctx context.Context, donation *domain.Donation) error {
-	// Calculate amounts
-	totalAmount := donation.Amount
-	gatewayFee := l.calculateGatewayFee(donation.PaymentGateway, totalAmount)
-	netAmount := totalAmount - gatewayFee
-
 	// Get current campaign balance
 	currentBalance, err := l.repo.GetCampaignBalance(ctx, donation.CampaignID)
 	if err != nil {
 		return err
 	}
 
-	// Entry 1: Debit - Cash/Bank Account (money coming in)
-	debitEntry := &domain.Ledger{
-		DonationID:    donation.ID,
-		AccountType:   "debit",
-		AccountName:   "cash_account",
-		Amount:        totalAmount,
-		BalanceBefore: currentBalance,
-		BalanceAfter:  currentBalance + totalAmount,
-		Description:   fmt.Sprintf("Donation received from transaction %s", donation.TransactionID),
+	for _, entry := range l.DonationEntries(donation, currentBalance) {
+		if err := l.repo.CreateLedgerEntry(ctx, entry); err != nil {
+			return err
+		}
 	}
 
-	if err := l.repo.CreateLedgerEntry(ctx, debitEntry); err != nil {
-		return err
-	}
+	return nil
+}
 
-	// Entry 2: Credit - Campaign Fund (liability to campaign)
-	creditEntry := &domain.Ledger{
-		DonationID:    donation.ID,
-		AccountType:   "credit",
-		AccountName:   "campaign_fund",
-		Amount:        netAmount,
-		BalanceBefore: currentBalance,
-		BalanceAfter:  currentBalance + netAmount,
-		Description:   fmt.Sprintf("Campaign fund for campaign ID %d", donation.CampaignID),
-	}
+// DonationEntries builds the double-entry ledger entries of a paid donation
+// given the campaign balance before it
+func (l *LedgerManager) DonationEntries(donation *domain.Donation, currentBalance int64) []*domain.Ledger {
+	// Calculate amounts
+	totalAmount := donation.Amount
+	gatewayFee := l.calculateGatewayFee(donation.PaymentGateway, totalAmount)
+	netAmount := totalAmount - gatewayFee
 
-	if err := l.repo.CreateLedgerEntry(ctx, creditEntry); err != nil {
-		return err
+	entries := []*domain.Ledger{
+		// Entry 1: Debit - Cash/Bank Account (money coming in)
+		{
+			DonationID:    donation.ID,
+			AccountType:   "debit",
+			AccountName:   "cash_account",
+			Amount:        totalAmount,
+			BalanceBefore: currentBalance,
+			BalanceAfter:  currentBalance + totalAmount,
+			Description:   fmt.Sprintf("Donation received from transaction %s", donation.TransactionID),
+			ReferenceType: domain.LedgerRefDonation,
+			ReferenceID:   donation.ID,
+		},
+		// Entry 2: Credit - Campaign Fund (liability to campaign)
+		{
+			DonationID:    donation.ID,
+			AccountType:   "credit",
+			AccountName:   "campaign_fund",
+			Amount:        netAmount,
+			BalanceBefore: currentBalance,
+			BalanceAfter:  currentBalance + netAmount,
+			Description:   fmt.Sprintf("Campaign fund for campaign ID %d", donation.CampaignID),
+			ReferenceType: domain.LedgerRefDonation,
+			ReferenceID:   donation.ID,
+		},
 	}
 
 	// Entry 3: Credit - Gateway Fee Expense
 	if gatewayFee > 0 {
-		feeEntry := &domain.Ledger{
+		entries = append(entries, &domain.Ledger{
 			DonationID:    donation.ID,
 			AccountType:   "credit",
 			AccountName:   "gateway_fee_expense",
@@ -71,14 +80,12 @@ func (l *LedgerManager) RecordDonation(ctx context.Context, donation *domain.Don
 			BalanceBefore: currentBalance,
 			BalanceAfter:  currentBalance,
 			Description:   fmt.Sprintf("Gateway fee for %s", donation.PaymentGateway),
-		}
-
-		if err := l.repo.CreateLedgerEntry(ctx, feeEntry); err != nil {
-			return err
-		}
+			ReferenceType: domain.LedgerRefDonation,
+			ReferenceID:   donation.ID,
+		})
 	}
 
-	return nil
+	return entries
 }
 
 // calculateGatewayFee calculates payment gateway fee
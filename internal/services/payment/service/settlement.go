@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+
+	"github.com/akordium-id/waqfwise/internal/services/payment/dto"
+	"github.com/akordium-id/waqfwise/internal/services/payment/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/events"
+)
+
+// Settlement completes donations confirmed by the payment gateway
+type Settlement struct {
+	repo      repository.Repository
+	ledger    *LedgerManager
+	publisher events.Publisher
+}
+
+// NewSettlement creates a new settlement handler
+func NewSettlement(repo repository.Repository, ledger *LedgerManager, publisher events.Publisher) *Settlement {
+	return &Settlement{
+		repo:      repo,
+		ledger:    ledger,
+		publisher: publisher,
+	}
+}
+
+// HandleCallback applies a payment gateway callback to its donation and
+// logs it. Callbacks may arrive more than once and out of order: a paid
+// donation is settled once, a failure never undoes a payment, and a success
+// for a failed, cancelled or refunded donation is only logged.
+func (s *Settlement) HandleCallback(ctx context.Context, req *dto.PaymentCallbackRequest) (*domain.Donation, error) {
+	donation, err := s.repo.FindDonationByTransactionID(ctx, req.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if donation.PaymentGateway != req.Gateway {
+		return nil, errors.New(errors.ErrCodeValidation, "Callback gateway does not match the donation", 400)
+	}
+
+	raw, _ := json.Marshal(req.RawData)
+	status := domain.PaymentStatus(req.Status)
+	if err := s.repo.CreatePaymentLog(ctx, &domain.PaymentLog{
+		DonationID:  donation.ID,
+		Status:      status,
+		Gateway:     req.Gateway,
+		RequestData: string(raw),
+	}); err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case domain.PaymentStatusSuccess:
+		return s.MarkSucceeded(ctx, donation.ID)
+	case domain.PaymentStatusFailed, domain.PaymentStatusCancelled:
+		if _, err := s.repo.MarkDonationFailed(ctx, donation, status); err != nil {
+			return nil, err
+		}
+	}
+
+	return donation, nil
+}
+
+// MarkSucceeded marks a donation as paid and books it in the ledger in one
+// transaction, then publishes a donation.succeeded event. Replayed
+// callbacks are no-ops.
+func (s *Settlement) MarkSucceeded(ctx context.Context, donationID int64) (*domain.Donation, error) {
+	donation, err := s.repo.FindDonationByID(ctx, donationID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := s.repo.SettleDonation(ctx, donation, s.ledger.DonationEntries)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		if donation.Status != domain.PaymentStatusSuccess {
+			log.Printf("payment settlement: success callback for donation %d ignored, donation is %s", donation.ID, donation.Status)
+		}
+		return donation, nil
+	}
+
+	s.publisher.Publish(ctx, events.Event{
+		Type: events.DonationSucceeded,
+		Key:  strconv.FormatInt(donation.ID, 10),
+		Payload: events.DonationSucceededPayload{
+			DonationID: donation.ID,
+			CampaignID: donation.CampaignID,
+			UserID:     donation.UserID,
+			DonorEmail: donation.DonorEmail,
+			Amount:     donation.Amount,
+			PaidAt:     *donation.PaidAt,
+		},
+	})
+
+	return donation, nil
+}
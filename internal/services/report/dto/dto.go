@@ -0,0 +1,15 @@
+package dto
+
+// PeriodQuery selects the reporting period; semester 0 means the full year
+type PeriodQuery struct {
+	Year     int `json:"year"`
+	Semester int `json:"semester,omitempty"` // 1 = Jan-Jun, 2 = Jul-Dec
+}
+
+// SubmitReportRequest represents BWI report submission request
+type SubmitReportRequest struct {
+	Year      int    `json:"year"`
+	Semester  int    `json:"semester,omitempty"`
+	Reference string `json:"reference,omitempty"` // BWI receipt number
+	Notes     string `json:"notes,omitempty"`
+}
@@ -0,0 +1,87 @@
+// Package export renders tabular reports as XLSX workbooks and PDF documents
+// without external dependencies.
+package export
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format represents an export file format
+type Format string
+
+const (
+	FormatXLSX Format = "xlsx"
+	FormatPDF  Format = "pdf"
+)
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatPDF:
+		return "application/pdf"
+	}
+	return "application/octet-stream"
+}
+
+// Document is a titled set of tables
+type Document struct {
+	Title    string
+	Subtitle string
+	Tables   []Table
+}
+
+// Table is a named table; cells are string, int, int64 or float64
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Write renders doc in the given format
+func Write(w io.Writer, format Format, doc *Document) error {
+	switch format {
+	case FormatPDF:
+		return WritePDF(w, doc)
+	default:
+		return WriteXLSX(w, doc)
+	}
+}
+
+// cellText formats a cell for text output; integers get thousand separators
+func cellText(v interface{}) string {
+	switch c := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return c
+	case int:
+		return groupThousands(strconv.FormatInt(int64(c), 10))
+	case int64:
+		return groupThousands(strconv.FormatInt(c, 10))
+	case float64:
+		return strconv.FormatFloat(c, 'f', 2, 64)
+	}
+	return ""
+}
+
+// groupThousands inserts Indonesian thousand separators (dots)
+func groupThousands(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+
+	return sign + b.String()
+}
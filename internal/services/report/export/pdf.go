@@ -0,0 +1,260 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Page layout: A4 landscape, monospaced text so tables line up
+const (
+	pageWidth    = 842
+	pageHeight   = 595
+	pageMargin   = 36
+	fontSize     = 8
+	lineHeight   = 11
+	charWidth    = 0.6 * fontSize // Courier advance width
+	maxCellWidth = 40
+)
+
+// pdfLine is a line of text on a page
+type pdfLine struct {
+	text string
+	bold bool
+}
+
+// WritePDF renders doc as a PDF with tables laid out in fixed-width columns
+func WritePDF(w io.Writer, doc *Document) error {
+	if err := CheckPDFText(doc); err != nil {
+		return err
+	}
+
+	usable := float64(pageWidth - 2*pageMargin)
+	maxChars := int(usable / charWidth)
+
+	lines := []pdfLine{{text: doc.Title, bold: true}}
+	if doc.Subtitle != "" {
+		lines = append(lines, pdfLine{text: doc.Subtitle})
+	}
+
+	for _, table := range doc.Tables {
+		lines = append(lines, pdfLine{}, pdfLine{text: table.Name, bold: true})
+		lines = append(lines, tableLines(table, maxChars)...)
+	}
+
+	linesPerPage := (pageHeight - 2*pageMargin) / lineHeight
+	var pages [][]pdfLine
+	for len(lines) > 0 {
+		n := linesPerPage
+		if n > len(lines) {
+			n = len(lines)
+		}
+		pages = append(pages, lines[:n])
+		lines = lines[n:]
+	}
+
+	return writePDFPages(w, pages)
+}
+
+// tableLines lays out a table as padded text rows
+func tableLines(table Table, maxChars int) []pdfLine {
+	widths := make([]int, len(table.Columns))
+	for i, c := range table.Columns {
+		widths[i] = utf8.RuneCountInString(c)
+	}
+	for _, row := range table.Rows {
+		for i, v := range row {
+			if i < len(widths) {
+				if n := utf8.RuneCountInString(cellText(v)); n > widths[i] {
+					widths[i] = n
+				}
+			}
+		}
+	}
+	for i := range widths {
+		if widths[i] > maxCellWidth {
+			widths[i] = maxCellWidth
+		}
+	}
+
+	format := func(cells []interface{}, header bool) string {
+		parts := make([]string, len(widths))
+		for i := range widths {
+			var v interface{}
+			if i < len(cells) {
+				v = cells[i]
+			}
+			text := []rune(cellText(v))
+			if len(text) > widths[i] {
+				text = append(text[:widths[i]-1], '~')
+			}
+			pad := strings.Repeat(" ", widths[i]-len(text))
+			switch v.(type) {
+			case int, int64, float64:
+				if !header {
+					parts[i] = pad + string(text)
+					continue
+				}
+			}
+			parts[i] = string(text) + pad
+		}
+
+		line := []rune(strings.Join(parts, "  "))
+		if len(line) > maxChars {
+			line = line[:maxChars]
+		}
+		return string(line)
+	}
+
+	header := make([]interface{}, len(table.Columns))
+	total := 0
+	for i, c := range table.Columns {
+		header[i] = c
+		total += widths[i] + 2
+	}
+
+	lines := []pdfLine{
+		{text: format(header, true), bold: true},
+		{text: strings.Repeat("-", minInt(total, maxChars))},
+	}
+	for _, row := range table.Rows {
+		lines = append(lines, pdfLine{text: format(row, false)})
+	}
+	if len(table.Rows) == 0 {
+		lines = append(lines, pdfLine{text: "(tidak ada data)"})
+	}
+
+	return lines
+}
+
+// writePDFPages writes a minimal PDF 1.4 file
+func writePDFPages(w io.Writer, pages [][]pdfLine) error {
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1-4 are the catalog, page tree and fonts; each page then adds a
+	// page object followed by its content stream.
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, lines := range pages {
+		var content bytes.Buffer
+		content.WriteString("BT\n")
+		fmt.Fprintf(&content, "%d TL\n%d %d Td\n", lineHeight, pageMargin, pageHeight-pageMargin-fontSize)
+		for _, line := range lines {
+			font := "F1"
+			if line.bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "/%s %d Tf\n(%s) Tj T*\n", font, fontSize, pdfString(line.text))
+		}
+		fmt.Fprintf(&content, "ET\nBT /F1 %d Tf %d %d Td (%d / %d) Tj ET\n", fontSize, pageWidth-pageMargin-40, pageMargin/2, i+1, len(pages))
+
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// CheckPDFText checks that every text of doc can be printed with the
+// WinAnsi-encoded standard fonts. Scripts such as Arabic cannot, and are
+// refused rather than printed as question marks.
+func CheckPDFText(doc *Document) error {
+	texts := []string{doc.Title, doc.Subtitle}
+	for _, table := range doc.Tables {
+		texts = append(texts, table.Name)
+		texts = append(texts, table.Columns...)
+		for _, row := range table.Rows {
+			for _, v := range row {
+				if text, ok := v.(string); ok {
+					texts = append(texts, text)
+				}
+			}
+		}
+	}
+
+	for _, text := range texts {
+		for _, r := range text {
+			if _, ok := winAnsi(r); !ok {
+				return fmt.Errorf("PDF fonts cannot print %q in %q", r, text)
+			}
+		}
+	}
+	return nil
+}
+
+// pdfString escapes text for a PDF literal string in WinAnsi encoding;
+// text is checked with CheckPDFText first
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x80:
+			b.WriteRune(r)
+		default:
+			code, _ := winAnsi(r)
+			fmt.Fprintf(&b, "\\%03o", code)
+		}
+	}
+	return b.String()
+}
+
+// winAnsiExtra maps the characters WinAnsi places in 0x80-0x9F, where
+// Latin-1 has control codes
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// winAnsi returns the WinAnsi code of r
+func winAnsi(r rune) (byte, bool) {
+	switch {
+	case r < 0x80:
+		return byte(r), true
+	case r >= 0xA0 && r <= 0xFF:
+		return byte(r), true
+	}
+	code, ok := winAnsiExtra[r]
+	return code, ok
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
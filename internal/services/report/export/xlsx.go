@@ -0,0 +1,176 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Cell styles defined in xlsxStyles
+const (
+	styleDefault = 0
+	styleBold    = 1
+	styleNumber  = 2
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+%s</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="3">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="3" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+</cellXfs>
+</styleSheet>`
+
+// WriteXLSX renders doc as a workbook with one sheet per table
+func WriteXLSX(w io.Writer, doc *Document) error {
+	zw := zip.NewWriter(w)
+
+	var overrides, sheets, rels strings.Builder
+	for i, table := range doc.Tables {
+		n := i + 1
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", n)
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(sheetName(table.Name, n)), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`+"\n", n, n)
+	}
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`+"\n", len(doc.Tables)+1)
+
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets>` + sheets.String() + `</sheets>
+</workbook>`
+
+	workbookRels := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+` + rels.String() + `</Relationships>`
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, overrides.String())},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", workbook},
+		{"xl/_rels/workbook.xml.rels", workbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	for i, table := range doc.Tables {
+		f, err := zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(worksheet(doc, table)); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// worksheet renders a table below the document title
+func worksheet(doc *Document, table Table) []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	row := 1
+	writeRow := func(style int, cells []interface{}) {
+		fmt.Fprintf(&b, `<row r="%d">`, row)
+		for col, v := range cells {
+			ref := columnName(col) + strconv.Itoa(row)
+			switch c := v.(type) {
+			case int, int64, float64:
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%v</v></c>`, ref, styleNumber, c)
+			default:
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, escapeXML(cellText(v)))
+			}
+		}
+		b.WriteString(`</row>`)
+		row++
+	}
+
+	writeRow(styleBold, []interface{}{doc.Title})
+	if doc.Subtitle != "" {
+		writeRow(styleDefault, []interface{}{doc.Subtitle})
+	}
+	writeRow(styleBold, []interface{}{table.Name})
+	row++
+
+	header := make([]interface{}, len(table.Columns))
+	for i, c := range table.Columns {
+		header[i] = c
+	}
+	writeRow(styleBold, header)
+
+	for _, cells := range table.Rows {
+		writeRow(styleDefault, cells)
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.Bytes()
+}
+
+// columnName converts a zero-based column index to A, B, ..., AA
+func columnName(col int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name
+}
+
+// sheetName makes a valid, unique sheet name (max 31 chars, no []:*?/\)
+func sheetName(name string, n int) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+
+	prefix := strconv.Itoa(n) + ". "
+	runes := []rune(name)
+	if len(prefix)+len(runes) > 31 {
+		runes = runes[:31-len(prefix)]
+	}
+	return prefix + string(runes)
+}
+
+func escapeXML(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
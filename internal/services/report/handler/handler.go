@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/akordium-id/waqfwise/internal/services/report/dto"
+	"github.com/akordium-id/waqfwise/internal/services/report/export"
+	"github.com/akordium-id/waqfwise/internal/services/report/service"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/middleware"
+	"github.com/akordium-id/waqfwise/internal/shared/request"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/akordium-id/waqfwise/internal/shared/validator"
+	"github.com/gorilla/mux"
+)
+
+// Handler handles HTTP requests for regulatory reports
+type Handler struct {
+	service service.Service
+	auth    *middleware.Auth
+}
+
+// New creates a new report handler
+func New(service service.Service, auth *middleware.Auth) *Handler {
+	return &Handler{
+		service: service,
+		auth:    auth,
+	}
+}
+
+// GetBWI handles BWI report preview
+func (h *Handler) GetBWI(w http.ResponseWriter, r *http.Request) {
+	period, err := periodQuery(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	report, err := h.service.GenerateBWI(r.Context(), period)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, report)
+}
+
+// ExportBWI handles BWI report download
+func (h *Handler) ExportBWI(w http.ResponseWriter, r *http.Request) {
+	period, err := periodQuery(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	format, err := formatQuery(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	file, err := h.service.ExportBWI(r.Context(), period, format)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	writeFile(w, file)
+}
+
+// SubmitBWI handles BWI report submission
+func (h *Handler) SubmitBWI(w http.ResponseWriter, r *http.Request) {
+	var req dto.SubmitReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, errors.New(errors.ErrCodeBadRequest, "Invalid request body", 400))
+		return
+	}
+
+	v := validator.New()
+	v.Min("year", int64(req.Year), 2000)
+	v.In("semester", strconv.Itoa(req.Semester), []string{"0", "1", "2"})
+	v.MaxLength("reference", req.Reference, 100)
+
+	if !v.IsValid() {
+		response.Error(w, v.Error())
+		return
+	}
+
+	submission, err := h.service.SubmitBWI(r.Context(), middleware.ActorFromContext(r.Context()), &req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, submission)
+}
+
+// GetSubmissions handles submitted BWI report listing
+func (h *Handler) GetSubmissions(w http.ResponseWriter, r *http.Request) {
+	page, perPage := request.Pagination(r)
+	submissions, total, err := h.service.GetSubmissions(r.Context(), perPage, (page-1)*perPage)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, submissions, page, perPage, total)
+}
+
+// GetSubmission handles submitted BWI report retrieval
+func (h *Handler) GetSubmission(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	submission, err := h.service.GetSubmission(r.Context(), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Success(w, submission)
+}
+
+// ExportSubmission handles submitted BWI report download
+func (h *Handler) ExportSubmission(w http.ResponseWriter, r *http.Request) {
+	id, err := request.PathID(r, "id")
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	format, err := formatQuery(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	file, err := h.service.ExportSubmission(r.Context(), id, format)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	writeFile(w, file)
+}
+
+// RegisterRoutes registers HTTP routes under the reports prefix
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	// BWI reports cover the whole institution, not a single nazir's campaigns
+	readers := middleware.RequireRole(domain.RoleAdmin, domain.RoleAuditor)
+	admins := middleware.RequireRole(domain.RoleAdmin)
+
+	protected := r.PathPrefix("/").Subrouter()
+	protected.Use(h.auth.Authenticate)
+	protected.Handle("/bwi", readers(http.HandlerFunc(h.GetBWI))).Methods("GET")
+	protected.Handle("/bwi/export", readers(http.HandlerFunc(h.ExportBWI))).Methods("GET")
+	protected.Handle("/bwi/submissions", readers(http.HandlerFunc(h.GetSubmissions))).Methods("GET")
+	protected.Handle("/bwi/submissions", admins(http.HandlerFunc(h.SubmitBWI))).Methods("POST")
+	protected.Handle("/bwi/submissions/{id:[0-9]+}", readers(http.HandlerFunc(h.GetSubmission))).Methods("GET")
+	protected.Handle("/bwi/submissions/{id:[0-9]+}/export", readers(http.HandlerFunc(h.ExportSubmission))).Methods("GET")
+}
+
+// periodQuery parses the year and semester query parameters
+func periodQuery(r *http.Request) (*dto.PeriodQuery, error) {
+	q := r.URL.Query()
+
+	year, err := strconv.Atoi(q.Get("year"))
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Invalid year", 400)
+	}
+
+	semester := 0
+	if s := q.Get("semester"); s != "" {
+		if semester, err = strconv.Atoi(s); err != nil {
+			return nil, errors.New(errors.ErrCodeBadRequest, "Invalid semester", 400)
+		}
+	}
+
+	return &dto.PeriodQuery{Year: year, Semester: semester}, nil
+}
+
+// formatQuery parses the export format, defaulting to XLSX
+func formatQuery(r *http.Request) (export.Format, error) {
+	switch format := export.Format(r.URL.Query().Get("format")); format {
+	case "", export.FormatXLSX:
+		return export.FormatXLSX, nil
+	case export.FormatPDF:
+		return format, nil
+	}
+	return "", errors.New(errors.ErrCodeBadRequest, "format must be xlsx or pdf", 400)
+}
+
+// writeFile sends a rendered report as a download
+func writeFile(w http.ResponseWriter, file *service.File) {
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Name))
+	w.Header().Set("Content-Length", strconv.Itoa(len(file.Content)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(file.Content)
+}
@@ -0,0 +1,362 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// Repository defines report repository interface
+type Repository interface {
+	GetAssets(ctx context.Context, asOf time.Time) ([]domain.BWIAssetRow, error)
+	GetValuations(ctx context.Context, from, to time.Time) ([]domain.BWIValuationRow, error)
+	GetCashWakaf(ctx context.Context, from, to time.Time) ([]domain.BWICashWakafRow, error)
+	GetInvestments(ctx context.Context, from, to time.Time) ([]domain.BWIInvestmentRow, error)
+	GetDistributions(ctx context.Context, from, to time.Time) ([]domain.BWIDistributionRow, error)
+	GetLedgerTotals(ctx context.Context, from, to time.Time) ([]domain.BWILedgerRow, error)
+	CreateSubmission(ctx context.Context, submission *domain.ReportSubmission) error
+	FindSubmissionByID(ctx context.Context, id int64) (*domain.ReportSubmission, error)
+	GetSubmissions(ctx context.Context, reportType string, limit, offset int) ([]*domain.ReportSubmission, int64, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// New creates a new report repository
+func New(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetAssets gets assets acquired by asOf, valued at their latest approved valuation on or before asOf
+func (r *repository) GetAssets(ctx context.Context, asOf time.Time) ([]domain.BWIAssetRow, error) {
+	query := `
+		SELECT a.id, a.name, a.type, a.status, COALESCE(a.location, ''), a.area, COALESCE(a.area_unit, ''),
+		       a.acquisition_date, a.purchase_value,
+		       COALESCE((
+		           SELECT v.valuation_value FROM asset_valuations v
+		           WHERE v.asset_id = a.id AND v.status = 'approved' AND v.valuation_date <= $1
+		           ORDER BY v.valuation_date DESC, v.id DESC
+		           LIMIT 1
+		       ), a.purchase_value)
+		FROM assets a
+		WHERE a.acquisition_date <= $1
+		ORDER BY a.type, a.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, asOf)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get assets", 500)
+	}
+	defer rows.Close()
+
+	assets := make([]domain.BWIAssetRow, 0)
+	for rows.Next() {
+		var a domain.BWIAssetRow
+		var area sql.NullFloat64
+		if err := rows.Scan(
+			&a.AssetID, &a.Name, &a.Type, &a.Status, &a.Location, &area, &a.AreaUnit,
+			&a.AcquisitionDate, &a.PurchaseValue, &a.Value,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan asset", 500)
+		}
+		if area.Valid {
+			a.Area = &area.Float64
+		}
+		assets = append(assets, a)
+	}
+
+	return assets, rows.Err()
+}
+
+// GetValuations gets approved asset valuations dated within [from, to]
+func (r *repository) GetValuations(ctx context.Context, from, to time.Time) ([]domain.BWIValuationRow, error) {
+	query := `
+		SELECT v.asset_id, a.name, v.valuation_date, v.valuation_value, COALESCE(v.method, ''), COALESCE(v.valued_by, '')
+		FROM asset_valuations v
+		JOIN assets a ON a.id = v.asset_id
+		WHERE v.status = 'approved' AND v.valuation_date BETWEEN $1 AND $2
+		ORDER BY v.valuation_date, v.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get valuations", 500)
+	}
+	defer rows.Close()
+
+	valuations := make([]domain.BWIValuationRow, 0)
+	for rows.Next() {
+		var v domain.BWIValuationRow
+		if err := rows.Scan(&v.AssetID, &v.AssetName, &v.ValuationDate, &v.ValuationValue, &v.Method, &v.ValuedBy); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan valuation", 500)
+		}
+		valuations = append(valuations, v)
+	}
+
+	return valuations, rows.Err()
+}
+
+// GetCashWakaf totals successful donations paid within [from, to] per campaign
+func (r *repository) GetCashWakaf(ctx context.Context, from, to time.Time) ([]domain.BWICashWakafRow, error) {
+	query := `
+		SELECT d.campaign_id, COALESCE(c.title, ''), COALESCE(c.type, ''), COUNT(*), SUM(d.amount)
+		FROM donations d
+		LEFT JOIN campaigns c ON c.id = d.campaign_id
+		WHERE d.status = $1 AND d.paid_at >= $2 AND d.paid_at < $3
+		GROUP BY d.campaign_id, c.title, c.type
+		ORDER BY d.campaign_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.PaymentStatusSuccess, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get cash wakaf", 500)
+	}
+	defer rows.Close()
+
+	cash := make([]domain.BWICashWakafRow, 0)
+	for rows.Next() {
+		var c domain.BWICashWakafRow
+		if err := rows.Scan(&c.CampaignID, &c.CampaignTitle, &c.CampaignType, &c.DonationCount, &c.Amount); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan cash wakaf", 500)
+		}
+		cash = append(cash, c)
+	}
+
+	return cash, rows.Err()
+}
+
+// GetInvestments totals asset income received within [from, to] per asset and source
+func (r *repository) GetInvestments(ctx context.Context, from, to time.Time) ([]domain.BWIInvestmentRow, error) {
+	query := `
+		SELECT i.asset_id, COALESCE(a.name, ''), i.source, COUNT(*), SUM(i.amount)
+		FROM asset_incomes i
+		LEFT JOIN assets a ON a.id = i.asset_id
+		WHERE i.received_at BETWEEN $1 AND $2
+		GROUP BY i.asset_id, a.name, i.source
+		ORDER BY i.asset_id, i.source
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get investment results", 500)
+	}
+	defer rows.Close()
+
+	investments := make([]domain.BWIInvestmentRow, 0)
+	for rows.Next() {
+		var i domain.BWIInvestmentRow
+		if err := rows.Scan(&i.AssetID, &i.AssetName, &i.Source, &i.IncomeCount, &i.Amount); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan investment result", 500)
+		}
+		investments = append(investments, i)
+	}
+
+	return investments, rows.Err()
+}
+
+// GetDistributions gets distribution runs whose period ends within [from, to]
+func (r *repository) GetDistributions(ctx context.Context, from, to time.Time) ([]domain.BWIDistributionRow, error) {
+	query := `
+		SELECT d.id, d.asset_id, COALESCE(a.name, ''), d.period_start, d.period_end,
+		       d.total_income, d.nazir_amount, d.beneficiary_amount
+		FROM distribution_runs d
+		LEFT JOIN assets a ON a.id = d.asset_id
+		WHERE d.period_end BETWEEN $1 AND $2
+		ORDER BY d.period_end, d.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get distributions", 500)
+	}
+	defer rows.Close()
+
+	distributions := make([]domain.BWIDistributionRow, 0)
+	for rows.Next() {
+		var d domain.BWIDistributionRow
+		if err := rows.Scan(
+			&d.RunID, &d.AssetID, &d.AssetName, &d.PeriodStart, &d.PeriodEnd,
+			&d.TotalIncome, &d.NazirAmount, &d.BeneficiaryAmount,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan distribution", 500)
+		}
+		distributions = append(distributions, d)
+	}
+
+	return distributions, rows.Err()
+}
+
+// GetLedgerTotals totals ledger postings dated within [from, to] per account.
+// Entries posted before entry dates existed fall back to their creation date.
+func (r *repository) GetLedgerTotals(ctx context.Context, from, to time.Time) ([]domain.BWILedgerRow, error) {
+	query := `
+		SELECT account_name,
+		       COALESCE(SUM(amount) FILTER (WHERE account_type = 'debit'), 0),
+		       COALESCE(SUM(amount) FILTER (WHERE account_type = 'credit'), 0)
+		FROM ledgers
+		WHERE COALESCE(entry_date, created_at::date) BETWEEN $1 AND $2
+		GROUP BY account_name
+		ORDER BY account_name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get ledger totals", 500)
+	}
+	defer rows.Close()
+
+	totals := make([]domain.BWILedgerRow, 0)
+	for rows.Next() {
+		var l domain.BWILedgerRow
+		if err := rows.Scan(&l.AccountName, &l.Debit, &l.Credit); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan ledger totals", 500)
+		}
+		totals = append(totals, l)
+	}
+
+	return totals, rows.Err()
+}
+
+// CreateSubmission stores a report snapshot as the next revision for its period
+func (r *repository) CreateSubmission(ctx context.Context, submission *domain.ReportSubmission) error {
+	content, err := json.Marshal(submission.Report)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to encode report", 500)
+	}
+	sum := sha256.Sum256(content)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to begin transaction", 500)
+	}
+	defer tx.Rollback()
+
+	// Serialize submissions for the same period so revisions stay gapless
+	if _, err := tx.ExecContext(ctx,
+		`SELECT pg_advisory_xact_lock(hashtext($1), $2)`,
+		submission.ReportType, submission.Year*10+submission.Semester,
+	); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to lock report period", 500)
+	}
+
+	revisionQuery := `
+		SELECT COALESCE(MAX(revision), 0) + 1 FROM report_submissions
+		WHERE report_type = $1 AND year = $2 AND semester = $3
+	`
+	if err := tx.QueryRowContext(ctx, revisionQuery,
+		submission.ReportType, submission.Year, submission.Semester,
+	).Scan(&submission.Revision); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to get report revision", 500)
+	}
+
+	query := `
+		INSERT INTO report_submissions (report_type, period_type, year, semester, revision, content,
+		                                content_hash, reference, notes, submitted_by, submitted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`
+
+	now := time.Now()
+	submission.ContentHash = hex.EncodeToString(sum[:])
+	err = tx.QueryRowContext(
+		ctx, query,
+		submission.ReportType,
+		submission.PeriodType,
+		submission.Year,
+		submission.Semester,
+		submission.Revision,
+		content,
+		submission.ContentHash,
+		submission.Reference,
+		submission.Notes,
+		submission.SubmittedBy,
+		now,
+	).Scan(&submission.ID)
+
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to create report submission", 500)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to commit report submission", 500)
+	}
+
+	submission.SubmittedAt = now
+	return nil
+}
+
+// FindSubmissionByID finds a report submission with its snapshot content
+func (r *repository) FindSubmissionByID(ctx context.Context, id int64) (*domain.ReportSubmission, error) {
+	query := `
+		SELECT id, report_type, period_type, year, semester, revision, content, content_hash,
+		       COALESCE(reference, ''), COALESCE(notes, ''), submitted_by, submitted_at
+		FROM report_submissions
+		WHERE id = $1
+	`
+
+	submission := &domain.ReportSubmission{}
+	var content []byte
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&submission.ID, &submission.ReportType, &submission.PeriodType, &submission.Year, &submission.Semester,
+		&submission.Revision, &content, &submission.ContentHash, &submission.Reference, &submission.Notes,
+		&submission.SubmittedBy, &submission.SubmittedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, errors.New(errors.ErrCodeNotFound, "Report submission not found", 404)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to find report submission", 500)
+	}
+
+	submission.Report = &domain.BWIReport{}
+	if err := json.Unmarshal(content, submission.Report); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to decode report snapshot", 500)
+	}
+
+	return submission, nil
+}
+
+// GetSubmissions lists report submissions without their content, latest first
+func (r *repository) GetSubmissions(ctx context.Context, reportType string, limit, offset int) ([]*domain.ReportSubmission, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM report_submissions WHERE report_type = $1`
+	if err := r.db.QueryRowContext(ctx, countQuery, reportType).Scan(&total); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to count report submissions", 500)
+	}
+
+	query := `
+		SELECT id, report_type, period_type, year, semester, revision, content_hash,
+		       COALESCE(reference, ''), COALESCE(notes, ''), submitted_by, submitted_at
+		FROM report_submissions
+		WHERE report_type = $1
+		ORDER BY year DESC, semester DESC, revision DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, reportType, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to get report submissions", 500)
+	}
+	defer rows.Close()
+
+	submissions := make([]*domain.ReportSubmission, 0)
+	for rows.Next() {
+		s := &domain.ReportSubmission{}
+		if err := rows.Scan(
+			&s.ID, &s.ReportType, &s.PeriodType, &s.Year, &s.Semester, &s.Revision, &s.ContentHash,
+			&s.Reference, &s.Notes, &s.SubmittedBy, &s.SubmittedAt,
+		); err != nil {
+			return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan report submission", 500)
+		}
+		submissions = append(submissions, s)
+	}
+
+	return submissions, total, rows.Err()
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/akordium-id/waqfwise/internal/services/report/export"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+)
+
+const dateLayout = "02-01-2006"
+
+// bwiDocument lays out the BWI report tables with the Indonesian headings used
+// in the nazir reporting form
+func bwiDocument(report *domain.BWIReport) *export.Document {
+	period := fmt.Sprintf("Tahun %d", report.Year)
+	if report.PeriodType == domain.ReportPeriodSemester {
+		period = fmt.Sprintf("Semester %d Tahun %d", report.Semester, report.Year)
+	}
+
+	doc := &export.Document{
+		Title: "Laporan Nazhir kepada Badan Wakaf Indonesia",
+		Subtitle: fmt.Sprintf("Periode %s (%s s.d. %s), dibuat %s",
+			period,
+			report.PeriodStart.Format(dateLayout),
+			report.PeriodEnd.Format(dateLayout),
+			report.GeneratedAt.Format("02-01-2006 15:04"),
+		),
+	}
+
+	sum := report.Summary
+	doc.Tables = append(doc.Tables, export.Table{
+		Name:    "Ringkasan",
+		Columns: []string{"Uraian", "Jumlah"},
+		Rows: [][]interface{}{
+			{"Jumlah aset wakaf", sum.AssetCount},
+			{"Nilai aset wakaf (Rp)", sum.TotalAssetValue},
+			{"Penerimaan wakaf uang (Rp)", sum.WakafUang},
+			{"Penerimaan wakaf melalui uang (Rp)", sum.WakafMelaluiUang},
+			{"Jumlah transaksi wakaf", sum.DonationCount},
+			{"Hasil pengelolaan/investasi (Rp)", sum.InvestmentIncome},
+			{"Hasil yang disalurkan (Rp)", sum.TotalDistributed},
+			{"Bagian nazhir (Rp)", sum.NazirShare},
+			{"Bagian mauquf alaih (Rp)", sum.BeneficiaryShare},
+		},
+	})
+
+	assets := export.Table{
+		Name:    "Aset Wakaf",
+		Columns: []string{"ID", "Nama Aset", "Jenis", "Status", "Lokasi", "Luas", "Satuan", "Tgl Perolehan", "Nilai Perolehan (Rp)", "Nilai Akhir (Rp)"},
+	}
+	for _, a := range report.Assets {
+		var area interface{}
+		if a.Area != nil {
+			area = *a.Area
+		}
+		assets.Rows = append(assets.Rows, []interface{}{
+			a.AssetID, a.Name, string(a.Type), string(a.Status), a.Location, area, a.AreaUnit,
+			a.AcquisitionDate.Format(dateLayout), a.PurchaseValue, a.Value,
+		})
+	}
+	doc.Tables = append(doc.Tables, assets)
+
+	valuations := export.Table{
+		Name:    "Penilaian Aset",
+		Columns: []string{"ID Aset", "Nama Aset", "Tgl Penilaian", "Nilai (Rp)", "Metode", "Penilai"},
+	}
+	for _, v := range report.Valuations {
+		valuations.Rows = append(valuations.Rows, []interface{}{
+			v.AssetID, v.AssetName, v.ValuationDate.Format(dateLayout), v.ValuationValue, v.Method, v.ValuedBy,
+		})
+	}
+	doc.Tables = append(doc.Tables, valuations)
+
+	cash := export.Table{
+		Name:    "Penerimaan Wakaf Uang",
+		Columns: []string{"ID Program", "Program Wakaf", "Jenis", "Jumlah Transaksi", "Jumlah (Rp)"},
+	}
+	for _, c := range report.CashWakaf {
+		cash.Rows = append(cash.Rows, []interface{}{
+			c.CampaignID, c.CampaignTitle, string(c.CampaignType), c.DonationCount, c.Amount,
+		})
+	}
+	doc.Tables = append(doc.Tables, cash)
+
+	investments := export.Table{
+		Name:    "Hasil Pengelolaan",
+		Columns: []string{"ID Aset", "Nama Aset", "Sumber", "Jumlah Transaksi", "Jumlah (Rp)"},
+	}
+	for _, i := range report.Investments {
+		investments.Rows = append(investments.Rows, []interface{}{
+			i.AssetID, i.AssetName, string(i.Source), i.IncomeCount, i.Amount,
+		})
+	}
+	doc.Tables = append(doc.Tables, investments)
+
+	distributions := export.Table{
+		Name:    "Penyaluran Hasil",
+		Columns: []string{"ID", "Nama Aset", "Periode Awal", "Periode Akhir", "Total Hasil (Rp)", "Nazhir (Rp)", "Mauquf Alaih (Rp)"},
+	}
+	for _, d := range report.Distributions {
+		distributions.Rows = append(distributions.Rows, []interface{}{
+			d.RunID, d.AssetName, d.PeriodStart.Format(dateLayout), d.PeriodEnd.Format(dateLayout),
+			d.TotalIncome, d.NazirAmount, d.BeneficiaryAmount,
+		})
+	}
+	doc.Tables = append(doc.Tables, distributions)
+
+	ledger := export.Table{
+		Name:    "Rekapitulasi Jurnal",
+		Columns: []string{"Akun", "Debit (Rp)", "Kredit (Rp)"},
+	}
+	for _, l := range report.Ledger {
+		ledger.Rows = append(ledger.Rows, []interface{}{l.AccountName, l.Debit, l.Credit})
+	}
+	doc.Tables = append(doc.Tables, ledger)
+
+	return doc
+}
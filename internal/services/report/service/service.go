@@ -0,0 +1,229 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/services/report/dto"
+	"github.com/akordium-id/waqfwise/internal/services/report/export"
+	"github.com/akordium-id/waqfwise/internal/services/report/repository"
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// File is a rendered report export
+type File struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// Service defines report service interface
+type Service interface {
+	GenerateBWI(ctx context.Context, period *dto.PeriodQuery) (*domain.BWIReport, error)
+	ExportBWI(ctx context.Context, period *dto.PeriodQuery, format export.Format) (*File, error)
+	SubmitBWI(ctx context.Context, actor *domain.Actor, req *dto.SubmitReportRequest) (*domain.ReportSubmission, error)
+	GetSubmission(ctx context.Context, id int64) (*domain.ReportSubmission, error)
+	GetSubmissions(ctx context.Context, limit, offset int) ([]*domain.ReportSubmission, int64, error)
+	ExportSubmission(ctx context.Context, id int64, format export.Format) (*File, error)
+}
+
+type service struct {
+	repo repository.Repository
+}
+
+// New creates a new report service
+func New(repo repository.Repository) Service {
+	return &service{repo: repo}
+}
+
+// GenerateBWI builds the BWI report tables for a semester or year
+func (s *service) GenerateBWI(ctx context.Context, period *dto.PeriodQuery) (*domain.BWIReport, error) {
+	report, err := newBWIReport(period)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := report.PeriodStart, report.PeriodEnd
+
+	if report.Assets, err = s.repo.GetAssets(ctx, to); err != nil {
+		return nil, err
+	}
+	if report.Valuations, err = s.repo.GetValuations(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if report.CashWakaf, err = s.repo.GetCashWakaf(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if report.Investments, err = s.repo.GetInvestments(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if report.Distributions, err = s.repo.GetDistributions(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if report.Ledger, err = s.repo.GetLedgerTotals(ctx, from, to); err != nil {
+		return nil, err
+	}
+
+	summarize(report)
+	return report, nil
+}
+
+// ExportBWI renders the current BWI report as XLSX or PDF
+func (s *service) ExportBWI(ctx context.Context, period *dto.PeriodQuery, format export.Format) (*File, error) {
+	report, err := s.GenerateBWI(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+
+	return render(report, format, "")
+}
+
+// SubmitBWI generates the report for an ended period and stores a snapshot of
+// what was submitted. Resubmitting a period creates a new revision.
+func (s *service) SubmitBWI(ctx context.Context, actor *domain.Actor, req *dto.SubmitReportRequest) (*domain.ReportSubmission, error) {
+	report, err := s.GenerateBWI(ctx, &dto.PeriodQuery{Year: req.Year, Semester: req.Semester})
+	if err != nil {
+		return nil, err
+	}
+
+	if !report.PeriodEnd.Before(today()) {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Reporting period has not ended yet", 400)
+	}
+
+	submission := &domain.ReportSubmission{
+		ReportType:  domain.ReportTypeBWI,
+		PeriodType:  report.PeriodType,
+		Year:        report.Year,
+		Semester:    report.Semester,
+		Report:      report,
+		Reference:   strings.TrimSpace(req.Reference),
+		Notes:       req.Notes,
+		SubmittedBy: actor.UserID,
+	}
+
+	if err := s.repo.CreateSubmission(ctx, submission); err != nil {
+		return nil, err
+	}
+
+	return submission, nil
+}
+
+// GetSubmission gets a submitted report snapshot
+func (s *service) GetSubmission(ctx context.Context, id int64) (*domain.ReportSubmission, error) {
+	return s.repo.FindSubmissionByID(ctx, id)
+}
+
+// GetSubmissions lists submitted BWI reports
+func (s *service) GetSubmissions(ctx context.Context, limit, offset int) ([]*domain.ReportSubmission, int64, error) {
+	return s.repo.GetSubmissions(ctx, domain.ReportTypeBWI, limit, offset)
+}
+
+// ExportSubmission renders a submitted snapshot exactly as it was submitted
+func (s *service) ExportSubmission(ctx context.Context, id int64, format export.Format) (*File, error) {
+	submission, err := s.repo.FindSubmissionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return render(submission.Report, format, fmt.Sprintf("-rev%d", submission.Revision))
+}
+
+// newBWIReport resolves the reporting period of a report
+func newBWIReport(period *dto.PeriodQuery) (*domain.BWIReport, error) {
+	if period.Year < 2000 || period.Year > time.Now().Year() {
+		return nil, errors.New(errors.ErrCodeValidation, "year is out of range", 400)
+	}
+
+	report := &domain.BWIReport{
+		Year:        period.Year,
+		GeneratedAt: time.Now(),
+	}
+
+	switch period.Semester {
+	case 0:
+		report.PeriodType = domain.ReportPeriodYear
+		report.PeriodStart = time.Date(period.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		report.PeriodEnd = time.Date(period.Year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	case 1:
+		report.PeriodType = domain.ReportPeriodSemester
+		report.Semester = 1
+		report.PeriodStart = time.Date(period.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		report.PeriodEnd = time.Date(period.Year, time.June, 30, 0, 0, 0, 0, time.UTC)
+	case 2:
+		report.PeriodType = domain.ReportPeriodSemester
+		report.Semester = 2
+		report.PeriodStart = time.Date(period.Year, time.July, 1, 0, 0, 0, 0, time.UTC)
+		report.PeriodEnd = time.Date(period.Year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	default:
+		return nil, errors.New(errors.ErrCodeValidation, "semester must be 1 or 2", 400)
+	}
+
+	return report, nil
+}
+
+// summarize totals the report tables
+func summarize(report *domain.BWIReport) {
+	sum := &report.Summary
+
+	sum.AssetCount = len(report.Assets)
+	for _, a := range report.Assets {
+		sum.TotalAssetValue += a.Value
+	}
+
+	for _, c := range report.CashWakaf {
+		sum.DonationCount += c.DonationCount
+		if c.CampaignType == domain.CampaignTypeCash {
+			sum.WakafUang += c.Amount
+		} else {
+			sum.WakafMelaluiUang += c.Amount
+		}
+	}
+
+	for _, i := range report.Investments {
+		sum.InvestmentIncome += i.Amount
+	}
+
+	for _, d := range report.Distributions {
+		sum.TotalDistributed += d.TotalIncome
+		sum.NazirShare += d.NazirAmount
+		sum.BeneficiaryShare += d.BeneficiaryAmount
+	}
+}
+
+// render exports a report; suffix distinguishes snapshot revisions in the file name
+func render(report *domain.BWIReport, format export.Format, suffix string) (*File, error) {
+	doc := bwiDocument(report)
+	if format == export.FormatPDF {
+		if err := export.CheckPDFText(doc); err != nil {
+			return nil, errors.New(errors.ErrCodeValidation, err.Error()+"; export as xlsx instead", 400)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, doc); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to export report", 500)
+	}
+
+	return &File{
+		Name:        fmt.Sprintf("laporan-bwi-%s%s.%s", periodLabel(report), suffix, format),
+		ContentType: format.ContentType(),
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+// periodLabel names the reporting period, e.g. 2025-S1 or 2025
+func periodLabel(report *domain.BWIReport) string {
+	if report.PeriodType == domain.ReportPeriodSemester {
+		return fmt.Sprintf("%d-S%d", report.Year, report.Semester)
+	}
+	return fmt.Sprintf("%d", report.Year)
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+)
+
+// PeriodStatus represents the status of an accounting period
+type PeriodStatus string
+
+const (
+	PeriodStatusOpen   PeriodStatus = "open"
+	PeriodStatusClosed PeriodStatus = "closed"
+)
+
+// AccountingPeriod represents a bookkeeping period that can be closed for posting
+type AccountingPeriod struct {
+	ID              int64        `json:"id" db:"id"`
+	Name            string       `json:"name" db:"name"` // e.g. 2024-01
+	StartDate       time.Time    `json:"start_date" db:"start_date"`
+	EndDate         time.Time    `json:"end_date" db:"end_date"` // inclusive
+	Status          PeriodStatus `json:"status" db:"status"`
+	ClosingSequence *int64       `json:"closing_sequence,omitempty" db:"closing_sequence"` // ledger chain head at close
+	ClosingHash     string       `json:"closing_hash,omitempty" db:"closing_hash"`
+	ClosedBy        *int64       `json:"closed_by,omitempty" db:"closed_by"`
+	ClosedAt        *time.Time   `json:"closed_at,omitempty" db:"closed_at"`
+	CreatedAt       time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// IsClosed checks if period no longer accepts postings
+func (p *AccountingPeriod) IsClosed() bool {
+	return p.Status == PeriodStatusClosed
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -75,18 +76,50 @@ type AssetValuation struct {
 	Notes          string    `json:"notes,omitempty" db:"notes"`
 	DocumentURL    string    `json:"document_url,omitempty" db:"document_url"`
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+
+	Status          ValuationStatus `json:"status" db:"status"`
+	PreviousValue   *int64          `json:"previous_value,omitempty" db:"previous_value"` // carrying value when approved
+	Adjustment      *int64          `json:"adjustment,omitempty" db:"adjustment"`         // surplus if positive, deficit if negative
+	RecordedBy      int64           `json:"recorded_by" db:"recorded_by"`
+	DecidedBy       *int64          `json:"decided_by,omitempty" db:"decided_by"`
+	DecidedAt       *time.Time      `json:"decided_at,omitempty" db:"decided_at"`
+	RejectionReason string          `json:"rejection_reason,omitempty" db:"rejection_reason"`
+}
+
+// Valuation methods
+const (
+	ValuationMethodMarketValue    = "market_value"
+	ValuationMethodIncomeApproach = "income_approach"
+	ValuationMethodCostApproach   = "cost_approach"
+)
+
+// ValuationStatus represents the approval state of an asset valuation
+type ValuationStatus string
+
+const (
+	ValuationStatusPending  ValuationStatus = "pending"
+	ValuationStatusApproved ValuationStatus = "approved" // applied to the asset and the ledger
+	ValuationStatusRejected ValuationStatus = "rejected"
+)
+
+// IsPending checks if the valuation awaits approval
+func (v *AssetValuation) IsPending() bool {
+	return v.Status == ValuationStatusPending
 }
 
 // GeospatialData represents additional geospatial information
 type GeospatialData struct {
-	AssetID     int64     `json:"asset_id" db:"asset_id"`
-	Geometry    string    `json:"geometry" db:"geometry"`         // PostGIS geometry
-	Boundary    string    `json:"boundary,omitempty" db:"boundary"` // GeoJSON polygon
-	LandUse     string    `json:"land_use,omitempty" db:"land_use"`
-	ZoningType  string    `json:"zoning_type,omitempty" db:"zoning_type"`
-	Elevation   *float64  `json:"elevation,omitempty" db:"elevation"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	AssetID    int64           `json:"asset_id" db:"asset_id"`
+	Geometry   string          `json:"geometry" db:"geometry"`           // PostGIS geometry as WKT
+	Boundary   json.RawMessage `json:"boundary,omitempty" db:"boundary"` // GeoJSON polygon
+	LandUse    string          `json:"land_use,omitempty" db:"land_use"`
+	ZoningType string          `json:"zoning_type,omitempty" db:"zoning_type"`
+	Elevation  *float64        `json:"elevation,omitempty" db:"elevation"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at" db:"updated_at"`
+
+	AreaSqm   float64 `json:"area_sqm" db:"area_sqm"` // geodesic area of the boundary
+	UpdatedBy int64   `json:"updated_by" db:"updated_by"`
 }
 
 // IsLand checks if asset is land type
@@ -98,3 +131,32 @@ func (a *Asset) IsLand() bool {
 func (a *Asset) HasGeolocation() bool {
 	return a.Latitude != nil && a.Longitude != nil
 }
+
+// AssetStatusChange records a change of an asset's status
+type AssetStatusChange struct {
+	ID         int64       `json:"id" db:"id"`
+	AssetID    int64       `json:"asset_id" db:"asset_id"`
+	FromStatus AssetStatus `json:"from_status" db:"from_status"`
+	ToStatus   AssetStatus `json:"to_status" db:"to_status"`
+	Reason     string      `json:"reason,omitempty" db:"reason"`
+	ChangedBy  int64       `json:"changed_by" db:"changed_by"`
+	CreatedAt  time.Time   `json:"created_at" db:"created_at"`
+}
+
+// assetTransitions lists the statuses an asset may move to from each status.
+// Disposal is final.
+var assetTransitions = map[AssetStatus][]AssetStatus{
+	AssetStatusActive:     {AssetStatusInactive, AssetStatusUnderMaint, AssetStatusDisposed},
+	AssetStatusInactive:   {AssetStatusActive, AssetStatusUnderMaint, AssetStatusDisposed},
+	AssetStatusUnderMaint: {AssetStatusActive, AssetStatusInactive, AssetStatusDisposed},
+}
+
+// CanTransitionTo checks if the asset may move to the given status
+func (a *Asset) CanTransitionTo(next AssetStatus) bool {
+	for _, status := range assetTransitions[a.Status] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
@@ -9,6 +9,7 @@ type CampaignStatus string
 
 const (
 	CampaignStatusDraft     CampaignStatus = "draft"
+	CampaignStatusPendingReview CampaignStatus = "pending_review"
 	CampaignStatusActive    CampaignStatus = "active"
 	CampaignStatusCompleted CampaignStatus = "completed"
 	CampaignStatusCancelled CampaignStatus = "cancelled"
@@ -48,6 +49,11 @@ type Campaign struct {
 	IsEndless       bool           `json:"is_endless" db:"is_endless"`
 	IsFeatured      bool           `json:"is_featured" db:"is_featured"`
 	IsUrgent        bool           `json:"is_urgent" db:"is_urgent"`
+	AutoPublish     bool           `json:"auto_publish" db:"auto_publish"`       // activate the draft on StartDate
+	AcceptOverflow  bool           `json:"accept_overflow" db:"accept_overflow"` // keep taking donations once completed
+	OverflowCampaignID *int64      `json:"overflow_campaign_id,omitempty" db:"overflow_campaign_id"` // general fund receiving overflow
+	ApprovedAt      *time.Time     `json:"approved_at,omitempty" db:"approved_at"` // last approval; nil until first approved
+	ApprovedBy      *int64         `json:"approved_by,omitempty" db:"approved_by"`
 	TenantID        *int64         `json:"tenant_id,omitempty" db:"tenant_id"`
 	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
@@ -83,3 +89,53 @@ func (c *Campaign) IsActive() bool {
 func (c *Campaign) IsCompleted() bool {
 	return c.CurrentAmount >= c.GoalAmount
 }
+
+// campaignTransitions lists the statuses a campaign may move to from each status
+var campaignTransitions = map[CampaignStatus][]CampaignStatus{
+	CampaignStatusDraft:         {CampaignStatusPendingReview, CampaignStatusCancelled},
+	CampaignStatusPendingReview: {CampaignStatusDraft, CampaignStatusActive, CampaignStatusCancelled},
+	CampaignStatusActive:        {CampaignStatusPaused, CampaignStatusCompleted, CampaignStatusCancelled},
+	CampaignStatusPaused:        {CampaignStatusActive, CampaignStatusCompleted, CampaignStatusCancelled},
+}
+
+// PublicCampaignStatuses are the statuses visible to donors
+var PublicCampaignStatuses = []CampaignStatus{
+	CampaignStatusActive,
+	CampaignStatusPaused,
+	CampaignStatusCompleted,
+}
+
+// CanTransitionTo checks if the campaign may move to the given status
+func (c *Campaign) CanTransitionTo(next CampaignStatus) bool {
+	for _, status := range campaignTransitions[c.Status] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPublic checks if the campaign is visible to donors
+func (c *Campaign) IsPublic() bool {
+	for _, status := range PublicCampaignStatuses {
+		if c.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// InOverflow checks if donations to the completed campaign go to its fallback fund
+func (c *Campaign) InOverflow() bool {
+	return c.Status == CampaignStatusCompleted && c.AcceptOverflow && c.OverflowCampaignID != nil
+}
+
+// IsApproved checks if an admin has approved the campaign
+func (c *Campaign) IsApproved() bool {
+	return c.ApprovedAt != nil
+}
+
+// IsClosed checks if the campaign has reached a final status
+func (c *Campaign) IsClosed() bool {
+	return c.Status == CampaignStatusCompleted || c.Status == CampaignStatusCancelled
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"time"
+)
+
+// BudgetCategory represents the kind of cost a budget line plans for
+type BudgetCategory string
+
+const (
+	BudgetCategoryLand          BudgetCategory = "land"
+	BudgetCategoryNotary        BudgetCategory = "notary"
+	BudgetCategoryCertification BudgetCategory = "certification"
+	BudgetCategoryConstruction  BudgetCategory = "construction"
+	BudgetCategoryEquipment     BudgetCategory = "equipment"
+	BudgetCategoryOperational   BudgetCategory = "operational"
+	BudgetCategoryOther         BudgetCategory = "other"
+)
+
+// BudgetLine is a line of a campaign's budget plan (RAB, rencana anggaran
+// biaya), e.g. the land price or one construction phase
+type BudgetLine struct {
+	ID            int64          `json:"id" db:"id"`
+	CampaignID    int64          `json:"campaign_id" db:"campaign_id"`
+	Category      BudgetCategory `json:"category" db:"category"`
+	Name          string         `json:"name" db:"name"`
+	Description   string         `json:"description,omitempty" db:"description"`
+	PlannedAmount int64          `json:"planned_amount" db:"planned_amount"`
+	SpentAmount   int64          `json:"spent_amount" db:"spent_amount"` // posted disbursements
+	Position      int            `json:"position" db:"position"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// Variance is the budget left on the line; negative when over budget
+func (l *BudgetLine) Variance() int64 {
+	return l.PlannedAmount - l.SpentAmount
+}
+
+// IsOverBudget checks if the line has spent more than planned
+func (l *BudgetLine) IsOverBudget() bool {
+	return l.SpentAmount > l.PlannedAmount
+}
+
+// DisbursementStatus represents the state of a campaign disbursement
+type DisbursementStatus string
+
+const (
+	DisbursementStatusPosted          DisbursementStatus = "posted"           // spent and in the ledger
+	DisbursementStatusPendingApproval DisbursementStatus = "pending_approval" // over budget, held for an admin
+	DisbursementStatusRejected        DisbursementStatus = "rejected"
+)
+
+// Disbursement is money paid out of a campaign's funds against a budget line
+type Disbursement struct {
+	ID              int64              `json:"id" db:"id"`
+	CampaignID      int64              `json:"campaign_id" db:"campaign_id"`
+	BudgetLineID    int64              `json:"budget_line_id" db:"budget_line_id"`
+	Amount          int64              `json:"amount" db:"amount"`
+	Recipient       string             `json:"recipient" db:"recipient"`
+	Description     string             `json:"description,omitempty" db:"description"`
+	ReceiptURL      string             `json:"receipt_url,omitempty" db:"receipt_url"`
+	DisbursedAt     time.Time          `json:"disbursed_at" db:"disbursed_at"` // date, determines the accounting period
+	Status          DisbursementStatus `json:"status" db:"status"`
+	OverBudget      bool               `json:"over_budget" db:"over_budget"` // took its line over the planned amount
+	RequestedBy     int64              `json:"requested_by" db:"requested_by"`
+	DecidedBy       *int64             `json:"decided_by,omitempty" db:"decided_by"`
+	DecidedAt       *time.Time         `json:"decided_at,omitempty" db:"decided_at"`
+	RejectionReason string             `json:"rejection_reason,omitempty" db:"rejection_reason"`
+	CreatedAt       time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// IsPosted checks if the disbursement counts as spent
+func (d *Disbursement) IsPosted() bool {
+	return d.Status == DisbursementStatusPosted
+}
+
+// IsPendingApproval checks if the disbursement waits for an admin
+func (d *Disbursement) IsPendingApproval() bool {
+	return d.Status == DisbursementStatusPendingApproval
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+)
+
+// ImpactIndicator is a concrete outcome a campaign reports on, such as
+// students served or square metres of land secured
+type ImpactIndicator struct {
+	ID             int64      `json:"id" db:"id"`
+	CampaignID     int64      `json:"campaign_id" db:"campaign_id"`
+	Name           string     `json:"name" db:"name"`
+	Unit           string     `json:"unit" db:"unit"` // e.g. "students", "m²"
+	Description    string     `json:"description,omitempty" db:"description"`
+	TargetValue    float64    `json:"target_value" db:"target_value"`
+	CurrentValue   float64    `json:"current_value" db:"current_value"` // latest measurement
+	Position       int        `json:"position" db:"position"`
+	LastMeasuredAt *time.Time `json:"last_measured_at,omitempty" db:"last_measured_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Progress calculates how much of the target has been reached, in percent
+func (i *ImpactIndicator) Progress() float64 {
+	if i.TargetValue == 0 {
+		return 0
+	}
+	return i.CurrentValue / i.TargetValue * 100
+}
+
+// ImpactMeasurement is a reading of an indicator's cumulative value on a
+// date, backed by evidence such as photos, attendance lists or certificates
+type ImpactMeasurement struct {
+	ID           int64     `json:"id" db:"id"`
+	IndicatorID  int64     `json:"indicator_id" db:"indicator_id"`
+	CampaignID   int64     `json:"campaign_id" db:"campaign_id"`
+	Value        float64   `json:"value" db:"value"`
+	Note         string    `json:"note,omitempty" db:"note"`
+	EvidenceURLs []string  `json:"evidence_urls" db:"evidence_urls"`
+	MeasuredAt   time.Time `json:"measured_at" db:"measured_at"` // date
+	RecordedBy   int64     `json:"recorded_by" db:"recorded_by"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
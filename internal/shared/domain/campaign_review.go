@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CampaignReviewDecision is an admin's decision on a campaign review
+type CampaignReviewDecision string
+
+const (
+	CampaignReviewApproved CampaignReviewDecision = "approved"
+	CampaignReviewRejected CampaignReviewDecision = "rejected"
+)
+
+// CampaignReview records an admin's approval or rejection of a campaign, or
+// of a change to an approved campaign, with the checklist they went through
+type CampaignReview struct {
+	ID            int64                  `json:"id" db:"id"`
+	CampaignID    int64                  `json:"campaign_id" db:"campaign_id"`
+	VersionID     *int64                 `json:"version_id,omitempty" db:"version_id"` // set when a change was reviewed
+	Decision      CampaignReviewDecision `json:"decision" db:"decision"`
+	LandDocuments bool                   `json:"land_documents" db:"land_documents"` // land certificate or deed attached
+	AkadStated    bool                   `json:"akad_stated" db:"akad_stated"`       // wakaf akad stated in the description
+	GoalJustified bool                   `json:"goal_justified" db:"goal_justified"` // goal amount backed by a budget
+	Comment       string                 `json:"comment,omitempty" db:"comment"`
+	ReviewerID    int64                  `json:"reviewer_id" db:"reviewer_id"`
+	CreatedAt     time.Time              `json:"created_at" db:"created_at"`
+}
+
+// NeedsLandDocuments checks if the land documents item applies to a campaign type
+func NeedsLandDocuments(t CampaignType) bool {
+	return t == CampaignTypeLand || t == CampaignTypeBuilding
+}
+
+// CampaignVersionStatus is the status of a campaign version
+type CampaignVersionStatus string
+
+const (
+	CampaignVersionApplied    CampaignVersionStatus = "applied"    // edit took effect at once
+	CampaignVersionPending    CampaignVersionStatus = "pending"    // material edit awaiting re-approval
+	CampaignVersionApproved   CampaignVersionStatus = "approved"   // approved state, including the first approval
+	CampaignVersionRejected   CampaignVersionStatus = "rejected"   // material edit turned down
+	CampaignVersionSuperseded CampaignVersionStatus = "superseded" // pending edit replaced by a newer one
+)
+
+// CampaignMaterialFields are the fields whose change requires re-approval
+var CampaignMaterialFields = []string{"goal_amount", "type"}
+
+// CampaignVersion is a snapshot of an approved campaign after an edit
+type CampaignVersion struct {
+	ID            int64                 `json:"id" db:"id"`
+	CampaignID    int64                 `json:"campaign_id" db:"campaign_id"`
+	Version       int                   `json:"version" db:"version"`
+	Snapshot      json.RawMessage       `json:"snapshot" db:"snapshot"` // the campaign as edited
+	ChangedFields []string              `json:"changed_fields" db:"changed_fields"`
+	Material      bool                  `json:"material" db:"material"`
+	Status        CampaignVersionStatus `json:"status" db:"status"`
+	EditedBy      int64                 `json:"edited_by" db:"edited_by"`
+	CreatedAt     time.Time             `json:"created_at" db:"created_at"`
+}
+
+// IsPending checks if the version awaits review
+func (v *CampaignVersion) IsPending() bool {
+	return v.Status == CampaignVersionPending
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+)
+
+// CampaignTemplate is a reusable starting point for campaigns run again and
+// again, such as Ramadan iftar, Qurban or wakaf Al-Quran
+type CampaignTemplate struct {
+	ID           int64                `json:"id" db:"id"`
+	Name         string               `json:"name" db:"name"`
+	Title        string               `json:"title" db:"title"`
+	Description  string               `json:"description" db:"description"`
+	ShortDesc    string               `json:"short_desc" db:"short_desc"`
+	Type         CampaignType         `json:"type" db:"type"`
+	GoalAmount   int64                `json:"goal_amount" db:"goal_amount"`
+	ImageURL     string               `json:"image_url,omitempty" db:"image_url"`
+	VideoURL     string               `json:"video_url,omitempty" db:"video_url"`
+	Location     string               `json:"location,omitempty" db:"location"`
+	IsEndless    bool                 `json:"is_endless" db:"is_endless"`
+	DurationDays int                  `json:"duration_days,omitempty" db:"duration_days"` // days from start to end date
+	Milestones   []TemplateMilestone  `json:"milestones" db:"milestones"`
+	BudgetLines  []TemplateBudgetLine `json:"budget_lines" db:"budget_lines"`
+	OwnerID      int64                `json:"owner_id" db:"owner_id"`
+	IsShared     bool                 `json:"is_shared" db:"is_shared"` // offered to every nazir of the tenant
+	TenantID     *int64               `json:"tenant_id,omitempty" db:"tenant_id"`
+	CreatedAt    time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// TemplateMilestone is a milestone a template creates
+type TemplateMilestone struct {
+	Title        string `json:"title"`
+	Description  string `json:"description,omitempty"`
+	TargetAmount int64  `json:"target_amount"`
+}
+
+// TemplateBudgetLine is a budget line a template creates
+type TemplateBudgetLine struct {
+	Category      BudgetCategory `json:"category"`
+	Name          string         `json:"name"`
+	Description   string         `json:"description,omitempty"`
+	PlannedAmount int64          `json:"planned_amount"`
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+)
+
+// CampaignUpdateStatus represents the status of a campaign update post
+type CampaignUpdateStatus string
+
+const (
+	CampaignUpdateStatusDraft     CampaignUpdateStatus = "draft"
+	CampaignUpdateStatusPublished CampaignUpdateStatus = "published"
+)
+
+// CampaignUpdate is a progress post (kabar terbaru) on a campaign
+type CampaignUpdate struct {
+	ID             int64                `json:"id" db:"id"`
+	CampaignID     int64                `json:"campaign_id" db:"campaign_id"`
+	CampaignTitle  string               `json:"campaign_title,omitempty" db:"-"` // set in donor feeds
+	CampaignSlug   string               `json:"campaign_slug,omitempty" db:"-"`
+	Title          string               `json:"title" db:"title"`
+	Body           string               `json:"body" db:"body"` // Markdown
+	Images         []string             `json:"images" db:"images"`
+	MilestoneID    *int64               `json:"milestone_id,omitempty" db:"milestone_id"`
+	DisbursementID *int64               `json:"disbursement_id,omitempty" db:"disbursement_id"`
+	Status         CampaignUpdateStatus `json:"status" db:"status"`
+	AuthorID       int64                `json:"author_id" db:"author_id"`
+	PublishedAt    *time.Time           `json:"published_at,omitempty" db:"published_at"`
+	CreatedAt      time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// IsPublished checks if the update is visible to donors
+func (u *CampaignUpdate) IsPublished() bool {
+	return u.Status == CampaignUpdateStatusPublished
+}
+
+// CampaignSubscription subscribes a donor to the updates of a campaign
+type CampaignSubscription struct {
+	ID             int64      `json:"id" db:"id"`
+	CampaignID     int64      `json:"campaign_id" db:"campaign_id"`
+	DonorKey       string     `json:"-" db:"donor_key"`
+	UserID         *int64     `json:"user_id,omitempty" db:"user_id"`
+	Email          string     `json:"email,omitempty" db:"email"` // falls back to the user's email
+	Phone          string     `json:"-" db:"-"`                   // the user's phone, for WhatsApp
+	Channel        string     `json:"channel" db:"channel"`
+	Token          string     `json:"-" db:"token"` // unsubscribe token for email links
+	UnsubscribedAt *time.Time `json:"unsubscribed_at,omitempty" db:"unsubscribed_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsActive checks if the subscriber still receives updates
+func (s *CampaignSubscription) IsActive() bool {
+	return s.UnsubscribedAt == nil
+}
@@ -0,0 +1,146 @@
+package domain
+
+import "time"
+
+// CertificationStage represents a step of turning donated land into
+// certified wakaf land (PP 42/2006)
+type CertificationStage string
+
+const (
+	StageIkrar            CertificationStage = "ikrar"             // ikrar wakaf before the PPAIW
+	StageAIWDeed          CertificationStage = "aiw_deed"          // Akta Ikrar Wakaf issued
+	StageKUARegistration  CertificationStage = "kua_registration"  // wakaf registered at the KUA
+	StageBPNCertification CertificationStage = "bpn_certification" // wakaf land certificate from BPN
+)
+
+// CertificationStages lists the stages in the order they are done
+var CertificationStages = []CertificationStage{
+	StageIkrar,
+	StageAIWDeed,
+	StageKUARegistration,
+	StageBPNCertification,
+}
+
+// AssetDocument types required by the certification stages
+const (
+	DocWakifIdentity        = "wakif_identity"       // KTP of the wakif
+	DocLandOwnership        = "land_ownership_proof" // SHM, girik or other proof of ownership
+	DocIkrarStatement       = "ikrar_statement"      // signed ikrar with two witnesses
+	DocAIWDeed              = "aiw_deed"
+	DocNazirAppointment     = "nazir_appointment" // nazir registration by the KUA
+	DocKUARegistration      = "kua_registration_receipt"
+	DocWakafLandCertificate = "wakaf_certificate"
+)
+
+var stageDocuments = map[CertificationStage][]string{
+	StageIkrar:            {DocWakifIdentity, DocLandOwnership, DocIkrarStatement},
+	StageAIWDeed:          {DocAIWDeed, DocNazirAppointment},
+	StageKUARegistration:  {DocKUARegistration},
+	StageBPNCertification: {DocWakafLandCertificate},
+}
+
+// RequiredDocuments returns the AssetDocument types a stage needs before it
+// can be completed
+func (s CertificationStage) RequiredDocuments() []string {
+	return stageDocuments[s]
+}
+
+// MissingDocuments returns the documents the stage needs that are not among
+// the given document types
+func (s CertificationStage) MissingDocuments(have []string) []string {
+	present := make(map[string]bool, len(have))
+	for _, t := range have {
+		present[t] = true
+	}
+
+	missing := make([]string, 0)
+	for _, t := range s.RequiredDocuments() {
+		if !present[t] {
+			missing = append(missing, t)
+		}
+	}
+	return missing
+}
+
+// CertificationStatus represents the state of a certification workflow
+type CertificationStatus string
+
+const (
+	CertificationInProgress CertificationStatus = "in_progress"
+	CertificationCompleted  CertificationStatus = "completed" // certificate issued
+	CertificationCancelled  CertificationStatus = "cancelled"
+)
+
+// StepStatus represents the state of a certification step
+type StepStatus string
+
+const (
+	StepPending    StepStatus = "pending"
+	StepInProgress StepStatus = "in_progress"
+	StepBlocked    StepStatus = "blocked" // waiting on something outside the nazir's hands
+	StepCompleted  StepStatus = "completed"
+)
+
+// CertificationWorkflow tracks the certification of a land asset as wakaf
+// land through its stages
+type CertificationWorkflow struct {
+	ID           int64                `json:"id" db:"id"`
+	AssetID      int64                `json:"asset_id" db:"asset_id"`
+	Status       CertificationStatus  `json:"status" db:"status"`
+	Notes        string               `json:"notes,omitempty" db:"notes"`
+	StartedBy    int64                `json:"started_by" db:"started_by"`
+	CompletedAt  *time.Time           `json:"completed_at,omitempty" db:"completed_at"`
+	CancelReason string               `json:"cancel_reason,omitempty" db:"cancel_reason"`
+	CreatedAt    time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at" db:"updated_at"`
+	Steps        []*CertificationStep `json:"steps"`
+}
+
+// CertificationStep is one stage of a certification workflow
+type CertificationStep struct {
+	ID                int64              `json:"id" db:"id"`
+	WorkflowID        int64              `json:"workflow_id" db:"workflow_id"`
+	AssetID           int64              `json:"asset_id" db:"asset_id"`
+	Stage             CertificationStage `json:"stage" db:"stage"`
+	Sequence          int                `json:"sequence" db:"sequence"` // 1 for the first stage
+	Status            StepStatus         `json:"status" db:"status"`
+	ResponsibleUserID *int64             `json:"responsible_user_id,omitempty" db:"responsible_user_id"`
+	ResponsibleName   string             `json:"responsible_name,omitempty" db:"responsible_name"` // e.g. the PPAIW official
+	DueDate           *time.Time         `json:"due_date,omitempty" db:"due_date"`
+	StartedAt         *time.Time         `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt       *time.Time         `json:"completed_at,omitempty" db:"completed_at"`
+	Notes             string             `json:"notes,omitempty" db:"notes"`
+	UpdatedBy         int64              `json:"updated_by" db:"updated_by"`
+	UpdatedAt         time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// IsActive checks if the workflow is still under way
+func (w *CertificationWorkflow) IsActive() bool {
+	return w.Status == CertificationInProgress
+}
+
+// Step returns the step of a stage
+func (w *CertificationWorkflow) Step(stage CertificationStage) *CertificationStep {
+	for _, step := range w.Steps {
+		if step.Stage == stage {
+			return step
+		}
+	}
+	return nil
+}
+
+// CurrentStep returns the first step not completed yet, or nil once every
+// step is
+func (w *CertificationWorkflow) CurrentStep() *CertificationStep {
+	for _, step := range w.Steps {
+		if step.Status != StepCompleted {
+			return step
+		}
+	}
+	return nil
+}
+
+// IsOverdue checks if the step is not completed after its due date
+func (s *CertificationStep) IsOverdue(day time.Time) bool {
+	return s.Status != StepCompleted && s.DueDate != nil && day.After(*s.DueDate)
+}
@@ -0,0 +1,166 @@
+package domain
+
+import "time"
+
+// DepreciationMethod represents how an asset's cost is spread over its
+// useful life
+type DepreciationMethod string
+
+const (
+	DepreciationStraightLine     DepreciationMethod = "straight_line"     // equal charge every month
+	DepreciationDecliningBalance DepreciationMethod = "declining_balance" // fixed rate of the remaining book value
+)
+
+// DepreciationDefault is the depreciation policy of every asset of a type
+// that has none of its own. Salvage is a share of each asset's cost.
+type DepreciationDefault struct {
+	AssetType        AssetType          `json:"asset_type" db:"asset_type"`
+	Method           DepreciationMethod `json:"method" db:"method"`
+	UsefulLifeMonths int                `json:"useful_life_months" db:"useful_life_months"`
+	SalvageBPS       int                `json:"salvage_bps" db:"salvage_bps"`
+	RateBPS          int                `json:"rate_bps,omitempty" db:"rate_bps"` // yearly declining balance rate
+	UpdatedBy        int64              `json:"updated_by" db:"updated_by"`
+	UpdatedAt        time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// AssetDepreciation is a depreciation policy set on one asset, overriding
+// the default of its type
+type AssetDepreciation struct {
+	AssetID          int64              `json:"asset_id" db:"asset_id"`
+	Method           DepreciationMethod `json:"method" db:"method"`
+	UsefulLifeMonths int                `json:"useful_life_months" db:"useful_life_months"`
+	SalvageValue     int64              `json:"salvage_value" db:"salvage_value"`
+	RateBPS          int                `json:"rate_bps,omitempty" db:"rate_bps"` // yearly declining balance rate
+	UpdatedBy        int64              `json:"updated_by" db:"updated_by"`
+	UpdatedAt        time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// DepreciationPolicy is the policy in effect for an asset
+type DepreciationPolicy struct {
+	Source           string             `json:"source"` // asset or asset_type
+	Method           DepreciationMethod `json:"method"`
+	UsefulLifeMonths int                `json:"useful_life_months"`
+	SalvageValue     int64              `json:"salvage_value"`
+	RateBPS          int                `json:"rate_bps"` // yearly declining balance rate in effect
+}
+
+// Depreciation policy sources
+const (
+	DepreciationSourceAsset     = "asset"
+	DepreciationSourceAssetType = "asset_type"
+)
+
+// DepreciationEntry is the depreciation of an asset for one month
+type DepreciationEntry struct {
+	ID          int64     `json:"id,omitempty" db:"id"`
+	AssetID     int64     `json:"asset_id" db:"asset_id"`
+	Period      time.Time `json:"period" db:"period"` // first day of the month
+	Amount      int64     `json:"amount" db:"amount"`
+	Accumulated int64     `json:"accumulated" db:"accumulated"`
+	BookValue   int64     `json:"book_value" db:"book_value"`
+	Projected   bool      `json:"projected,omitempty" db:"-"` // not posted yet
+	CreatedAt   time.Time `json:"created_at,omitempty" db:"created_at"`
+}
+
+// Policy returns the asset's own policy
+func (d *AssetDepreciation) Policy() *DepreciationPolicy {
+	return newDepreciationPolicy(DepreciationSourceAsset, d.Method, d.UsefulLifeMonths, d.SalvageValue, d.RateBPS)
+}
+
+// PolicyFor returns the type default applied to an asset of the given cost
+func (d *DepreciationDefault) PolicyFor(cost int64) *DepreciationPolicy {
+	salvage := cost * int64(d.SalvageBPS) / FullShareBPS
+	return newDepreciationPolicy(DepreciationSourceAssetType, d.Method, d.UsefulLifeMonths, salvage, d.RateBPS)
+}
+
+// newDepreciationPolicy builds a policy; declining balance without a rate
+// uses double the straight-line rate
+func newDepreciationPolicy(source string, method DepreciationMethod, lifeMonths int, salvage int64, rateBPS int) *DepreciationPolicy {
+	if method == DepreciationDecliningBalance && rateBPS == 0 && lifeMonths > 0 {
+		rateBPS = 2 * 12 * FullShareBPS / lifeMonths
+	}
+	if method == DepreciationStraightLine {
+		rateBPS = 0
+	}
+
+	return &DepreciationPolicy{
+		Source:           source,
+		Method:           method,
+		UsefulLifeMonths: lifeMonths,
+		SalvageValue:     salvage,
+		RateBPS:          rateBPS,
+	}
+}
+
+// Charge returns the depreciation of the given month of the useful life,
+// counted from 0, after accumulated has been charged before it. It is
+// worked out from what is left, so a changed policy applies from then on.
+// The last month of the useful life brings the book value down to salvage.
+func (p *DepreciationPolicy) Charge(cost, accumulated int64, month int) int64 {
+	remaining := p.UsefulLifeMonths - month
+	depreciable := cost - p.SalvageValue - accumulated
+	if month < 0 || remaining <= 0 || depreciable <= 0 {
+		return 0
+	}
+	if remaining == 1 {
+		return depreciable
+	}
+
+	charge := (depreciable + int64(remaining)/2) / int64(remaining)
+	if p.Method == DepreciationDecliningBalance {
+		// Switch to straight line once it charges more, so the tail of the
+		// useful life is not left to one large last charge
+		book := cost - accumulated
+		if declining := (book*int64(p.RateBPS) + 6*FullShareBPS) / (12 * FullShareBPS); declining > charge {
+			charge = declining
+		}
+	}
+
+	if charge > depreciable {
+		return depreciable
+	}
+	return charge
+}
+
+// Project builds the entries of every month from the given one to the end of
+// the useful life that starts in the start month
+func (p *DepreciationPolicy) Project(assetID, cost, accumulated int64, start, from time.Time) []*DepreciationEntry {
+	entries := make([]*DepreciationEntry, 0)
+	for period := from; ; period = period.AddDate(0, 1, 0) {
+		amount := p.Charge(cost, accumulated, MonthsBetween(start, period))
+		if amount <= 0 {
+			return entries
+		}
+
+		accumulated += amount
+		entries = append(entries, &DepreciationEntry{
+			AssetID:     assetID,
+			Period:      period,
+			Amount:      amount,
+			Accumulated: accumulated,
+			BookValue:   cost - accumulated,
+		})
+	}
+}
+
+// DepreciationStart returns the first month an asset is depreciated: the
+// month it was acquired
+func DepreciationStart(acquired time.Time) time.Time {
+	return MonthStart(acquired)
+}
+
+// MonthStart returns the first day of the month of t at midnight UTC
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// MonthsBetween counts the whole calendar months from one month to another
+func MonthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+// IsDepreciable checks if assets of the type lose value over time; land
+// does not
+func (t AssetType) IsDepreciable() bool {
+	return t != AssetTypeLand
+}
@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDepreciationPolicyCharge(t *testing.T) {
+	straight := (&AssetDepreciation{Method: DepreciationStraightLine, UsefulLifeMonths: 12, SalvageValue: 2400}).Policy()
+	declining := (&AssetDepreciation{Method: DepreciationDecliningBalance, UsefulLifeMonths: 12, SalvageValue: 100000}).Policy()
+
+	tests := []struct {
+		name        string
+		policy      *DepreciationPolicy
+		cost        int64
+		accumulated int64
+		month       int
+		want        int64
+	}{
+		{"straight line", straight, 12000, 0, 0, 800},
+		{"straight line mid-life", straight, 12000, 4000, 5, 800},
+		{"straight line rounds to nearest", (&AssetDepreciation{Method: DepreciationStraightLine, UsefulLifeMonths: 3}).Policy(), 10000, 0, 0, 3333},
+		{"straight line picks up a changed policy", straight, 12000, 0, 6, 1600},
+		{"last month lands on salvage", straight, 12000, 8799, 11, 801},
+		{"before the useful life", straight, 12000, 0, -1, 0},
+		{"after the useful life", straight, 12000, 9600, 12, 0},
+		{"already at salvage", straight, 12000, 9600, 8, 0},
+		{"declining balance", declining, 1300000, 0, 0, 216667},
+		{"declining balance of the remaining book value", declining, 1300000, 216667, 1, 180556},
+		{"declining balance switches to straight line", declining, 1300000, 997662, 8, 50585},
+		{"declining balance last month lands on salvage", declining, 1300000, 1149416, 11, 50584},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Charge(tt.cost, tt.accumulated, tt.month); got != tt.want {
+				t.Errorf("Charge(%d, %d, %d) = %d, want %d", tt.cost, tt.accumulated, tt.month, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDepreciationPolicyProject(t *testing.T) {
+	start := date(2026, 1, 1)
+
+	tests := []struct {
+		name        string
+		policy      *DepreciationPolicy
+		cost        int64
+		accumulated int64
+		from        time.Time
+		want        []int64
+	}{
+		{
+			name:   "straight line",
+			policy: (&AssetDepreciation{Method: DepreciationStraightLine, UsefulLifeMonths: 4}).Policy(),
+			cost:   10000,
+			from:   start,
+			want:   []int64{2500, 2500, 2500, 2500},
+		},
+		{
+			name:   "straight line stops at salvage",
+			policy: (&AssetDepreciation{Method: DepreciationStraightLine, UsefulLifeMonths: 3, SalvageValue: 1}).Policy(),
+			cost:   10000,
+			from:   start,
+			want:   []int64{3333, 3333, 3333},
+		},
+		{
+			name:   "declining balance switches to straight line in September",
+			policy: (&AssetDepreciation{Method: DepreciationDecliningBalance, UsefulLifeMonths: 12, SalvageValue: 100000}).Policy(),
+			cost:   1300000,
+			from:   start,
+			want: []int64{
+				216667, 180556, 150463, 125386, 104488, 87073, 72561, 60468,
+				50585, 50584, 50585, 50584,
+			},
+		},
+		{
+			name:        "resumes after the months posted",
+			policy:      (&AssetDepreciation{Method: DepreciationStraightLine, UsefulLifeMonths: 4}).Policy(),
+			cost:        10000,
+			accumulated: 5000,
+			from:        date(2026, 3, 1),
+			want:        []int64{2500, 2500},
+		},
+		{
+			name:   "type default salvage is a share of cost",
+			policy: (&DepreciationDefault{Method: DepreciationStraightLine, UsefulLifeMonths: 2, SalvageBPS: 1000}).PolicyFor(10000),
+			cost:   10000,
+			from:   start,
+			want:   []int64{4500, 4500},
+		},
+		{
+			name:        "nothing left to depreciate",
+			policy:      (&AssetDepreciation{Method: DepreciationStraightLine, UsefulLifeMonths: 4}).Policy(),
+			cost:        10000,
+			accumulated: 10000,
+			from:        date(2026, 3, 1),
+			want:        []int64{},
+		},
+		{
+			name:   "past the useful life",
+			policy: (&AssetDepreciation{Method: DepreciationStraightLine, UsefulLifeMonths: 4}).Policy(),
+			cost:   10000,
+			from:   date(2026, 5, 1),
+			want:   []int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := tt.policy.Project(1, tt.cost, tt.accumulated, start, tt.from)
+			if len(entries) != len(tt.want) {
+				t.Fatalf("Project() gave %d months, want %d", len(entries), len(tt.want))
+			}
+
+			accumulated := tt.accumulated
+			for i, entry := range entries {
+				accumulated += tt.want[i]
+				period := AddMonths(tt.from, i)
+
+				if entry.Amount != tt.want[i] {
+					t.Errorf("month %d amount = %d, want %d", i, entry.Amount, tt.want[i])
+				}
+				if !entry.Period.Equal(period) {
+					t.Errorf("month %d period = %s, want %s", i, entry.Period.Format("2006-01"), period.Format("2006-01"))
+				}
+				if entry.Accumulated != accumulated || entry.BookValue != tt.cost-accumulated {
+					t.Errorf("month %d accumulated %d book value %d, want %d and %d",
+						i, entry.Accumulated, entry.BookValue, accumulated, tt.cost-accumulated)
+				}
+			}
+
+			if len(entries) > 0 {
+				if last := entries[len(entries)-1]; last.BookValue != tt.policy.SalvageValue {
+					t.Errorf("final book value = %d, want salvage %d", last.BookValue, tt.policy.SalvageValue)
+				}
+			}
+		})
+	}
+}
+
+func TestDecliningBalanceDefaultRate(t *testing.T) {
+	tests := []struct {
+		lifeMonths int
+		rateBPS    int
+		want       int
+	}{
+		{60, 0, 4000},    // double the 20% straight-line rate
+		{120, 0, 2000},   // double the 10% straight-line rate
+		{60, 3000, 3000}, // a set rate is kept
+	}
+
+	for _, tt := range tests {
+		policy := (&AssetDepreciation{Method: DepreciationDecliningBalance, UsefulLifeMonths: tt.lifeMonths, RateBPS: tt.rateBPS}).Policy()
+		if policy.RateBPS != tt.want {
+			t.Errorf("rate for %d months (set %d) = %d, want %d", tt.lifeMonths, tt.rateBPS, policy.RateBPS, tt.want)
+		}
+	}
+
+	straight := (&AssetDepreciation{Method: DepreciationStraightLine, UsefulLifeMonths: 60, RateBPS: 3000}).Policy()
+	if straight.RateBPS != 0 {
+		t.Errorf("straight line rate = %d, want 0", straight.RateBPS)
+	}
+}
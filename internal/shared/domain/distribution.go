@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"time"
+)
+
+// IncomeSource represents where productive wakaf income comes from
+type IncomeSource string
+
+const (
+	IncomeSourceRent        IncomeSource = "rent"
+	IncomeSourceProfitShare IncomeSource = "profit_share"
+	IncomeSourceSales       IncomeSource = "sales"
+	IncomeSourceOther       IncomeSource = "other"
+)
+
+// BeneficiaryType represents who receives a share of wakaf income
+type BeneficiaryType string
+
+const (
+	BeneficiaryMauqufAlaih BeneficiaryType = "mauquf_alaih"
+	BeneficiaryNazir       BeneficiaryType = "nazir"
+)
+
+const (
+	// FullShareBPS is 100% expressed in basis points
+	FullShareBPS = 10000
+	// NazirShareCapBPS caps the nazir share at 10% of net income (UU 41/2004 Pasal 12)
+	NazirShareCapBPS = 1000
+)
+
+// AssetIncome represents income produced by a productive wakaf asset (hasil wakaf)
+type AssetIncome struct {
+	ID                int64        `json:"id" db:"id"`
+	AssetID           int64        `json:"asset_id" db:"asset_id"`
+	Source            IncomeSource `json:"source" db:"source"`
+	Amount            int64        `json:"amount" db:"amount"`
+	ReceivedAt        time.Time    `json:"received_at" db:"received_at"`
+	Description       string       `json:"description,omitempty" db:"description"`
+	RecordedBy        int64        `json:"recorded_by" db:"recorded_by"`
+	DistributionRunID *int64       `json:"distribution_run_id,omitempty" db:"distribution_run_id"`
+	CreatedAt         time.Time    `json:"created_at" db:"created_at"`
+}
+
+// DistributionRule represents a configured share of an asset's income
+type DistributionRule struct {
+	ID              int64           `json:"id" db:"id"`
+	AssetID         int64           `json:"asset_id" db:"asset_id"`
+	BeneficiaryType BeneficiaryType `json:"beneficiary_type" db:"beneficiary_type"`
+	BeneficiaryName string          `json:"beneficiary_name" db:"beneficiary_name"`
+	ShareBPS        int             `json:"share_bps" db:"share_bps"` // basis points, 10000 = 100%
+	IsActive        bool            `json:"is_active" db:"is_active"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// DistributionRun represents one distribution of an asset's income for a period
+type DistributionRun struct {
+	ID                int64     `json:"id" db:"id"`
+	AssetID           int64     `json:"asset_id" db:"asset_id"`
+	PeriodStart       time.Time `json:"period_start" db:"period_start"`
+	PeriodEnd         time.Time `json:"period_end" db:"period_end"`
+	TotalIncome       int64     `json:"total_income" db:"total_income"`
+	NazirAmount       int64     `json:"nazir_amount" db:"nazir_amount"`
+	BeneficiaryAmount int64     `json:"beneficiary_amount" db:"beneficiary_amount"`
+	IncomeCount       int       `json:"income_count" db:"income_count"`
+	ExecutedBy        int64     `json:"executed_by" db:"executed_by"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+}
+
+// DistributionAllocation represents the amount a beneficiary receives in a run
+type DistributionAllocation struct {
+	ID              int64           `json:"id" db:"id"`
+	RunID           int64           `json:"run_id" db:"run_id"`
+	RuleID          int64           `json:"rule_id" db:"rule_id"`
+	BeneficiaryType BeneficiaryType `json:"beneficiary_type" db:"beneficiary_type"`
+	BeneficiaryName string          `json:"beneficiary_name" db:"beneficiary_name"`
+	ShareBPS        int             `json:"share_bps" db:"share_bps"`
+	Amount          int64           `json:"amount" db:"amount"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+}
+
+// IsDistributed checks if income has already been distributed
+func (i *AssetIncome) IsDistributed() bool {
+	return i.DistributionRunID != nil
+}
+
+// IsNazir checks if rule allocates the nazir share
+func (r *DistributionRule) IsNazir() bool {
+	return r.BeneficiaryType == BeneficiaryNazir
+}
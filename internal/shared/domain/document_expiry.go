@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// DocumentExpiryAlert records an alert sent to a nazir about an asset
+// document that is expiring or has expired
+type DocumentExpiryAlert struct {
+	ID           int64     `json:"id" db:"id"`
+	DocumentID   int64     `json:"document_id" db:"document_id"`
+	AssetID      int64     `json:"asset_id" db:"asset_id"`
+	DocumentType string    `json:"document_type" db:"document_type"`
+	ExpiryDate   time.Time `json:"expiry_date" db:"expiry_date"`
+	WindowDays   int       `json:"window_days" db:"window_days"` // 0 once expired
+	DaysLeft     int       `json:"days_left" db:"days_left"`     // negative once expired
+	NazirID      *int64    `json:"nazir_id,omitempty" db:"nazir_id"`
+	Recipients   []string  `json:"recipients" db:"recipients"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+)
+
+// AnonymousDonorName is shown in place of the name of a donor who gave anonymously
+const AnonymousDonorName = "Hamba Allah"
+
+// LeaderboardPeriod represents the span a leaderboard is ranked over
+type LeaderboardPeriod string
+
+const (
+	LeaderboardPeriodAll    LeaderboardPeriod = "all"
+	LeaderboardPeriodWeek   LeaderboardPeriod = "week"  // since Monday
+	LeaderboardPeriodMonth  LeaderboardPeriod = "month" // since the first of the month
+	LeaderboardPeriodYear   LeaderboardPeriod = "year"  // since 1 January
+	LeaderboardPeriodCustom LeaderboardPeriod = "custom"
+)
+
+// DonorWallEntry is a donation as shown on a campaign's public donor wall. It
+// never carries the donor's user ID or contact details.
+type DonorWallEntry struct {
+	DonationID    int64     `json:"donation_id"`
+	DonorName     string    `json:"donor_name"`
+	IsAnonymous   bool      `json:"is_anonymous"`
+	Amount        int64     `json:"amount"`
+	Message       string    `json:"message,omitempty"`
+	MessageHidden bool      `json:"message_hidden,omitempty"` // only reported to campaign managers
+	FundraiserID  *int64    `json:"fundraiser_id,omitempty"`
+	DonatedAt     time.Time `json:"donated_at"`
+}
+
+// LeaderboardEntry is a ranked donor or fundraiser page. Anonymous donations
+// rank one by one as "Hamba Allah" rather than under their donor.
+type LeaderboardEntry struct {
+	Rank           int    `json:"rank"`
+	Name           string `json:"name"`
+	IsAnonymous    bool   `json:"is_anonymous,omitempty"`
+	FundraiserID   *int64 `json:"fundraiser_id,omitempty"`
+	FundraiserSlug string `json:"fundraiser_slug,omitempty"`
+	Amount         int64  `json:"amount"`
+	Count          int64  `json:"count"` // donations for a donor, distinct donors for a fundraiser page
+	Key            string `json:"-"`     // opaque tie-breaker for cursor pagination
+}
+
+// PublicDonorName gets the name to show publicly for a donation
+func PublicDonorName(name string, anonymous bool) string {
+	if anonymous || name == "" {
+		return AnonymousDonorName
+	}
+	return name
+}
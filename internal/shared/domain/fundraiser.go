@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+)
+
+// FundraiserStatus represents the visibility of a fundraiser page
+type FundraiserStatus string
+
+const (
+	FundraiserStatusActive FundraiserStatus = "active"
+	FundraiserStatusHidden FundraiserStatus = "hidden" // hidden by the campaign nazir
+)
+
+// Fundraiser is a supporter's peer-to-peer page collecting for a parent
+// campaign. Donations made through the page count towards both.
+type Fundraiser struct {
+	ID            int64            `json:"id" db:"id"`
+	CampaignID    int64            `json:"campaign_id" db:"campaign_id"`
+	UserID        int64            `json:"user_id" db:"user_id"`
+	Title         string           `json:"title" db:"title"`
+	Slug          string           `json:"slug" db:"slug"`
+	Story         string           `json:"story" db:"story"` // Markdown
+	GoalAmount    int64            `json:"goal_amount" db:"goal_amount"`
+	CurrentAmount int64            `json:"current_amount" db:"current_amount"`
+	DonorCount    int              `json:"donor_count" db:"donor_count"`
+	Status        FundraiserStatus `json:"status" db:"status"`
+	HiddenReason  string           `json:"hidden_reason,omitempty" db:"hidden_reason"`
+	HiddenBy      *int64           `json:"hidden_by,omitempty" db:"hidden_by"`
+	HiddenAt      *time.Time       `json:"hidden_at,omitempty" db:"hidden_at"`
+	Rank          int              `json:"rank,omitempty" db:"-"` // leaderboard position among visible pages
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// Progress calculates fundraiser progress percentage
+func (f *Fundraiser) Progress() float64 {
+	if f.GoalAmount == 0 {
+		return 0
+	}
+	return (float64(f.CurrentAmount) / float64(f.GoalAmount)) * 100
+}
+
+// IsVisible checks if the page is shown to donors
+func (f *Fundraiser) IsVisible() bool {
+	return f.Status == FundraiserStatusActive
+}
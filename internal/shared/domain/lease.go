@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// LeaseStatus represents the state of a lease contract
+type LeaseStatus string
+
+const (
+	LeaseStatusActive     LeaseStatus = "active"
+	LeaseStatusEnded      LeaseStatus = "ended"      // ran until its end date
+	LeaseStatusTerminated LeaseStatus = "terminated" // ended early
+)
+
+// BillingCycle represents how often rent of a lease is invoiced
+type BillingCycle string
+
+const (
+	BillingMonthly    BillingCycle = "monthly"
+	BillingQuarterly  BillingCycle = "quarterly"
+	BillingSemiannual BillingCycle = "semiannual"
+	BillingYearly     BillingCycle = "yearly"
+)
+
+// Months returns the length of a billing period in months
+func (c BillingCycle) Months() int {
+	switch c {
+	case BillingQuarterly:
+		return 3
+	case BillingSemiannual:
+		return 6
+	case BillingYearly:
+		return 12
+	}
+	return 1
+}
+
+// DepositStatus represents what happened to a lessee's security deposit
+type DepositStatus string
+
+const (
+	DepositStatusHeld      DepositStatus = "held"
+	DepositStatusReturned  DepositStatus = "returned"
+	DepositStatusForfeited DepositStatus = "forfeited"
+)
+
+// Lease represents a rental contract of a productive wakaf asset (ijarah)
+type Lease struct {
+	ID            int64  `json:"id" db:"id"`
+	AssetID       int64  `json:"asset_id" db:"asset_id"`
+	LesseeName    string `json:"lessee_name" db:"lessee_name"`
+	LesseeIDNo    string `json:"lessee_id_number,omitempty" db:"lessee_id_number"` // NIK or NPWP
+	LesseeEmail   string `json:"lessee_email,omitempty" db:"lessee_email"`
+	LesseePhone   string `json:"lessee_phone,omitempty" db:"lessee_phone"`
+	LesseeAddress string `json:"lessee_address,omitempty" db:"lessee_address"`
+
+	StartDate       time.Time    `json:"start_date" db:"start_date"`
+	EndDate         time.Time    `json:"end_date" db:"end_date"`
+	RentAmount      int64        `json:"rent_amount" db:"rent_amount"` // per billing period, before escalation
+	BillingCycle    BillingCycle `json:"billing_cycle" db:"billing_cycle"`
+	PaymentTermDays int          `json:"payment_term_days" db:"payment_term_days"` // days from period start to due date
+
+	// Rent rises by EscalationBPS every EscalationMonths months from the start date
+	EscalationBPS    int `json:"escalation_bps" db:"escalation_bps"`
+	EscalationMonths int `json:"escalation_months" db:"escalation_months"`
+
+	DepositAmount int64         `json:"deposit_amount" db:"deposit_amount"`
+	DepositStatus DepositStatus `json:"deposit_status" db:"deposit_status"`
+
+	Status            LeaseStatus `json:"status" db:"status"`
+	TerminatedAt      *time.Time  `json:"terminated_at,omitempty" db:"terminated_at"`
+	TerminationReason string      `json:"termination_reason,omitempty" db:"termination_reason"`
+	Notes             string      `json:"notes,omitempty" db:"notes"`
+	CreatedBy         int64       `json:"created_by" db:"created_by"`
+	CreatedAt         time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// InvoiceStatus represents the payment state of a rent invoice
+type InvoiceStatus string
+
+const (
+	InvoiceStatusUnpaid  InvoiceStatus = "unpaid"
+	InvoiceStatusPartial InvoiceStatus = "partial"
+	InvoiceStatusPaid    InvoiceStatus = "paid"
+	InvoiceStatusVoid    InvoiceStatus = "void" // period cancelled by early termination
+)
+
+// LeaseInvoice represents the rent due for one billing period of a lease
+type LeaseInvoice struct {
+	ID          int64         `json:"id" db:"id"`
+	LeaseID     int64         `json:"lease_id" db:"lease_id"`
+	AssetID     int64         `json:"asset_id" db:"asset_id"`
+	Sequence    int           `json:"sequence" db:"sequence"` // 1 for the first period of the lease
+	Number      string        `json:"number" db:"number"`
+	PeriodStart time.Time     `json:"period_start" db:"period_start"`
+	PeriodEnd   time.Time     `json:"period_end" db:"period_end"` // inclusive
+	Amount      int64         `json:"amount" db:"amount"`
+	AmountPaid  int64         `json:"amount_paid" db:"amount_paid"`
+	DueDate     time.Time     `json:"due_date" db:"due_date"`
+	Status      InvoiceStatus `json:"status" db:"status"`
+	PaidAt      *time.Time    `json:"paid_at,omitempty" db:"paid_at"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// LeasePayment represents rent received against an invoice. Each payment is
+// recorded as rent income of the asset.
+type LeasePayment struct {
+	ID         int64     `json:"id" db:"id"`
+	InvoiceID  int64     `json:"invoice_id" db:"invoice_id"`
+	LeaseID    int64     `json:"lease_id" db:"lease_id"`
+	AssetID    int64     `json:"asset_id" db:"asset_id"`
+	IncomeID   int64     `json:"income_id" db:"income_id"`
+	Amount     int64     `json:"amount" db:"amount"`
+	PaidAt     time.Time `json:"paid_at" db:"paid_at"`
+	Method     string    `json:"method" db:"method"` // transfer, cash, qris
+	Reference  string    `json:"reference,omitempty" db:"reference"`
+	Notes      string    `json:"notes,omitempty" db:"notes"`
+	RecordedBy int64     `json:"recorded_by" db:"recorded_by"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Lease payment methods
+const (
+	LeasePaymentTransfer = "transfer"
+	LeasePaymentCash     = "cash"
+	LeasePaymentQRIS     = "qris"
+)
+
+// IsActive checks if the lease still bills rent
+func (l *Lease) IsActive() bool {
+	return l.Status == LeaseStatusActive
+}
+
+// PeriodStart returns the first day of the nth billing period, counted from 0
+func (l *Lease) PeriodStart(n int) time.Time {
+	return AddMonths(l.StartDate, n*l.BillingCycle.Months())
+}
+
+// RentAt returns the full-period rent of a period starting on the given day,
+// compounding the escalation once per elapsed escalation interval
+func (l *Lease) RentAt(periodStart time.Time) int64 {
+	rent := l.RentAmount
+	if l.EscalationBPS <= 0 || l.EscalationMonths <= 0 {
+		return rent
+	}
+
+	for step := 1; !AddMonths(l.StartDate, step*l.EscalationMonths).After(periodStart); step++ {
+		rent += (rent*int64(l.EscalationBPS) + FullShareBPS/2) / FullShareBPS
+	}
+	return rent
+}
+
+// Invoice builds the invoice of the nth billing period, counted from 0. A
+// last period cut short by the end date is charged pro rata by day.
+func (l *Lease) Invoice(n int) *LeaseInvoice {
+	start := l.PeriodStart(n)
+	next := l.PeriodStart(n + 1)
+	end := next.AddDate(0, 0, -1)
+
+	amount := l.RentAt(start)
+	if end.After(l.EndDate) {
+		full := daysBetween(start, next)
+		end = l.EndDate
+		amount = amount * int64(daysBetween(start, end)+1) / int64(full)
+	}
+
+	return &LeaseInvoice{
+		LeaseID:     l.ID,
+		AssetID:     l.AssetID,
+		Sequence:    n + 1,
+		Number:      fmt.Sprintf("SEWA/%d/%03d", l.ID, n+1),
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Amount:      amount,
+		DueDate:     start.AddDate(0, 0, l.PaymentTermDays),
+		Status:      InvoiceStatusUnpaid,
+	}
+}
+
+// Outstanding returns the rent still owed on the invoice
+func (i *LeaseInvoice) Outstanding() int64 {
+	if i.Status == InvoiceStatusVoid {
+		return 0
+	}
+	return i.Amount - i.AmountPaid
+}
+
+// IsOverdue checks if the invoice is still owed after its due date
+func (i *LeaseInvoice) IsOverdue(day time.Time) bool {
+	return i.Outstanding() > 0 && day.After(i.DueDate)
+}
+
+// AddMonths adds months to a date, keeping the day of month but clamping it
+// to the last day of a shorter month (Jan 31 + 1 month is Feb 28 or 29)
+func AddMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+
+	day := t.Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// daysBetween counts the days from one date to a later one
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours()/24 + 0.5)
+}
@@ -0,0 +1,250 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestLeaseRentAt(t *testing.T) {
+	tests := []struct {
+		name        string
+		lease       Lease
+		periodStart time.Time
+		want        int64
+	}{
+		{
+			name:        "no escalation",
+			lease:       Lease{StartDate: date(2026, 1, 1), RentAmount: 1000000},
+			periodStart: date(2030, 1, 1),
+			want:        1000000,
+		},
+		{
+			name:        "escalation without interval",
+			lease:       Lease{StartDate: date(2026, 1, 1), RentAmount: 1000000, EscalationBPS: 500},
+			periodStart: date(2030, 1, 1),
+			want:        1000000,
+		},
+		{
+			name:        "before the first escalation",
+			lease:       Lease{StartDate: date(2026, 1, 1), RentAmount: 1000000, EscalationBPS: 500, EscalationMonths: 12},
+			periodStart: date(2026, 12, 1),
+			want:        1000000,
+		},
+		{
+			name:        "on the first escalation",
+			lease:       Lease{StartDate: date(2026, 1, 1), RentAmount: 1000000, EscalationBPS: 500, EscalationMonths: 12},
+			periodStart: date(2027, 1, 1),
+			want:        1050000,
+		},
+		{
+			name:        "compounded over three years",
+			lease:       Lease{StartDate: date(2026, 1, 1), RentAmount: 1000000, EscalationBPS: 500, EscalationMonths: 12},
+			periodStart: date(2029, 1, 1),
+			want:        1157625,
+		},
+		{
+			name:        "each step rounds to the rupiah",
+			lease:       Lease{StartDate: date(2026, 1, 1), RentAmount: 333333, EscalationBPS: 1000, EscalationMonths: 12},
+			periodStart: date(2028, 1, 1),
+			want:        403333, // 333333 + 33333, then 366666 + 36667
+		},
+		{
+			name:        "escalation date clamped to a shorter month",
+			lease:       Lease{StartDate: date(2026, 1, 31), RentAmount: 1000000, EscalationBPS: 1000, EscalationMonths: 1},
+			periodStart: date(2026, 2, 28),
+			want:        1100000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.lease.RentAt(tt.periodStart); got != tt.want {
+				t.Errorf("RentAt(%s) = %d, want %d", tt.periodStart.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaseInvoice(t *testing.T) {
+	monthly := Lease{
+		ID:              5,
+		StartDate:       date(2026, 1, 1),
+		EndDate:         date(2026, 12, 31),
+		RentAmount:      1000000,
+		BillingCycle:    BillingMonthly,
+		PaymentTermDays: 7,
+	}
+
+	withEnd := func(lease Lease, end time.Time) Lease {
+		lease.EndDate = end
+		return lease
+	}
+	withStart := func(lease Lease, start time.Time) Lease {
+		lease.StartDate = start
+		return lease
+	}
+
+	quarterly := withEnd(monthly, date(2026, 4, 30))
+	quarterly.BillingCycle = BillingQuarterly
+	quarterly.RentAmount = 3000000
+
+	escalating := withEnd(monthly, date(2028, 12, 31))
+	escalating.BillingCycle = BillingYearly
+	escalating.RentAmount = 12000000
+	escalating.EscalationBPS = 500
+	escalating.EscalationMonths = 12
+
+	tests := []struct {
+		name      string
+		lease     Lease
+		n         int
+		wantStart time.Time
+		wantEnd   time.Time
+		wantDue   time.Time
+		want      int64
+		wantNo    string
+	}{
+		{
+			name:      "first month",
+			lease:     monthly,
+			n:         0,
+			wantStart: date(2026, 1, 1),
+			wantEnd:   date(2026, 1, 31),
+			wantDue:   date(2026, 1, 8),
+			want:      1000000,
+			wantNo:    "SEWA/5/001",
+		},
+		{
+			name:      "last full month",
+			lease:     monthly,
+			n:         11,
+			wantStart: date(2026, 12, 1),
+			wantEnd:   date(2026, 12, 31),
+			wantDue:   date(2026, 12, 8),
+			want:      1000000,
+			wantNo:    "SEWA/5/012",
+		},
+		{
+			name:      "last month cut short is pro rata by day",
+			lease:     withEnd(monthly, date(2026, 3, 15)),
+			n:         2,
+			wantStart: date(2026, 3, 1),
+			wantEnd:   date(2026, 3, 15),
+			wantDue:   date(2026, 3, 8),
+			want:      483870, // 15 of 31 days
+			wantNo:    "SEWA/5/003",
+		},
+		{
+			name:      "last quarter cut short is pro rata by day",
+			lease:     quarterly,
+			n:         1,
+			wantStart: date(2026, 4, 1),
+			wantEnd:   date(2026, 4, 30),
+			wantDue:   date(2026, 4, 8),
+			want:      989010, // 30 of 91 days
+			wantNo:    "SEWA/5/002",
+		},
+		{
+			name:      "started on the 31st, billed through February",
+			lease:     withStart(monthly, date(2026, 1, 31)),
+			n:         0,
+			wantStart: date(2026, 1, 31),
+			wantEnd:   date(2026, 2, 27),
+			wantDue:   date(2026, 2, 7),
+			want:      1000000,
+			wantNo:    "SEWA/5/001",
+		},
+		{
+			name:      "started on the 31st, period start clamped to February",
+			lease:     withStart(monthly, date(2026, 1, 31)),
+			n:         1,
+			wantStart: date(2026, 2, 28),
+			wantEnd:   date(2026, 3, 30),
+			wantDue:   date(2026, 3, 7),
+			want:      1000000,
+			wantNo:    "SEWA/5/002",
+		},
+		{
+			name:      "started on the 31st, back to the 31st without drifting",
+			lease:     withStart(monthly, date(2026, 1, 31)),
+			n:         2,
+			wantStart: date(2026, 3, 31),
+			wantEnd:   date(2026, 4, 29),
+			wantDue:   date(2026, 4, 7),
+			want:      1000000,
+			wantNo:    "SEWA/5/003",
+		},
+		{
+			name:      "leap year February",
+			lease:     withEnd(withStart(monthly, date(2028, 1, 31)), date(2028, 12, 31)),
+			n:         1,
+			wantStart: date(2028, 2, 29),
+			wantEnd:   date(2028, 3, 30),
+			wantDue:   date(2028, 3, 7),
+			want:      1000000,
+			wantNo:    "SEWA/5/002",
+		},
+		{
+			name:      "escalated year",
+			lease:     escalating,
+			n:         2,
+			wantStart: date(2028, 1, 1),
+			wantEnd:   date(2028, 12, 31),
+			wantDue:   date(2028, 1, 8),
+			want:      13230000,
+			wantNo:    "SEWA/5/003",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := tt.lease.Invoice(tt.n)
+
+			if !invoice.PeriodStart.Equal(tt.wantStart) || !invoice.PeriodEnd.Equal(tt.wantEnd) {
+				t.Errorf("period = %s to %s, want %s to %s",
+					invoice.PeriodStart.Format("2006-01-02"), invoice.PeriodEnd.Format("2006-01-02"),
+					tt.wantStart.Format("2006-01-02"), tt.wantEnd.Format("2006-01-02"))
+			}
+			if !invoice.DueDate.Equal(tt.wantDue) {
+				t.Errorf("due date = %s, want %s", invoice.DueDate.Format("2006-01-02"), tt.wantDue.Format("2006-01-02"))
+			}
+			if invoice.Amount != tt.want {
+				t.Errorf("amount = %d, want %d", invoice.Amount, tt.want)
+			}
+			if invoice.Number != tt.wantNo || invoice.Sequence != tt.n+1 {
+				t.Errorf("number = %s (sequence %d), want %s (sequence %d)", invoice.Number, invoice.Sequence, tt.wantNo, tt.n+1)
+			}
+			if invoice.Status != InvoiceStatusUnpaid {
+				t.Errorf("status = %s, want %s", invoice.Status, InvoiceStatusUnpaid)
+			}
+		})
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		from   time.Time
+		months int
+		want   time.Time
+	}{
+		{date(2026, 1, 15), 1, date(2026, 2, 15)},
+		{date(2026, 1, 31), 1, date(2026, 2, 28)},
+		{date(2028, 1, 31), 1, date(2028, 2, 29)},
+		{date(2026, 3, 31), 1, date(2026, 4, 30)},
+		{date(2026, 1, 31), 3, date(2026, 4, 30)},
+		{date(2026, 11, 30), 3, date(2027, 2, 28)},
+		{date(2026, 3, 31), -1, date(2026, 2, 28)},
+		{date(2026, 8, 31), 0, date(2026, 8, 31)},
+	}
+
+	for _, tt := range tests {
+		if got := AddMonths(tt.from, tt.months); !got.Equal(tt.want) {
+			t.Errorf("AddMonths(%s, %d) = %s, want %s",
+				tt.from.Format("2006-01-02"), tt.months, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// MaintenanceSchedule represents recurring upkeep of an asset, e.g. a yearly
+// roof inspection
+type MaintenanceSchedule struct {
+	ID             int64      `json:"id" db:"id"`
+	AssetID        int64      `json:"asset_id" db:"asset_id"`
+	Title          string     `json:"title" db:"title"`
+	Description    string     `json:"description,omitempty" db:"description"`
+	IntervalMonths int        `json:"interval_months" db:"interval_months"`
+	NextDueDate    time.Time  `json:"next_due_date" db:"next_due_date"`
+	LastDoneDate   *time.Time `json:"last_done_date,omitempty" db:"last_done_date"`
+	EstimatedCost  int64      `json:"estimated_cost" db:"estimated_cost"`
+	IsActive       bool       `json:"is_active" db:"is_active"`
+	CreatedBy      int64      `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// WorkOrderStatus represents the state of a maintenance work order
+type WorkOrderStatus string
+
+const (
+	WorkOrderStatusOpen      WorkOrderStatus = "open"
+	WorkOrderStatusCompleted WorkOrderStatus = "completed" // signed off, cost posted to the ledger
+	WorkOrderStatusCancelled WorkOrderStatus = "cancelled"
+)
+
+// WorkOrder represents a maintenance job on an asset. While any work order of
+// an asset is open the asset is under maintenance.
+type WorkOrder struct {
+	ID            int64           `json:"id" db:"id"`
+	AssetID       int64           `json:"asset_id" db:"asset_id"`
+	ScheduleID    *int64          `json:"schedule_id,omitempty" db:"schedule_id"`
+	Title         string          `json:"title" db:"title"`
+	Description   string          `json:"description,omitempty" db:"description"`
+	Vendor        string          `json:"vendor,omitempty" db:"vendor"`
+	VendorContact string          `json:"vendor_contact,omitempty" db:"vendor_contact"`
+	EstimatedCost int64           `json:"estimated_cost" db:"estimated_cost"`
+	ActualCost    *int64          `json:"actual_cost,omitempty" db:"actual_cost"`
+	PhotoURLs     []string        `json:"photo_urls" db:"photo_urls"`
+	Status        WorkOrderStatus `json:"status" db:"status"`
+	RestoreStatus AssetStatus     `json:"restore_status" db:"restore_status"` // asset status once no work order is open
+	OpenedBy      int64           `json:"opened_by" db:"opened_by"`
+	ClosedBy      *int64          `json:"closed_by,omitempty" db:"closed_by"`
+	CompletedOn   *time.Time      `json:"completed_on,omitempty" db:"completed_on"`
+	ClosedAt      *time.Time      `json:"closed_at,omitempty" db:"closed_at"`
+	CloseNotes    string          `json:"close_notes,omitempty" db:"close_notes"` // sign-off remarks or cancel reason
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// IsOpen checks if the work order is still in progress
+func (w *WorkOrder) IsOpen() bool {
+	return w.Status == WorkOrderStatusOpen
+}
+
+// Advance moves the schedule past a completion: the next due date is counted
+// from the day the work was done
+func (s *MaintenanceSchedule) Advance(doneOn time.Time) {
+	s.LastDoneDate = &doneOn
+	s.NextDueDate = AddMonths(doneOn, s.IntervalMonths)
+}
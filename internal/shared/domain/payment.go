@@ -40,6 +40,7 @@ const (
 type Donation struct {
 	ID              int64          `json:"id" db:"id"`
 	CampaignID      int64          `json:"campaign_id" db:"campaign_id"`
+	FundraiserID    *int64         `json:"fundraiser_id,omitempty" db:"fundraiser_id"` // peer-to-peer page the donation came through
 	UserID          int64          `json:"user_id" db:"user_id"`
 	Amount          int64          `json:"amount" db:"amount"`
 	Status          PaymentStatus  `json:"status" db:"status"`
@@ -83,9 +84,26 @@ type Ledger struct {
 	BalanceBefore  int64     `json:"balance_before" db:"balance_before"`
 	BalanceAfter   int64     `json:"balance_after" db:"balance_after"`
 	Description    string    `json:"description" db:"description"`
+	ReferenceType  string    `json:"reference_type,omitempty" db:"reference_type"` // donation, asset_income, distribution_run, disbursement, asset_revaluation
+	ReferenceID    int64     `json:"reference_id,omitempty" db:"reference_id"`
+	EntryDate      time.Time `json:"entry_date" db:"entry_date"` // determines the accounting period
+	Sequence       int64     `json:"sequence" db:"sequence"`     // position in the hash chain
+	PrevHash       string    `json:"prev_hash" db:"prev_hash"`
+	Hash           string    `json:"hash" db:"hash"`
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
 }
 
+// Ledger reference types
+const (
+	LedgerRefDonation          = "donation"
+	LedgerRefAssetIncome       = "asset_income"
+	LedgerRefDistributionRun   = "distribution_run"
+	LedgerRefDisbursement      = "disbursement"
+	LedgerRefAssetRevaluation  = "asset_revaluation"
+	LedgerRefAssetMaintenance  = "asset_maintenance"
+	LedgerRefAssetDepreciation = "asset_depreciation"
+)
+
 // FraudCheck represents fraud detection results
 type FraudCheck struct {
 	ID          int64     `json:"id" db:"id"`
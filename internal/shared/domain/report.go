@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"time"
+)
+
+// ReportPeriodType represents the reporting period of a BWI report
+type ReportPeriodType string
+
+const (
+	ReportPeriodSemester ReportPeriodType = "semester"
+	ReportPeriodYear     ReportPeriodType = "year"
+)
+
+// ReportTypeBWI identifies the periodic nazir report submitted to Badan Wakaf Indonesia
+const ReportTypeBWI = "bwi"
+
+// BWIReport is the periodic nazir report to Badan Wakaf Indonesia
+type BWIReport struct {
+	PeriodType    ReportPeriodType     `json:"period_type"`
+	Year          int                  `json:"year"`
+	Semester      int                  `json:"semester,omitempty"` // 1 or 2, empty for yearly reports
+	PeriodStart   time.Time            `json:"period_start"`
+	PeriodEnd     time.Time            `json:"period_end"`
+	GeneratedAt   time.Time            `json:"generated_at"`
+	Summary       BWIReportSummary     `json:"summary"`
+	Assets        []BWIAssetRow        `json:"assets"`
+	Valuations    []BWIValuationRow    `json:"valuations"`
+	CashWakaf     []BWICashWakafRow    `json:"cash_wakaf"`
+	Investments   []BWIInvestmentRow   `json:"investments"`
+	Distributions []BWIDistributionRow `json:"distributions"`
+	Ledger        []BWILedgerRow       `json:"ledger"`
+}
+
+// BWIReportSummary totals the report tables
+type BWIReportSummary struct {
+	AssetCount       int   `json:"asset_count"`
+	TotalAssetValue  int64 `json:"total_asset_value"`
+	WakafUang        int64 `json:"wakaf_uang"`         // donations to cash wakaf campaigns
+	WakafMelaluiUang int64 `json:"wakaf_melalui_uang"` // donations to acquire or build assets
+	DonationCount    int64 `json:"donation_count"`
+	InvestmentIncome int64 `json:"investment_income"`
+	TotalDistributed int64 `json:"total_distributed"`
+	NazirShare       int64 `json:"nazir_share"`
+	BeneficiaryShare int64 `json:"beneficiary_share"`
+}
+
+// BWIAssetRow is an asset held at the end of the period
+type BWIAssetRow struct {
+	AssetID         int64       `json:"asset_id"`
+	Name            string      `json:"name"`
+	Type            AssetType   `json:"type"`
+	Status          AssetStatus `json:"status"`
+	Location        string      `json:"location"`
+	Area            *float64    `json:"area,omitempty"`
+	AreaUnit        string      `json:"area_unit,omitempty"`
+	AcquisitionDate time.Time   `json:"acquisition_date"`
+	PurchaseValue   int64       `json:"purchase_value"`
+	Value           int64       `json:"value"` // latest valuation at period end, else purchase value
+}
+
+// BWIValuationRow is an asset valuation made during the period
+type BWIValuationRow struct {
+	AssetID        int64     `json:"asset_id"`
+	AssetName      string    `json:"asset_name"`
+	ValuationDate  time.Time `json:"valuation_date"`
+	ValuationValue int64     `json:"valuation_value"`
+	Method         string    `json:"method"`
+	ValuedBy       string    `json:"valued_by"`
+}
+
+// BWICashWakafRow is wakaf money received per campaign during the period
+type BWICashWakafRow struct {
+	CampaignID    int64        `json:"campaign_id"`
+	CampaignTitle string       `json:"campaign_title"`
+	CampaignType  CampaignType `json:"campaign_type"`
+	DonationCount int64        `json:"donation_count"`
+	Amount        int64        `json:"amount"`
+}
+
+// BWIInvestmentRow is income produced by an asset during the period
+type BWIInvestmentRow struct {
+	AssetID     int64        `json:"asset_id"`
+	AssetName   string       `json:"asset_name"`
+	Source      IncomeSource `json:"source"`
+	IncomeCount int64        `json:"income_count"`
+	Amount      int64        `json:"amount"`
+}
+
+// BWIDistributionRow is a distribution run executed for the period
+type BWIDistributionRow struct {
+	RunID             int64     `json:"run_id"`
+	AssetID           int64     `json:"asset_id"`
+	AssetName         string    `json:"asset_name"`
+	PeriodStart       time.Time `json:"period_start"`
+	PeriodEnd         time.Time `json:"period_end"`
+	TotalIncome       int64     `json:"total_income"`
+	NazirAmount       int64     `json:"nazir_amount"`
+	BeneficiaryAmount int64     `json:"beneficiary_amount"`
+}
+
+// BWILedgerRow totals ledger postings per account during the period
+type BWILedgerRow struct {
+	AccountName string `json:"account_name"`
+	Debit       int64  `json:"debit"`
+	Credit      int64  `json:"credit"`
+}
+
+// ReportSubmission is the stored snapshot of a submitted report
+type ReportSubmission struct {
+	ID          int64            `json:"id" db:"id"`
+	ReportType  string           `json:"report_type" db:"report_type"`
+	PeriodType  ReportPeriodType `json:"period_type" db:"period_type"`
+	Year        int              `json:"year" db:"year"`
+	Semester    int              `json:"semester,omitempty" db:"semester"`
+	Revision    int              `json:"revision" db:"revision"`
+	Report      *BWIReport       `json:"report,omitempty" db:"content"`
+	ContentHash string           `json:"content_hash" db:"content_hash"`     // sha256 of the stored content
+	Reference   string           `json:"reference,omitempty" db:"reference"` // BWI receipt number
+	Notes       string           `json:"notes,omitempty" db:"notes"`
+	SubmittedBy int64            `json:"submitted_by" db:"submitted_by"`
+	SubmittedAt time.Time        `json:"submitted_at" db:"submitted_at"`
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+)
+
+// Locale is a language campaign content is written in
+type Locale string
+
+const (
+	LocaleIndonesian Locale = "id"
+	LocaleEnglish    Locale = "en"
+	LocaleArabic     Locale = "ar"
+)
+
+// DefaultLocale is the language campaigns are written in; other locales are
+// translations of it
+const DefaultLocale = LocaleIndonesian
+
+// SupportedLocales lists the locales content can be translated into
+var SupportedLocales = []Locale{LocaleIndonesian, LocaleEnglish, LocaleArabic}
+
+// IsSupported checks if content can be served in the locale
+func (l Locale) IsSupported() bool {
+	for _, supported := range SupportedLocales {
+		if l == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRTL checks if the locale is written right to left
+func (l Locale) IsRTL() bool {
+	return l == LocaleArabic
+}
+
+// Direction is the HTML dir value of the locale
+func (l Locale) Direction() string {
+	if l.IsRTL() {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// Fallbacks lists the locales to try, in order, when content is missing in
+// the locale. Arabic readers get English before Indonesian; the default
+// locale always ends the chain.
+func (l Locale) Fallbacks() []Locale {
+	switch l {
+	case LocaleArabic:
+		return []Locale{LocaleArabic, LocaleEnglish, DefaultLocale}
+	case LocaleEnglish:
+		return []Locale{LocaleEnglish, DefaultLocale}
+	default:
+		return []Locale{DefaultLocale}
+	}
+}
+
+// TranslationEntity is the kind of content a translation belongs to
+type TranslationEntity string
+
+const (
+	TranslationEntityCampaign  TranslationEntity = "campaign"
+	TranslationEntityMilestone TranslationEntity = "milestone"
+	TranslationEntityUpdate    TranslationEntity = "update"
+)
+
+// Translation holds the text of a campaign, milestone or update in another
+// locale. Description is the campaign or milestone description, or the
+// Markdown body of an update; ShortDesc is only used by campaigns.
+type Translation struct {
+	ID          int64             `json:"id" db:"id"`
+	CampaignID  int64             `json:"campaign_id" db:"campaign_id"`
+	EntityType  TranslationEntity `json:"entity_type" db:"entity_type"`
+	EntityID    int64             `json:"entity_id" db:"entity_id"`
+	Locale      Locale            `json:"locale" db:"locale"`
+	Title       string            `json:"title" db:"title"`
+	ShortDesc   string            `json:"short_desc,omitempty" db:"short_desc"`
+	Description string            `json:"description" db:"description"`
+	UpdatedBy   int64             `json:"updated_by" db:"updated_by"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+}
@@ -45,6 +45,13 @@ type UserProfile struct {
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Actor identifies the authenticated user performing an operation
+type Actor struct {
+	UserID   int64  `json:"user_id"`
+	Role     Role   `json:"role"`
+	TenantID *int64 `json:"tenant_id,omitempty"`
+}
+
 // HasRole checks if user has a specific role
 func (u *User) HasRole(role Role) bool {
 	return u.Role == role
@@ -59,3 +66,19 @@ func (u *User) IsAdmin() bool {
 func (u *User) CanAccessEnterprise() bool {
 	return u.TenantID != nil
 }
+
+
+// IsAdmin checks if actor is an admin
+func (a *Actor) IsAdmin() bool {
+	return a.Role == RoleAdmin
+}
+
+// HasAnyRole checks if actor has one of the given roles
+func (a *Actor) HasAnyRole(roles ...Role) bool {
+	for _, role := range roles {
+		if a.Role == role {
+			return true
+		}
+	}
+	return false
+}
@@ -43,6 +43,7 @@ const (
 	ErrCodePaymentFailed   = "PAYMENT_FAILED"
 	ErrCodeInsufficientFunds = "INSUFFICIENT_FUNDS"
 	ErrCodeFraudDetected   = "FRAUD_DETECTED"
+	ErrCodePeriodClosed    = "PERIOD_CLOSED"
 )
 
 // New creates a new AppError
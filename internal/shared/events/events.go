@@ -0,0 +1,92 @@
+// Package events provides an in-process event bus used to decouple services
+// running in the same binary. Consumers in other processes reconcile from the
+// database instead, so handlers must be idempotent.
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	DonationSucceeded = "donation.succeeded"
+	MilestoneReached  = "campaign.milestone_reached"
+)
+
+// Event is a domain event; Payload holds one of the payload types below
+type Event struct {
+	Type       string      `json:"type"`
+	Key        string      `json:"key"`
+	Payload    interface{} `json:"payload"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+// DonationSucceededPayload is published when a donation is paid
+type DonationSucceededPayload struct {
+	DonationID int64     `json:"donation_id"`
+	CampaignID int64     `json:"campaign_id"`
+	UserID     int64     `json:"user_id,omitempty"`
+	DonorEmail string    `json:"donor_email,omitempty"`
+	Amount     int64     `json:"amount"`
+	PaidAt     time.Time `json:"paid_at"`
+}
+
+// MilestoneReachedPayload is published when campaign totals cross a milestone
+type MilestoneReachedPayload struct {
+	CampaignID    int64     `json:"campaign_id"`
+	MilestoneID   int64     `json:"milestone_id"`
+	Title         string    `json:"title"`
+	TargetAmount  int64     `json:"target_amount"`
+	CurrentAmount int64     `json:"current_amount"`
+	CompletedAt   time.Time `json:"completed_at"`
+}
+
+// Handler handles a published event
+type Handler func(ctx context.Context, event Event) error
+
+// Publisher publishes domain events
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Bus dispatches events synchronously to in-process subscribers
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewBus creates a new event bus
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe registers a handler for an event type
+func (b *Bus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish delivers the event to every subscriber. A failing subscriber is
+// logged and does not fail the publisher; it is expected to catch up later.
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers[event.Type]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			log.Printf("events: %s handler for %s failed: %v", event.Type, event.Key, err)
+		}
+	}
+
+	return nil
+}
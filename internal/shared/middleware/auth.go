@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/akordium-id/waqfwise/internal/shared/response"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Claims mirrors the JWT claims issued by the auth service
+type Claims struct {
+	UserID   int64       `json:"user_id"`
+	Email    string      `json:"email"`
+	Role     domain.Role `json:"role"`
+	TenantID *int64      `json:"tenant_id,omitempty"`
+	jwt.StandardClaims
+}
+
+// Auth authenticates requests using tokens issued by the auth service
+type Auth struct {
+	jwtSecret []byte
+}
+
+// NewAuth creates a new auth middleware
+func NewAuth(jwtSecret string) *Auth {
+	return &Auth{jwtSecret: []byte(jwtSecret)}
+}
+
+// Authenticate rejects requests without a valid bearer token
+func (a *Auth) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			response.Error(w, errors.ErrUnauthorized)
+			return
+		}
+
+		claims, err := a.parse(authHeader)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
+	})
+}
+
+// Optional attaches the actor when a valid bearer token is present
+func (a *Auth) Optional(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+			if claims, err := a.parse(authHeader); err == nil {
+				r = r.WithContext(withClaims(r.Context(), claims))
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireRole restricts access to authenticated users with one of the given roles
+func RequireRole(roles ...domain.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor := ActorFromContext(r.Context())
+			if actor == nil {
+				response.Error(w, errors.ErrUnauthorized)
+				return
+			}
+
+			if !actor.HasAnyRole(roles...) {
+				response.Error(w, errors.ErrForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// parse validates the Authorization header and returns its claims
+func (a *Auth) parse(authHeader string) (*Claims, error) {
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, errors.ErrInvalidToken
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(parts[1], claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return a.jwtSecret, nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInvalidToken, "Invalid token", 401)
+	}
+
+	if !token.Valid || claims.UserID == 0 {
+		return nil, errors.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// Context helpers
+type contextKey string
+
+const actorKey contextKey = "actor"
+
+func withClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, actorKey, &domain.Actor{
+		UserID:   claims.UserID,
+		Role:     claims.Role,
+		TenantID: claims.TenantID,
+	})
+}
+
+// ActorFromContext returns the authenticated actor, or nil for anonymous requests
+func ActorFromContext(ctx context.Context) *domain.Actor {
+	actor, ok := ctx.Value(actorKey).(*domain.Actor)
+	if !ok {
+		return nil
+	}
+	return actor
+}
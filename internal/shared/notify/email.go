@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// EmailSender sends plain-text email over SMTP
+type EmailSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewEmailSender creates a new SMTP email sender. Authentication is skipped
+// when username is empty, e.g. for a local relay.
+func NewEmailSender(host, port, username, password, from string) *EmailSender {
+	sender := &EmailSender{
+		addr: net.JoinHostPort(host, port),
+		from: from,
+	}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender
+}
+
+// Send sends msg as a UTF-8 plain-text email
+func (s *EmailSender) Send(ctx context.Context, msg *Message) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{msg.To}, b.Bytes()); err != nil {
+		return fmt.Errorf("notify: send email: %w", err)
+	}
+	return nil
+}
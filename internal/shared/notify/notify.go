@@ -0,0 +1,110 @@
+// Package notify delivers email and WhatsApp notifications. Services queue
+// messages in the notifications table inside their own transaction and an
+// Outbox loop sends them, so a failed send is retried instead of lost.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Channel is a notification delivery channel
+type Channel string
+
+const (
+	ChannelEmail    Channel = "email"
+	ChannelWhatsApp Channel = "whatsapp"
+)
+
+// Channels lists the supported delivery channels
+var Channels = []string{string(ChannelEmail), string(ChannelWhatsApp)}
+
+// Message is a notification to a single recipient
+type Message struct {
+	Channel       Channel
+	To            string // email address or WhatsApp phone number
+	Subject       string // email only
+	Body          string
+	DedupeKey     string // optional; a message whose key was already queued is skipped
+	ReferenceType string
+	ReferenceID   int64
+}
+
+// Sender sends a message over one or more channels
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Dispatcher routes messages to the sender registered for their channel
+type Dispatcher struct {
+	mu      sync.RWMutex
+	senders map[Channel]Sender
+}
+
+// NewDispatcher creates a new dispatcher without senders
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{senders: make(map[Channel]Sender)}
+}
+
+// Register sets the sender for a channel
+func (d *Dispatcher) Register(channel Channel, sender Sender) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.senders[channel] = sender
+}
+
+// Send sends msg with the sender of its channel
+func (d *Dispatcher) Send(ctx context.Context, msg *Message) error {
+	d.mu.RLock()
+	sender, ok := d.senders[msg.Channel]
+	d.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("notify: no sender for channel %q", msg.Channel)
+	}
+	return sender.Send(ctx, msg)
+}
+
+// LogSender logs messages instead of sending them; used for channels that
+// are not configured, e.g. in development
+type LogSender struct{}
+
+// Send logs the message
+func (LogSender) Send(ctx context.Context, msg *Message) error {
+	log.Printf("notify: [%s] to %s: %s", msg.Channel, msg.To, msg.Subject)
+	return nil
+}
+
+// Config holds channel settings; a channel left unconfigured is logged
+// instead of sent
+type Config struct {
+	SMTPHost       string
+	SMTPPort       string
+	SMTPUsername   string
+	SMTPPassword   string
+	SMTPFrom       string
+	WhatsAppAPIURL string
+	WhatsAppToken  string
+}
+
+// NewDispatcherFromConfig creates a dispatcher with a sender per channel
+func NewDispatcherFromConfig(cfg Config) *Dispatcher {
+	d := NewDispatcher()
+
+	if cfg.SMTPHost != "" {
+		d.Register(ChannelEmail, NewEmailSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom))
+	} else {
+		d.Register(ChannelEmail, LogSender{})
+	}
+
+	if cfg.WhatsAppAPIURL != "" {
+		d.Register(ChannelWhatsApp, NewWhatsAppSender(cfg.WhatsAppAPIURL, cfg.WhatsAppToken))
+	} else {
+		d.Register(ChannelWhatsApp, LogSender{})
+	}
+
+	return d
+}
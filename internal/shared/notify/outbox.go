@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+)
+
+// Notification statuses
+const (
+	StatusPending = "pending"
+	StatusSending = "sending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+)
+
+const (
+	// deliverBatch bounds the notifications sent per pass
+	deliverBatch = 100
+	// maxAttempts is how often a notification is tried before it fails
+	maxAttempts = 5
+	// claimLease is how long a claimed notification is left to its sender
+	// before another pass may claim it again
+	claimLease = 5 * time.Minute
+)
+
+// Enqueue queues messages for delivery inside tx, so they are only sent if
+// the caller's change commits. Messages whose DedupeKey is already queued are
+// skipped.
+func Enqueue(ctx context.Context, tx *sql.Tx, messages ...*Message) error {
+	query := `
+		INSERT INTO notifications (channel, recipient, subject, body, dedupe_key, reference_type, reference_id,
+		                           status, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, 0), $8, 0, $9, $9)
+		ON CONFLICT (dedupe_key) DO NOTHING
+	`
+
+	now := time.Now()
+	for _, msg := range messages {
+		if _, err := tx.ExecContext(ctx, query,
+			msg.Channel, msg.To, msg.Subject, msg.Body, msg.DedupeKey, msg.ReferenceType, msg.ReferenceID,
+			StatusPending, now,
+		); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to queue notification", 500)
+		}
+	}
+
+	return nil
+}
+
+// Outbox sends queued notifications, retrying failures with backoff
+type Outbox struct {
+	db     *sql.DB
+	sender Sender
+}
+
+// NewOutbox creates a new notification outbox
+func NewOutbox(db *sql.DB, sender Sender) *Outbox {
+	return &Outbox{
+		db:     db,
+		sender: sender,
+	}
+}
+
+// Deliver sends due notifications until none are left. Rows are claimed with
+// SKIP LOCKED so several processes can deliver side by side; a claim not
+// settled within claimLease, such as after a crash, is claimed again.
+func (o *Outbox) Deliver(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		n, claimed, err := o.deliverBatch(ctx)
+		sent += n
+		if err != nil || claimed < deliverBatch {
+			return sent, err
+		}
+	}
+}
+
+// Run delivers on an interval until ctx is cancelled
+func (o *Outbox) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := o.Deliver(ctx); err != nil {
+			log.Printf("notify: delivery failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverBatch sends one batch and reports how many were sent and claimed.
+// The batch is claimed in its own short transaction, so no row lock is held
+// while sending, and each result is recorded on its own.
+func (o *Outbox) deliverBatch(ctx context.Context) (int, int, error) {
+	query := `
+		UPDATE notifications SET status = $1, next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE status IN ($3, $1) AND next_attempt_at <= $4
+			ORDER BY id
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, channel, recipient, COALESCE(subject, ''), body, attempts
+	`
+
+	// The lease doubles as the claim's token; Postgres keeps microseconds
+	now := time.Now()
+	lease := now.Add(claimLease).Truncate(time.Microsecond)
+	rows, err := o.db.QueryContext(ctx, query, StatusSending, lease, StatusPending, now, deliverBatch)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to claim notifications", 500)
+	}
+
+	type queued struct {
+		id       int64
+		attempts int
+		msg      Message
+	}
+
+	batch := make([]*queued, 0)
+	for rows.Next() {
+		q := &queued{}
+		if err := rows.Scan(&q.id, &q.msg.Channel, &q.msg.To, &q.msg.Subject, &q.msg.Body, &q.attempts); err != nil {
+			rows.Close()
+			return 0, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to scan notification", 500)
+		}
+		batch = append(batch, q)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to claim notifications", 500)
+	}
+
+	// Results only apply while this pass holds the claim, so a pass that
+	// outlived its lease cannot overwrite the pass that claimed the row again
+	sentQuery := `
+		UPDATE notifications SET status = $1, attempts = attempts + 1, sent_at = $2, last_error = NULL
+		WHERE id = $3 AND status = $4 AND next_attempt_at = $5
+	`
+	retryQuery := `
+		UPDATE notifications SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4
+		WHERE id = $5 AND status = $6 AND next_attempt_at = $7
+	`
+
+	sent := 0
+	for _, q := range batch {
+		sendErr := o.sender.Send(ctx, &q.msg)
+		if sendErr == nil {
+			if _, err := o.db.ExecContext(ctx, sentQuery, StatusSent, time.Now(), q.id, StatusSending, lease); err != nil {
+				return sent, len(batch), errors.Wrap(err, errors.ErrCodeInternal, "Failed to mark notification sent", 500)
+			}
+			sent++
+			continue
+		}
+
+		attempts := q.attempts + 1
+		status := StatusPending
+		if attempts >= maxAttempts {
+			status = StatusFailed
+		}
+		// Back off 1, 4, 9, 16 minutes between attempts
+		next := time.Now().Add(time.Duration(attempts*attempts) * time.Minute)
+		if _, err := o.db.ExecContext(ctx, retryQuery, status, attempts, next, sendErr.Error(), q.id, StatusSending, lease); err != nil {
+			return sent, len(batch), errors.Wrap(err, errors.ErrCodeInternal, "Failed to record notification failure", 500)
+		}
+	}
+
+	return sent, len(batch), nil
+}
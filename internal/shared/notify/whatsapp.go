@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WhatsAppSender sends text messages through the WhatsApp Business Cloud API
+type WhatsAppSender struct {
+	apiURL string // e.g. https://graph.facebook.com/v18.0/<phone-number-id>/messages
+	token  string
+	client *http.Client
+}
+
+// NewWhatsAppSender creates a new WhatsApp sender
+func NewWhatsAppSender(apiURL, token string) *WhatsAppSender {
+	return &WhatsAppSender{
+		apiURL: apiURL,
+		token:  token,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Send sends msg as a WhatsApp text message
+func (s *WhatsAppSender) Send(ctx context.Context, msg *Message) error {
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"to":                NormalizePhone(msg.To),
+		"type":              "text",
+		"text":              map[string]string{"body": msg.Body},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("notify: encode whatsapp message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.apiURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify: build whatsapp request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: send whatsapp message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notify: whatsapp api returned %d: %s", resp.StatusCode, detail)
+	}
+	return nil
+}
+
+// NormalizePhone converts an Indonesian phone number to international
+// digits, e.g. "0812-3456-789" to "628123456789"
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+
+	digits := b.String()
+	if strings.HasPrefix(digits, "0") {
+		return "62" + digits[1:]
+	}
+	return digits
+}
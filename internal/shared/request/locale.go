@@ -0,0 +1,45 @@
+package request
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/akordium-id/waqfwise/internal/shared/domain"
+)
+
+// Locale negotiates the content locale from the lang query parameter, then
+// the Accept-Language header, falling back to the default locale. Region
+// subtags are ignored, so "en-GB" and "ar-SA" match English and Arabic.
+func Locale(r *http.Request) domain.Locale {
+	if lang := baseLocale(r.URL.Query().Get("lang")); lang.IsSupported() {
+		return lang
+	}
+
+	best := domain.DefaultLocale
+	bestQuality := 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		locale := baseLocale(tag)
+		if locale.IsSupported() && quality > bestQuality {
+			best, bestQuality = locale, quality
+		}
+	}
+
+	return best
+}
+
+// baseLocale reduces a language tag to its primary language subtag
+func baseLocale(tag string) domain.Locale {
+	primary, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	return domain.Locale(strings.ToLower(primary))
+}
@@ -0,0 +1,48 @@
+package request
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/akordium-id/waqfwise/internal/shared/errors"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+// PathID parses a positive numeric path variable
+func PathID(r *http.Request, name string) (int64, error) {
+	id, err := strconv.ParseInt(mux.Vars(r)[name], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New(errors.ErrCodeBadRequest, "Invalid "+name, 400)
+	}
+	return id, nil
+}
+
+// Pagination parses page and per_page query parameters
+func Pagination(r *http.Request) (page, perPage int) {
+	page, _ = strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	perPage, _ = strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > maxPerPage {
+		perPage = defaultPerPage
+	}
+
+	return page, perPage
+}
+
+// Cursor parses cursor and limit query parameters for cursor-paginated lists
+func Cursor(r *http.Request) (cursor string, limit int) {
+	limit, _ = strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > maxPerPage {
+		limit = defaultPerPage
+	}
+
+	return r.URL.Query().Get("cursor"), limit
+}
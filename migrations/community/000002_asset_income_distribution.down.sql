@@ -0,0 +1,11 @@
+-- WaqfWise Community Edition - Rollback productive wakaf income distribution
+
+ALTER TABLE IF EXISTS ledgers DROP COLUMN IF EXISTS reference_id;
+ALTER TABLE IF EXISTS ledgers DROP COLUMN IF EXISTS reference_type;
+
+DROP TABLE IF EXISTS distribution_allocations;
+ALTER TABLE IF EXISTS asset_incomes DROP CONSTRAINT IF EXISTS fk_asset_incomes_run;
+DROP TABLE IF EXISTS distribution_runs;
+DROP TABLE IF EXISTS distribution_rules;
+DROP TABLE IF EXISTS asset_incomes;
+DROP TABLE IF EXISTS assets;
@@ -0,0 +1,96 @@
+-- WaqfWise Community Edition - Productive wakaf income distribution (hasil wakaf)
+
+-- Assets are linked to the campaign that raised them; the campaign's nazir manages them
+CREATE TABLE IF NOT EXISTS assets (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'active',
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    location TEXT,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    area DOUBLE PRECISION CHECK (area > 0),
+    area_unit VARCHAR(20),
+    purchase_value BIGINT NOT NULL DEFAULT 0 CHECK (purchase_value >= 0),
+    current_value BIGINT NOT NULL DEFAULT 0 CHECK (current_value >= 0),
+    last_valuation_at TIMESTAMP WITH TIME ZONE,
+    acquisition_date DATE NOT NULL,
+    tenant_id BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_assets_campaign ON assets(campaign_id);
+CREATE INDEX IF NOT EXISTS idx_assets_status ON assets(status, type);
+CREATE INDEX IF NOT EXISTS idx_assets_tenant ON assets(tenant_id);
+
+-- Income produced by wakaf assets
+CREATE TABLE IF NOT EXISTS asset_incomes (
+    id BIGSERIAL PRIMARY KEY,
+    asset_id BIGINT NOT NULL REFERENCES assets(id),
+    source VARCHAR(50) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    received_at DATE NOT NULL,
+    description TEXT,
+    recorded_by BIGINT NOT NULL,
+    distribution_run_id BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_asset_incomes_asset ON asset_incomes(asset_id, received_at);
+CREATE INDEX idx_asset_incomes_undistributed ON asset_incomes(received_at) WHERE distribution_run_id IS NULL;
+
+-- Distribution rules per asset, shares in basis points
+CREATE TABLE IF NOT EXISTS distribution_rules (
+    id BIGSERIAL PRIMARY KEY,
+    asset_id BIGINT NOT NULL REFERENCES assets(id),
+    beneficiary_type VARCHAR(50) NOT NULL,
+    beneficiary_name VARCHAR(255) NOT NULL,
+    share_bps INTEGER NOT NULL CHECK (share_bps > 0 AND share_bps <= 10000),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_distribution_rules_asset ON distribution_rules(asset_id) WHERE is_active;
+
+-- Distribution runs per asset and period
+CREATE TABLE IF NOT EXISTS distribution_runs (
+    id BIGSERIAL PRIMARY KEY,
+    asset_id BIGINT NOT NULL REFERENCES assets(id),
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    total_income BIGINT NOT NULL,
+    nazir_amount BIGINT NOT NULL DEFAULT 0,
+    beneficiary_amount BIGINT NOT NULL DEFAULT 0,
+    income_count INTEGER NOT NULL DEFAULT 0,
+    executed_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- Nazir share is capped at 10% (UU 41/2004 Pasal 12)
+    CONSTRAINT chk_distribution_runs_nazir_cap CHECK (nazir_amount * 10 <= total_income)
+);
+
+CREATE INDEX idx_distribution_runs_asset ON distribution_runs(asset_id, period_end);
+
+ALTER TABLE asset_incomes
+    ADD CONSTRAINT fk_asset_incomes_run FOREIGN KEY (distribution_run_id) REFERENCES distribution_runs(id);
+
+-- Allocations of a run to each beneficiary
+CREATE TABLE IF NOT EXISTS distribution_allocations (
+    id BIGSERIAL PRIMARY KEY,
+    run_id BIGINT NOT NULL REFERENCES distribution_runs(id),
+    rule_id BIGINT NOT NULL REFERENCES distribution_rules(id),
+    beneficiary_type VARCHAR(50) NOT NULL,
+    beneficiary_name VARCHAR(255) NOT NULL,
+    share_bps INTEGER NOT NULL,
+    amount BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_distribution_allocations_run ON distribution_allocations(run_id);
+
+-- Ledger entries not tied to a donation reference their source record
+ALTER TABLE IF EXISTS ledgers ADD COLUMN IF NOT EXISTS reference_type VARCHAR(50);
+ALTER TABLE IF EXISTS ledgers ADD COLUMN IF NOT EXISTS reference_id BIGINT;
@@ -0,0 +1,15 @@
+-- WaqfWise Community Edition - Rollback accounting periods and tamper-evident ledger
+
+DROP TABLE IF EXISTS accounting_periods;
+
+DROP TRIGGER IF EXISTS trg_ledgers_append_only ON ledgers;
+DROP FUNCTION IF EXISTS ledgers_append_only();
+
+DROP INDEX IF EXISTS idx_ledgers_reference;
+DROP INDEX IF EXISTS idx_ledgers_entry_date;
+DROP INDEX IF EXISTS idx_ledgers_sequence;
+
+ALTER TABLE IF EXISTS ledgers DROP COLUMN IF EXISTS hash;
+ALTER TABLE IF EXISTS ledgers DROP COLUMN IF EXISTS prev_hash;
+ALTER TABLE IF EXISTS ledgers DROP COLUMN IF EXISTS sequence;
+ALTER TABLE IF EXISTS ledgers DROP COLUMN IF EXISTS entry_date;
@@ -0,0 +1,58 @@
+-- WaqfWise Community Edition - Accounting periods and tamper-evident ledger
+
+-- Double-entry ledger (created here for databases that predate it)
+CREATE TABLE IF NOT EXISTS ledgers (
+    id BIGSERIAL PRIMARY KEY,
+    donation_id BIGINT,
+    account_type VARCHAR(10) NOT NULL,
+    account_name VARCHAR(100) NOT NULL,
+    amount BIGINT NOT NULL,
+    balance_before BIGINT NOT NULL DEFAULT 0,
+    balance_after BIGINT NOT NULL DEFAULT 0,
+    description TEXT,
+    reference_type VARCHAR(50),
+    reference_id BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Hash chain columns; rows posted before this migration stay unchained
+ALTER TABLE ledgers ADD COLUMN IF NOT EXISTS entry_date DATE;
+ALTER TABLE ledgers ADD COLUMN IF NOT EXISTS sequence BIGINT;
+ALTER TABLE ledgers ADD COLUMN IF NOT EXISTS prev_hash CHAR(64);
+ALTER TABLE ledgers ADD COLUMN IF NOT EXISTS hash CHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledgers_sequence ON ledgers(sequence);
+CREATE INDEX IF NOT EXISTS idx_ledgers_entry_date ON ledgers(entry_date);
+CREATE INDEX IF NOT EXISTS idx_ledgers_reference ON ledgers(reference_type, reference_id);
+
+-- Ledger rows are append-only; corrections are posted as new entries
+CREATE OR REPLACE FUNCTION ledgers_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger entries are append-only; post an adjusting entry instead';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_ledgers_append_only ON ledgers;
+CREATE TRIGGER trg_ledgers_append_only
+    BEFORE UPDATE OR DELETE ON ledgers
+    FOR EACH ROW EXECUTE FUNCTION ledgers_append_only();
+
+-- Accounting periods
+CREATE TABLE IF NOT EXISTS accounting_periods (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    closing_sequence BIGINT,
+    closing_hash CHAR(64),
+    closed_by BIGINT,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_accounting_periods_range CHECK (end_date >= start_date),
+    -- Periods never overlap, even when created concurrently
+    CONSTRAINT excl_accounting_periods_overlap EXCLUDE USING gist (daterange(start_date, end_date, '[]') WITH &&)
+);
+
+CREATE INDEX idx_accounting_periods_range ON accounting_periods(start_date, end_date);
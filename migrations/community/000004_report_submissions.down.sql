@@ -0,0 +1,7 @@
+-- WaqfWise Community Edition - Rollback periodic BWI report snapshots
+
+DROP TRIGGER IF EXISTS trg_report_submissions_immutable ON report_submissions;
+DROP FUNCTION IF EXISTS report_submissions_immutable();
+
+DROP TABLE IF EXISTS report_submissions;
+DROP TABLE IF EXISTS asset_valuations;
@@ -0,0 +1,49 @@
+-- WaqfWise Community Edition - Periodic BWI report snapshots
+
+-- Valuation history of assets, reported as the value at period end
+CREATE TABLE IF NOT EXISTS asset_valuations (
+    id BIGSERIAL PRIMARY KEY,
+    asset_id BIGINT NOT NULL REFERENCES assets(id),
+    valuation_value BIGINT NOT NULL CHECK (valuation_value >= 0),
+    valuation_date DATE NOT NULL,
+    valued_by VARCHAR(255),
+    method VARCHAR(50),
+    notes TEXT,
+    document_url TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_valuations_asset ON asset_valuations(asset_id, valuation_date DESC);
+
+-- Snapshot of each submitted report; content is kept byte-for-byte as
+-- submitted (JSON, not JSONB) so content_hash can be re-verified
+CREATE TABLE IF NOT EXISTS report_submissions (
+    id BIGSERIAL PRIMARY KEY,
+    report_type VARCHAR(50) NOT NULL,
+    period_type VARCHAR(20) NOT NULL,
+    year INTEGER NOT NULL,
+    semester INTEGER NOT NULL DEFAULT 0,
+    revision INTEGER NOT NULL DEFAULT 1,
+    content JSON NOT NULL,
+    content_hash CHAR(64) NOT NULL,
+    reference VARCHAR(100),
+    notes TEXT,
+    submitted_by BIGINT NOT NULL,
+    submitted_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_report_submissions_semester CHECK (semester BETWEEN 0 AND 2),
+    CONSTRAINT uq_report_submissions_revision UNIQUE (report_type, year, semester, revision)
+);
+
+CREATE INDEX idx_report_submissions_period ON report_submissions(report_type, year DESC, semester DESC);
+
+-- Submitted snapshots are immutable; corrections are submitted as a new revision
+CREATE OR REPLACE FUNCTION report_submissions_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'report submissions are immutable; submit a new revision instead';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_report_submissions_immutable ON report_submissions;
+CREATE TRIGGER trg_report_submissions_immutable
+    BEFORE UPDATE OR DELETE ON report_submissions
+    FOR EACH ROW EXECUTE FUNCTION report_submissions_immutable();
@@ -0,0 +1,27 @@
+-- WaqfWise Community Edition - Rollback campaign service
+
+DROP TABLE IF EXISTS campaign_milestones;
+
+DROP INDEX IF EXISTS idx_campaigns_featured;
+DROP INDEX IF EXISTS idx_campaigns_type;
+DROP INDEX IF EXISTS idx_campaigns_slug;
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS is_urgent;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS is_featured;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS is_endless;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS location;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS video_url;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS image_url;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS donor_count;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS type;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS short_desc;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS slug;
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'campaigns' AND column_name = 'goal_amount') THEN
+        ALTER TABLE campaigns RENAME COLUMN goal_amount TO target_amount;
+    END IF;
+END $$;
@@ -0,0 +1,42 @@
+-- WaqfWise Community Edition - Campaign service
+
+-- Campaign columns used by the campaign service
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'campaigns' AND column_name = 'target_amount') THEN
+        ALTER TABLE campaigns RENAME COLUMN target_amount TO goal_amount;
+    END IF;
+END $$;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS slug VARCHAR(100);
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS short_desc VARCHAR(500);
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS type VARCHAR(50) NOT NULL DEFAULT 'general';
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS donor_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS image_url TEXT;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS video_url TEXT;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS location VARCHAR(255);
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS is_endless BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS is_featured BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS is_urgent BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS tenant_id BIGINT;
+ALTER TABLE campaigns ALTER COLUMN end_date DROP NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaigns_slug ON campaigns(slug);
+CREATE INDEX IF NOT EXISTS idx_campaigns_type ON campaigns(type);
+CREATE INDEX IF NOT EXISTS idx_campaigns_featured ON campaigns(is_featured, created_at DESC);
+
+-- Campaign milestones
+CREATE TABLE IF NOT EXISTS campaign_milestones (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    target_amount BIGINT NOT NULL CHECK (target_amount > 0),
+    is_completed BOOLEAN NOT NULL DEFAULT FALSE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_campaign_milestones_campaign ON campaign_milestones(campaign_id, target_amount);
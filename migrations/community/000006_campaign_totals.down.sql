@@ -0,0 +1,3 @@
+-- WaqfWise Community Edition - Rollback campaign totals from successful donations
+
+DROP TABLE IF EXISTS campaign_donations;
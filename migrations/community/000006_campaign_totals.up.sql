@@ -0,0 +1,13 @@
+-- WaqfWise Community Edition - Campaign totals from successful donations
+
+-- Donations already counted in campaign totals; the primary key makes
+-- applying a donation idempotent
+CREATE TABLE IF NOT EXISTS campaign_donations (
+    donation_id BIGINT PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    donor_key VARCHAR(320) NOT NULL,
+    amount BIGINT NOT NULL,
+    applied_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_campaign_donations_donor ON campaign_donations(campaign_id, donor_key);
@@ -0,0 +1,13 @@
+-- WaqfWise Community Edition - Rollback campaign full-text search
+
+DROP INDEX IF EXISTS idx_campaign_donations_applied;
+DROP INDEX IF EXISTS idx_campaigns_search;
+
+DROP TRIGGER IF EXISTS trg_campaigns_search_vector ON campaigns;
+DROP FUNCTION IF EXISTS campaigns_search_vector();
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS waqfwise_indonesian_clean(TEXT);
+DROP TEXT SEARCH CONFIGURATION IF EXISTS waqfwise_indonesian;
+DROP TEXT SEARCH DICTIONARY IF EXISTS waqfwise_indonesian_stem;
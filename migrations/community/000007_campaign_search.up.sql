@@ -0,0 +1,55 @@
+-- WaqfWise Community Edition - Campaign full-text search
+
+-- Indonesian text search: Snowball stemming (PostgreSQL 13+) on top of the
+-- simple parser. PostgreSQL ships no Indonesian stopword file, so stopwords
+-- are stripped by waqfwise_indonesian_clean before indexing and querying.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_dict WHERE dictname = 'waqfwise_indonesian_stem') THEN
+        CREATE TEXT SEARCH DICTIONARY waqfwise_indonesian_stem (TEMPLATE = snowball, LANGUAGE = indonesian);
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'waqfwise_indonesian') THEN
+        CREATE TEXT SEARCH CONFIGURATION waqfwise_indonesian (COPY = simple);
+        ALTER TEXT SEARCH CONFIGURATION waqfwise_indonesian
+            ALTER MAPPING FOR asciiword, asciihword, hword_asciipart, word, hword, hword_part
+            WITH waqfwise_indonesian_stem;
+    END IF;
+END $$;
+
+-- Removes common Indonesian function words so they neither bloat the index
+-- nor dilute ranking
+CREATE OR REPLACE FUNCTION waqfwise_indonesian_clean(input TEXT) RETURNS TEXT AS $$
+    SELECT regexp_replace(
+        lower(COALESCE(input, '')),
+        '\m(yang|dan|di|ke|dari|untuk|dengan|pada|dalam|ini|itu|atau|juga|akan|adalah|oleh|sebagai|bagi|kepada|karena|agar|supaya|serta|telah|sudah|masih|bisa|dapat|tidak|ada|kami|kita|mereka|anda|saya|ia|dia|para|sang|si|pun|lah|kah|nya|tersebut|sehingga|namun|tetapi|jika|bila|maka|hingga|sampai|secara|antara|setiap|semua|lebih|sangat|hanya|mari|yuk)\M',
+        ' ',
+        'g'
+    );
+$$ LANGUAGE sql IMMUTABLE;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+-- Title weighs most, then the short description and location, then the story
+CREATE OR REPLACE FUNCTION campaigns_search_vector() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('waqfwise_indonesian', waqfwise_indonesian_clean(NEW.title)), 'A') ||
+        setweight(to_tsvector('waqfwise_indonesian', waqfwise_indonesian_clean(NEW.short_desc)), 'B') ||
+        setweight(to_tsvector('waqfwise_indonesian', waqfwise_indonesian_clean(NEW.location)), 'B') ||
+        setweight(to_tsvector('waqfwise_indonesian', waqfwise_indonesian_clean(NEW.description)), 'C');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_campaigns_search_vector ON campaigns;
+CREATE TRIGGER trg_campaigns_search_vector
+    BEFORE INSERT OR UPDATE OF title, short_desc, location, description ON campaigns
+    FOR EACH ROW EXECUTE FUNCTION campaigns_search_vector();
+
+-- Index existing campaigns
+UPDATE campaigns SET title = title;
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_search ON campaigns USING GIN(search_vector);
+
+-- Recent donations drive the momentum part of search ranking
+CREATE INDEX IF NOT EXISTS idx_campaign_donations_applied ON campaign_donations(applied_at, campaign_id);
@@ -0,0 +1,5 @@
+-- WaqfWise Community Edition - Rollback campaign updates and subscriber notifications
+
+DROP TABLE IF EXISTS campaign_subscriptions;
+DROP TABLE IF EXISTS campaign_updates;
+DROP TABLE IF EXISTS notifications;
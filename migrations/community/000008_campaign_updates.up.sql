@@ -0,0 +1,68 @@
+-- WaqfWise Community Edition - Campaign updates and subscriber notifications
+
+-- Notification outbox; rows are sent and retried by the notify outbox loop
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    channel VARCHAR(20) NOT NULL,
+    recipient VARCHAR(320) NOT NULL,
+    subject VARCHAR(255),
+    body TEXT NOT NULL,
+    dedupe_key VARCHAR(255) UNIQUE,
+    reference_type VARCHAR(50),
+    reference_id BIGINT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Claimed rows are 'sending' until sent or retried; next_attempt_at holds the claim's lease
+CREATE INDEX idx_notifications_pending ON notifications(next_attempt_at, id) WHERE status IN ('pending', 'sending');
+CREATE INDEX idx_notifications_reference ON notifications(reference_type, reference_id);
+
+-- Campaign updates (kabar terbaru); the body is Markdown
+CREATE TABLE IF NOT EXISTS campaign_updates (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    images TEXT[] NOT NULL DEFAULT '{}',
+    milestone_id BIGINT,
+    disbursement_id BIGINT,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    author_id BIGINT NOT NULL,
+    published_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_campaign_updates_feed ON campaign_updates(campaign_id, published_at DESC) WHERE status = 'published';
+
+-- Donors subscribed to campaign updates, keyed like campaign_donations
+CREATE TABLE IF NOT EXISTS campaign_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    donor_key VARCHAR(320) NOT NULL,
+    user_id BIGINT,
+    email VARCHAR(255),
+    channel VARCHAR(20) NOT NULL DEFAULT 'email',
+    token VARCHAR(64) NOT NULL DEFAULT replace(gen_random_uuid()::text, '-', ''),
+    unsubscribed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_campaign_subscriptions_donor UNIQUE (campaign_id, donor_key)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_subscriptions_token ON campaign_subscriptions(token);
+
+-- Subscribe donors already counted in campaign totals
+INSERT INTO campaign_subscriptions (campaign_id, donor_key, user_id, email)
+SELECT DISTINCT ON (cd.campaign_id, cd.donor_key)
+       cd.campaign_id, cd.donor_key, NULLIF(d.user_id, 0), NULLIF(TRIM(d.donor_email), '')
+FROM campaign_donations cd
+JOIN donations d ON d.id = cd.donation_id
+WHERE cd.donor_key NOT LIKE 'donation:%'
+ORDER BY cd.campaign_id, cd.donor_key, cd.donation_id DESC
+ON CONFLICT (campaign_id, donor_key) DO NOTHING;
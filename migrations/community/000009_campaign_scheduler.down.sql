@@ -0,0 +1,10 @@
+-- WaqfWise Community Edition - Rollback scheduled campaign publishing, expiry and overflow
+
+DROP INDEX IF EXISTS idx_campaigns_end_date;
+DROP INDEX IF EXISTS idx_campaigns_auto_publish;
+
+ALTER TABLE donations DROP COLUMN IF EXISTS overflow_from_campaign_id;
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS overflow_campaign_id;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS accept_overflow;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS auto_publish;
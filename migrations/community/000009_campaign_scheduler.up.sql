@@ -0,0 +1,12 @@
+-- WaqfWise Community Edition - Scheduled campaign publishing, expiry and overflow
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS auto_publish BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS accept_overflow BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS overflow_campaign_id BIGINT;
+
+-- Campaign a donation was made to before it moved to the overflow fund
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS overflow_from_campaign_id BIGINT;
+
+-- Scheduler lookups: drafts due to go live and campaigns past their end date
+CREATE INDEX IF NOT EXISTS idx_campaigns_auto_publish ON campaigns(start_date) WHERE status = 'draft' AND auto_publish;
+CREATE INDEX IF NOT EXISTS idx_campaigns_end_date ON campaigns(end_date) WHERE status IN ('active', 'paused') AND NOT is_endless;
@@ -0,0 +1,11 @@
+-- WaqfWise Community Edition - Rollback campaign moderation, review history and versions
+
+DROP TABLE IF EXISTS campaign_reviews;
+DROP TABLE IF EXISTS campaign_versions;
+
+DROP INDEX IF EXISTS idx_campaigns_pending_review;
+
+UPDATE campaigns SET status = 'draft' WHERE status = 'pending_review';
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS approved_by;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS approved_at;
@@ -0,0 +1,44 @@
+-- WaqfWise Community Edition - Campaign moderation, review history and versions
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS approved_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS approved_by BIGINT;
+
+-- Campaigns already live before moderation count as approved
+UPDATE campaigns SET approved_at = updated_at
+WHERE approved_at IS NULL AND status IN ('active', 'paused', 'completed');
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_pending_review ON campaigns(updated_at) WHERE status = 'pending_review';
+
+-- Snapshots of approved campaigns after each edit; material edits wait as
+-- pending versions until an admin approves them
+CREATE TABLE IF NOT EXISTS campaign_versions (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    version INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    changed_fields TEXT[] NOT NULL DEFAULT '{}',
+    material BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL,
+    edited_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_campaign_versions_version UNIQUE (campaign_id, version)
+);
+
+-- At most one change awaits review per campaign
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_versions_pending ON campaign_versions(campaign_id) WHERE status = 'pending';
+
+-- Admin decisions with the review checklist
+CREATE TABLE IF NOT EXISTS campaign_reviews (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    version_id BIGINT,
+    decision VARCHAR(20) NOT NULL,
+    land_documents BOOLEAN NOT NULL DEFAULT FALSE,
+    akad_stated BOOLEAN NOT NULL DEFAULT FALSE,
+    goal_justified BOOLEAN NOT NULL DEFAULT FALSE,
+    comment TEXT,
+    reviewer_id BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_campaign_reviews_campaign ON campaign_reviews(campaign_id, created_at DESC);
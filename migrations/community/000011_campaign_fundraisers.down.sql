@@ -0,0 +1,7 @@
+-- WaqfWise Community Edition - Rollback peer-to-peer fundraiser pages
+
+DROP INDEX IF EXISTS idx_campaign_donations_fundraiser;
+ALTER TABLE campaign_donations DROP COLUMN IF EXISTS fundraiser_id;
+ALTER TABLE donations DROP COLUMN IF EXISTS fundraiser_id;
+
+DROP TABLE IF EXISTS campaign_fundraisers;
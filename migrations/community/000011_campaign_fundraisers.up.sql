@@ -0,0 +1,30 @@
+-- WaqfWise Community Edition - Peer-to-peer fundraiser pages
+
+CREATE TABLE IF NOT EXISTS campaign_fundraisers (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    story TEXT NOT NULL,
+    goal_amount BIGINT NOT NULL,
+    current_amount BIGINT NOT NULL DEFAULT 0,
+    donor_count INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    hidden_reason TEXT,
+    hidden_by BIGINT,
+    hidden_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_campaign_fundraisers_user UNIQUE (campaign_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_fundraisers_leaderboard ON campaign_fundraisers(campaign_id, current_amount DESC) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_campaign_fundraisers_user ON campaign_fundraisers(user_id);
+
+-- Page a donation came through; counted in campaign_donations only when the
+-- page belongs to the campaign the donation is applied to
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS fundraiser_id BIGINT;
+ALTER TABLE campaign_donations ADD COLUMN IF NOT EXISTS fundraiser_id BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_campaign_donations_fundraiser ON campaign_donations(fundraiser_id) WHERE fundraiser_id IS NOT NULL;
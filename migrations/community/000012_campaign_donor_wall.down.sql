@@ -0,0 +1,5 @@
+-- WaqfWise Community Edition - Rollback public donor wall and leaderboards
+
+DROP INDEX IF EXISTS idx_campaign_donations_wall;
+ALTER TABLE campaign_donations DROP COLUMN IF EXISTS message_hidden_at;
+ALTER TABLE campaign_donations DROP COLUMN IF EXISTS message_hidden_by;
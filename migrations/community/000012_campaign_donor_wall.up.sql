@@ -0,0 +1,8 @@
+-- WaqfWise Community Edition - Public donor wall and leaderboards
+
+-- Donor messages hidden from the wall by a campaign manager
+ALTER TABLE campaign_donations ADD COLUMN IF NOT EXISTS message_hidden_by BIGINT;
+ALTER TABLE campaign_donations ADD COLUMN IF NOT EXISTS message_hidden_at TIMESTAMP WITH TIME ZONE;
+
+-- Donor wall pages and leaderboard periods, newest first
+CREATE INDEX IF NOT EXISTS idx_campaign_donations_wall ON campaign_donations(campaign_id, applied_at DESC, donation_id DESC);
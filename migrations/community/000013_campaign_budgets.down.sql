@@ -0,0 +1,4 @@
+-- WaqfWise Community Edition - Rollback campaign budget plans (RAB) and disbursements
+
+DROP TABLE IF EXISTS campaign_disbursements;
+DROP TABLE IF EXISTS campaign_budget_lines;
@@ -0,0 +1,43 @@
+-- WaqfWise Community Edition - Campaign budget plans (RAB) and disbursements
+
+CREATE TABLE IF NOT EXISTS campaign_budget_lines (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    category VARCHAR(30) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    planned_amount BIGINT NOT NULL CHECK (planned_amount > 0),
+    spent_amount BIGINT NOT NULL DEFAULT 0,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_budget_lines_campaign ON campaign_budget_lines(campaign_id, position);
+
+-- Money paid out of campaign funds against a budget line. Only posted
+-- disbursements count as spent and reach the ledger.
+CREATE TABLE IF NOT EXISTS campaign_disbursements (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    budget_line_id BIGINT NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    recipient VARCHAR(255) NOT NULL,
+    description TEXT,
+    receipt_url TEXT,
+    disbursed_at DATE NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    over_budget BOOLEAN NOT NULL DEFAULT FALSE,
+    requested_by BIGINT NOT NULL,
+    decided_by BIGINT,
+    decided_at TIMESTAMP WITH TIME ZONE,
+    rejection_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_disbursements_campaign ON campaign_disbursements(campaign_id, disbursed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_campaign_disbursements_line ON campaign_disbursements(budget_line_id);
+
+-- An over-budget disbursement awaiting approval blocks further spending on its line
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_disbursements_pending ON campaign_disbursements(budget_line_id) WHERE status = 'pending_approval';
@@ -0,0 +1,3 @@
+-- WaqfWise Community Edition - Rollback campaign templates
+
+DROP TABLE IF EXISTS campaign_templates;
@@ -0,0 +1,26 @@
+-- WaqfWise Community Edition - Campaign templates
+
+CREATE TABLE IF NOT EXISTS campaign_templates (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    short_desc VARCHAR(500),
+    type VARCHAR(50) NOT NULL,
+    goal_amount BIGINT NOT NULL,
+    image_url TEXT,
+    video_url TEXT,
+    location VARCHAR(255),
+    is_endless BOOLEAN NOT NULL DEFAULT FALSE,
+    duration_days INTEGER,
+    milestones JSONB NOT NULL DEFAULT '[]',
+    budget_lines JSONB NOT NULL DEFAULT '[]',
+    owner_id BIGINT NOT NULL,
+    is_shared BOOLEAN NOT NULL DEFAULT FALSE,
+    tenant_id BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_templates_owner ON campaign_templates(owner_id);
+CREATE INDEX IF NOT EXISTS idx_campaign_templates_shared ON campaign_templates(tenant_id) WHERE is_shared;
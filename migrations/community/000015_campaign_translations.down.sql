@@ -0,0 +1,3 @@
+-- WaqfWise Community Edition - Rollback campaign translations
+
+DROP TABLE IF EXISTS campaign_translations;
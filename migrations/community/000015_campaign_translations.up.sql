@@ -0,0 +1,20 @@
+-- WaqfWise Community Edition - Campaign translations
+
+-- Campaigns are written in Indonesian; each row translates the text of a
+-- campaign, milestone or update into another locale
+CREATE TABLE IF NOT EXISTS campaign_translations (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id BIGINT NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    short_desc VARCHAR(500) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    updated_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaign_translations_entity ON campaign_translations(entity_type, entity_id, locale);
+CREATE INDEX IF NOT EXISTS idx_campaign_translations_campaign ON campaign_translations(campaign_id);
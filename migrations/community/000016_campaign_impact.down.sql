@@ -0,0 +1,4 @@
+-- WaqfWise Community Edition - Rollback campaign impact indicators
+
+DROP TABLE IF EXISTS campaign_impact_measurements;
+DROP TABLE IF EXISTS campaign_impact_indicators;
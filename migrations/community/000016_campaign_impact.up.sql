@@ -0,0 +1,32 @@
+-- WaqfWise Community Edition - Campaign impact indicators
+
+CREATE TABLE IF NOT EXISTS campaign_impact_indicators (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    unit VARCHAR(50) NOT NULL,
+    description TEXT,
+    target_value NUMERIC(20, 2) NOT NULL CHECK (target_value > 0),
+    current_value NUMERIC(20, 2) NOT NULL DEFAULT 0,
+    position INTEGER NOT NULL DEFAULT 0,
+    last_measured_at DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_impact_indicators_campaign ON campaign_impact_indicators(campaign_id, position);
+
+-- Measurements are cumulative readings; the latest one is the indicator's current value
+CREATE TABLE IF NOT EXISTS campaign_impact_measurements (
+    id BIGSERIAL PRIMARY KEY,
+    indicator_id BIGINT NOT NULL,
+    campaign_id BIGINT NOT NULL,
+    value NUMERIC(20, 2) NOT NULL CHECK (value >= 0),
+    note TEXT,
+    evidence_urls TEXT[] NOT NULL DEFAULT '{}',
+    measured_at DATE NOT NULL,
+    recorded_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_impact_measurements_indicator ON campaign_impact_measurements(indicator_id, measured_at DESC, id DESC);
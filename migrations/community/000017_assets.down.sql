@@ -0,0 +1,4 @@
+-- WaqfWise Community Edition - Rollback wakaf asset documents and status history
+
+DROP TABLE IF EXISTS asset_status_changes;
+DROP TABLE IF EXISTS asset_documents;
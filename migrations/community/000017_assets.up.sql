@@ -0,0 +1,36 @@
+-- WaqfWise Community Edition - Wakaf asset documents and status history
+
+-- assets and asset_valuations are created with the distribution (000002) and
+-- report (000004) schemas that read them first
+
+-- Legal documents attached to assets; files live in object storage
+CREATE TABLE IF NOT EXISTS asset_documents (
+    id BIGSERIAL PRIMARY KEY,
+    asset_id BIGINT NOT NULL,
+    document_type VARCHAR(50) NOT NULL,
+    document_name VARCHAR(255) NOT NULL,
+    file_url TEXT NOT NULL,
+    file_size BIGINT NOT NULL DEFAULT 0 CHECK (file_size >= 0),
+    mime_type VARCHAR(100),
+    issue_date DATE,
+    expiry_date DATE,
+    notes TEXT,
+    uploaded_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_documents_asset ON asset_documents(asset_id, document_type);
+
+-- Status history of assets
+CREATE TABLE IF NOT EXISTS asset_status_changes (
+    id BIGSERIAL PRIMARY KEY,
+    asset_id BIGINT NOT NULL,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    reason TEXT,
+    changed_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_status_changes_asset ON asset_status_changes(asset_id, created_at DESC);
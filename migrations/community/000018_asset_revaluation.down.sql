@@ -0,0 +1,12 @@
+-- WaqfWise Community Edition - Rollback asset valuation approval and revaluation
+
+DROP INDEX IF EXISTS idx_asset_valuations_pending_queue;
+DROP INDEX IF EXISTS idx_asset_valuations_pending;
+
+ALTER TABLE asset_valuations DROP COLUMN IF EXISTS rejection_reason;
+ALTER TABLE asset_valuations DROP COLUMN IF EXISTS decided_at;
+ALTER TABLE asset_valuations DROP COLUMN IF EXISTS decided_by;
+ALTER TABLE asset_valuations DROP COLUMN IF EXISTS recorded_by;
+ALTER TABLE asset_valuations DROP COLUMN IF EXISTS adjustment;
+ALTER TABLE asset_valuations DROP COLUMN IF EXISTS previous_value;
+ALTER TABLE asset_valuations DROP COLUMN IF EXISTS status;
@@ -0,0 +1,16 @@
+-- WaqfWise Community Edition - Asset valuation approval and revaluation
+
+ALTER TABLE asset_valuations ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending';
+ALTER TABLE asset_valuations ADD COLUMN IF NOT EXISTS previous_value BIGINT;
+ALTER TABLE asset_valuations ADD COLUMN IF NOT EXISTS adjustment BIGINT;
+ALTER TABLE asset_valuations ADD COLUMN IF NOT EXISTS recorded_by BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE asset_valuations ADD COLUMN IF NOT EXISTS decided_by BIGINT;
+ALTER TABLE asset_valuations ADD COLUMN IF NOT EXISTS decided_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE asset_valuations ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+
+-- Valuations recorded before approval existed count as approved
+UPDATE asset_valuations SET status = 'approved', decided_at = created_at WHERE status = 'pending';
+
+-- At most one valuation awaits approval per asset
+CREATE UNIQUE INDEX IF NOT EXISTS idx_asset_valuations_pending ON asset_valuations(asset_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_asset_valuations_pending_queue ON asset_valuations(created_at) WHERE status = 'pending';
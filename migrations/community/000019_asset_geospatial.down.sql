@@ -0,0 +1,6 @@
+-- WaqfWise Community Edition - Rollback asset boundaries and spatial queries
+
+DROP INDEX IF EXISTS idx_assets_location;
+DROP TABLE IF EXISTS asset_geospatial;
+
+-- The postgis extension is left installed; other database objects may use it
@@ -0,0 +1,26 @@
+-- WaqfWise Community Edition - Asset boundaries and spatial queries
+
+CREATE EXTENSION IF NOT EXISTS postgis;
+
+-- Surveyed boundary of an asset, validated and measured when it is saved.
+-- The normalized GeoJSON is kept alongside the geometry built from it.
+CREATE TABLE IF NOT EXISTS asset_geospatial (
+    asset_id BIGINT PRIMARY KEY,
+    geometry GEOMETRY(Polygon, 4326) NOT NULL,
+    boundary JSONB NOT NULL,
+    land_use VARCHAR(100),
+    zoning_type VARCHAR(100),
+    elevation DOUBLE PRECISION,
+    area_sqm DOUBLE PRECISION NOT NULL CHECK (area_sqm > 0),
+    updated_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_geospatial_geometry ON asset_geospatial USING GIST (geometry);
+CREATE INDEX IF NOT EXISTS idx_asset_geospatial_geography ON asset_geospatial USING GIST ((geometry::geography));
+
+-- Assets without a boundary are located by their point
+CREATE INDEX IF NOT EXISTS idx_assets_location ON assets
+    USING GIST ((ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography))
+    WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
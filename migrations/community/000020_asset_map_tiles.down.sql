@@ -0,0 +1,7 @@
+-- WaqfWise Community Edition - Rollback asset map vector tiles
+
+DROP TRIGGER IF EXISTS trg_asset_geospatial_map_version ON asset_geospatial;
+DROP TRIGGER IF EXISTS trg_assets_map_version ON assets;
+DROP FUNCTION IF EXISTS bump_asset_map_version();
+
+DROP TABLE IF EXISTS asset_map_version;
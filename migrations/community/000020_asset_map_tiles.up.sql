@@ -0,0 +1,31 @@
+-- WaqfWise Community Edition - Asset map vector tiles
+
+-- Version of the public asset map, bumped whenever something drawn on it
+-- changes. Vector tiles are cached and tagged with this version. The bump
+-- happens inside the changing transaction, so a new version is only seen
+-- together with the data it stands for.
+CREATE TABLE IF NOT EXISTS asset_map_version (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    version BIGINT NOT NULL DEFAULT 1,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO asset_map_version (id) VALUES (1) ON CONFLICT (id) DO NOTHING;
+
+CREATE OR REPLACE FUNCTION bump_asset_map_version() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE asset_map_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Only the columns drawn on the map or deciding what is drawn
+DROP TRIGGER IF EXISTS trg_assets_map_version ON assets;
+CREATE TRIGGER trg_assets_map_version
+    AFTER INSERT OR DELETE OR UPDATE OF name, type, status, latitude, longitude ON assets
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_asset_map_version();
+
+DROP TRIGGER IF EXISTS trg_asset_geospatial_map_version ON asset_geospatial;
+CREATE TRIGGER trg_asset_geospatial_map_version
+    AFTER INSERT OR DELETE OR UPDATE OF geometry ON asset_geospatial
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_asset_map_version();
@@ -0,0 +1,5 @@
+-- WaqfWise Community Edition - Rollback lease contracts, rent invoices and payments
+
+DROP TABLE IF EXISTS lease_payments;
+DROP TABLE IF EXISTS lease_invoices;
+DROP TABLE IF EXISTS leases;
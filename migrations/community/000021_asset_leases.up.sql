@@ -0,0 +1,72 @@
+-- WaqfWise Community Edition - Lease contracts, rent invoices and payments
+
+-- Rental contracts of productive wakaf assets
+CREATE TABLE IF NOT EXISTS leases (
+    id BIGSERIAL PRIMARY KEY,
+    asset_id BIGINT NOT NULL,
+    lessee_name VARCHAR(255) NOT NULL,
+    lessee_id_number VARCHAR(50),
+    lessee_email VARCHAR(255),
+    lessee_phone VARCHAR(50),
+    lessee_address TEXT,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    rent_amount BIGINT NOT NULL CHECK (rent_amount > 0),
+    billing_cycle VARCHAR(20) NOT NULL,
+    payment_term_days INTEGER NOT NULL DEFAULT 0 CHECK (payment_term_days >= 0),
+    escalation_bps INTEGER NOT NULL DEFAULT 0 CHECK (escalation_bps >= 0),
+    escalation_months INTEGER NOT NULL DEFAULT 0 CHECK (escalation_months >= 0),
+    deposit_amount BIGINT NOT NULL DEFAULT 0 CHECK (deposit_amount >= 0),
+    deposit_status VARCHAR(20) NOT NULL DEFAULT 'held',
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    terminated_at DATE,
+    termination_reason TEXT,
+    notes TEXT,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_leases_asset ON leases(asset_id, start_date DESC);
+CREATE INDEX IF NOT EXISTS idx_leases_active ON leases(end_date) WHERE status = 'active';
+
+-- Rent due per billing period; the scheduler issues one per period as it starts
+CREATE TABLE IF NOT EXISTS lease_invoices (
+    id BIGSERIAL PRIMARY KEY,
+    lease_id BIGINT NOT NULL,
+    asset_id BIGINT NOT NULL,
+    sequence INTEGER NOT NULL CHECK (sequence > 0),
+    number VARCHAR(50) NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    amount_paid BIGINT NOT NULL DEFAULT 0 CHECK (amount_paid >= 0 AND amount_paid <= amount),
+    due_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'unpaid',
+    paid_at DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (lease_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lease_invoices_open ON lease_invoices(due_date) WHERE status IN ('unpaid', 'partial');
+
+-- Rent received; each payment is also an asset_incomes row of source rent
+CREATE TABLE IF NOT EXISTS lease_payments (
+    id BIGSERIAL PRIMARY KEY,
+    invoice_id BIGINT NOT NULL,
+    lease_id BIGINT NOT NULL,
+    asset_id BIGINT NOT NULL,
+    income_id BIGINT NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    paid_at DATE NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    reference VARCHAR(255),
+    notes TEXT,
+    recorded_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_lease_payments_lease ON lease_payments(lease_id, paid_at DESC);
+CREATE INDEX IF NOT EXISTS idx_lease_payments_invoice ON lease_payments(invoice_id);
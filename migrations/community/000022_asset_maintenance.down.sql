@@ -0,0 +1,4 @@
+-- WaqfWise Community Edition - Rollback asset maintenance schedules and work orders
+
+DROP TABLE IF EXISTS work_orders;
+DROP TABLE IF EXISTS maintenance_schedules;
@@ -0,0 +1,47 @@
+-- WaqfWise Community Edition - Asset maintenance schedules and work orders
+
+-- Recurring upkeep of assets
+CREATE TABLE IF NOT EXISTS maintenance_schedules (
+    id BIGSERIAL PRIMARY KEY,
+    asset_id BIGINT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    interval_months INTEGER NOT NULL CHECK (interval_months > 0),
+    next_due_date DATE NOT NULL,
+    last_done_date DATE,
+    estimated_cost BIGINT NOT NULL DEFAULT 0 CHECK (estimated_cost >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_schedules_asset ON maintenance_schedules(asset_id);
+CREATE INDEX IF NOT EXISTS idx_maintenance_schedules_due ON maintenance_schedules(next_due_date) WHERE is_active;
+
+-- Maintenance jobs; an asset is under maintenance while one is open
+CREATE TABLE IF NOT EXISTS work_orders (
+    id BIGSERIAL PRIMARY KEY,
+    asset_id BIGINT NOT NULL,
+    schedule_id BIGINT,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    vendor VARCHAR(255),
+    vendor_contact VARCHAR(255),
+    estimated_cost BIGINT NOT NULL DEFAULT 0 CHECK (estimated_cost >= 0),
+    actual_cost BIGINT CHECK (actual_cost >= 0),
+    photo_urls TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    restore_status VARCHAR(50) NOT NULL,
+    opened_by BIGINT NOT NULL,
+    closed_by BIGINT,
+    completed_on DATE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    close_notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_work_orders_asset ON work_orders(asset_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_work_orders_open ON work_orders(asset_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_work_orders_schedule ON work_orders(schedule_id) WHERE schedule_id IS NOT NULL;
@@ -0,0 +1,5 @@
+-- WaqfWise Community Edition - Rollback asset depreciation
+
+DROP TABLE IF EXISTS asset_depreciation_entries;
+DROP TABLE IF EXISTS asset_depreciation;
+DROP TABLE IF EXISTS depreciation_defaults;
@@ -0,0 +1,35 @@
+-- WaqfWise Community Edition - Asset depreciation
+
+-- Depreciation policy of every asset of a type, unless the asset has its own
+CREATE TABLE IF NOT EXISTS depreciation_defaults (
+    asset_type VARCHAR(50) PRIMARY KEY,
+    method VARCHAR(30) NOT NULL CHECK (method IN ('straight_line', 'declining_balance')),
+    useful_life_months INTEGER NOT NULL CHECK (useful_life_months > 0),
+    salvage_bps INTEGER NOT NULL DEFAULT 0 CHECK (salvage_bps >= 0 AND salvage_bps < 10000),
+    rate_bps INTEGER NOT NULL DEFAULT 0 CHECK (rate_bps >= 0),
+    updated_by BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Depreciation policy set on one asset
+CREATE TABLE IF NOT EXISTS asset_depreciation (
+    asset_id BIGINT PRIMARY KEY,
+    method VARCHAR(30) NOT NULL CHECK (method IN ('straight_line', 'declining_balance')),
+    useful_life_months INTEGER NOT NULL CHECK (useful_life_months > 0),
+    salvage_value BIGINT NOT NULL DEFAULT 0 CHECK (salvage_value >= 0),
+    rate_bps INTEGER NOT NULL DEFAULT 0 CHECK (rate_bps >= 0),
+    updated_by BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Depreciation posted for each month of an asset's useful life
+CREATE TABLE IF NOT EXISTS asset_depreciation_entries (
+    id BIGSERIAL PRIMARY KEY,
+    asset_id BIGINT NOT NULL,
+    period DATE NOT NULL, -- first day of the month
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    accumulated BIGINT NOT NULL,
+    book_value BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (asset_id, period)
+);
@@ -0,0 +1,4 @@
+-- WaqfWise Community Edition - Rollback ikrar wakaf and land certification workflow
+
+DROP TABLE IF EXISTS certification_steps;
+DROP TABLE IF EXISTS certification_workflows;
@@ -0,0 +1,41 @@
+-- WaqfWise Community Edition - Ikrar wakaf and land certification workflow
+
+-- Certification of a land asset as wakaf land; one live workflow per asset
+CREATE TABLE IF NOT EXISTS certification_workflows (
+    id BIGSERIAL PRIMARY KEY,
+    asset_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress',
+    notes TEXT,
+    started_by BIGINT NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    cancel_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_certification_workflows_live ON certification_workflows(asset_id)
+    WHERE status <> 'cancelled';
+
+-- Steps of a workflow: ikrar before the PPAIW, AIW deed, KUA registration
+-- and BPN certification, done in sequence
+CREATE TABLE IF NOT EXISTS certification_steps (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_id BIGINT NOT NULL,
+    asset_id BIGINT NOT NULL,
+    stage VARCHAR(30) NOT NULL,
+    sequence INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    responsible_user_id BIGINT,
+    responsible_name VARCHAR(255),
+    due_date DATE,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    notes TEXT,
+    updated_by BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (workflow_id, stage),
+    UNIQUE (workflow_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_certification_steps_open ON certification_steps(workflow_id, sequence)
+    WHERE status <> 'completed';
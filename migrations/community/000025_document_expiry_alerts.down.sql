@@ -0,0 +1,4 @@
+-- WaqfWise Community Edition - Rollback asset document expiry alerts
+
+DROP TABLE IF EXISTS document_expiry_alerts;
+DROP INDEX IF EXISTS idx_asset_documents_expiry;
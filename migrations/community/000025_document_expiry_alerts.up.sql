@@ -0,0 +1,21 @@
+-- WaqfWise Community Edition - Asset document expiry alerts
+
+CREATE INDEX IF NOT EXISTS idx_asset_documents_expiry ON asset_documents(expiry_date) WHERE expiry_date IS NOT NULL;
+
+-- Alerts sent to nazirs about documents expiring or expired; window_days is
+-- 0 for the alert sent once a document has expired
+CREATE TABLE IF NOT EXISTS document_expiry_alerts (
+    id BIGSERIAL PRIMARY KEY,
+    document_id BIGINT NOT NULL,
+    asset_id BIGINT NOT NULL,
+    document_type VARCHAR(100) NOT NULL,
+    expiry_date DATE NOT NULL,
+    window_days INTEGER NOT NULL CHECK (window_days >= 0),
+    days_left INTEGER NOT NULL,
+    nazir_id BIGINT,
+    recipients TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(document_id, expiry_date, window_days)
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_expiry_alerts_asset ON document_expiry_alerts(asset_id, created_at DESC);